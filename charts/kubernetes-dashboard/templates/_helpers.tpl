@@ -99,6 +99,30 @@ private.key: {{ randBytes 256 | b64enc | quote }}
 {{- end -}}
 {{- end -}}
 
+{{- define "kubernetes-dashboard.app.veleroAudit.secret.name" -}}
+{{- printf "%s-%s" ( include "kubernetes-dashboard.fullname" . ) "velero-audit"}}
+{{- end -}}
+
+{{- define "kubernetes-dashboard.app.veleroAudit.secret.key" -}}
+{{- printf "audit.key" }}
+{{- end -}}
+
+{{- define "kubernetes-dashboard.app.veleroAudit.secret.value" -}}
+{{- $secretName := (include "kubernetes-dashboard.app.veleroAudit.secret.name" .) -}}
+{{- $secret := lookup "v1" "Secret" .Release.Namespace $secretName -}}
+{{- if .Values.app.security.veleroAuditKey -}}
+audit.key: {{ .Values.app.security.veleroAuditKey | b64enc | quote }}
+{{- else if and $secret (hasKey $secret "data") (hasKey $secret.data "audit.key") (index $secret.data "audit.key") -}}
+audit.key: {{ index $secret.data "audit.key" }}
+{{- else -}}
+audit.key: {{ randAlphaNum 64 | b64enc | quote }}
+{{- end -}}
+{{- end -}}
+
+{{- define "kubernetes-dashboard.velero.namespace" -}}
+{{- .Values.app.settings.veleroNamespace | default "velero" }}
+{{- end -}}
+
 {{- define "kubernetes-dashboard.metrics-scraper.name" -}}
 {{- printf "%s-%s" ( include "kubernetes-dashboard.fullname" . ) ( .Values.metricsScraper.role )}}
 {{- end -}}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/spf13/pflag"
 	"k8s.io/klog/v2"
@@ -53,7 +54,7 @@ var (
 	argDisableCSRFProtection    = pflag.Bool("disable-csrf-protection", false, "allows disabling CSRF protection")
 	argIsProxyEnabled           = pflag.Bool("act-as-proxy", false, "forces dashboard to work in full proxy mode, meaning that any in-cluster calls are disabled")
 	argOpenAPIEnabled           = pflag.Bool("openapi-enabled", false, "enables OpenAPI v2 endpoint under '/apidocs.json'")
-	argProfiler                 = pflag.Bool("profiler", false, "Enable pprof handler. By default it will be exposed on localhost:8070 under '/debug/pprof'")
+	argProfiler                 = pflag.Bool("profiler", false, "Enable pprof handlers, including CPU profiles and execution traces. By default they will be exposed on localhost:8070 under '/debug/pprof'")
 	argPrometheusEnabled        = pflag.Bool("prometheus-enabled", false, "Enable prometheus metrics handler. By default it will be exposed on localhost:8080 under '/metrics'")
 	argApiServerSkipTLSVerify   = pflag.Bool("apiserver-skip-tls-verify", false, "enable if connection with remote Kubernetes API server should skip TLS verify")
 	argAutoGenerateCertificates = pflag.Bool("auto-generate-certificates", false, "enables automatic certificates generation used to serve HTTPS")
@@ -74,6 +75,16 @@ var (
 	argKubeConfigFile            = pflag.String("kubeconfig", "", "path to kubeconfig file with control plane location information")
 	argNamespace                 = pflag.String("namespace", helpers.GetEnv("POD_NAMESPACE", "kubernetes-dashboard"), "Namespace to use when accessing Dashboard specific resources, i.e. metrics scraper service")
 	argMetricsScraperServiceName = pflag.String("metrics-scraper-service-name", "kubernetes-dashboard-metrics-scraper", "name of the dashboard metrics scraper service")
+	argSettingsConfigMapName     = pflag.String("settings-config-map-name", "kubernetes-dashboard-settings", "name of the config map that stores Dashboard settings, i.e. backup exclusion presets")
+
+	argVeleroMaxListItems           = pflag.Int("velero-max-list-items", 10000, "maximum number of Velero resources of one kind read into memory for a single request, larger lists are truncated and reported as partial, 0 disables the limit")
+	argVeleroOperationTimeout       = pflag.Duration("velero-operation-timeout", 30*time.Second, "maximum time a single Velero operation may spend on calls to the API server, 0 disables the timeout")
+	argVeleroRestoreReadinessWindow = pflag.Duration("velero-restore-readiness-window", 0, "time after a Velero restore completes during which readiness of restored workloads is checked and recorded on the restore, 0 disables the check")
+	argVeleroAPIVersions            = pflag.StringToString("velero-api-versions", nil, "versions to request Velero resources at, by resource, e.g. 'datauploads=v2alpha1,backups=v1', overriding the versions chosen from the served versions of the Velero CRDs")
+	argVeleroListWorkers            = pflag.Int("velero-list-workers", 8, "maximum number of concurrent calls to the API server made by a single Velero list request, e.g. when namespaces are listed one by one")
+	argVeleroListMetadataOnly       = pflag.Bool("velero-list-metadata-only", false, "list only the metadata of Velero backups unless a request asks for the enriched list, which keeps lists of many thousands of backups fast")
+	argVeleroNamespace              = pflag.String("velero-namespace", helpers.GetEnv("VELERO_NAMESPACE", ""), "namespace Velero is installed in, used by default for Velero resources, if empty it is read from the settings config map or detected in the cluster")
+	argVeleroAuditKey               = pflag.String("velero-audit-key", helpers.GetEnv("VELERO_AUDIT_KEY", ""), "secret key audit events of actions on Velero resources are signed with, so that faked events can be told apart, if empty events are not signed")
 )
 
 func init() {
@@ -171,6 +182,42 @@ func Namespace() string {
 	return *argNamespace
 }
 
+func SettingsConfigMapName() string {
+	return *argSettingsConfigMapName
+}
+
+func VeleroMaxListItems() int {
+	return *argVeleroMaxListItems
+}
+
+func VeleroOperationTimeout() time.Duration {
+	return *argVeleroOperationTimeout
+}
+
+func VeleroRestoreReadinessWindow() time.Duration {
+	return *argVeleroRestoreReadinessWindow
+}
+
+func VeleroAPIVersions() map[string]string {
+	return *argVeleroAPIVersions
+}
+
+func VeleroListWorkers() int {
+	return *argVeleroListWorkers
+}
+
+func VeleroListMetadataOnly() bool {
+	return *argVeleroListMetadataOnly
+}
+
+func VeleroNamespace() string {
+	return *argVeleroNamespace
+}
+
+func VeleroAuditKey() string {
+	return *argVeleroAuditKey
+}
+
 func IsCSRFProtectionEnabled() bool {
 	return !*argDisableCSRFProtection
 }
@@ -27,6 +27,10 @@ func initProfiler() {
 
 	mux := http.NewServeMux()
 	mux.HandleFunc(defaultProfilerPath, pprof.Index)
+	mux.HandleFunc(defaultProfilerPath+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(defaultProfilerPath+"profile", pprof.Profile)
+	mux.HandleFunc(defaultProfilerPath+"symbol", pprof.Symbol)
+	mux.HandleFunc(defaultProfilerPath+"trace", pprof.Trace)
 	go func() {
 		if err := http.ListenAndServe(fmt.Sprintf(":%d", defaultProfilerPort), mux); err != nil {
 			klog.Fatal(err)
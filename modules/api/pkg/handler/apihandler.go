@@ -30,7 +30,6 @@ import (
 
 	"k8s.io/dashboard/api/pkg/handler/parser"
 	"k8s.io/dashboard/api/pkg/integration"
-	"k8s.io/dashboard/api/pkg/resource/backup"
 	"k8s.io/dashboard/api/pkg/resource/clusterrole"
 	"k8s.io/dashboard/api/pkg/resource/clusterrolebinding"
 	"k8s.io/dashboard/api/pkg/resource/common"
@@ -58,10 +57,8 @@ import (
 	"k8s.io/dashboard/api/pkg/resource/poddisruptionbudget"
 	"k8s.io/dashboard/api/pkg/resource/replicaset"
 	"k8s.io/dashboard/api/pkg/resource/replicationcontroller"
-	"k8s.io/dashboard/api/pkg/resource/restore"
 	"k8s.io/dashboard/api/pkg/resource/role"
 	"k8s.io/dashboard/api/pkg/resource/rolebinding"
-	"k8s.io/dashboard/api/pkg/resource/schedule"
 	"k8s.io/dashboard/api/pkg/resource/secret"
 	"k8s.io/dashboard/api/pkg/resource/service"
 	"k8s.io/dashboard/api/pkg/resource/serviceaccount"
@@ -767,102 +764,8 @@ func CreateHTTPAPIHandler(iManager integration.Manager) (*restful.Container, err
 			Writes(secret.SecretList{}).
 			Returns(http.StatusOK, "OK", secret.SecretList{}))
 
-	// Velero Backup
-	apiV1Ws.Route(apiV1Ws.GET("/backup").To(apiHandler.handleGetBackupList).
-		// docs
-		Doc("returns a list of Velero Backups from all namespaces").
-		Writes(backup.BackupList{}).
-		Returns(http.StatusOK, "OK", backup.BackupList{}))
-	apiV1Ws.Route(apiV1Ws.GET("/backup/{namespace}").To(apiHandler.handleGetBackupList).
-		// docs
-		Doc("returns a list of Velero Backups in a namespace").
-		Param(apiV1Ws.PathParameter("namespace", "namespace of the Backup")).
-		Writes(backup.BackupList{}).
-		Returns(http.StatusOK, "OK", backup.BackupList{}))
-	apiV1Ws.Route(apiV1Ws.GET("/backup/{namespace}/{name}").To(apiHandler.handleGetBackupDetail).
-		// docs
-		Doc("returns detailed information about Velero Backup").
-		Param(apiV1Ws.PathParameter("namespace", "namespace of the Backup")).
-		Param(apiV1Ws.PathParameter("name", "name of the Backup")).
-		Writes(backup.BackupDetail{}).
-		Returns(http.StatusOK, "OK", backup.BackupDetail{}))
-	apiV1Ws.Route(apiV1Ws.POST("/backup/{namespace}").To(apiHandler.handleCreateBackup).
-		// docs
-		Doc("creates a new Velero Backup").
-		Param(apiV1Ws.PathParameter("namespace", "namespace for the Backup")).
-		Reads(backup.BackupSpec{}).
-		Writes(backup.Backup{}).
-		Returns(http.StatusCreated, "Created", backup.Backup{}))
-	apiV1Ws.Route(apiV1Ws.DELETE("/backup/{namespace}/{name}").To(apiHandler.handleDeleteBackup).
-		// docs
-		Doc("deletes a Velero Backup").
-		Param(apiV1Ws.PathParameter("namespace", "namespace of the Backup")).
-		Param(apiV1Ws.PathParameter("name", "name of the Backup")).
-		Returns(http.StatusOK, "OK", nil))
-	// Velero Restore
-	apiV1Ws.Route(apiV1Ws.GET("/restore").To(apiHandler.handleGetRestoreList).
-		// docs
-		Doc("returns a list of Velero Restores from all namespaces").
-		Writes(restore.RestoreList{}).
-		Returns(http.StatusOK, "OK", restore.RestoreList{}))
-	apiV1Ws.Route(apiV1Ws.GET("/restore/{namespace}").To(apiHandler.handleGetRestoreList).
-		// docs
-		Doc("returns a list of Velero Restores in a namespace").
-		Param(apiV1Ws.PathParameter("namespace", "namespace of the Restore")).
-		Writes(restore.RestoreList{}).
-		Returns(http.StatusOK, "OK", restore.RestoreList{}))
-	apiV1Ws.Route(apiV1Ws.GET("/restore/{namespace}/{name}").To(apiHandler.handleGetRestoreDetail).
-		// docs
-		Doc("returns detailed information about Velero Restore").
-		Param(apiV1Ws.PathParameter("namespace", "namespace of the Restore")).
-		Param(apiV1Ws.PathParameter("name", "name of the Restore")).
-		Writes(restore.RestoreDetail{}).
-		Returns(http.StatusOK, "OK", restore.RestoreDetail{}))
-	apiV1Ws.Route(apiV1Ws.POST("/restore/{namespace}").To(apiHandler.handleCreateRestore).
-		// docs
-		Doc("creates a new Velero Restore").
-		Param(apiV1Ws.PathParameter("namespace", "namespace for the Restore")).
-		Reads(restore.RestoreSpec{}).
-		Writes(restore.Restore{}).
-		Returns(http.StatusCreated, "Created", restore.Restore{}))
-	apiV1Ws.Route(apiV1Ws.DELETE("/restore/{namespace}/{name}").To(apiHandler.handleDeleteRestore).
-		// docs
-		Doc("deletes a Velero Restore").
-		Param(apiV1Ws.PathParameter("namespace", "namespace of the Restore")).
-		Param(apiV1Ws.PathParameter("name", "name of the Restore")).
-		Returns(http.StatusOK, "OK", nil))
-	// Velero Schedule
-	apiV1Ws.Route(apiV1Ws.GET("/schedule").To(apiHandler.handleGetScheduleList).
-		// docs
-		Doc("returns a list of Velero Schedules from all namespaces").
-		Writes(schedule.ScheduleList{}).
-		Returns(http.StatusOK, "OK", schedule.ScheduleList{}))
-	apiV1Ws.Route(apiV1Ws.GET("/schedule/{namespace}").To(apiHandler.handleGetScheduleList).
-		// docs
-		Doc("returns a list of Velero Schedules in a namespace").
-		Param(apiV1Ws.PathParameter("namespace", "namespace of the Schedule")).
-		Writes(schedule.ScheduleList{}).
-		Returns(http.StatusOK, "OK", schedule.ScheduleList{}))
-	apiV1Ws.Route(apiV1Ws.GET("/schedule/{namespace}/{name}").To(apiHandler.handleGetScheduleDetail).
-		// docs
-		Doc("returns detailed information about Velero Schedule").
-		Param(apiV1Ws.PathParameter("namespace", "namespace of the Schedule")).
-		Param(apiV1Ws.PathParameter("name", "name of the Schedule")).
-		Writes(schedule.ScheduleDetail{}).
-		Returns(http.StatusOK, "OK", schedule.ScheduleDetail{}))
-	apiV1Ws.Route(apiV1Ws.POST("/schedule/{namespace}").To(apiHandler.handleCreateSchedule).
-		// docs
-		Doc("creates a new Velero Schedule").
-		Param(apiV1Ws.PathParameter("namespace", "namespace for the Schedule")).
-		Reads(schedule.ScheduleSpec{}).
-		Writes(schedule.Schedule{}).
-		Returns(http.StatusCreated, "Created", schedule.Schedule{}))
-	apiV1Ws.Route(apiV1Ws.DELETE("/schedule/{namespace}/{name}").To(apiHandler.handleDeleteSchedule).
-		// docs
-		Doc("deletes a Velero Schedule").
-		Param(apiV1Ws.PathParameter("namespace", "namespace of the Schedule")).
-		Param(apiV1Ws.PathParameter("name", "name of the Schedule")).
-		Returns(http.StatusOK, "OK", nil))
+	// Velero
+	apiHandler.installVeleroRoutes(apiV1Ws)
 
 	// Ingress
 	apiV1Ws.Route(apiV1Ws.GET("/ingress").To(apiHandler.handleGetIngressList).
@@ -1377,177 +1280,6 @@ func CreateHTTPAPIHandler(iManager integration.Manager) (*restful.Container, err
 	return wsContainer, nil
 }
 
-func (in *APIHandler) handleGetBackupList(request *restful.Request, response *restful.Response) {
-	dataSelect := parser.ParseDataSelectPathParameter(request)
-	namespace := parseNamespacePathParameter(request)
-	result, err := backup.GetBackupList(request.Request, namespace, dataSelect)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
-}
-
-func (in *APIHandler) handleGetBackupDetail(request *restful.Request, response *restful.Response) {
-	namespace := parseNamespacePathParameter(request)
-	name := request.PathParameter("name")
-	result, err := backup.GetBackupDetail(request.Request, namespace, name)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
-}
-
-func (in *APIHandler) handleCreateBackup(request *restful.Request, response *restful.Response) {
-	namespace := parseNamespacePathParameter(request)
-
-	var spec backup.BackupSpec
-	if err := request.ReadEntity(&spec); err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-
-	// Set namespace from URL if not provided in spec
-	if spec.Namespace == "" {
-		spec.Namespace = namespace.ToRequestParam()
-	}
-
-	result, err := backup.CreateBackup(request.Request, &spec)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-	_ = response.WriteHeaderAndEntity(http.StatusCreated, result)
-}
-
-func (in *APIHandler) handleDeleteBackup(request *restful.Request, response *restful.Response) {
-	namespace := parseNamespacePathParameter(request)
-	name := request.PathParameter("name")
-
-	err := backup.DeleteBackup(request.Request, namespace.ToRequestParam(), name)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-
-	response.WriteHeader(http.StatusOK)
-}
-
-func (in *APIHandler) handleGetRestoreList(request *restful.Request, response *restful.Response) {
-	dataSelect := parser.ParseDataSelectPathParameter(request)
-	namespace := parseNamespacePathParameter(request)
-	result, err := restore.GetRestoreList(request.Request, namespace, dataSelect)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
-}
-
-func (in *APIHandler) handleGetRestoreDetail(request *restful.Request, response *restful.Response) {
-	namespace := parseNamespacePathParameter(request)
-	name := request.PathParameter("name")
-	result, err := restore.GetRestoreDetail(request.Request, namespace, name)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
-}
-
-func (in *APIHandler) handleCreateRestore(request *restful.Request, response *restful.Response) {
-	namespace := parseNamespacePathParameter(request)
-
-	var spec restore.RestoreSpec
-	if err := request.ReadEntity(&spec); err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-
-	// Set namespace from URL if not provided in spec
-	if spec.Namespace == "" {
-		spec.Namespace = namespace.ToRequestParam()
-	}
-
-	result, err := restore.CreateRestore(request.Request, &spec)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-	_ = response.WriteHeaderAndEntity(http.StatusCreated, result)
-}
-
-func (in *APIHandler) handleDeleteRestore(request *restful.Request, response *restful.Response) {
-	namespace := parseNamespacePathParameter(request)
-	name := request.PathParameter("name")
-
-	err := restore.DeleteRestore(request.Request, namespace.ToRequestParam(), name)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-
-	response.WriteHeader(http.StatusOK)
-}
-
-func (in *APIHandler) handleGetScheduleList(request *restful.Request, response *restful.Response) {
-	dataSelect := parser.ParseDataSelectPathParameter(request)
-	namespace := parseNamespacePathParameter(request)
-	result, err := schedule.GetScheduleList(request.Request, namespace, dataSelect)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
-}
-
-func (in *APIHandler) handleGetScheduleDetail(request *restful.Request, response *restful.Response) {
-	namespace := parseNamespacePathParameter(request)
-	name := request.PathParameter("name")
-	result, err := schedule.GetScheduleDetail(request.Request, namespace, name)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
-}
-
-func (in *APIHandler) handleCreateSchedule(request *restful.Request, response *restful.Response) {
-	namespace := parseNamespacePathParameter(request)
-
-	var spec schedule.ScheduleSpec
-	if err := request.ReadEntity(&spec); err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-
-	// Set namespace from URL if not provided in spec
-	if spec.Namespace == "" {
-		spec.Namespace = namespace.ToRequestParam()
-	}
-
-	result, err := schedule.CreateSchedule(request.Request, &spec)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-	_ = response.WriteHeaderAndEntity(http.StatusCreated, result)
-}
-
-func (in *APIHandler) handleDeleteSchedule(request *restful.Request, response *restful.Response) {
-	namespace := parseNamespacePathParameter(request)
-	name := request.PathParameter("name")
-
-	err := schedule.DeleteSchedule(request.Request, namespace.ToRequestParam(), name)
-	if err != nil {
-		errors.HandleInternalError(response, err)
-		return
-	}
-
-	response.WriteHeader(http.StatusOK)
-}
-
 func (in *APIHandler) handleGetClusterRoleList(request *restful.Request, response *restful.Response) {
 	k8sClient, err := client.Client(request.Request)
 	if err != nil {
@@ -3714,3 +3446,18 @@ func parseNamespacePathParameter(request *restful.Request) *common.NamespaceQuer
 	}
 	return common.NewNamespaceQuery(nonEmptyNamespaces)
 }
+
+// parseDryRunQueryParameter tells whether the request asks to only validate changes without persisting them.
+func parseDryRunQueryParameter(request *restful.Request) bool {
+	dryRun, err := strconv.ParseBool(request.QueryParameter("dryRun"))
+	return err == nil && dryRun
+}
+
+// createdStatus returns the status of a successful create request, which created nothing in dry run.
+func createdStatus(dryRun bool) int {
+	if dryRun {
+		return http.StatusOK
+	}
+
+	return http.StatusCreated
+}
@@ -112,6 +112,7 @@ func metricsFilter(req *restful.Request, resp *restful.Response,
 	chain *restful.FilterChain) {
 	resource := helpers.GetResourceFromPath(req.SelectedRoutePath())
 	httpClient := utilnet.GetHTTPClient(req.Request)
+	reqStart := time.Now()
 
 	chain.ProcessFilter(req, resp)
 
@@ -121,9 +122,11 @@ func metricsFilter(req *restful.Request, resp *restful.Response,
 			*resource, httpClient,
 			resp.Header().Get("Content-Type"),
 			resp.StatusCode(),
-			time.Now(),
+			reqStart,
 		)
 	}
+
+	monitorVelero(req.Request.Method, req.SelectedRoutePath(), resp.StatusCode(), reqStart)
 }
 
 // Post requests should set correct X-CSRF-TOKEN header, all other requests
@@ -16,6 +16,7 @@ package handler
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -47,13 +48,33 @@ var (
 		},
 		[]string{"verb", "resource"},
 	)
+	veleroRequestCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dashboard_velero_requests_total",
+			Help: "Counter of Velero API requests broken out for each operation and HTTP response code.",
+		},
+		[]string{"operation", "code"},
+	)
+	veleroRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dashboard_velero_request_duration_seconds",
+			Help:    "Response latency distribution in seconds for each Velero API operation.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"operation"},
+	)
 )
 
+// veleroRoutePrefix is the path prefix of all Velero routes, see installVeleroRoutes.
+const veleroRoutePrefix = "/api/v1/velero/"
+
 // Initialize all metrics in prometheus
 func init() {
 	prometheus.MustRegister(requestCounter)
 	prometheus.MustRegister(requestLatencies)
 	prometheus.MustRegister(requestLatenciesSummary)
+	prometheus.MustRegister(veleroRequestCounter)
+	prometheus.MustRegister(veleroRequestDuration)
 }
 
 // Track API call in prometheus
@@ -63,3 +84,24 @@ func monitor(verb, resource string, client, contentType string, httpCode int, re
 	requestLatencies.WithLabelValues(verb, resource).Observe(elapsed)
 	requestLatenciesSummary.WithLabelValues(verb, resource).Observe(elapsed)
 }
+
+// Track Velero API call in prometheus, if the route is one of the Velero routes
+func monitorVelero(method, routePath string, httpCode int, reqStart time.Time) {
+	operation, ok := toVeleroOperation(method, routePath)
+	if !ok {
+		return
+	}
+
+	veleroRequestCounter.WithLabelValues(operation, strconv.Itoa(httpCode)).Inc()
+	veleroRequestDuration.WithLabelValues(operation).Observe(time.Since(reqStart).Seconds())
+}
+
+// toVeleroOperation names the operation of a Velero route by its method and path template, e.g.
+// "GET /velero/backup/{namespace}", so that requests for different objects count as the same operation.
+func toVeleroOperation(method, routePath string) (string, bool) {
+	if !strings.HasPrefix(routePath, veleroRoutePrefix) {
+		return "", false
+	}
+
+	return method + " " + strings.TrimPrefix(routePath, "/api/v1"), true
+}
@@ -0,0 +1,38 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import "testing"
+
+func TestToVeleroOperation(t *testing.T) {
+	cases := []struct {
+		method, routePath string
+		expected          string
+		expectedOk        bool
+	}{
+		{"GET", "/api/v1/velero/backup/{namespace}/{name}", "GET /velero/backup/{namespace}/{name}", true},
+		{"POST", "/api/v1/velero/restore", "POST /velero/restore", true},
+		{"GET", "/api/v1/pod/{namespace}", "", false},
+		{"GET", "/api/v1/veleroish", "", false},
+	}
+
+	for _, c := range cases {
+		actual, ok := toVeleroOperation(c.method, c.routePath)
+		if actual != c.expected || ok != c.expectedOk {
+			t.Errorf("toVeleroOperation(%q, %q) == %q, %t, expected %q, %t", c.method, c.routePath, actual, ok,
+				c.expected, c.expectedOk)
+		}
+	}
+}
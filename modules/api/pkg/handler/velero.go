@@ -0,0 +1,2198 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/emicklei/go-restful/v3"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/handler/parser"
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/deletebackuprequest"
+	"k8s.io/dashboard/api/pkg/resource/resourcepolicy"
+	"k8s.io/dashboard/api/pkg/resource/restore"
+	"k8s.io/dashboard/api/pkg/resource/schedule"
+	"k8s.io/dashboard/api/pkg/resource/snapshotclass"
+	"k8s.io/dashboard/api/pkg/resource/snapshotlocation"
+	"k8s.io/dashboard/api/pkg/resource/storagelocation"
+	"k8s.io/dashboard/api/pkg/resource/subscription"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
+	"k8s.io/dashboard/api/pkg/resource/velero/bootstrap"
+	"k8s.io/dashboard/api/pkg/resource/velero/maintenance"
+	"k8s.io/dashboard/api/pkg/resource/velero/manifest"
+	"k8s.io/dashboard/api/pkg/resource/velero/overview"
+	"k8s.io/dashboard/api/pkg/resource/velero/protection"
+	"k8s.io/dashboard/errors"
+	dashboardtypes "k8s.io/dashboard/types"
+)
+
+// installVeleroRoutes registers the Velero routes under /velero. Resources are listed at /velero/{resource} and
+// /velero/{resource}/{namespace}, returned at /velero/{resource}/{namespace}/{name} and changed by actions below it,
+// e.g. /velero/backup/{namespace}/{name}/ttl. Endpoints spanning namespaces are kept below /velero/{resource}/-, as "-"
+// is no valid namespace name, so that they do not hide namespaces of the same name. Mutations accept the dryRun query
+// parameter.
+func (in *APIHandler) installVeleroRoutes(ws *restful.WebService) {
+	// Velero Backup
+	ws.Route(ws.GET("/velero/backup").To(in.handleGetBackupList).
+		// docs
+		Doc("returns a list of Velero Backups from all namespaces").
+		Param(ws.QueryParameter("enriched", "list Backups with their phase, times and sizes rather than only their metadata (default: true, unless the dashboard lists only metadata)")).
+		Writes(backup.BackupList{}).
+		Returns(http.StatusOK, "OK", backup.BackupList{}))
+	ws.Route(ws.GET("/velero/backup/{namespace}").To(in.handleGetBackupList).
+		// docs
+		Doc("returns a list of Velero Backups in a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.QueryParameter("enriched", "list Backups with their phase, times and sizes rather than only their metadata (default: true, unless the dashboard lists only metadata)")).
+		Writes(backup.BackupList{}).
+		Returns(http.StatusOK, "OK", backup.BackupList{}))
+	ws.Route(ws.GET("/velero/backup/-/watch").To(in.handleWatchBackupList).
+		// Compressed responses cannot be upgraded to a WebSocket.
+		ContentEncodingEnabled(false).
+		// docs
+		Doc("streams Velero Backups from all namespaces being added, modified or deleted over a WebSocket").
+		Writes(subscription.Event{}).
+		Returns(http.StatusSwitchingProtocols, "Switching Protocols", subscription.Event{}))
+	ws.Route(ws.GET("/velero/backup/-/watch/{namespace}").To(in.handleWatchBackupList).
+		// Compressed responses cannot be upgraded to a WebSocket.
+		ContentEncodingEnabled(false).
+		// docs
+		Doc("streams Velero Backups in a namespace being added, modified or deleted over a WebSocket").
+		Param(ws.PathParameter("namespace", "namespace of the Backups")).
+		Writes(subscription.Event{}).
+		Returns(http.StatusSwitchingProtocols, "Switching Protocols", subscription.Event{}))
+	ws.Route(ws.GET("/velero/backup/-/grouped").To(in.handleGetGroupedBackupList).
+		// docs
+		Doc("returns Velero Schedules from all namespaces with their most recent Backups and the Backups of no Schedule").
+		Param(ws.QueryParameter("limit", "number of Backups returned for each Schedule (default: 5)")).
+		Writes(schedule.GroupedBackupList{}).
+		Returns(http.StatusOK, "OK", schedule.GroupedBackupList{}))
+	ws.Route(ws.GET("/velero/backup/-/grouped/{namespace}").To(in.handleGetGroupedBackupList).
+		// docs
+		Doc("returns Velero Schedules in a namespace with their most recent Backups and the Backups of no Schedule").
+		Param(ws.PathParameter("namespace", "namespace of the Schedules and Backups")).
+		Param(ws.QueryParameter("limit", "number of Backups returned for each Schedule (default: 5)")).
+		Writes(schedule.GroupedBackupList{}).
+		Returns(http.StatusOK, "OK", schedule.GroupedBackupList{}))
+	ws.Route(ws.GET("/velero/backup/-/heatmap").To(in.handleGetBackupHeatmap).
+		// docs
+		Doc("returns Velero Backup activity from all namespaces bucketed by hour of day and day of week").
+		Param(ws.QueryParameter("period", "period to cover, e.g. '72h' or '30d' (default: 30d)")).
+		Param(ws.QueryParameter("timeZone", "IANA time zone used for bucketing (default: UTC)")).
+		Writes(backup.BackupHeatmap{}).
+		Returns(http.StatusOK, "OK", backup.BackupHeatmap{}))
+	ws.Route(ws.GET("/velero/backup/-/heatmap/{namespace}").To(in.handleGetBackupHeatmap).
+		// docs
+		Doc("returns Velero Backup activity in a namespace bucketed by hour of day and day of week").
+		Param(ws.PathParameter("namespace", "namespace of the Backups")).
+		Param(ws.QueryParameter("period", "period to cover, e.g. '72h' or '30d' (default: 30d)")).
+		Param(ws.QueryParameter("timeZone", "IANA time zone used for bucketing (default: UTC)")).
+		Writes(backup.BackupHeatmap{}).
+		Returns(http.StatusOK, "OK", backup.BackupHeatmap{}))
+	ws.Route(ws.GET("/velero/backup/-/usage").To(in.handleGetBackupUsageReport).
+		// docs
+		Doc("returns the size of Velero Backups from all namespaces grouped by the value of a label, e.g. per team").
+		Param(ws.QueryParameter("groupBy", "label key read from Backups or the namespaces they include, e.g. 'team'")).
+		Param(ws.QueryParameter("pricePerGiB", "price of a GiB of stored volume data to compute costs (default: none)")).
+		Writes(backup.BackupUsageReport{}).
+		Returns(http.StatusOK, "OK", backup.BackupUsageReport{}))
+	ws.Route(ws.GET("/velero/backup/-/usage/{namespace}").To(in.handleGetBackupUsageReport).
+		// docs
+		Doc("returns the size of Velero Backups in a namespace grouped by the value of a label, e.g. per team").
+		Param(ws.PathParameter("namespace", "namespace of the Backups")).
+		Param(ws.QueryParameter("groupBy", "label key read from Backups or the namespaces they include, e.g. 'team'")).
+		Param(ws.QueryParameter("pricePerGiB", "price of a GiB of stored volume data to compute costs (default: none)")).
+		Writes(backup.BackupUsageReport{}).
+		Returns(http.StatusOK, "OK", backup.BackupUsageReport{}))
+	ws.Route(ws.GET("/velero/backup/-/expiring").To(in.handleGetExpiringBackups).
+		// docs
+		Doc("returns Velero Backups from all namespaces expiring within a window, the ones expiring first at the top").
+		Param(ws.QueryParameter("within", "window to look ahead, e.g. '72h' or '7d' (default: 72h)")).
+		Writes(backup.ExpiringBackupList{}).
+		Returns(http.StatusOK, "OK", backup.ExpiringBackupList{}))
+	ws.Route(ws.GET("/velero/backup/-/expiring/{namespace}").To(in.handleGetExpiringBackups).
+		// docs
+		Doc("returns Velero Backups in a namespace expiring within a window, the ones expiring first at the top").
+		Param(ws.PathParameter("namespace", "namespace of the Backups")).
+		Param(ws.QueryParameter("within", "window to look ahead, e.g. '72h' or '7d' (default: 72h)")).
+		Writes(backup.ExpiringBackupList{}).
+		Returns(http.StatusOK, "OK", backup.ExpiringBackupList{}))
+	ws.Route(ws.GET("/velero/backup/-/orphaned").To(in.handleGetOrphanedBackups).
+		// docs
+		Doc("returns Velero Backups from all namespaces whose schedule was deleted or whose included namespaces are gone").
+		Writes(backup.OrphanedBackupList{}).
+		Returns(http.StatusOK, "OK", backup.OrphanedBackupList{}))
+	ws.Route(ws.GET("/velero/backup/-/orphaned/{namespace}").To(in.handleGetOrphanedBackups).
+		// docs
+		Doc("returns Velero Backups in a namespace whose schedule was deleted or whose included namespaces are gone").
+		Param(ws.PathParameter("namespace", "namespace of the Backups")).
+		Writes(backup.OrphanedBackupList{}).
+		Returns(http.StatusOK, "OK", backup.OrphanedBackupList{}))
+	ws.Route(ws.GET("/velero/backup/-/export").To(in.handleExportBackups).
+		Produces("text/csv", "application/x-ndjson").
+		// docs
+		Doc("exports the Velero Backup inventory from all namespaces as CSV or NDJSON").
+		Param(ws.QueryParameter("format", "format of the export, csv or ndjson (default: csv)")).
+		Returns(http.StatusOK, "OK", nil))
+	ws.Route(ws.GET("/velero/backup/-/export/{namespace}").To(in.handleExportBackups).
+		Produces("text/csv", "application/x-ndjson").
+		// docs
+		Doc("exports the Velero Backup inventory of a namespace as CSV or NDJSON").
+		Param(ws.PathParameter("namespace", "namespace of the Backups")).
+		Param(ws.QueryParameter("format", "format of the export, csv or ndjson (default: csv)")).
+		Returns(http.StatusOK, "OK", nil))
+	ws.Route(ws.GET("/velero/backup/-/dependencies/{namespace}").To(in.handleGetBackupDependencyAnalysis).
+		// docs
+		Doc("returns Secrets, ConfigMaps and ServiceAccounts referenced by workloads in a backup selection but not included in it").
+		Param(ws.PathParameter("namespace", "namespace of the backup selection")).
+		Param(ws.QueryParameter("labelSelector", "label selector of the backup selection, e.g. 'app=shop'")).
+		Writes(backup.DependencyAnalysis{}).
+		Returns(http.StatusOK, "OK", backup.DependencyAnalysis{}))
+	ws.Route(ws.GET("/velero/backup/{namespace}/{name}").To(in.handleGetBackupDetail).
+		// docs
+		Doc("returns detailed information about Velero Backup").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Writes(backup.BackupDetail{}).
+		Returns(http.StatusOK, "OK", backup.BackupDetail{}))
+	ws.Route(ws.GET("/velero/backup/{namespace}/{name}/spec").To(in.handleGetBackupSpec).
+		// docs
+		Doc("returns the spec Velero Backup was created with, to create an edited copy of it").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Writes(backup.BackupSpec{}).
+		Returns(http.StatusOK, "OK", backup.BackupSpec{}))
+	ws.Route(ws.GET("/velero/backup/{namespace}/{name}/diff").To(in.handleDiffBackups).
+		// docs
+		Doc("compares the resources stored in Velero Backup with the ones of an earlier Backup").
+		Param(ws.PathParameter("namespace", "namespace of the Backups")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Param(ws.QueryParameter("base", "name of the earlier Backup to compare with")).
+		Writes(backup.BackupDiff{}).
+		Returns(http.StatusOK, "OK", backup.BackupDiff{}))
+	ws.Route(ws.GET("/velero/backup/{namespace}/{name}/itemoperations").To(in.handleGetBackupItemOperations).
+		// docs
+		Doc("returns asynchronous item operations plugins ran for Velero Backup, downloaded from object storage").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Writes(velero.ItemOperationList{}).
+		Returns(http.StatusOK, "OK", velero.ItemOperationList{}))
+	ws.Route(ws.GET("/velero/backup/{namespace}/{name}/event").To(in.handleGetBackupEvents).
+		// docs
+		Doc("returns events of Velero Backup, e.g. validation failures and controller errors").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Writes(common.EventList{}).
+		Returns(http.StatusOK, "OK", common.EventList{}))
+	ws.Route(ws.GET("/velero/backup/{namespace}/{name}/log").To(in.handleGetBackupLogs).
+		// docs
+		Doc("returns the logs Velero wrote while running Velero Backup").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Produces("text/plain").
+		Writes([]byte{}).
+		Returns(http.StatusOK, "OK", []byte{}))
+	ws.Route(ws.GET("/velero/backup/{namespace}/{name}/restores").To(in.handleGetBackupRestores).
+		// docs
+		Doc("returns a list of Velero Restores created from the Backup").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Writes(restore.RestoreList{}).
+		Returns(http.StatusOK, "OK", restore.RestoreList{}))
+	ws.Route(ws.GET("/velero/backup/{namespace}/{name}/progress").To(in.handleStreamBackupProgress).
+		// Compressed responses are buffered, which would hold events back.
+		ContentEncodingEnabled(false).
+		Produces("text/event-stream").
+		// docs
+		Doc("streams progress of a Velero Backup as server-sent events until the Backup finishes").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Writes(backup.BackupProgressEvent{}).
+		Returns(http.StatusOK, "OK", backup.BackupProgressEvent{}))
+	ws.Route(ws.POST("/velero/backup/{namespace}").To(in.handleCreateBackup).
+		// docs
+		Doc("creates a new Velero Backup").
+		Param(ws.PathParameter("namespace", "namespace for the Backup")).
+		Param(ws.QueryParameter("dryRun", "only validate the Backup without creating it (default: false)")).
+		Reads(backup.BackupSpec{}).
+		Writes(backup.Backup{}).
+		Returns(http.StatusCreated, "Created", backup.Backup{}))
+	ws.Route(ws.POST("/velero/backup").To(in.handleCreateBackup).
+		// docs
+		Doc("creates a new Velero Backup in the Velero namespace, unless the spec sets one").
+		Param(ws.QueryParameter("dryRun", "only validate the Backup without creating it (default: false)")).
+		Reads(backup.BackupSpec{}).
+		Writes(backup.Backup{}).
+		Returns(http.StatusCreated, "Created", backup.Backup{}))
+	ws.Route(ws.POST("/velero/backup/{namespace}/preflight").To(in.handleGetBackupPreflight).
+		// docs
+		Doc("checks a Velero Backup spec against the cluster and returns issues per field, without creating the Backup").
+		Param(ws.PathParameter("namespace", "namespace for the Backup")).
+		Reads(backup.BackupSpec{}).
+		Writes(backup.BackupPreflight{}).
+		Returns(http.StatusOK, "OK", backup.BackupPreflight{}))
+	ws.Route(ws.POST("/velero/backup/{namespace}/workload").To(in.handleCreateWorkloadBackup).
+		// docs
+		Doc("creates a Velero Backup of a Deployment or StatefulSet and the ConfigMaps, Secrets and PVCs it uses").
+		Param(ws.PathParameter("namespace", "namespace for the Backup")).
+		Param(ws.QueryParameter("dryRun", "only validate the labels and the Backup without persisting them (default: false)")).
+		Reads(backup.WorkloadBackupSpec{}).
+		Writes(backup.Backup{}).
+		Returns(http.StatusCreated, "Created", backup.Backup{}))
+	ws.Route(ws.POST("/velero/backup/{namespace}/workload/plan").To(in.handleGetWorkloadBackupPlan).
+		// docs
+		Doc("returns the Velero Backup spec that would be created for a Deployment or StatefulSet").
+		Param(ws.PathParameter("namespace", "namespace for the Backup")).
+		Reads(backup.WorkloadBackupSpec{}).
+		Writes(backup.WorkloadBackupPlan{}).
+		Returns(http.StatusOK, "OK", backup.WorkloadBackupPlan{}))
+	ws.Route(ws.POST("/velero/backup/{namespace}/bulkdelete").To(in.handleDeleteBackups).
+		// docs
+		Doc("creates a Velero DeleteBackupRequest for each Backup matching a label selector, phases and age").
+		Param(ws.PathParameter("namespace", "namespace of the Backups")).
+		Param(ws.QueryParameter("dryRun", "only validate the DeleteBackupRequests without creating them (default: false)")).
+		Reads(backup.BulkDeleteSpec{}).
+		Writes(backup.BulkDeleteResult{}).
+		Returns(http.StatusOK, "OK", backup.BulkDeleteResult{}))
+	ws.Route(ws.POST("/velero/backup/-/bulkdelete").To(in.handleDeleteBackups).
+		// docs
+		Doc("creates a Velero DeleteBackupRequest for each Backup in the Velero namespace matching a label selector, phases and age").
+		Param(ws.QueryParameter("dryRun", "only validate the DeleteBackupRequests without creating them (default: false)")).
+		Reads(backup.BulkDeleteSpec{}).
+		Writes(backup.BulkDeleteResult{}).
+		Returns(http.StatusOK, "OK", backup.BulkDeleteResult{}))
+	ws.Route(ws.POST("/velero/backup/{namespace}/{name}/retry").To(in.handleRetryBackup).
+		// docs
+		Doc("creates a new Velero Backup with the spec of a failed one, annotated with the name of the failed Backup").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the failed Backup")).
+		Param(ws.QueryParameter("dryRun", "only validate the new Backup without creating it (default: false)")).
+		Writes(backup.Backup{}).
+		Returns(http.StatusCreated, "Created", backup.Backup{}))
+	ws.Route(ws.PUT("/velero/backup/{namespace}/{name}/ttl").To(in.handleUpdateBackupTTL).
+		// docs
+		Doc("replaces the TTL of Velero Backup and moves its expiration accordingly").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Param(ws.QueryParameter("dryRun", "only validate the TTL without updating the Backup (default: false)")).
+		Reads(backup.BackupTTLSpec{}).
+		Writes(backup.Backup{}).
+		Returns(http.StatusOK, "OK", backup.Backup{}))
+	ws.Route(ws.PATCH("/velero/backup/{namespace}/{name}/metadata").To(in.handleUpdateBackupMetadata).
+		// docs
+		Doc("adds and removes labels and annotations of Velero Backup").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Param(ws.QueryParameter("dryRun", "only validate the changes without updating the Backup (default: false)")).
+		Reads(velero.MetadataSpec{}).
+		Writes(dashboardtypes.ObjectMeta{}).
+		Returns(http.StatusOK, "OK", dashboardtypes.ObjectMeta{}))
+	ws.Route(ws.DELETE("/velero/backup/{namespace}/{name}").To(in.handleDeleteBackup).
+		// docs
+		Doc("deletes a Velero Backup and returns the objects referencing it").
+		Param(ws.PathParameter("namespace", "namespace of the Backup")).
+		Param(ws.PathParameter("name", "name of the Backup")).
+		Param(ws.QueryParameter("dryRun", "only validate the deletion without deleting the Backup (default: false)")).
+		Writes(velero.DeletionImpact{}).
+		Returns(http.StatusOK, "OK", velero.DeletionImpact{}))
+	// Velero Restore
+	ws.Route(ws.GET("/velero/restore").To(in.handleGetRestoreList).
+		// docs
+		Doc("returns a list of Velero Restores from all namespaces").
+		Writes(restore.RestoreList{}).
+		Returns(http.StatusOK, "OK", restore.RestoreList{}))
+	ws.Route(ws.GET("/velero/restore/{namespace}").To(in.handleGetRestoreList).
+		// docs
+		Doc("returns a list of Velero Restores in a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the Restore")).
+		Writes(restore.RestoreList{}).
+		Returns(http.StatusOK, "OK", restore.RestoreList{}))
+	ws.Route(ws.GET("/velero/restore/-/watch").To(in.handleWatchRestoreList).
+		// Compressed responses cannot be upgraded to a WebSocket.
+		ContentEncodingEnabled(false).
+		// docs
+		Doc("streams Velero Restores from all namespaces being added, modified or deleted over a WebSocket").
+		Writes(subscription.Event{}).
+		Returns(http.StatusSwitchingProtocols, "Switching Protocols", subscription.Event{}))
+	ws.Route(ws.GET("/velero/restore/-/watch/{namespace}").To(in.handleWatchRestoreList).
+		// Compressed responses cannot be upgraded to a WebSocket.
+		ContentEncodingEnabled(false).
+		// docs
+		Doc("streams Velero Restores in a namespace being added, modified or deleted over a WebSocket").
+		Param(ws.PathParameter("namespace", "namespace of the Restores")).
+		Writes(subscription.Event{}).
+		Returns(http.StatusSwitchingProtocols, "Switching Protocols", subscription.Event{}))
+	ws.Route(ws.GET("/velero/restore/-/resourcemodifier/{namespace}").To(in.handleGetResourceModifierList).
+		// docs
+		Doc("returns ConfigMaps with resource modifier rules a Velero Restore in the namespace can reference").
+		Param(ws.PathParameter("namespace", "namespace Velero is installed in")).
+		Writes(restore.ResourceModifierList{}).
+		Returns(http.StatusOK, "OK", restore.ResourceModifierList{}))
+	ws.Route(ws.GET("/velero/restore/{namespace}/{name}").To(in.handleGetRestoreDetail).
+		// docs
+		Doc("returns detailed information about Velero Restore").
+		Param(ws.PathParameter("namespace", "namespace of the Restore")).
+		Param(ws.PathParameter("name", "name of the Restore")).
+		Writes(restore.RestoreDetail{}).
+		Returns(http.StatusOK, "OK", restore.RestoreDetail{}))
+	ws.Route(ws.GET("/velero/restore/{namespace}/{name}/itemoperations").To(in.handleGetRestoreItemOperations).
+		// docs
+		Doc("returns asynchronous item operations plugins ran for Velero Restore, downloaded from object storage").
+		Param(ws.PathParameter("namespace", "namespace of the Restore")).
+		Param(ws.PathParameter("name", "name of the Restore")).
+		Writes(velero.ItemOperationList{}).
+		Returns(http.StatusOK, "OK", velero.ItemOperationList{}))
+	ws.Route(ws.GET("/velero/restore/{namespace}/{name}/event").To(in.handleGetRestoreEvents).
+		// docs
+		Doc("returns events of Velero Restore, e.g. validation failures and controller errors").
+		Param(ws.PathParameter("namespace", "namespace of the Restore")).
+		Param(ws.PathParameter("name", "name of the Restore")).
+		Writes(common.EventList{}).
+		Returns(http.StatusOK, "OK", common.EventList{}))
+	ws.Route(ws.GET("/velero/restore/{namespace}/{name}/log").To(in.handleGetRestoreLogs).
+		// docs
+		Doc("returns the logs Velero wrote while running Velero Restore").
+		Param(ws.PathParameter("namespace", "namespace of the Restore")).
+		Param(ws.PathParameter("name", "name of the Restore")).
+		Produces("text/plain").
+		Writes([]byte{}).
+		Returns(http.StatusOK, "OK", []byte{}))
+	ws.Route(ws.POST("/velero/restore/{namespace}").To(in.handleCreateRestore).
+		// docs
+		Doc("creates a new Velero Restore").
+		Param(ws.PathParameter("namespace", "namespace for the Restore")).
+		Param(ws.QueryParameter("dryRun", "only validate the Restore without creating it (default: false)")).
+		Reads(restore.RestoreSpec{}).
+		Writes(restore.Restore{}).
+		Returns(http.StatusCreated, "Created", restore.Restore{}))
+	ws.Route(ws.POST("/velero/restore").To(in.handleCreateRestore).
+		// docs
+		Doc("creates a new Velero Restore in the Velero namespace, unless the spec sets one").
+		Param(ws.QueryParameter("dryRun", "only validate the Restore without creating it (default: false)")).
+		Reads(restore.RestoreSpec{}).
+		Writes(restore.Restore{}).
+		Returns(http.StatusCreated, "Created", restore.Restore{}))
+	ws.Route(ws.POST("/velero/restore/{namespace}/collisions").To(in.handleGetRestoreCollisionReport).
+		// docs
+		Doc("returns which target namespaces of a proposed Velero Restore already contain resources from the Backup").
+		Param(ws.PathParameter("namespace", "namespace of the Restore")).
+		Param(ws.QueryParameter("samples", "number of resources of each kind checked in a namespace (default: 20)")).
+		Reads(restore.RestoreSpec{}).
+		Writes(restore.CollisionReport{}).
+		Returns(http.StatusOK, "OK", restore.CollisionReport{}))
+	ws.Route(ws.POST("/velero/restore/{namespace}/preview").To(in.handlePreviewRestore).
+		// docs
+		Doc("returns the resources a proposed Velero Restore would bring back and which of them already exist, without creating it").
+		Param(ws.PathParameter("namespace", "namespace of the Restore")).
+		Reads(restore.RestoreSpec{}).
+		Writes(restore.RestorePreview{}).
+		Returns(http.StatusOK, "OK", restore.RestorePreview{}))
+	ws.Route(ws.PATCH("/velero/restore/{namespace}/{name}/metadata").To(in.handleUpdateRestoreMetadata).
+		// docs
+		Doc("adds and removes labels and annotations of Velero Restore").
+		Param(ws.PathParameter("namespace", "namespace of the Restore")).
+		Param(ws.PathParameter("name", "name of the Restore")).
+		Param(ws.QueryParameter("dryRun", "only validate the changes without updating the Restore (default: false)")).
+		Reads(velero.MetadataSpec{}).
+		Writes(dashboardtypes.ObjectMeta{}).
+		Returns(http.StatusOK, "OK", dashboardtypes.ObjectMeta{}))
+	ws.Route(ws.DELETE("/velero/restore/{namespace}/{name}").To(in.handleDeleteRestore).
+		// docs
+		Doc("deletes a Velero Restore and returns the objects referencing it").
+		Param(ws.PathParameter("namespace", "namespace of the Restore")).
+		Param(ws.PathParameter("name", "name of the Restore")).
+		Param(ws.QueryParameter("dryRun", "only validate the deletion without deleting the Restore (default: false)")).
+		Writes(velero.DeletionImpact{}).
+		Returns(http.StatusOK, "OK", velero.DeletionImpact{}))
+	// Velero Schedule
+	ws.Route(ws.GET("/velero/schedule").To(in.handleGetScheduleList).
+		// docs
+		Doc("returns a list of Velero Schedules from all namespaces").
+		Writes(schedule.ScheduleList{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleList{}))
+	ws.Route(ws.GET("/velero/schedule/{namespace}").To(in.handleGetScheduleList).
+		// docs
+		Doc("returns a list of Velero Schedules in a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Writes(schedule.ScheduleList{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleList{}))
+	ws.Route(ws.GET("/velero/schedule/-/cron").To(in.handleGetCronPreview).
+		// docs
+		Doc("validates a Velero Schedule cron expression, describes it and returns its next runs").
+		Param(ws.QueryParameter("schedule", "cron expression, e.g. '0 2 * * *' or '@daily'")).
+		Param(ws.QueryParameter("runs", "number of next runs to return (default: 5, max: 100)")).
+		Writes(schedule.CronPreview{}).
+		Returns(http.StatusOK, "OK", schedule.CronPreview{}))
+	ws.Route(ws.GET("/velero/schedule/-/window").To(in.handleGetWindowAdherenceReport).
+		// docs
+		Doc("returns Velero Schedules from all namespaces whose Backups started or finished outside the backup window").
+		Param(ws.QueryParameter("start", "time of day the backup window opens, e.g. '01:00'")).
+		Param(ws.QueryParameter("end", "time of day the backup window closes, e.g. '05:00'")).
+		Param(ws.QueryParameter("timeZone", "IANA time zone of the backup window (default: UTC)")).
+		Param(ws.QueryParameter("period", "period to cover, e.g. '72h' or '30d' (default: 30d)")).
+		Writes(schedule.WindowAdherenceReport{}).
+		Returns(http.StatusOK, "OK", schedule.WindowAdherenceReport{}))
+	ws.Route(ws.GET("/velero/schedule/-/window/{namespace}").To(in.handleGetWindowAdherenceReport).
+		// docs
+		Doc("returns Velero Schedules in a namespace whose Backups started or finished outside the backup window").
+		Param(ws.PathParameter("namespace", "namespace of the Schedules")).
+		Param(ws.QueryParameter("start", "time of day the backup window opens, e.g. '01:00'")).
+		Param(ws.QueryParameter("end", "time of day the backup window closes, e.g. '05:00'")).
+		Param(ws.QueryParameter("timeZone", "IANA time zone of the backup window (default: UTC)")).
+		Param(ws.QueryParameter("period", "period to cover, e.g. '72h' or '30d' (default: 30d)")).
+		Writes(schedule.WindowAdherenceReport{}).
+		Returns(http.StatusOK, "OK", schedule.WindowAdherenceReport{}))
+	ws.Route(ws.GET("/velero/schedule/-/conflicts").To(in.handleGetScheduleConflicts).
+		// docs
+		Doc("returns pairs of Velero Schedules from all namespaces with overlapping runs in the next week").
+		Param(ws.QueryParameter("tolerance", "how close runs have to be to overlap, e.g. '0s' or '30m' (default: 15m)")).
+		Writes(schedule.ScheduleConflictList{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleConflictList{}))
+	ws.Route(ws.GET("/velero/schedule/-/conflicts/{namespace}").To(in.handleGetScheduleConflicts).
+		// docs
+		Doc("returns pairs of Velero Schedules in a namespace with overlapping runs in the next week").
+		Param(ws.PathParameter("namespace", "namespace of the Schedules")).
+		Param(ws.QueryParameter("tolerance", "how close runs have to be to overlap, e.g. '0s' or '30m' (default: 15m)")).
+		Writes(schedule.ScheduleConflictList{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleConflictList{}))
+	ws.Route(ws.GET("/velero/schedule/-/stats").To(in.handleGetScheduleStats).
+		// docs
+		Doc("returns success rate, duration and failure streaks of Velero Schedules from all namespaces").
+		Param(ws.QueryParameter("period", "period to cover, e.g. '72h' or '30d' (default: 30d)")).
+		Writes(schedule.ScheduleStatsList{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleStatsList{}))
+	ws.Route(ws.GET("/velero/schedule/-/stats/{namespace}").To(in.handleGetScheduleStats).
+		// docs
+		Doc("returns success rate, duration and failure streaks of Velero Schedules in a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the Schedules")).
+		Param(ws.QueryParameter("period", "period to cover, e.g. '72h' or '30d' (default: 30d)")).
+		Writes(schedule.ScheduleStatsList{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleStatsList{}))
+	ws.Route(ws.GET("/velero/schedule/-/watch").To(in.handleWatchScheduleList).
+		// Compressed responses cannot be upgraded to a WebSocket.
+		ContentEncodingEnabled(false).
+		// docs
+		Doc("streams Velero Schedules from all namespaces being added, modified or deleted over a WebSocket").
+		Writes(subscription.Event{}).
+		Returns(http.StatusSwitchingProtocols, "Switching Protocols", subscription.Event{}))
+	ws.Route(ws.GET("/velero/schedule/-/watch/{namespace}").To(in.handleWatchScheduleList).
+		// Compressed responses cannot be upgraded to a WebSocket.
+		ContentEncodingEnabled(false).
+		// docs
+		Doc("streams Velero Schedules in a namespace being added, modified or deleted over a WebSocket").
+		Param(ws.PathParameter("namespace", "namespace of the Schedules")).
+		Writes(subscription.Event{}).
+		Returns(http.StatusSwitchingProtocols, "Switching Protocols", subscription.Event{}))
+	ws.Route(ws.GET("/velero/schedule/{namespace}/{name}").To(in.handleGetScheduleDetail).
+		// docs
+		Doc("returns detailed information about Velero Schedule").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Param(ws.PathParameter("name", "name of the Schedule")).
+		Writes(schedule.ScheduleDetail{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleDetail{}))
+	ws.Route(ws.GET("/velero/schedule/{namespace}/{name}/backups").To(in.handleGetScheduleBackups).
+		// docs
+		Doc("returns a list of Velero Backups created by the Schedule").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Param(ws.PathParameter("name", "name of the Schedule")).
+		Writes(backup.BackupList{}).
+		Returns(http.StatusOK, "OK", backup.BackupList{}))
+	ws.Route(ws.GET("/velero/schedule/{namespace}/{name}/spec").To(in.handleGetScheduleSpec).
+		// docs
+		Doc("returns the spec Velero Schedule was created with, to create an edited copy of it").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Param(ws.PathParameter("name", "name of the Schedule")).
+		Writes(schedule.ScheduleSpec{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleSpec{}))
+	ws.Route(ws.POST("/velero/schedule/{namespace}/{name}/clone").To(in.handleCloneSchedule).
+		// docs
+		Doc("creates a copy of Velero Schedule under a new name").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Param(ws.PathParameter("name", "name of the Schedule")).
+		Param(ws.QueryParameter("dryRun", "only validate the copy without creating it (default: false)")).
+		Reads(schedule.CloneScheduleSpec{}).
+		Writes(schedule.Schedule{}).
+		Returns(http.StatusCreated, "Created", schedule.Schedule{}))
+	ws.Route(ws.GET("/velero/schedule/{namespace}/{name}/history").To(in.handleGetScheduleRunHistory).
+		// docs
+		Doc("returns the most recent runs of Velero Schedule with their outcome streaks").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Param(ws.PathParameter("name", "name of the Schedule")).
+		Param(ws.QueryParameter("limit", "number of runs returned (default: 20, max: 100)")).
+		Writes(schedule.ScheduleRunHistory{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleRunHistory{}))
+	ws.Route(ws.GET("/velero/schedule/{namespace}/{name}/slo").To(in.handleGetScheduleSLOReport).
+		// docs
+		Doc("returns SLO attainment and error budget burn of Velero Schedule over rolling windows").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Param(ws.PathParameter("name", "name of the Schedule")).
+		Param(ws.QueryParameter("windows", "comma delimited list of rolling windows, e.g. '7d,30d'")).
+		Writes(schedule.ScheduleSLOReport{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleSLOReport{}))
+	ws.Route(ws.PUT("/velero/schedule/{namespace}/{name}/slo").To(in.handleUpdateScheduleSLO).
+		// docs
+		Doc("attaches an availability SLO to Velero Schedule, zero target removes it").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Param(ws.PathParameter("name", "name of the Schedule")).
+		Param(ws.QueryParameter("dryRun", "only validate the SLO without attaching it (default: false)")).
+		Reads(schedule.ScheduleSLO{}).
+		Writes(schedule.ScheduleSLO{}).
+		Returns(http.StatusOK, "OK", schedule.ScheduleSLO{}))
+	ws.Route(ws.POST("/velero/schedule/{namespace}").To(in.handleCreateSchedule).
+		// docs
+		Doc("creates a new Velero Schedule").
+		Param(ws.PathParameter("namespace", "namespace for the Schedule")).
+		Param(ws.QueryParameter("dryRun", "only validate the Schedule without creating it (default: false)")).
+		Reads(schedule.ScheduleSpec{}).
+		Writes(schedule.Schedule{}).
+		Returns(http.StatusCreated, "Created", schedule.Schedule{}))
+	ws.Route(ws.POST("/velero/schedule").To(in.handleCreateSchedule).
+		// docs
+		Doc("creates a new Velero Schedule in the Velero namespace, unless the spec sets one").
+		Param(ws.QueryParameter("dryRun", "only validate the Schedule without creating it (default: false)")).
+		Reads(schedule.ScheduleSpec{}).
+		Writes(schedule.Schedule{}).
+		Returns(http.StatusCreated, "Created", schedule.Schedule{}))
+	ws.Route(ws.POST("/velero/schedule/{namespace}/bulk").To(in.handleCreateSchedules).
+		// docs
+		Doc("creates a Velero Schedule for each of the given namespaces from a template").
+		Param(ws.PathParameter("namespace", "namespace for the Schedules")).
+		Param(ws.QueryParameter("dryRun", "only validate the Schedules without creating them (default: false)")).
+		Reads(schedule.BulkScheduleSpec{}).
+		Writes(schedule.BulkScheduleResult{}).
+		Returns(http.StatusOK, "OK", schedule.BulkScheduleResult{}))
+	ws.Route(ws.PATCH("/velero/schedule/{namespace}/{name}/metadata").To(in.handleUpdateScheduleMetadata).
+		// docs
+		Doc("adds and removes labels and annotations of Velero Schedule").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Param(ws.PathParameter("name", "name of the Schedule")).
+		Param(ws.QueryParameter("dryRun", "only validate the changes without updating the Schedule (default: false)")).
+		Reads(velero.MetadataSpec{}).
+		Writes(dashboardtypes.ObjectMeta{}).
+		Returns(http.StatusOK, "OK", dashboardtypes.ObjectMeta{}))
+	ws.Route(ws.DELETE("/velero/schedule/{namespace}/{name}").To(in.handleDeleteSchedule).
+		// docs
+		Doc("deletes a Velero Schedule and returns the objects referencing it").
+		Param(ws.PathParameter("namespace", "namespace of the Schedule")).
+		Param(ws.PathParameter("name", "name of the Schedule")).
+		Param(ws.QueryParameter("dryRun", "only validate the deletion without deleting the Schedule (default: false)")).
+		Writes(velero.DeletionImpact{}).
+		Returns(http.StatusOK, "OK", velero.DeletionImpact{}))
+
+	// Velero DeleteBackupRequest
+	ws.Route(ws.GET("/velero/deletebackuprequest").To(in.handleGetDeleteBackupRequestList).
+		// docs
+		Doc("returns a list of Velero DeleteBackupRequests from all namespaces").
+		Writes(deletebackuprequest.DeleteBackupRequestList{}).
+		Returns(http.StatusOK, "OK", deletebackuprequest.DeleteBackupRequestList{}))
+	ws.Route(ws.GET("/velero/deletebackuprequest/{namespace}").To(in.handleGetDeleteBackupRequestList).
+		// docs
+		Doc("returns a list of Velero DeleteBackupRequests in a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the DeleteBackupRequests")).
+		Writes(deletebackuprequest.DeleteBackupRequestList{}).
+		Returns(http.StatusOK, "OK", deletebackuprequest.DeleteBackupRequestList{}))
+	ws.Route(ws.GET("/velero/deletebackuprequest/{namespace}/{name}").To(in.handleGetDeleteBackupRequestDetail).
+		// docs
+		Doc("returns Velero DeleteBackupRequest and whether its Backup is gone").
+		Param(ws.PathParameter("namespace", "namespace of the DeleteBackupRequest")).
+		Param(ws.PathParameter("name", "name of the DeleteBackupRequest")).
+		Writes(deletebackuprequest.DeleteBackupRequestDetail{}).
+		Returns(http.StatusOK, "OK", deletebackuprequest.DeleteBackupRequestDetail{}))
+
+	// Velero BackupStorageLocation
+	ws.Route(ws.GET("/velero/backupstoragelocation").To(in.handleGetStorageLocationList).
+		// docs
+		Doc("returns a list of Velero BackupStorageLocations from all namespaces").
+		Writes(storagelocation.StorageLocationList{}).
+		Returns(http.StatusOK, "OK", storagelocation.StorageLocationList{}))
+	ws.Route(ws.GET("/velero/backupstoragelocation/{namespace}").To(in.handleGetStorageLocationList).
+		// docs
+		Doc("returns a list of Velero BackupStorageLocations in a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the BackupStorageLocations")).
+		Writes(storagelocation.StorageLocationList{}).
+		Returns(http.StatusOK, "OK", storagelocation.StorageLocationList{}))
+	ws.Route(ws.GET("/velero/backupstoragelocation/{namespace}/{name}").To(in.handleGetStorageLocationDetail).
+		// docs
+		Doc("returns detailed information about Velero BackupStorageLocation").
+		Param(ws.PathParameter("namespace", "namespace of the BackupStorageLocation")).
+		Param(ws.PathParameter("name", "name of the BackupStorageLocation")).
+		Writes(storagelocation.StorageLocationDetail{}).
+		Returns(http.StatusOK, "OK", storagelocation.StorageLocationDetail{}))
+	ws.Route(ws.PUT("/velero/backupstoragelocation/{namespace}/{name}/credential").To(in.handleUpdateStorageLocationCredential).
+		// docs
+		Doc("stores credentials in a Secret and sets it as the credential of Velero BackupStorageLocation, never returning the credentials").
+		Param(ws.PathParameter("namespace", "namespace of the BackupStorageLocation")).
+		Param(ws.PathParameter("name", "name of the BackupStorageLocation")).
+		Param(ws.QueryParameter("dryRun", "only validate the Secret and BackupStorageLocation changes without applying them (default: false)")).
+		Reads(storagelocation.CredentialSpec{}).
+		Writes(storagelocation.Credential{}).
+		Returns(http.StatusOK, "OK", storagelocation.Credential{}))
+	ws.Route(ws.PUT("/velero/backupstoragelocation/{namespace}/{name}/accessmode").To(in.handleUpdateStorageLocationAccessMode).
+		// docs
+		Doc("switches Velero BackupStorageLocation between ReadWrite and ReadOnly access mode").
+		Param(ws.PathParameter("namespace", "namespace of the BackupStorageLocation")).
+		Param(ws.PathParameter("name", "name of the BackupStorageLocation")).
+		Param(ws.QueryParameter("dryRun", "only validate the access mode without updating the BackupStorageLocation (default: false)")).
+		Reads(storagelocation.AccessModeSpec{}).
+		Writes(storagelocation.StorageLocationAccessMode{}).
+		Returns(http.StatusOK, "OK", storagelocation.StorageLocationAccessMode{}))
+	ws.Route(ws.GET("/velero/defaultstoragelocation").To(in.handleGetDefaultStorageLocation).
+		// docs
+		Doc("returns the default Velero BackupStorageLocation in the Velero namespace, used by Backups created without one").
+		Writes(storagelocation.DefaultStorageLocation{}).
+		Returns(http.StatusOK, "OK", storagelocation.DefaultStorageLocation{}))
+	ws.Route(ws.GET("/velero/defaultstoragelocation/{namespace}").To(in.handleGetDefaultStorageLocation).
+		// docs
+		Doc("returns the default Velero BackupStorageLocation in a namespace, used by Backups created without one").
+		Param(ws.PathParameter("namespace", "namespace of the BackupStorageLocations")).
+		Writes(storagelocation.DefaultStorageLocation{}).
+		Returns(http.StatusOK, "OK", storagelocation.DefaultStorageLocation{}))
+	ws.Route(ws.PUT("/velero/backupstoragelocation/{namespace}/{name}/default").To(in.handleSetDefaultStorageLocation).
+		// docs
+		Doc("marks Velero BackupStorageLocation as the default one and unmarks all others in its namespace").
+		Param(ws.PathParameter("namespace", "namespace of the BackupStorageLocation")).
+		Param(ws.PathParameter("name", "name of the BackupStorageLocation")).
+		Param(ws.QueryParameter("dryRun", "only validate marking the BackupStorageLocation as default (default: false)")).
+		Writes(storagelocation.DefaultStorageLocation{}).
+		Returns(http.StatusOK, "OK", storagelocation.DefaultStorageLocation{}))
+
+	// Velero VolumeSnapshotLocation
+	ws.Route(ws.GET("/velero/volumesnapshotlocation").To(in.handleGetSnapshotLocationList).
+		// docs
+		Doc("returns a list of Velero VolumeSnapshotLocations from all namespaces").
+		Writes(snapshotlocation.SnapshotLocationList{}).
+		Returns(http.StatusOK, "OK", snapshotlocation.SnapshotLocationList{}))
+	ws.Route(ws.GET("/velero/volumesnapshotlocation/{namespace}").To(in.handleGetSnapshotLocationList).
+		// docs
+		Doc("returns a list of Velero VolumeSnapshotLocations in a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the VolumeSnapshotLocations")).
+		Writes(snapshotlocation.SnapshotLocationList{}).
+		Returns(http.StatusOK, "OK", snapshotlocation.SnapshotLocationList{}))
+	ws.Route(ws.GET("/velero/volumesnapshotlocation/{namespace}/{name}").To(in.handleGetSnapshotLocationDetail).
+		// docs
+		Doc("returns detailed information about Velero VolumeSnapshotLocation").
+		Param(ws.PathParameter("namespace", "namespace of the VolumeSnapshotLocation")).
+		Param(ws.PathParameter("name", "name of the VolumeSnapshotLocation")).
+		Writes(snapshotlocation.SnapshotLocationDetail{}).
+		Returns(http.StatusOK, "OK", snapshotlocation.SnapshotLocationDetail{}))
+
+	// CSI VolumeSnapshotClass
+	ws.Route(ws.GET("/velero/volumesnapshotclass").To(in.handleGetSnapshotClassList).
+		// docs
+		Doc("returns CSI VolumeSnapshotClasses and the class Velero uses for CSI snapshots of each driver").
+		Writes(snapshotclass.SnapshotClassList{}).
+		Returns(http.StatusOK, "OK", snapshotclass.SnapshotClassList{}))
+
+	// Velero resource policies
+	ws.Route(ws.GET("/velero/resourcepolicy/{namespace}").To(in.handleGetResourcePolicyList).
+		// docs
+		Doc("returns ConfigMaps with resource policies a Velero Backup or Schedule in the namespace can reference").
+		Param(ws.PathParameter("namespace", "namespace Velero is installed in")).
+		Writes(resourcepolicy.ResourcePolicyList{}).
+		Returns(http.StatusOK, "OK", resourcepolicy.ResourcePolicyList{}))
+	ws.Route(ws.GET("/velero/resourcepolicy/{namespace}/{name}").To(in.handleGetResourcePolicyDetail).
+		// docs
+		Doc("returns the volume policies of a resource policy ConfigMap").
+		Param(ws.PathParameter("namespace", "namespace of the ConfigMap")).
+		Param(ws.PathParameter("name", "name of the ConfigMap")).
+		Writes(resourcepolicy.ResourcePolicyDetail{}).
+		Returns(http.StatusOK, "OK", resourcepolicy.ResourcePolicyDetail{}))
+	ws.Route(ws.POST("/velero/resourcepolicy/{namespace}").To(in.handleCreateResourcePolicy).
+		// docs
+		Doc("creates a ConfigMap with resource policies").
+		Param(ws.PathParameter("namespace", "namespace for the ConfigMap")).
+		Param(ws.QueryParameter("dryRun", "only validate the resource policies without creating the ConfigMap (default: false)")).
+		Reads(resourcepolicy.ResourcePolicySpec{}).
+		Writes(resourcepolicy.ResourcePolicy{}).
+		Returns(http.StatusCreated, "Created", resourcepolicy.ResourcePolicy{}))
+	ws.Route(ws.POST("/velero/resourcepolicy").To(in.handleCreateResourcePolicy).
+		// docs
+		Doc("creates a ConfigMap with resource policies in the Velero namespace, unless the spec sets one").
+		Param(ws.QueryParameter("dryRun", "only validate the resource policies without creating the ConfigMap (default: false)")).
+		Reads(resourcepolicy.ResourcePolicySpec{}).
+		Writes(resourcepolicy.ResourcePolicy{}).
+		Returns(http.StatusCreated, "Created", resourcepolicy.ResourcePolicy{}))
+
+	// Velero Overview
+	ws.Route(ws.GET("/velero/status").To(in.handleGetVeleroStatus).
+		// docs
+		Doc("returns whether Velero is installed in the cluster, its served API versions and namespace").
+		Writes(velero.InstallStatus{}).
+		Returns(http.StatusOK, "OK", velero.InstallStatus{}))
+	ws.Route(ws.GET("/velero/namespace").To(in.handleGetVeleroNamespace).
+		// docs
+		Doc("returns the namespace Velero resources are created in when no namespace is given, and where it is taken from").
+		Writes(velero.Namespace{}).
+		Returns(http.StatusOK, "OK", velero.Namespace{}))
+	ws.Route(ws.GET("/velero/health").To(in.handleGetVeleroHealth).
+		// docs
+		Doc("returns the health of the Velero server Deployment and node-agent DaemonSet in the Velero namespace").
+		Writes(velero.ControllerHealth{}).
+		Returns(http.StatusOK, "OK", velero.ControllerHealth{}))
+	ws.Route(ws.GET("/velero/bootstrap").To(in.handleGetVeleroBootstrapStatus).
+		// docs
+		Doc("returns which parts of a Velero installation exist and whether the user may bootstrap the missing ones").
+		Writes(bootstrap.Status{}).
+		Returns(http.StatusOK, "OK", bootstrap.Status{}))
+	ws.Route(ws.POST("/velero/bootstrap").To(in.handleBootstrapVelero).
+		// docs
+		Doc("installs Velero with its CRDs, server Deployment, default BackupStorageLocation and credentials Secret, for cluster admins only").
+		Param(ws.QueryParameter("dryRun", "only validate the spec and return the objects that would be applied (default: false)")).
+		Reads(bootstrap.Spec{}).
+		Writes(bootstrap.Result{}).
+		Returns(http.StatusCreated, "Created", bootstrap.Result{}))
+	ws.Route(ws.POST("/velero/maintenance/cleanup").To(in.handleCleanupVeleroRequests).
+		// docs
+		Doc("removes processed DeleteBackupRequests and stale DownloadRequests from the Velero namespace").
+		Param(ws.QueryParameter("olderThan", "age of the requests removed, e.g. '24h' or '7d' (default: 24h)")).
+		Param(ws.QueryParameter("dryRun", "only validate the deletions without removing the requests (default: false)")).
+		Writes(maintenance.CleanupResult{}).
+		Returns(http.StatusOK, "OK", maintenance.CleanupResult{}))
+	ws.Route(ws.POST("/velero/maintenance/cleanup/{namespace}").To(in.handleCleanupVeleroRequests).
+		// docs
+		Doc("removes processed DeleteBackupRequests and stale DownloadRequests from a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the requests")).
+		Param(ws.QueryParameter("olderThan", "age of the requests removed, e.g. '24h' or '7d' (default: 24h)")).
+		Param(ws.QueryParameter("dryRun", "only validate the deletions without removing the requests (default: false)")).
+		Writes(maintenance.CleanupResult{}).
+		Returns(http.StatusOK, "OK", maintenance.CleanupResult{}))
+	ws.Route(ws.GET("/velero/overview").To(in.handleGetVeleroOverview).
+		// docs
+		Doc("returns the summary of Velero Backups, Restores, Schedules and storage locations from all namespaces").
+		Param(ws.QueryParameter("expiring", "period within which expiring Backups are listed, e.g. 72h or 7d (default: 7d)")).
+		Writes(overview.Overview{}).
+		Returns(http.StatusOK, "OK", overview.Overview{}))
+	ws.Route(ws.GET("/velero/overview/{namespace}").To(in.handleGetVeleroOverview).
+		// docs
+		Doc("returns the summary of Velero Backups, Restores, Schedules and storage locations in a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the Velero resources")).
+		Param(ws.QueryParameter("expiring", "period within which expiring Backups are listed, e.g. 72h or 7d (default: 7d)")).
+		Writes(overview.Overview{}).
+		Returns(http.StatusOK, "OK", overview.Overview{}))
+	ws.Route(ws.GET("/velero/namespacedeletion/{name}").To(in.handleGetNamespaceDeletionAdvice).
+		// docs
+		Doc("returns warnings about Velero Schedules and recent Backups that protect a namespace about to be deleted").
+		Param(ws.PathParameter("name", "name of the Namespace")).
+		Param(ws.QueryParameter("period", "how far back Backups count as recent, e.g. '72h' or '7d' (default: 7d)")).
+		Writes(protection.NamespaceDeletionAdvice{}).
+		Returns(http.StatusOK, "OK", protection.NamespaceDeletionAdvice{}))
+	ws.Route(ws.GET("/velero/rpo").To(in.handleGetRPOReport).
+		// docs
+		Doc("returns the Namespaces whose latest successful Velero Backup is older than the recovery point objective").
+		Param(ws.QueryParameter("threshold", "maximum age of the latest successful Backup, e.g. '24h' or '7d' (default: 24h)")).
+		Param(ws.QueryParameter("exclude", "comma separated Namespaces or glob patterns left out of the report")).
+		Writes(protection.RPOReport{}).
+		Returns(http.StatusOK, "OK", protection.RPOReport{}))
+	ws.Route(ws.GET("/velero/coverage").To(in.handleGetNamespaceCoverage).
+		// docs
+		Doc("returns the Velero Schedules and Backups including each Namespace and its latest successful Backup").
+		Writes(protection.NamespaceCoverageList{}).
+		Returns(http.StatusOK, "OK", protection.NamespaceCoverageList{}))
+	ws.Route(ws.GET("/velero/coverage/{namespace}").To(in.handleGetNamespaceCoverage).
+		// docs
+		Doc("returns the Velero Schedules and Backups including a Namespace and its latest successful Backup").
+		Param(ws.PathParameter("namespace", "name of the Namespace")).
+		Writes(protection.NamespaceCoverageList{}).
+		Returns(http.StatusOK, "OK", protection.NamespaceCoverageList{}))
+	ws.Route(ws.POST("/velero/import/{namespace}").To(in.handleImportVeleroManifests).
+		// docs
+		Doc("creates Velero Backups, Schedules, Restores and BackupStorageLocations from YAML or JSON manifests").
+		Param(ws.PathParameter("namespace", "namespace for the imported objects")).
+		Param(ws.QueryParameter("dryRun", "only validate the manifests without creating anything (default: false)")).
+		Reads(manifest.ImportSpec{}).
+		Writes(manifest.ImportResult{}).
+		Returns(http.StatusCreated, "Created", manifest.ImportResult{}))
+	ws.Route(ws.POST("/velero/import").To(in.handleImportVeleroManifests).
+		// docs
+		Doc("creates Velero Backups, Schedules, Restores and BackupStorageLocations from YAML or JSON manifests in the Velero namespace").
+		Param(ws.QueryParameter("dryRun", "only validate the manifests without creating anything (default: false)")).
+		Reads(manifest.ImportSpec{}).
+		Writes(manifest.ImportResult{}).
+		Returns(http.StatusCreated, "Created", manifest.ImportResult{}))
+	ws.Route(ws.GET("/velero/protection/{kind}/{namespace}/{name}").To(in.handleGetWorkloadProtection).
+		// docs
+		Doc("returns the Velero Schedules covering a workload and its latest Backup").
+		Param(ws.PathParameter("kind", "kind of the workload, deployment or statefulset")).
+		Param(ws.PathParameter("namespace", "namespace of the workload")).
+		Param(ws.PathParameter("name", "name of the workload")).
+		Writes(protection.WorkloadProtection{}).
+		Returns(http.StatusOK, "OK", protection.WorkloadProtection{}))
+	ws.Route(ws.GET("/velero/cani/{namespace}").To(in.handleGetVeleroAccessList).
+		// docs
+		Doc("returns which Velero Backups, Restores and Schedules the user can create, delete or patch in a namespace").
+		Param(ws.PathParameter("namespace", "namespace of the Velero resources")).
+		Writes(velero.AccessList{}).
+		Returns(http.StatusOK, "OK", velero.AccessList{}))
+	ws.Route(ws.GET("/velero/audit").To(in.handleGetVeleroAuditLog).
+		// docs
+		Doc("returns Velero Backups, Restores and Schedules created or deleted through the dashboard in all namespaces, and by whom").
+		Writes(audit.Log{}).
+		Returns(http.StatusOK, "OK", audit.Log{}))
+	ws.Route(ws.GET("/velero/audit/{namespace}").To(in.handleGetVeleroAuditLog).
+		// docs
+		Doc("returns Velero Backups, Restores and Schedules created or deleted through the dashboard in a namespace, and by whom").
+		Param(ws.PathParameter("namespace", "namespace of the Velero resources")).
+		Writes(audit.Log{}).
+		Returns(http.StatusOK, "OK", audit.Log{}))
+}
+
+func (in *APIHandler) handleGetBackupList(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	getBackupList := backup.GetBackupListWithSizes
+	if !parseEnrichedQueryParameter(request) {
+		getBackupList = backup.GetBackupMetadataList
+	}
+
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return getBackupList(request, namespace, dataSelect)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleWatchBackupList(request *restful.Request, response *restful.Response) {
+	handleListWatch(request, response, backup.SubscribeBackupList)
+}
+
+func (in *APIHandler) handleGetBackupDetail(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return backup.GetBackupDetail(request, namespace, name)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleGetBackupSpec(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	result, err := backup.GetBackupSpec(request.Request, namespace, name)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleDiffBackups(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	result, err := backup.DiffBackups(request.Request, namespace, request.QueryParameter("base"), name)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetBackupItemOperations(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	result, err := velero.GetItemOperations(request.Request, velero.DownloadTargetKindBackupItemOperations, namespace, name)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetBackupEvents(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+
+	result, err := backup.GetBackupEvents(request.Request, namespace, name, dataSelect)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetBackupLogs(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	result, err := velero.GetLogs(request.Request, velero.DownloadTargetKindBackupLog, namespace, name)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	handleDownload(response, io.NopCloser(bytes.NewReader(result)))
+}
+
+func (in *APIHandler) handleGetBackupRestores(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+	result, err := restore.GetBackupRestores(request.Request, namespace, name, dataSelect)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetGroupedBackupList(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	limit, err := strconv.Atoi(request.QueryParameter("limit"))
+	if err != nil || limit <= 0 {
+		limit = schedule.DefaultGroupedBackups
+	}
+
+	result, err := schedule.GetGroupedBackupList(request.Request, namespace, limit)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetBackupHeatmap(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	period := request.QueryParameter("period")
+	if period == "" {
+		period = backup.DefaultHeatmapPeriod
+	}
+	timeZone := request.QueryParameter("timeZone")
+	if timeZone == "" {
+		timeZone = time.UTC.String()
+	}
+
+	result, err := backup.GetBackupHeatmap(request.Request, namespace, period, timeZone)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetBackupUsageReport(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	pricePerGiB, err := parsePriceQueryParameter(request)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	result, err := backup.GetBackupUsageReport(request.Request, namespace, request.QueryParameter("groupBy"), pricePerGiB)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// parsePriceQueryParameter returns the price per GiB the request asks costs to be computed with, zero when it does not.
+func parsePriceQueryParameter(request *restful.Request) (float64, error) {
+	value := request.QueryParameter("pricePerGiB")
+	if value == "" {
+		return 0, nil
+	}
+
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || price < 0 || math.IsInf(price, 0) || math.IsNaN(price) {
+		return 0, errors.NewBadRequest(fmt.Sprintf("invalid pricePerGiB %q, expected a non-negative number", value))
+	}
+
+	return price, nil
+}
+
+func (in *APIHandler) handleGetExpiringBackups(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	within := request.QueryParameter("within")
+	if within == "" {
+		within = backup.DefaultExpiringWithin
+	}
+
+	result, err := backup.GetExpiringBackups(request.Request, namespace, within)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetOrphanedBackups(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	result, err := backup.GetOrphanedBackups(request.Request, namespace)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleExportBackups(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	format := backup.ExportFormat(request.QueryParameter("format"))
+	if format == "" {
+		format = backup.ExportFormatCSV
+	}
+
+	// The inventory is buffered, so that errors can still be reported with a matching status code.
+	var buffer bytes.Buffer
+	nonCriticalErrors, err := backup.ExportBackups(request.Request, namespace, dataSelect, format, &buffer)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	for _, nonCriticalError := range nonCriticalErrors {
+		response.AddHeader("Warning", fmt.Sprintf("299 - %q", nonCriticalError.Error()))
+	}
+	response.AddHeader(restful.HEADER_ContentType, format.ContentType())
+	response.AddHeader("Content-Disposition", fmt.Sprintf("attachment; filename=\"backups.%s\"", format))
+	response.WriteHeader(http.StatusOK)
+	_, _ = response.Write(buffer.Bytes())
+}
+
+func (in *APIHandler) handleGetBackupDependencyAnalysis(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	labelSelector := request.QueryParameter("labelSelector")
+
+	result, err := backup.GetDependencyAnalysis(request.Request, namespace, labelSelector)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleStreamBackupProgress(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	streaming := false
+
+	err := backup.WatchBackupProgress(request.Request.Context(), request.Request, namespace, name,
+		func(event *backup.BackupProgressEvent) error {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			// Headers are sent with the first event, so that errors before it get a regular error response.
+			if !streaming {
+				response.Header().Set("Content-Type", "text/event-stream")
+				response.Header().Set("Cache-Control", "no-cache")
+				response.Header().Set("X-Accel-Buffering", "no")
+				streaming = true
+			}
+			if _, err := fmt.Fprintf(response, "data: %s\n\n", data); err != nil {
+				return err
+			}
+			response.Flush()
+			return nil
+		})
+	if err != nil && !streaming {
+		handleVeleroError(request, response, err)
+		return
+	}
+	if err != nil {
+		klog.ErrorS(err, "Backup progress stream failed", "namespace", namespace, "name", name)
+	}
+}
+
+func (in *APIHandler) handleCreateBackup(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+
+	var spec backup.BackupSpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	// Set namespace from URL if not provided in spec
+	if spec.Namespace == "" {
+		spec.Namespace = namespace.ToRequestParam()
+	}
+
+	dryRun := parseDryRunQueryParameter(request)
+	result, err := backup.CreateBackup(request.Request, &spec, dryRun)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(createdStatus(dryRun), result)
+}
+
+func (in *APIHandler) handleGetBackupPreflight(request *restful.Request, response *restful.Response) {
+	var spec backup.BackupSpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	// Set namespace from URL if not provided in spec
+	if spec.Namespace == "" {
+		spec.Namespace = parseNamespacePathParameter(request).ToRequestParam()
+	}
+
+	result, err := backup.GetBackupPreflight(request.Request, &spec)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleCreateWorkloadBackup(request *restful.Request, response *restful.Response) {
+	spec, err := readWorkloadBackupSpec(request)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	dryRun := parseDryRunQueryParameter(request)
+	result, err := backup.CreateWorkloadBackup(request.Request, spec, dryRun)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(createdStatus(dryRun), result)
+}
+
+func (in *APIHandler) handleGetWorkloadBackupPlan(request *restful.Request, response *restful.Response) {
+	spec, err := readWorkloadBackupSpec(request)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	result, err := backup.GetWorkloadBackupPlan(request.Request, spec)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func readWorkloadBackupSpec(request *restful.Request) (*backup.WorkloadBackupSpec, error) {
+	spec := new(backup.WorkloadBackupSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		return nil, err
+	}
+
+	// Set namespace from URL if not provided in spec
+	if spec.Namespace == "" {
+		spec.Namespace = parseNamespacePathParameter(request).ToRequestParam()
+	}
+
+	return spec, nil
+}
+
+func (in *APIHandler) handleDeleteBackups(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+
+	var spec backup.BulkDeleteSpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	if spec.Namespace == "" {
+		spec.Namespace = namespace.ToRequestParam()
+	}
+
+	result, err := backup.DeleteBackups(request.Request, &spec, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleRetryBackup(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	dryRun := parseDryRunQueryParameter(request)
+	result, err := backup.RetryBackup(request.Request, namespace, name, dryRun)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(createdStatus(dryRun), result)
+}
+
+func (in *APIHandler) handleUpdateBackupTTL(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	spec := new(backup.BackupTTLSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	result, err := backup.UpdateBackupTTL(request.Request, namespace, name, spec, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleUpdateBackupMetadata(request *restful.Request, response *restful.Response) {
+	in.handleUpdateVeleroMetadata(request, response, velero.BackupGVR)
+}
+
+func (in *APIHandler) handleDeleteBackup(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+
+	result, err := backup.DeleteBackup(request.Request, namespace.ToRequestParam(), name, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetRestoreList(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return restore.GetRestoreList(request, namespace, dataSelect)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleWatchRestoreList(request *restful.Request, response *restful.Response) {
+	handleListWatch(request, response, restore.SubscribeRestoreList)
+}
+
+func (in *APIHandler) handleGetRestoreItemOperations(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	result, err := velero.GetItemOperations(request.Request, velero.DownloadTargetKindRestoreItemOperations, namespace, name)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetRestoreEvents(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+
+	result, err := restore.GetRestoreEvents(request.Request, namespace, name, dataSelect)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetRestoreLogs(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	result, err := velero.GetLogs(request.Request, velero.DownloadTargetKindRestoreLog, namespace, name)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	handleDownload(response, io.NopCloser(bytes.NewReader(result)))
+}
+
+func (in *APIHandler) handleGetRestoreDetail(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return restore.GetRestoreDetail(request, namespace, name)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleCreateRestore(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+
+	var spec restore.RestoreSpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	// Set namespace from URL if not provided in spec
+	if spec.Namespace == "" {
+		spec.Namespace = namespace.ToRequestParam()
+	}
+
+	dryRun := parseDryRunQueryParameter(request)
+	result, err := restore.CreateRestore(request.Request, &spec, dryRun)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(createdStatus(dryRun), result)
+}
+
+func (in *APIHandler) handleGetRestoreCollisionReport(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	samples, err := strconv.Atoi(request.QueryParameter("samples"))
+	if err != nil || samples <= 0 {
+		samples = restore.DefaultCollisionSamples
+	}
+
+	var spec restore.RestoreSpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	if spec.Namespace == "" {
+		spec.Namespace = namespace.ToRequestParam()
+	}
+
+	result, err := restore.GetCollisionReport(request.Request, &spec, samples)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handlePreviewRestore(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+
+	var spec restore.RestoreSpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	if spec.Namespace == "" {
+		spec.Namespace = namespace.ToRequestParam()
+	}
+
+	result, err := restore.PreviewRestore(request.Request, &spec)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetResourceModifierList(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	result, err := restore.GetResourceModifierList(request.Request, namespace)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleUpdateRestoreMetadata(request *restful.Request, response *restful.Response) {
+	in.handleUpdateVeleroMetadata(request, response, velero.RestoreGVR)
+}
+
+func (in *APIHandler) handleDeleteRestore(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+
+	result, err := restore.DeleteRestore(request.Request, namespace.ToRequestParam(), name, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetScheduleList(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.VeleroMetrics
+	namespace := parseNamespacePathParameter(request)
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return schedule.GetScheduleListWithMetrics(request, in.iManager.Metric().Client(), namespace, dataSelect)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleWatchScheduleList(request *restful.Request, response *restful.Response) {
+	handleListWatch(request, response, schedule.SubscribeScheduleList)
+}
+
+func (in *APIHandler) handleGetScheduleDetail(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return schedule.GetScheduleDetail(request, namespace, name)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleGetScheduleBackups(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+	result, err := schedule.GetScheduleBackups(request.Request, namespace, name, dataSelect)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetWindowAdherenceReport(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	window := schedule.BackupWindow{
+		Start:    request.QueryParameter("start"),
+		End:      request.QueryParameter("end"),
+		TimeZone: request.QueryParameter("timeZone"),
+	}
+	if window.TimeZone == "" {
+		window.TimeZone = time.UTC.String()
+	}
+	period := request.QueryParameter("period")
+	if period == "" {
+		period = schedule.DefaultWindowAdherencePeriod
+	}
+
+	result, err := schedule.GetWindowAdherenceReport(request.Request, namespace, window, period)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetScheduleConflicts(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	tolerance := request.QueryParameter("tolerance")
+	if tolerance == "" {
+		tolerance = schedule.DefaultConflictTolerance
+	}
+
+	result, err := schedule.GetScheduleConflicts(request.Request, namespace, tolerance)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetScheduleStats(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	period := request.QueryParameter("period")
+	if period == "" {
+		period = schedule.DefaultStatsPeriod
+	}
+
+	result, err := schedule.GetScheduleStats(request.Request, namespace, period)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetScheduleSpec(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	result, err := schedule.GetScheduleSpec(request.Request, namespace, name)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleCloneSchedule(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	clone := new(schedule.CloneScheduleSpec)
+	if err := request.ReadEntity(clone); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	dryRun := parseDryRunQueryParameter(request)
+	result, err := schedule.CloneSchedule(request.Request, namespace, name, clone, dryRun)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(createdStatus(dryRun), result)
+}
+
+func (in *APIHandler) handleGetScheduleRunHistory(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+	limit, err := strconv.Atoi(request.QueryParameter("limit"))
+	if err != nil || limit <= 0 {
+		limit = schedule.DefaultRunHistory
+	}
+	limit = min(limit, schedule.MaxRunHistory)
+
+	result, err := schedule.GetScheduleRunHistory(request.Request, namespace, name, limit)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetScheduleSLOReport(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+	windows := schedule.DefaultSLOWindows
+	if param := request.QueryParameter("windows"); param != "" {
+		windows = strings.Split(param, ",")
+	}
+
+	result, err := schedule.GetScheduleSLOReport(request.Request, namespace, name, windows)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleUpdateScheduleSLO(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	slo := new(schedule.ScheduleSLO)
+	if err := request.ReadEntity(slo); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	result, err := schedule.UpdateScheduleSLO(request.Request, namespace, name, slo, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetCronPreview(request *restful.Request, response *restful.Response) {
+	runs, err := strconv.Atoi(request.QueryParameter("runs"))
+	if err != nil || runs <= 0 {
+		runs = schedule.DefaultCronPreviewRuns
+	}
+	runs = min(runs, schedule.MaxCronPreviewRuns)
+
+	result, err := schedule.GetCronPreview(request.QueryParameter("schedule"), runs)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleCreateSchedule(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+
+	var spec schedule.ScheduleSpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	// Set namespace from URL if not provided in spec
+	if spec.Namespace == "" {
+		spec.Namespace = namespace.ToRequestParam()
+	}
+
+	dryRun := parseDryRunQueryParameter(request)
+	result, err := schedule.CreateSchedule(request.Request, &spec, dryRun)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(createdStatus(dryRun), result)
+}
+
+func (in *APIHandler) handleCreateSchedules(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+
+	var spec schedule.BulkScheduleSpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	if spec.Template.Namespace == "" {
+		spec.Template.Namespace = namespace.ToRequestParam()
+	}
+
+	result, err := schedule.CreateSchedules(request.Request, &spec, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleUpdateScheduleMetadata(request *restful.Request, response *restful.Response) {
+	in.handleUpdateVeleroMetadata(request, response, velero.ScheduleGVR)
+}
+
+// handleUpdateVeleroMetadata adds and removes labels and annotations of a namespaced Velero resource.
+func (in *APIHandler) handleUpdateVeleroMetadata(request *restful.Request, response *restful.Response, resource schema.GroupVersionResource) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	spec := new(velero.MetadataSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	result, err := velero.UpdateMetadata(request.Request, resource, namespace, name, spec, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleDeleteSchedule(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	name := request.PathParameter("name")
+
+	result, err := schedule.DeleteSchedule(request.Request, namespace.ToRequestParam(), name, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetVeleroOverview(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	expiring := request.QueryParameter("expiring")
+	if expiring == "" {
+		expiring = overview.DefaultExpiringPeriod
+	}
+
+	result, err := overview.GetOverview(request.Request, namespace, expiring)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetNamespaceDeletionAdvice(request *restful.Request, response *restful.Response) {
+	period := request.QueryParameter("period")
+	if period == "" {
+		period = protection.DefaultRecentPeriod
+	}
+
+	result, err := protection.GetNamespaceDeletionAdvice(request.Request, request.PathParameter("name"), period)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetNamespaceCoverage(request *restful.Request, response *restful.Response) {
+	result, err := protection.GetNamespaceCoverage(request.Request, request.PathParameter("namespace"))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetRPOReport(request *restful.Request, response *restful.Response) {
+	threshold := request.QueryParameter("threshold")
+	if threshold == "" {
+		threshold = protection.DefaultRPOThreshold
+	}
+
+	var excluded []string
+	if param := request.QueryParameter("exclude"); param != "" {
+		excluded = strings.Split(param, ",")
+	}
+
+	result, err := protection.GetRPOReport(request.Request, threshold, excluded)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleImportVeleroManifests(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+
+	var spec manifest.ImportSpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	if spec.Namespace == "" {
+		spec.Namespace = namespace.ToRequestParam()
+	}
+
+	dryRun := parseDryRunQueryParameter(request)
+	result, err := manifest.Import(request.Request, &spec, dryRun)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(createdStatus(dryRun), result)
+}
+
+func (in *APIHandler) handleGetWorkloadProtection(request *restful.Request, response *restful.Response) {
+	ref := backup.WorkloadReference{
+		Kind:      request.PathParameter("kind"),
+		Namespace: request.PathParameter("namespace"),
+		Name:      request.PathParameter("name"),
+	}
+
+	result, err := protection.GetWorkloadProtection(request.Request, ref)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetVeleroNamespace(request *restful.Request, response *restful.Response) {
+	_ = response.WriteHeaderAndEntity(http.StatusOK, velero.GetNamespace(request.Request))
+}
+
+func (in *APIHandler) handleGetVeleroAccessList(request *restful.Request, response *restful.Response) {
+	result, err := velero.GetAccessList(request.Request, request.PathParameter("namespace"))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetVeleroAuditLog(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	result, err := audit.GetLog(request.Request, namespace, dataSelect)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetVeleroStatus(request *restful.Request, response *restful.Response) {
+	result, err := velero.GetInstallStatus(request.Request)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetDeleteBackupRequestList(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return deletebackuprequest.GetDeleteBackupRequestList(request, namespace, dataSelect)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleGetDeleteBackupRequestDetail(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return deletebackuprequest.GetDeleteBackupRequestDetail(request, namespace, name)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleGetStorageLocationList(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return storagelocation.GetStorageLocationList(request, namespace, dataSelect)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleGetStorageLocationDetail(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return storagelocation.GetStorageLocationDetail(request, namespace, name)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleUpdateStorageLocationCredential(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	spec := new(storagelocation.CredentialSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	result, err := storagelocation.UpdateCredential(request.Request, namespace, name, spec, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleUpdateStorageLocationAccessMode(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	spec := new(storagelocation.AccessModeSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	result, err := storagelocation.UpdateAccessMode(request.Request, namespace, name, spec, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetDefaultStorageLocation(request *restful.Request, response *restful.Response) {
+	result, err := storagelocation.GetDefaultStorageLocation(request.Request, request.PathParameter("namespace"))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleSetDefaultStorageLocation(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	result, err := storagelocation.SetDefaultStorageLocation(request.Request, namespace, name, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetSnapshotLocationList(request *restful.Request, response *restful.Response) {
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return snapshotlocation.GetSnapshotLocationList(request, namespace, dataSelect)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleGetSnapshotLocationDetail(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		return snapshotlocation.GetSnapshotLocationDetail(request, namespace, name)
+	})
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	writeVeleroEntity(request, response, read)
+}
+
+func (in *APIHandler) handleGetSnapshotClassList(request *restful.Request, response *restful.Response) {
+	result, err := snapshotclass.GetSnapshotClassList(request.Request)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetResourcePolicyList(request *restful.Request, response *restful.Response) {
+	result, err := resourcepolicy.GetResourcePolicyList(request.Request, request.PathParameter("namespace"))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetResourcePolicyDetail(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := resourcepolicy.GetResourcePolicyDetail(request.Request, namespace, name)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleCreateResourcePolicy(request *restful.Request, response *restful.Response) {
+	var spec resourcepolicy.ResourcePolicySpec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	// Set namespace from URL if not provided in spec
+	if spec.Namespace == "" {
+		spec.Namespace = request.PathParameter("namespace")
+	}
+
+	dryRun := parseDryRunQueryParameter(request)
+	result, err := resourcepolicy.CreateResourcePolicy(request.Request, &spec, dryRun)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(createdStatus(dryRun), result)
+}
+
+func (in *APIHandler) handleGetVeleroHealth(request *restful.Request, response *restful.Response) {
+	result, err := velero.GetControllerHealth(request.Request)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleGetVeleroBootstrapStatus(request *restful.Request, response *restful.Response) {
+	result, err := bootstrap.GetStatus(request.Request)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (in *APIHandler) handleBootstrapVelero(request *restful.Request, response *restful.Response) {
+	var spec bootstrap.Spec
+	if err := request.ReadEntity(&spec); err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+
+	dryRun := parseDryRunQueryParameter(request)
+	result, err := bootstrap.Bootstrap(request.Request, &spec, dryRun)
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(createdStatus(dryRun), result)
+}
+
+func (in *APIHandler) handleCleanupVeleroRequests(request *restful.Request, response *restful.Response) {
+	spec := &maintenance.CleanupSpec{
+		Namespace: request.PathParameter("namespace"),
+		OlderThan: request.QueryParameter("olderThan"),
+	}
+
+	result, err := maintenance.CleanupRequests(request.Request, spec, parseDryRunQueryParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	_ = response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// handleVeleroError writes the error of a Velero operation to the response. Not found errors caused by Velero missing
+// from the cluster are reported as ErrVeleroNotInstalled. Errors caused by the request, e.g. conflicts and invalid
+// objects, are written as Kubernetes statuses with their code and field causes.
+func handleVeleroError(request *restful.Request, response *restful.Response, err error) {
+	if err = velero.CheckInstalled(request.Request, err); velero.IsNotInstalled(err) {
+		response.AddHeader("Content-Type", "text/plain")
+		_ = response.WriteError(http.StatusNotFound, err)
+		return
+	}
+	if status, ok := velero.ToRequestStatusError(err); ok {
+		_ = response.WriteHeaderAndEntity(int(status.ErrStatus.Code), status)
+		return
+	}
+	errors.HandleInternalError(response, err)
+}
+
+// parseEnrichedQueryParameter tells whether the request asks for Velero resources with their spec and status rather
+// than only their metadata. Without the parameter, the default set by the dashboard arguments applies.
+func parseEnrichedQueryParameter(request *restful.Request) bool {
+	enriched, err := strconv.ParseBool(request.QueryParameter("enriched"))
+	if err != nil {
+		return !args.VeleroListMetadataOnly()
+	}
+
+	return enriched
+}
+
+// veleroReads coalesces identical Velero list and detail reads made at the same time by the same user, so that a
+// burst of polling frontends costs the API server a single read.
+var veleroReads singleflight.Group
+
+// veleroRead is the result of a coalesced read, shared by all requests waiting for it.
+type veleroRead struct {
+	entity interface{}
+	etag   string
+}
+
+// readVeleroEntity reads an entity with get, coalesced with the identical read of the same user in flight, if any. The
+// versions of the Velero objects read are tracked for the ETag of the entity. The read is not cancelled with the
+// request that started it, as other requests may be waiting for it, so it is only bound by the Velero operation
+// timeout.
+func readVeleroEntity(request *restful.Request, get func(request *http.Request) (interface{}, error)) (*veleroRead, error) {
+	result, err, _ := veleroReads.Do(veleroReadKey(request.Request), func() (interface{}, error) {
+		shared, tracker := velero.WithVersionTracker(request.Request.WithContext(context.WithoutCancel(request.Request.Context())))
+		entity, err := get(shared)
+		if err != nil {
+			return nil, err
+		}
+
+		return &veleroRead{entity: entity, etag: tracker.ETag()}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*veleroRead), nil
+}
+
+// veleroReadKey identifies a read by the requested URL and the user, known by the token and impersonation headers.
+// Credentials are hashed so that they are not kept in the key.
+func veleroReadKey(request *http.Request) string {
+	names := make([]string, 0)
+	for name := range request.Header {
+		if name == "Authorization" || strings.HasPrefix(name, "Impersonate-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		_, _ = fmt.Fprintf(hash, "%s: %s\n", name, strings.Join(request.Header.Values(name), ", "))
+	}
+
+	return request.URL.RequestURI() + " " + hex.EncodeToString(hash.Sum(nil))
+}
+
+// writeVeleroEntity writes the entity read with its ETag, if it has one. When the client already has the entity, as
+// told by If-None-Match, only 304 Not Modified is written, which spares serializing the entity for frontends polling
+// it.
+func writeVeleroEntity(request *restful.Request, response *restful.Response, read *veleroRead) {
+	if read.etag == "" {
+		_ = response.WriteHeaderAndEntity(http.StatusOK, read.entity)
+		return
+	}
+
+	response.AddHeader("ETag", read.etag)
+	if matchesETag(request.HeaderParameter("If-None-Match"), read.etag) {
+		response.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	_ = response.WriteHeaderAndEntity(http.StatusOK, read.entity)
+}
+
+// matchesETag tells whether the If-None-Match header lists the entity tag, using the weak comparison.
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,212 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/emicklei/go-restful/v3"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+func TestMatchesETag(t *testing.T) {
+	cases := []struct {
+		ifNoneMatch string
+		expected    bool
+	}{
+		{"", false},
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`"xyz", W/"abc"`, true},
+		{`W/"xyz"`, false},
+		{"*", true},
+	}
+
+	for _, c := range cases {
+		if actual := matchesETag(c.ifNoneMatch, `W/"abc"`); actual != c.expected {
+			t.Errorf("matchesETag(%q) == %t, expected %t", c.ifNoneMatch, actual, c.expected)
+		}
+	}
+}
+
+func TestWriteVeleroEntity(t *testing.T) {
+	cases := []struct {
+		info           string
+		etag           string
+		ifNoneMatch    string
+		expectedStatus int
+	}{
+		{"writes the entity with its ETag", `W/"abc"`, "", http.StatusOK},
+		{"writes not modified for a matching ETag", `W/"abc"`, `W/"abc"`, http.StatusNotModified},
+		{"writes the entity for another ETag", `W/"abc"`, `W/"xyz"`, http.StatusOK},
+		{"writes the entity without ETag", "", `W/"abc"`, http.StatusOK},
+	}
+
+	for _, c := range cases {
+		request := restful.NewRequest(httptest.NewRequest(http.MethodGet, "/api/v1/velero/backup", nil))
+		if c.ifNoneMatch != "" {
+			request.Request.Header.Set("If-None-Match", c.ifNoneMatch)
+		}
+
+		recorder := httptest.NewRecorder()
+		response := restful.NewResponse(recorder)
+		response.SetRequestAccepts(restful.MIME_JSON)
+		writeVeleroEntity(request, response, &veleroRead{entity: map[string]string{"name": "daily"}, etag: c.etag})
+
+		if recorder.Code != c.expectedStatus || recorder.Header().Get("ETag") != c.etag {
+			t.Errorf("%s: writeVeleroEntity() wrote %d with ETag %q, expected %d with ETag %q", c.info, recorder.Code,
+				recorder.Header().Get("ETag"), c.expectedStatus, c.etag)
+		}
+		if c.expectedStatus == http.StatusNotModified && recorder.Body.Len() > 0 {
+			t.Errorf("%s: writeVeleroEntity() wrote a body with not modified", c.info)
+		}
+	}
+}
+
+func TestReadVeleroEntity(t *testing.T) {
+	request := restful.NewRequest(httptest.NewRequest(http.MethodGet, "/api/v1/velero/backup/velero/daily", nil))
+	read, err := readVeleroEntity(request, func(request *http.Request) (interface{}, error) {
+		daily := unstructured.Unstructured{}
+		daily.SetName("daily")
+		daily.SetResourceVersion("5")
+		// Objects read through the Velero clients of the request are recorded the same way.
+		velero.Record(request, velero.BackupGVR, daily)
+		return "daily", nil
+	})
+	if err != nil {
+		t.Fatalf("readVeleroEntity() returned error: %s", err.Error())
+	}
+
+	if read.entity != "daily" || read.etag == "" {
+		t.Errorf("readVeleroEntity() == %#v, expected the entity with an ETag", read)
+	}
+}
+
+func TestVeleroReadKey(t *testing.T) {
+	newRequest := func(url, token, impersonatedUser string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, url, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		if impersonatedUser != "" {
+			request.Header.Set("Impersonate-User", impersonatedUser)
+		}
+		return request
+	}
+
+	key := veleroReadKey(newRequest("/api/v1/velero/backup?page=1", "alice", ""))
+	cases := []struct {
+		info     string
+		request  *http.Request
+		expected bool
+	}{
+		{"same user and URL", newRequest("/api/v1/velero/backup?page=1", "alice", ""), true},
+		{"another query", newRequest("/api/v1/velero/backup?page=2", "alice", ""), false},
+		{"another token", newRequest("/api/v1/velero/backup?page=1", "bob", ""), false},
+		{"impersonating another user", newRequest("/api/v1/velero/backup?page=1", "alice", "bob"), false},
+	}
+
+	for _, c := range cases {
+		if actual := veleroReadKey(c.request) == key; actual != c.expected {
+			t.Errorf("%s: veleroReadKey() matched %t, expected %t", c.info, actual, c.expected)
+		}
+	}
+
+	if strings.Contains(key, "alice") {
+		t.Errorf("veleroReadKey() == %q, expected the token to be hashed", key)
+	}
+}
+
+func TestVeleroRoutesDoNotHideNamespaces(t *testing.T) {
+	ws := new(restful.WebService)
+	new(APIHandler).installVeleroRoutes(ws)
+
+	namespaced := make(map[string]bool)
+	for _, route := range ws.Routes() {
+		segments := strings.Split(route.Path, "/")
+		for i, segment := range segments {
+			if segment == "{namespace}" {
+				namespaced[strings.Join(segments[:i], "/")] = true
+			}
+		}
+	}
+
+	for _, route := range ws.Routes() {
+		segments := strings.Split(route.Path, "/")
+		for i, segment := range segments {
+			prefix := strings.Join(segments[:i], "/")
+			if namespaced[prefix] && segment != "-" && !strings.HasPrefix(segment, "{") {
+				t.Errorf("%s %s hides the namespace %q of routes below %s", route.Method, route.Path, segment, prefix)
+			}
+		}
+	}
+}
+
+func TestParsePriceQueryParameter(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected float64
+		isError  bool
+	}{
+		{"", 0, false},
+		{"pricePerGiB=0.023", 0.023, false},
+		{"pricePerGiB=2", 2, false},
+		{"pricePerGiB=-1", 0, true},
+		{"pricePerGiB=NaN", 0, true},
+		{"pricePerGiB=Inf", 0, true},
+		{"pricePerGiB=cheap", 0, true},
+	}
+
+	for _, c := range cases {
+		request := restful.NewRequest(httptest.NewRequest(http.MethodGet, "/api/v1/velero/backup/-/usage?"+c.query, nil))
+		actual, err := parsePriceQueryParameter(request)
+		if actual != c.expected || (err != nil) != c.isError {
+			t.Errorf("parsePriceQueryParameter(%q) == %v, %v, expected %v and error: %t", c.query, actual, err, c.expected,
+				c.isError)
+		}
+		if err != nil && !k8serrors.IsBadRequest(err) {
+			t.Errorf("parsePriceQueryParameter(%q) returned %v, expected a bad request", c.query, err)
+		}
+	}
+}
+
+func TestHandleGetCronPreview(t *testing.T) {
+	cases := []struct {
+		schedule       string
+		expectedStatus int
+	}{
+		{"0+2+*+*+*", http.StatusOK},
+		{"@daily", http.StatusOK},
+		{"0+25+*+*+*", http.StatusBadRequest},
+		{"", http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		request := restful.NewRequest(httptest.NewRequest(http.MethodGet, "/api/v1/velero/schedule/-/cron?schedule="+c.schedule, nil))
+		recorder := httptest.NewRecorder()
+		response := restful.NewResponse(recorder)
+		response.SetRequestAccepts(restful.MIME_JSON)
+		new(APIHandler).handleGetCronPreview(request, response)
+
+		if recorder.Code != c.expectedStatus {
+			t.Errorf("handleGetCronPreview(%q) wrote %d, expected %d", c.schedule, recorder.Code, c.expectedStatus)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/gorilla/websocket"
+	"k8s.io/klog/v2"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/subscription"
+)
+
+const (
+	// watchPingPeriod keeps idle list watches open through proxies.
+	watchPingPeriod = 30 * time.Second
+	// watchWriteTimeout drops clients that stopped reading.
+	watchWriteTimeout = 10 * time.Second
+)
+
+// watchUpgrader only accepts connections from the origin serving the API, same as browsers do for regular requests.
+var watchUpgrader = websocket.Upgrader{}
+
+type listSubscriber func(request *http.Request, namespace *common.NamespaceQuery) (*subscription.Subscription, error)
+
+// handleListWatch streams list changes over a WebSocket as JSON encoded subscription events until the client
+// disconnects.
+func handleListWatch(request *restful.Request, response *restful.Response, subscribe listSubscriber) {
+	watch, err := subscribe(request.Request, parseNamespacePathParameter(request))
+	if err != nil {
+		handleVeleroError(request, response, err)
+		return
+	}
+	defer watch.Close()
+
+	conn, err := watchUpgrader.Upgrade(response, request.Request, nil)
+	if err != nil {
+		// The upgrader already responded with an error.
+		klog.V(args.LogLevelVerbose).InfoS("Could not upgrade list watch connection", "path", request.Request.URL.Path, "err", err)
+		return
+	}
+	defer conn.Close()
+
+	// Clients are not expected to send messages, reading only detects when the connection is closed.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(watchPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-watch.Events():
+			_ = conn.SetWriteDeadline(time.Now().Add(watchWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(watchWriteTimeout)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
@@ -112,6 +112,15 @@ const (
 	MemoryUsage = "memory/usage"
 )
 
+// Velero metrics are collected by the sidecar from the Velero server and labelled per schedule.
+const (
+	VeleroBackupDurationSeconds     = "velero/backup_duration_seconds"
+	VeleroBackupAttemptTotal        = "velero/backup_attempt_total"
+	VeleroBackupSuccessTotal        = "velero/backup_success_total"
+	VeleroBackupPartialFailureTotal = "velero/backup_partial_failure_total"
+	VeleroBackupFailureTotal        = "velero/backup_failure_total"
+)
+
 type DataPoints []DataPoint
 
 type DataPoint struct {
@@ -22,8 +22,9 @@ import (
 // SidecarAllInOneDownloadConfig holds config information specifying whether given native Sidecar
 // resource type supports list download.
 var SidecarAllInOneDownloadConfig = map[types.ResourceKind]bool{
-	types.ResourceKindPod:  true,
-	types.ResourceKindNode: false,
+	types.ResourceKindPod:            true,
+	types.ResourceKindNode:           false,
+	types.ResourceKindVeleroSchedule: true,
 }
 
 // DataPointsFromMetricJSONFormat converts all the data points from format used by sidecar to our
@@ -103,7 +103,7 @@ func getMyPodsFromCache(selector metricapi.ResourceSelector, cachedPods []v1.Pod
 // returns error if requested resource is not native or is not supported.
 func newSidecarSelectorFromNativeResource(resourceType types.ResourceKind, namespace string,
 	resourceNames []string, resourceUIDs []apimachinery.UID) (sidecarSelector, error) {
-	// Here we have 2 possibilities because this module allows downloading Nodes and Pods from sidecar
+	// Here we have 3 possibilities because this module allows downloading Nodes, Pods and Velero schedules from sidecar
 	switch resourceType {
 	case types.ResourceKindPod:
 		return sidecarSelector{
@@ -119,6 +119,13 @@ func newSidecarSelectorFromNativeResource(resourceType types.ResourceKind, names
 			Resources:          resourceNames,
 			Label:              metricapi.Label{resourceType: resourceUIDs},
 		}, nil
+	case types.ResourceKindVeleroSchedule:
+		return sidecarSelector{
+			TargetResourceType: types.ResourceKindVeleroSchedule,
+			Path:               `namespaces/` + namespace + `/schedule-list/`,
+			Resources:          resourceNames,
+			Label:              metricapi.Label{resourceType: resourceUIDs},
+		}, nil
 	default:
 		return sidecarSelector{}, fmt.Errorf(`resource "%s" is not a native sidecar resource type or is not supported`, resourceType)
 	}
@@ -97,6 +97,17 @@ func TestGetSidecarSelector(t *testing.T) {
 			types.ResourceKindNode,
 			[]string{"foon"},
 		},
+		{
+			"ResourceSelector for native resource - velero schedule",
+			metricapi.ResourceSelector{
+				Namespace:    "velero",
+				ResourceType: types.ResourceKindVeleroSchedule,
+				ResourceName: "daily",
+			},
+			`namespaces/velero/schedule-list/`,
+			types.ResourceKindVeleroSchedule,
+			[]string{"daily"},
+		},
 		{
 			"ResourceSelector for derived resource with old style selector",
 			metricapi.ResourceSelector{
@@ -0,0 +1,210 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+// BulkDeleteSpec selects the backups to delete, e.g. all failed backups older than 7 days. Backups have to match all
+// of the filters set, at least one of which is required.
+type BulkDeleteSpec struct {
+	// Namespace of the backups, the Velero namespace when empty.
+	Namespace string `json:"namespace"`
+
+	LabelSelector string               `json:"labelSelector,omitempty"`
+	Phases        []velero.BackupPhase `json:"phases,omitempty"`
+
+	// OlderThan selects backups created before that long ago, e.g. "72h" or "7d".
+	OlderThan string `json:"olderThan,omitempty"`
+}
+
+// BulkDeleteResult contains the outcome of deleting each of the matching backups.
+type BulkDeleteResult struct {
+	Items []BulkDeleteItem `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// BulkDeleteItem is the outcome of deleting a single backup. Request is the name of the delete backup request created
+// for it, Error is set when creating it failed.
+type BulkDeleteItem struct {
+	Name    string             `json:"name"`
+	Phase   velero.BackupPhase `json:"phase"`
+	Request string             `json:"request,omitempty"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// DeleteBackups creates a delete backup request for each backup matching the spec, so that Velero removes the backup
+// data from object storage along with the backup. Backups already being deleted are skipped. Creating a request does
+// not stop at errors of previous ones, they are reported per item instead. In dry run each request is only validated
+// by the API server.
+func DeleteBackups(request *http.Request, spec *BulkDeleteSpec, dryRun bool) (*BulkDeleteResult, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	spec.Namespace = velero.NamespaceOrDefault(request, spec.Namespace)
+
+	selector, err := spec.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := velero.CheckAccess(request, velero.VerbCreate, velero.DeleteBackupRequestGVR, spec.Namespace, ""); err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := deleteBackups(ctx, dynamicClient, spec, selector, dryRun, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Items {
+		if item.Request != "" {
+			meta := types.ObjectMeta{Namespace: spec.Namespace, Name: item.Name}
+			if err := audit.Record(request, audit.ActionDeleted, velero.BackupGVR.GroupVersion().WithKind("Backup"), meta, dryRun); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// validate returns the parsed label selector, so that nothing is deleted when any of the filters is invalid.
+func (in *BulkDeleteSpec) validate() (labels.Selector, error) {
+	if in.LabelSelector == "" && len(in.Phases) == 0 && in.OlderThan == "" {
+		return nil, errors.NewBadRequest("at least one of label selector, phases or older than is required")
+	}
+
+	selector, err := labels.Parse(in.LabelSelector)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid label selector %q: %s", in.LabelSelector, err.Error()))
+	}
+
+	if in.OlderThan != "" {
+		if _, err := ParseWindow(in.OlderThan); err != nil {
+			return nil, err
+		}
+	}
+
+	return selector, nil
+}
+
+func deleteBackups(ctx context.Context, client dynamic.Interface, spec *BulkDeleteSpec, selector labels.Selector, dryRun bool, now time.Time) (*BulkDeleteResult, error) {
+	items, nonCriticalErrors, err := getBackups(ctx, client, common.NewSameNamespaceQuery(spec.Namespace),
+		metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkDeleteResult{Items: make([]BulkDeleteItem, 0), Errors: nonCriticalErrors}
+	for _, backup := range filterBackups(items, spec, now) {
+		phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+		item := BulkDeleteItem{Name: backup.GetName(), Phase: velero.BackupPhase(phase)}
+
+		created, err := client.Resource(velero.DeleteBackupRequestGVR).Namespace(spec.Namespace).
+			Create(ctx, newDeleteBackupRequest(backup), velero.CreateOptions(dryRun))
+		if err != nil {
+			item.Error = err.Error()
+		} else {
+			item.Request = created.GetName()
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
+
+// filterBackups returns backups matching the phases and age of the spec. The label selector is applied when listing.
+func filterBackups(items []unstructured.Unstructured, spec *BulkDeleteSpec, now time.Time) []unstructured.Unstructured {
+	var cutoff time.Time
+	if spec.OlderThan != "" {
+		duration, _ := ParseWindow(spec.OlderThan)
+		cutoff = now.Add(-duration)
+	}
+
+	result := make([]unstructured.Unstructured, 0)
+	for _, item := range items {
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		if velero.BackupPhase(phase).Status() == velero.StatusDeleting {
+			continue
+		}
+
+		if len(spec.Phases) > 0 && !slices.Contains(spec.Phases, velero.BackupPhase(phase)) {
+			continue
+		}
+
+		if !cutoff.IsZero() && !item.GetCreationTimestamp().Time.Before(cutoff) {
+			continue
+		}
+
+		result = append(result, item)
+	}
+
+	return result
+}
+
+// newDeleteBackupRequest returns the request Velero's own CLI creates to delete the backup.
+func newDeleteBackupRequest(backup unstructured.Unstructured) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "DeleteBackupRequest",
+		"metadata": map[string]interface{}{
+			"generateName": backup.GetName() + "-",
+			"namespace":    backup.GetNamespace(),
+			"labels": map[string]interface{}{
+				BackupNameLabel: toLabelValue(backup.GetName()),
+				BackupUIDLabel:  string(backup.GetUID()),
+			},
+		},
+		"spec": map[string]interface{}{"backupName": backup.GetName()},
+	}}
+}
+
+// toLabelValue shortens names too long for a label value the same way Velero does, replacing the end with a hash.
+func toLabelValue(name string) string {
+	if len(name) <= validation.LabelValueMaxLength {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	return name[:validation.LabelValueMaxLength-6] + hex.EncodeToString(hash[:])[:6]
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newBulkDeleteTestBackup(name string, phase velero.BackupPhase, created time.Time, app string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Backup",
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         "velero",
+			"uid":               name + "-uid",
+			"labels":            map[string]interface{}{"app": app},
+			"creationTimestamp": created.Format(time.RFC3339),
+		},
+		"status": map[string]interface{}{"phase": string(phase)},
+	}}
+}
+
+func TestBulkDeleteSpecValidate(t *testing.T) {
+	cases := []struct {
+		info  string
+		spec  *BulkDeleteSpec
+		valid bool
+	}{
+		{"no filters", &BulkDeleteSpec{}, false},
+		{"invalid label selector", &BulkDeleteSpec{LabelSelector: "app in (shop"}, false},
+		{"invalid age", &BulkDeleteSpec{OlderThan: "week"}, false},
+		{"phase filter", &BulkDeleteSpec{Phases: []velero.BackupPhase{velero.BackupPhaseFailed}}, true},
+		{"all filters", &BulkDeleteSpec{
+			LabelSelector: "app=shop",
+			Phases:        []velero.BackupPhase{velero.BackupPhaseFailed},
+			OlderThan:     "7d",
+		}, true},
+	}
+
+	for _, c := range cases {
+		if _, err := c.spec.validate(); (err == nil) != c.valid {
+			t.Errorf("%s: validate() == %v, expected valid %t", c.info, err, c.valid)
+		}
+	}
+}
+
+func TestDeleteBackups(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-8 * 24 * time.Hour)
+	longName := strings.Repeat("a", 70)
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newBulkDeleteTestBackup("failed-old", velero.BackupPhaseFailed, old, "shop"),
+		newBulkDeleteTestBackup("failed-recent", velero.BackupPhaseFailed, now.Add(-time.Hour), "shop"),
+		newBulkDeleteTestBackup("completed-old", velero.BackupPhaseCompleted, old, "shop"),
+		newBulkDeleteTestBackup("deleting-old", velero.BackupPhaseDeleting, old, "shop"),
+		newBulkDeleteTestBackup("blog-failed-old", velero.BackupPhaseFailed, old, "blog"),
+		newBulkDeleteTestBackup(longName, velero.BackupPhaseFailed, old, "shop"))
+	dynamicClient.PrependReactor("create", "deletebackuprequests", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		object := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		object.SetName(object.GetGenerateName() + "x7k2p")
+		return false, nil, nil
+	})
+
+	spec := &BulkDeleteSpec{
+		Namespace:     "velero",
+		LabelSelector: "app=shop",
+		Phases:        []velero.BackupPhase{velero.BackupPhaseFailed, velero.BackupPhaseDeleting},
+		OlderThan:     "7d",
+	}
+	selector, _ := labels.Parse(spec.LabelSelector)
+
+	actual, err := deleteBackups(context.TODO(), dynamicClient, spec, selector, false, now)
+	if err != nil {
+		t.Fatalf("deleteBackups() returned error: %s", err.Error())
+	}
+
+	expected := &BulkDeleteResult{
+		Items: []BulkDeleteItem{
+			{Name: longName, Phase: velero.BackupPhaseFailed, Request: longName + "-x7k2p"},
+			{Name: "failed-old", Phase: velero.BackupPhaseFailed, Request: "failed-old-x7k2p"},
+		},
+		Errors: []error{},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("deleteBackups() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+
+	requests, err := dynamicClient.Resource(velero.DeleteBackupRequestGVR).Namespace("velero").
+		List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() returned error: %s", err.Error())
+	}
+
+	backupNames := make(map[string]string)
+	for _, item := range requests.Items {
+		name, _, _ := unstructured.NestedString(item.Object, "spec", "backupName")
+		backupNames[name] = item.GetLabels()[BackupNameLabel]
+	}
+
+	expectedNames := map[string]string{"failed-old": "failed-old", longName: toLabelValue(longName)}
+	if !reflect.DeepEqual(backupNames, expectedNames) {
+		t.Errorf("deleteBackups() created requests for %v, expected %v", backupNames, expectedNames)
+	}
+
+	if value := toLabelValue(longName); len(value) != 63 || !strings.HasPrefix(value, longName[:57]) {
+		t.Errorf("toLabelValue() == %q, expected 63 characters starting with the name", value)
+	}
+}
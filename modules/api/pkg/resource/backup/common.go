@@ -0,0 +1,125 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// ScheduleNameLabel is the label Velero puts on every backup created by a schedule.
+const ScheduleNameLabel = "velero.io/schedule-name"
+
+// suggestedPollSeconds returns how often a backup in the phase is worth refreshing. Backups only change while
+// Velero works on them, afterwards they are refreshed just to notice expiration or deletion.
+func suggestedPollSeconds(phase velero.BackupPhase) int {
+	if phase.IsFinal() {
+		return common.StablePollSeconds
+	}
+
+	return common.ActivePollSeconds
+}
+
+// The code below allows to perform complex data section on []Backup
+
+type BackupCell Backup
+
+func (in BackupCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(in.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(in.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(in.ObjectMeta.Namespace)
+	case dataselect.StartTimeProperty:
+		return comparableTime(in.StartTime)
+	case dataselect.CompletionTimeProperty:
+		return comparableTime(in.CompletionTime)
+	case dataselect.ExpirationProperty:
+		return comparableTime(in.Expiration)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+// comparableTime makes unset timestamps compare as the zero time, so backups that have not started or finished yet
+// end up together at one end of the list.
+func comparableTime(t *metav1.Time) dataselect.ComparableValue {
+	if t == nil {
+		return dataselect.StdComparableTime(time.Time{})
+	}
+
+	return dataselect.StdComparableTime(t.Time)
+}
+
+func toCells(std []Backup) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = BackupCell(std[i])
+	}
+	return cells
+}
+
+func fromCells(cells []dataselect.DataCell) []Backup {
+	std := make([]Backup, len(cells))
+	for i := range std {
+		std[i] = Backup(cells[i].(BackupCell))
+	}
+	return std
+}
+
+// nestedTime reads an RFC3339 timestamp from the object, returning nil when it is not set.
+func nestedTime(obj map[string]interface{}, fields ...string) *metav1.Time {
+	value, found, err := unstructured.NestedString(obj, fields...)
+	if err != nil || !found {
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	result := metav1.NewTime(parsed)
+	return &result
+}
+
+// ParseWindow parses a reporting window. It accepts Go durations (e.g. "72h") as well as whole days (e.g. "7d").
+func ParseWindow(window string) (time.Duration, error) {
+	duration, err := time.ParseDuration(window)
+	if days, found := strings.CutSuffix(window, "d"); found {
+		var count int
+		count, err = strconv.Atoi(days)
+		duration = time.Duration(count) * 24 * time.Hour
+	}
+
+	if err != nil || duration <= 0 {
+		return 0, errors.NewBadRequest(fmt.Sprintf("invalid window %q, expected a positive duration such as 72h or 7d", window))
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+func TestParseWindow(t *testing.T) {
+	cases := []struct {
+		window   string
+		expected time.Duration
+		isError  bool
+	}{
+		{"72h", 72 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"0d", 0, true},
+		{"week", 0, true},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseWindow(c.window)
+		if (err != nil) != c.isError || actual != c.expected {
+			t.Errorf("ParseWindow(%s) == %v, %v, expected %v, error %v", c.window, actual, err, c.expected, c.isError)
+		}
+	}
+}
+
+func TestSuggestedPollSeconds(t *testing.T) {
+	cases := []struct {
+		phase    velero.BackupPhase
+		expected int
+	}{
+		{"", common.ActivePollSeconds},
+		{"New", common.ActivePollSeconds},
+		{"InProgress", common.ActivePollSeconds},
+		{"Finalizing", common.ActivePollSeconds},
+		{"Completed", common.StablePollSeconds},
+		{"PartiallyFailed", common.StablePollSeconds},
+		{"FailedValidation", common.StablePollSeconds},
+	}
+
+	for _, c := range cases {
+		if actual := suggestedPollSeconds(c.phase); actual != c.expected {
+			t.Errorf("suggestedPollSeconds(%s) == %d, expected %d", c.phase, actual, c.expected)
+		}
+	}
+}
@@ -16,22 +16,67 @@ package backup
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
 
-	crdv1 "k8s.io/dashboard/api/pkg/resource/customresourcedefinition/v1"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
 	"k8s.io/dashboard/types"
 )
 
-// CreateBackup creates a new Velero backup
-func CreateBackup(request *http.Request, spec *BackupSpec) (*Backup, error) {
-	// This GVR is not needed for REST client approach
+const (
+	// defaultNamePrefix names the backups created without a name or a name prefix.
+	defaultNamePrefix = "backup"
+
+	// nameTimeFormat is the suffix format of generated backup names, the same Velero uses for scheduled backups.
+	nameTimeFormat = "20060102150405"
+)
+
+// CreateBackup creates a new Velero backup. In dry run it is only validated by the API server, which returns it without
+// persisting it. Specs with preflight errors are rejected, see GetBackupPreflight.
+func CreateBackup(request *http.Request, spec *BackupSpec, dryRun bool) (*Backup, error) {
+	return createBackup(request, spec, map[string]string{}, dryRun)
+}
+
+// createBackup creates the backup with the given annotations, adding the ones set for the spec to them.
+func createBackup(request *http.Request, spec *BackupSpec, annotations map[string]string, dryRun bool) (*Backup, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	spec.Namespace = velero.NamespaceOrDefault(request, spec.Namespace)
+	if spec.Name == "" {
+		spec.Name = toGeneratedName(spec.NamePrefix, time.Now())
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := velero.CheckAccess(request, velero.VerbCreate, velero.BackupGVR, spec.Namespace, spec.Name); err != nil {
+		return nil, err
+	}
+
+	if err := spec.ValidateResourcePolicy(ctx, request, spec.Namespace); err != nil {
+		return nil, err
+	}
+
+	if err := checkPreflight(ctx, request, spec); err != nil {
+		return nil, err
+	}
+
+	if len(spec.ExclusionPresets) > 0 {
+		if err := applyExclusionPresets(ctx, request, spec); err != nil {
+			return nil, err
+		}
+	}
 
 	// Create unstructured object for the backup
 	backup := &unstructured.Unstructured{
@@ -42,109 +87,84 @@ func CreateBackup(request *http.Request, spec *BackupSpec) (*Backup, error) {
 				"name":      spec.Name,
 				"namespace": spec.Namespace,
 			},
-			"spec": map[string]interface{}{
-				"includedNamespaces": spec.IncludedNamespaces,
-				"storageLocation":    spec.StorageLocation,
-				"ttl":                spec.TTL,
-			},
+			"spec": spec.ToUnstructured(),
 		},
 	}
 
-	// Add optional fields if provided
-	if len(spec.ExcludedNamespaces) > 0 {
-		backup.Object["spec"].(map[string]interface{})["excludedNamespaces"] = spec.ExcludedNamespaces
-	}
-	if len(spec.IncludedResources) > 0 {
-		backup.Object["spec"].(map[string]interface{})["includedResources"] = spec.IncludedResources
-	}
-	if len(spec.ExcludedResources) > 0 {
-		backup.Object["spec"].(map[string]interface{})["excludedResources"] = spec.ExcludedResources
+	if len(spec.ExclusionPresets) > 0 {
+		annotations[ExclusionPresetsAnnotation] = strings.Join(spec.ExclusionPresets, ",")
 	}
-	if spec.LabelSelector != nil {
-		backup.Object["spec"].(map[string]interface{})["labelSelector"] = spec.LabelSelector
+	if len(annotations) > 0 {
+		backup.SetAnnotations(annotations)
 	}
 
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
-	if err != nil {
+	if err := checkNameAvailable(ctx, dynamicClient, spec.Namespace, spec.Name); err != nil {
 		return nil, err
 	}
 
-	// Get the backup CRD definition
-	customResourceDefinition, err := apiExtClient.ApiextensionsV1().
-		CustomResourceDefinitions().
-		Get(context.TODO(), "backups.velero.io", metav1.GetOptions{})
+	audit.SetCreatedBy(request, backup)
+	created, err := dynamicClient.Resource(velero.BackupGVR).Namespace(spec.Namespace).
+		Create(ctx, backup, velero.CreateOptions(dryRun))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Failed to create backup: %w", err)
 	}
 
-	// Create REST client for the backup CRD
-	restClient, err := crdv1.NewRESTClient(config, customResourceDefinition)
-	if err != nil {
-		return nil, err
+	// Convert to our Backup struct
+	createdBackupResult := &Backup{
+		ObjectMeta: velero.NewObjectMeta(created.Object),
+		TypeMeta: types.TypeMeta{
+			Kind: "Backup",
+		},
 	}
 
-	// Convert our backup object to JSON
-	backupJSON, err := json.Marshal(backup.Object)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to marshal backup: %s", err.Error())
+	if err := audit.Record(request, audit.ActionCreated, velero.BackupGVR.GroupVersion().WithKind("Backup"), createdBackupResult.ObjectMeta, dryRun); err != nil {
+		createdBackupResult.Errors = append(createdBackupResult.Errors, err)
 	}
+	return createdBackupResult, nil
+}
 
-	// Create the backup via REST client
-	result := restClient.Post().
-		NamespaceIfScoped(spec.Namespace, customResourceDefinition.Spec.Scope == apiextensionsv1.NamespaceScoped).
-		Resource(customResourceDefinition.Spec.Names.Plural).
-		Body(backupJSON).
-		Do(context.TODO())
-
-	if result.Error() != nil {
-		return nil, fmt.Errorf("Failed to create backup: %s", result.Error().Error())
+// checkNameAvailable rejects the name of an existing backup with an AlreadyExists error before the backup is built.
+// Other errors are left for the creation to report.
+func checkNameAvailable(ctx context.Context, client dynamic.Interface, namespace, name string) error {
+	_, err := client.Resource(velero.BackupGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return k8serrors.NewAlreadyExists(velero.BackupGVR.GroupResource(), name)
 	}
 
-	// Get the raw response
-	raw, err := result.Raw()
-	if err != nil {
-		return nil, fmt.Errorf("Failed to get backup response: %s", err.Error())
-	}
+	return nil
+}
 
-	// Parse the response to extract basic info
-	var createdBackup map[string]interface{}
-	if err := json.Unmarshal(raw, &createdBackup); err != nil {
-		return nil, fmt.Errorf("Failed to parse backup response: %s", err.Error())
+// toGeneratedName names a backup after the prefix and the current time, the same way Velero names scheduled backups.
+func toGeneratedName(prefix string, now time.Time) string {
+	if prefix == "" {
+		prefix = defaultNamePrefix
 	}
 
-	// Extract metadata
-	metadata := createdBackup["metadata"].(map[string]interface{})
+	return toSuffixedName(prefix, "-"+now.UTC().Format(nameTimeFormat))
+}
 
-	// Convert to our Backup struct
-	createdBackupResult := &Backup{
-		ObjectMeta: types.ObjectMeta{
-			Name:      metadata["name"].(string),
-			Namespace: metadata["namespace"].(string),
-		},
-		TypeMeta: types.TypeMeta{
-			Kind: "Backup",
-		},
+// toSuffixedName appends the suffix to the name, shortening the name to keep the result a valid object name.
+func toSuffixedName(name, suffix string) string {
+	if len(name)+len(suffix) > validation.DNS1123SubdomainMaxLength {
+		name = name[:validation.DNS1123SubdomainMaxLength-len(suffix)]
 	}
 
-	return createdBackupResult, nil
+	return name + suffix
 }
 
 // BackupSpec represents the specification for creating a backup
 type BackupSpec struct {
-	Name               string                `json:"name"`
-	Namespace          string                `json:"namespace"`
-	IncludedNamespaces []string              `json:"includedNamespaces,omitempty"`
-	ExcludedNamespaces []string              `json:"excludedNamespaces,omitempty"`
-	IncludedResources  []string              `json:"includedResources,omitempty"`
-	ExcludedResources  []string              `json:"excludedResources,omitempty"`
-	LabelSelector      *metav1.LabelSelector `json:"labelSelector,omitempty"`
-	StorageLocation    string                `json:"storageLocation,omitempty"`
-	TTL                string                `json:"ttl,omitempty"`
+	// Name is generated from NamePrefix and the current time when left empty.
+	Name       string `json:"name"`
+	NamePrefix string `json:"namePrefix,omitempty"`
+	Namespace  string `json:"namespace"`
+	BackupTemplate
+
+	// ExclusionPresets are expanded into ExcludedResources when the backup is created.
+	ExclusionPresets []string `json:"exclusionPresets,omitempty"`
 }
@@ -0,0 +1,57 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func TestToGeneratedName(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 30, 5, 0, time.UTC)
+	cases := []struct {
+		prefix   string
+		expected string
+	}{
+		{"", "backup-20261017123005"},
+		{"shop", "shop-20261017123005"},
+		{strings.Repeat("a", 250), strings.Repeat("a", 238) + "-20261017123005"},
+	}
+
+	for _, c := range cases {
+		if actual := toGeneratedName(c.prefix, now); actual != c.expected {
+			t.Errorf("toGeneratedName(%q) == %q, expected %q", c.prefix, actual, c.expected)
+		}
+	}
+}
+
+func TestCheckNameAvailable(t *testing.T) {
+	dynamicClient := velerofake.NewDynamicClient(nil, velerofake.NewObject(velero.BackupGVR, "velero", "daily"))
+
+	if err := checkNameAvailable(context.TODO(), dynamicClient, "velero", "weekly"); err != nil {
+		t.Errorf("checkNameAvailable(weekly) returned error: %s", err.Error())
+	}
+
+	if err := checkNameAvailable(context.TODO(), dynamicClient, "velero", "daily"); !k8serrors.IsAlreadyExists(err) {
+		t.Errorf("checkNameAvailable(daily) == %v, expected already exists error", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// CSISnapshot is a CSI volume snapshot taken by a backup. Velero deletes the VolumeSnapshot once the backup
+// completes and keeps its content, so either side may be missing.
+type CSISnapshot struct {
+	// Namespace and name of the VolumeSnapshot
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+
+	// PersistentVolumeClaim the snapshot was taken of
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+
+	// Name of the VolumeSnapshotContent and the snapshot of the storage provider it represents
+	ContentName    string `json:"contentName,omitempty"`
+	Driver         string `json:"driver,omitempty"`
+	SnapshotHandle string `json:"snapshotHandle,omitempty"`
+
+	ReadyToUse  bool   `json:"readyToUse"`
+	RestoreSize string `json:"restoreSize,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// getCSISnapshots returns the CSI volume snapshots of the backup, sorted by namespace, name and content. Snapshots
+// and contents are found by the backup name label Velero puts on them. Either of them is left out when it cannot be
+// listed, e.g. when the snapshot CRDs are not installed or the user may not list cluster-scoped contents.
+func getCSISnapshots(ctx context.Context, client dynamic.Interface, backupName string) []CSISnapshot {
+	options := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{BackupNameLabel: toLabelValue(backupName)}).String(),
+	}
+
+	var snapshots, contents []unstructured.Unstructured
+	if list, err := client.Resource(velero.VolumeSnapshotGVR).List(ctx, options); err == nil {
+		snapshots = list.Items
+	}
+	if list, err := client.Resource(velero.VolumeSnapshotContentGVR).List(ctx, options); err == nil {
+		contents = list.Items
+	}
+
+	return toCSISnapshots(snapshots, contents)
+}
+
+func toCSISnapshots(snapshots, contents []unstructured.Unstructured) []CSISnapshot {
+	result := make([]CSISnapshot, 0, max(len(snapshots), len(contents)))
+	byContent := make(map[string]int, len(snapshots))
+	for _, item := range snapshots {
+		snapshot := CSISnapshot{Namespace: item.GetNamespace(), Name: item.GetName()}
+		snapshot.PersistentVolumeClaim, _, _ = unstructured.NestedString(item.Object, "spec", "source", "persistentVolumeClaimName")
+		snapshot.ContentName, _, _ = unstructured.NestedString(item.Object, "status", "boundVolumeSnapshotContentName")
+		snapshot.ReadyToUse, _, _ = unstructured.NestedBool(item.Object, "status", "readyToUse")
+		snapshot.RestoreSize, _, _ = unstructured.NestedString(item.Object, "status", "restoreSize")
+		snapshot.Error, _, _ = unstructured.NestedString(item.Object, "status", "error", "message")
+
+		if snapshot.ContentName != "" {
+			byContent[snapshot.ContentName] = len(result)
+		}
+		result = append(result, snapshot)
+	}
+
+	for _, item := range contents {
+		i, found := byContent[item.GetName()]
+		if !found {
+			i = len(result)
+			result = append(result, CSISnapshot{ContentName: item.GetName()})
+			result[i].Namespace, _, _ = unstructured.NestedString(item.Object, "spec", "volumeSnapshotRef", "namespace")
+			result[i].Name, _, _ = unstructured.NestedString(item.Object, "spec", "volumeSnapshotRef", "name")
+		}
+
+		snapshot := &result[i]
+		snapshot.Driver, _, _ = unstructured.NestedString(item.Object, "spec", "driver")
+		snapshot.SnapshotHandle, _, _ = unstructured.NestedString(item.Object, "status", "snapshotHandle")
+		if readyToUse, found, _ := unstructured.NestedBool(item.Object, "status", "readyToUse"); found {
+			snapshot.ReadyToUse = readyToUse
+		}
+		if snapshot.Error == "" {
+			snapshot.Error, _, _ = unstructured.NestedString(item.Object, "status", "error", "message")
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ContentName < result[j].ContentName
+	})
+
+	return result
+}
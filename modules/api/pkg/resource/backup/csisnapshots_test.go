@@ -0,0 +1,84 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newCSIObject(resource schema.GroupVersionResource, kind, namespace, name, backupName string, fields map[string]interface{}) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: fields}
+	object.SetAPIVersion(resource.GroupVersion().String())
+	object.SetKind(kind)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	object.SetLabels(map[string]string{BackupNameLabel: backupName})
+	return object
+}
+
+func TestGetCSISnapshots(t *testing.T) {
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newCSIObject(velero.VolumeSnapshotGVR, "VolumeSnapshot", "shop", "velero-data-db-0-abcde", "daily", map[string]interface{}{
+			"spec":   map[string]interface{}{"source": map[string]interface{}{"persistentVolumeClaimName": "data-db-0"}},
+			"status": map[string]interface{}{"boundVolumeSnapshotContentName": "snapcontent-1", "readyToUse": false, "restoreSize": "10Gi"},
+		}),
+		newCSIObject(velero.VolumeSnapshotContentGVR, "VolumeSnapshotContent", "", "snapcontent-1", "daily", map[string]interface{}{
+			"spec":   map[string]interface{}{"driver": "ebs.csi.aws.com"},
+			"status": map[string]interface{}{"snapshotHandle": "snap-0123", "readyToUse": true},
+		}),
+		newCSIObject(velero.VolumeSnapshotContentGVR, "VolumeSnapshotContent", "", "snapcontent-2", "daily", map[string]interface{}{
+			"spec": map[string]interface{}{"driver": "ebs.csi.aws.com",
+				"volumeSnapshotRef": map[string]interface{}{"namespace": "shop", "name": "velero-cache-abcde"}},
+			"status": map[string]interface{}{"snapshotHandle": "snap-0456", "readyToUse": false,
+				"error": map[string]interface{}{"message": "snapshot quota exceeded"}},
+		}),
+		newCSIObject(velero.VolumeSnapshotContentGVR, "VolumeSnapshotContent", "", "snapcontent-3", "weekly", map[string]interface{}{}),
+	)
+
+	expected := []CSISnapshot{
+		{Namespace: "shop", Name: "velero-cache-abcde", ContentName: "snapcontent-2", Driver: "ebs.csi.aws.com",
+			SnapshotHandle: "snap-0456", Error: "snapshot quota exceeded"},
+		{Namespace: "shop", Name: "velero-data-db-0-abcde", PersistentVolumeClaim: "data-db-0", ContentName: "snapcontent-1",
+			Driver: "ebs.csi.aws.com", SnapshotHandle: "snap-0123", ReadyToUse: true, RestoreSize: "10Gi"},
+	}
+
+	actual := getCSISnapshots(context.Background(), dynamicClient, "daily")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getCSISnapshots() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestGetCSISnapshotsWithoutCRDs(t *testing.T) {
+	dynamicClient := velerofake.NewDynamicClient(nil)
+	dynamicClient.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewNotFound(action.GetResource().GroupResource(), "")
+	})
+
+	actual := getCSISnapshots(context.Background(), dynamicClient, "daily")
+	if len(actual) != 0 || actual == nil {
+		t.Errorf("getCSISnapshots() == %#v, expected an empty list", actual)
+	}
+}
@@ -15,55 +15,47 @@
 package backup
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
-	crdv1 "k8s.io/dashboard/api/pkg/resource/customresourcedefinition/v1"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
 )
 
-// DeleteBackup deletes a Velero backup
-func DeleteBackup(request *http.Request, namespace, name string) error {
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
-	if err != nil {
-		return err
-	}
+// DeleteBackup deletes a Velero backup. In dry run the deletion is only validated by the API server. Restores created
+// from the backup are returned as related objects.
+func DeleteBackup(request *http.Request, namespace, name string, dryRun bool) (*velero.DeletionImpact, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
 
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
-	if err != nil {
-		return err
+	if err := velero.CheckAccess(request, velero.VerbDelete, velero.BackupGVR, namespace, name); err != nil {
+		return nil, err
 	}
 
-	// Get the backup CRD definition
-	customResourceDefinition, err := apiExtClient.ApiextensionsV1().
-		CustomResourceDefinitions().
-		Get(context.TODO(), "backups.velero.io", metav1.GetOptions{})
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Create REST client for the backup CRD
-	restClient, err := crdv1.NewRESTClient(config, customResourceDefinition)
+	impact, err := velero.Delete(ctx, dynamicClient, velero.BackupGVR, "Backup", namespace, name, dryRun)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("Failed to delete backup: %w", err)
+	}
+	if err := audit.Record(request, audit.ActionDeleted, velero.BackupGVR.GroupVersion().WithKind("Backup"), impact.ObjectMeta, dryRun); err != nil {
+		impact.Errors = append(impact.Errors, err)
 	}
 
-	// Delete the backup via REST client
-	result := restClient.Delete().
-		NamespaceIfScoped(namespace, customResourceDefinition.Spec.Scope == apiextensionsv1.NamespaceScoped).
-		Resource(customResourceDefinition.Spec.Names.Plural).
-		Name(name).
-		Do(context.TODO())
+	impact.AddRelated(ctx, dynamicClient, velero.RestoreGVR, "Restore", metav1.ListOptions{}, func(item unstructured.Unstructured) bool {
+		return isRestoreOf(item, name)
+	})
 
-	if result.Error() != nil {
-		return fmt.Errorf("Failed to delete backup: %s", result.Error().Error())
-	}
+	return impact, nil
+}
 
-	return nil
-}
\ No newline at end of file
+func isRestoreOf(restore unstructured.Unstructured, backupName string) bool {
+	name, _, _ := unstructured.NestedString(restore.Object, "spec", "backupName")
+	return name == backupName
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/deletebackuprequest"
+)
+
+// addDeletions sets the latest delete backup request of each backup. Failing to list the requests leaves them out and
+// is returned as a non-critical error.
+func addDeletions(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery, backups []Backup) []error {
+	if len(backups) == 0 {
+		return nil
+	}
+
+	requests, nonCriticalErrors, err := deletebackuprequest.GetDeleteBackupRequests(ctx, client, namespace, metav1.ListOptions{})
+	if err != nil {
+		return []error{err}
+	}
+
+	latest := deletebackuprequest.GetLatestPerBackup(requests)
+	for i := range backups {
+		key := k8stypes.NamespacedName{Namespace: backups[i].ObjectMeta.Namespace, Name: backups[i].ObjectMeta.Name}
+		backups[i].Deletion = latest[key]
+	}
+
+	return nonCriticalErrors
+}
+
+// getDeletion returns the latest request to delete the backup, nil when there is none or the requests can not be read.
+func getDeletion(ctx context.Context, client dynamic.Interface, namespace, name string) *deletebackuprequest.DeleteBackupRequest {
+	selector := labels.SelectorFromSet(labels.Set{BackupNameLabel: toLabelValue(name)}).String()
+	requests, _, err := deletebackuprequest.GetDeleteBackupRequests(ctx, client, common.NewSameNamespaceQuery(namespace),
+		metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil
+	}
+
+	return deletebackuprequest.GetLatestPerBackup(requests)[k8stypes.NamespacedName{Namespace: namespace, Name: name}]
+}
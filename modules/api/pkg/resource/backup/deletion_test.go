@@ -0,0 +1,79 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+	"k8s.io/dashboard/types"
+)
+
+func newTestDeleteBackupRequest(name, backupName, phase string, created time.Time) *unstructured.Unstructured {
+	item := newDeleteBackupRequest(*velerofake.NewObject(velero.BackupGVR, "velero", backupName))
+	item.SetName(name)
+	item.SetCreationTimestamp(metav1.NewTime(created))
+	_ = unstructured.SetNestedField(item.Object, phase, "status", "phase")
+	return item
+}
+
+func TestAddDeletions(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newTestDeleteBackupRequest("daily-1", "daily", "Processed", now.Add(-time.Hour)),
+		newTestDeleteBackupRequest("daily-2", "daily", "InProgress", now),
+	)
+	backups := []Backup{
+		{ObjectMeta: types.ObjectMeta{Namespace: "velero", Name: "daily"}},
+		{ObjectMeta: types.ObjectMeta{Namespace: "velero", Name: "weekly"}},
+	}
+
+	nonCriticalErrors := addDeletions(context.Background(), dynamicClient, common.NewSameNamespaceQuery("velero"), backups)
+	if len(nonCriticalErrors) > 0 {
+		t.Fatalf("addDeletions() returned non-critical errors: %v", nonCriticalErrors)
+	}
+
+	if deletion := backups[0].Deletion; deletion == nil || deletion.ObjectMeta.Name != "daily-2" ||
+		deletion.Status != velero.StatusRunning {
+		t.Errorf("addDeletions() set deletion %#v of daily, expected the running request daily-2", backups[0].Deletion)
+	}
+	if backups[1].Deletion != nil {
+		t.Errorf("addDeletions() set deletion %#v of weekly, expected none", backups[1].Deletion)
+	}
+}
+
+func TestGetDeletion(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newTestDeleteBackupRequest("daily-1", "daily", "Processed", now),
+		newTestDeleteBackupRequest("weekly-1", "weekly", "InProgress", now),
+	)
+
+	actual := getDeletion(context.Background(), dynamicClient, "velero", "daily")
+	if actual == nil || actual.ObjectMeta.Name != "daily-1" || actual.Status != velero.StatusSucceeded {
+		t.Errorf("getDeletion(daily) == %#v, expected the processed request daily-1", actual)
+	}
+
+	if actual := getDeletion(context.Background(), dynamicClient, "velero", "monthly"); actual != nil {
+		t.Errorf("getDeletion(monthly) == %#v, expected nil", actual)
+	}
+}
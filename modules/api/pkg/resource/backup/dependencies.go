@@ -0,0 +1,267 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+// DependencyAnalysis lists Secrets, ConfigMaps and ServiceAccounts used by workloads in a backup selection that
+// the selection itself would not back up.
+type DependencyAnalysis struct {
+	Namespace     string               `json:"namespace"`
+	LabelSelector string               `json:"labelSelector,omitempty"`
+	Dependencies  []ExternalDependency `json:"dependencies"`
+}
+
+// ExternalDependency is a resource referenced from the selection that does not match its label selector or does
+// not exist at all.
+type ExternalDependency struct {
+	Kind         types.ResourceKind  `json:"kind"`
+	Name         string              `json:"name"`
+	Missing      bool                `json:"missing"`
+	ReferencedBy []ResourceReference `json:"referencedBy"`
+}
+
+// ResourceReference identifies a resource in the analyzed namespace.
+type ResourceReference struct {
+	Kind types.ResourceKind `json:"kind"`
+	Name string             `json:"name"`
+}
+
+// dependent is a workload or bare pod together with the pod spec it runs.
+type dependent struct {
+	ref     ResourceReference
+	labels  map[string]string
+	podSpec v1.PodSpec
+}
+
+// GetDependencyAnalysis finds resources referenced by pods and workload templates in the namespace matching the
+// label selector that a backup with the same namespace and label selector would miss.
+func GetDependencyAnalysis(request *http.Request, namespace, labelSelector string) (*DependencyAnalysis, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid label selector %q: %s", labelSelector, err.Error()))
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	dependents, err := getDependents(ctx, k8sClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	available, serviceAccounts, err := getAvailableDependencies(ctx, k8sClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DependencyAnalysis{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+		Dependencies:  toExternalDependencies(selector, dependents, available, serviceAccounts),
+	}, nil
+}
+
+// getDependents returns workloads and bare pods of the namespace. Pods and Jobs managed by a controller are skipped
+// as their controller's template is analyzed instead.
+func getDependents(ctx context.Context, client kubernetes.Interface, namespace string) ([]dependent, error) {
+	options := metav1.ListOptions{}
+	result := make([]dependent, 0)
+
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range deployments.Items {
+		result = append(result, dependent{ResourceReference{types.ResourceKindDeployment, item.Name}, item.Labels, item.Spec.Template.Spec})
+	}
+
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range statefulSets.Items {
+		result = append(result, dependent{ResourceReference{types.ResourceKindStatefulSet, item.Name}, item.Labels, item.Spec.Template.Spec})
+	}
+
+	daemonSets, err := client.AppsV1().DaemonSets(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range daemonSets.Items {
+		result = append(result, dependent{ResourceReference{types.ResourceKindDaemonSet, item.Name}, item.Labels, item.Spec.Template.Spec})
+	}
+
+	cronJobs, err := client.BatchV1().CronJobs(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range cronJobs.Items {
+		result = append(result, dependent{ResourceReference{types.ResourceKindCronJob, item.Name}, item.Labels, item.Spec.JobTemplate.Spec.Template.Spec})
+	}
+
+	jobs, err := client.BatchV1().Jobs(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range jobs.Items {
+		if metav1.GetControllerOf(&item) == nil {
+			result = append(result, dependent{ResourceReference{types.ResourceKindJob, item.Name}, item.Labels, item.Spec.Template.Spec})
+		}
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range pods.Items {
+		if metav1.GetControllerOf(&item) == nil {
+			result = append(result, dependent{ResourceReference{types.ResourceKindPod, item.Name}, item.Labels, item.Spec})
+		}
+	}
+
+	return result, nil
+}
+
+// getAvailableDependencies returns labels of ConfigMaps, Secrets and ServiceAccounts existing in the namespace
+// together with the ServiceAccounts, which reference Secrets of their own.
+func getAvailableDependencies(ctx context.Context, client kubernetes.Interface, namespace string) (map[ResourceReference]labels.Set, []v1.ServiceAccount, error) {
+	available := make(map[ResourceReference]labels.Set)
+
+	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range configMaps.Items {
+		available[ResourceReference{types.ResourceKindConfigMap, item.Name}] = item.Labels
+	}
+
+	secrets, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range secrets.Items {
+		available[ResourceReference{types.ResourceKindSecret, item.Name}] = item.Labels
+	}
+
+	serviceAccounts, err := client.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range serviceAccounts.Items {
+		available[ResourceReference{types.ResourceKindServiceAccount, item.Name}] = item.Labels
+	}
+
+	return available, serviceAccounts.Items, nil
+}
+
+func toExternalDependencies(selector labels.Selector, dependents []dependent, available map[ResourceReference]labels.Set,
+	serviceAccounts []v1.ServiceAccount) []ExternalDependency {
+	references := make(map[ResourceReference]map[ResourceReference]bool)
+	add := func(target, from ResourceReference) {
+		if references[target] == nil {
+			references[target] = make(map[ResourceReference]bool)
+		}
+		references[target][from] = true
+	}
+
+	for _, item := range dependents {
+		if !selector.Matches(labels.Set(item.labels)) {
+			continue
+		}
+
+		for _, dependency := range getPodSpecDependencies([]v1.PodSpec{item.podSpec}) {
+			if dependency.Kind != types.ResourceKindPersistentVolumeClaim {
+				add(ResourceReference{dependency.Kind, dependency.Name}, item.ref)
+			}
+		}
+
+		serviceAccount := item.podSpec.ServiceAccountName
+		if serviceAccount == "" {
+			serviceAccount = "default"
+		}
+		add(ResourceReference{types.ResourceKindServiceAccount, serviceAccount}, item.ref)
+	}
+
+	for _, serviceAccount := range serviceAccounts {
+		ref := ResourceReference{types.ResourceKindServiceAccount, serviceAccount.Name}
+		if references[ref] == nil {
+			continue
+		}
+		for _, secret := range serviceAccount.Secrets {
+			add(ResourceReference{types.ResourceKindSecret, secret.Name}, ref)
+		}
+		for _, secret := range serviceAccount.ImagePullSecrets {
+			add(ResourceReference{types.ResourceKindSecret, secret.Name}, ref)
+		}
+	}
+
+	// Every namespace gets its own default ServiceAccount, so it does not need to be backed up.
+	delete(references, ResourceReference{types.ResourceKindServiceAccount, "default"})
+
+	result := make([]ExternalDependency, 0)
+	for target, from := range references {
+		labelSet, exists := available[target]
+		if exists && selector.Matches(labelSet) {
+			continue
+		}
+
+		dependency := ExternalDependency{Kind: target.Kind, Name: target.Name, Missing: !exists,
+			ReferencedBy: make([]ResourceReference, 0, len(from))}
+		for ref := range from {
+			dependency.ReferencedBy = append(dependency.ReferencedBy, ref)
+		}
+		sortReferences(dependency.ReferencedBy)
+		result = append(result, dependency)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func sortReferences(references []ResourceReference) {
+	sort.Slice(references, func(i, j int) bool {
+		if references[i].Kind != references[j].Kind {
+			return references[i].Kind < references[j].Kind
+		}
+		return references[i].Name < references[j].Name
+	})
+}
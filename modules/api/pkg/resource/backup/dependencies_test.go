@@ -0,0 +1,98 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"k8s.io/dashboard/types"
+)
+
+func TestToExternalDependencies(t *testing.T) {
+	web := ResourceReference{types.ResourceKindDeployment, "web"}
+	worker := ResourceReference{types.ResourceKindDeployment, "worker"}
+	webSA := ResourceReference{types.ResourceKindServiceAccount, "web"}
+	dependents := []dependent{
+		{web, map[string]string{"app": "shop"}, v1.PodSpec{
+			ServiceAccountName: "web",
+			Volumes: []v1.Volume{
+				{Name: "config", VolumeSource: v1.VolumeSource{
+					ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "shop-config"}}}},
+				{Name: "data", VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "web-data"}}},
+			},
+			Containers: []v1.Container{{Name: "web", EnvFrom: []v1.EnvFromSource{
+				{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "shop-credentials"}}},
+				{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "feature-flags"}}},
+			}}},
+		}},
+		{worker, map[string]string{"app": "shop"}, v1.PodSpec{
+			Containers: []v1.Container{{Name: "worker", Env: []v1.EnvVar{{Name: "TOKEN", ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "shop-credentials"}}}}}}},
+		}},
+		{ResourceReference{types.ResourceKindPod, "debug"}, nil, v1.PodSpec{
+			Containers: []v1.Container{{Name: "debug", EnvFrom: []v1.EnvFromSource{
+				{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "unrelated"}}},
+			}}},
+		}},
+	}
+	available := map[ResourceReference]labels.Set{
+		{types.ResourceKindConfigMap, "shop-config"}:          {"app": "shop"},
+		{types.ResourceKindSecret, "shop-credentials"}:        nil,
+		{types.ResourceKindSecret, "registry"}:                nil,
+		{types.ResourceKindServiceAccount, "web"}:             {"app": "shop"},
+		{types.ResourceKindServiceAccount, "default"}:         nil,
+		{types.ResourceKindSecret, "unrelated"}:               nil,
+		{types.ResourceKindConfigMap, "kube-root-ca.crt"}:     nil,
+		{types.ResourceKindPersistentVolumeClaim, "web-data"}: nil,
+	}
+	serviceAccounts := []v1.ServiceAccount{{
+		ObjectMeta:       metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "shop"}},
+		ImagePullSecrets: []v1.LocalObjectReference{{Name: "registry"}},
+	}}
+
+	cases := []struct {
+		selector string
+		expected []ExternalDependency
+	}{
+		{
+			"app=shop",
+			[]ExternalDependency{
+				{Kind: types.ResourceKindConfigMap, Name: "feature-flags", Missing: true, ReferencedBy: []ResourceReference{web}},
+				{Kind: types.ResourceKindSecret, Name: "registry", ReferencedBy: []ResourceReference{webSA}},
+				{Kind: types.ResourceKindSecret, Name: "shop-credentials", ReferencedBy: []ResourceReference{web, worker}},
+			},
+		},
+		{
+			"",
+			[]ExternalDependency{
+				{Kind: types.ResourceKindConfigMap, Name: "feature-flags", Missing: true, ReferencedBy: []ResourceReference{web}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		selector, _ := labels.Parse(c.selector)
+		actual := toExternalDependencies(selector, dependents, available, serviceAccounts)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toExternalDependencies(%q) == \n%#v\nexpected \n%#v\n", c.selector, actual, c.expected)
+		}
+	}
+}
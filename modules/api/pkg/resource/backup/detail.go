@@ -19,13 +19,13 @@ import (
 	"encoding/json"
 	"net/http"
 
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/rest"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/dashboard/api/pkg/resource/common"
-	crdv1 "k8s.io/dashboard/api/pkg/resource/customresourcedefinition/v1"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/deletebackuprequest"
+	"k8s.io/dashboard/api/pkg/resource/velero"
 	dashboardtypes "k8s.io/dashboard/types"
 )
 
@@ -35,8 +35,8 @@ type BackupDetail struct {
 	TypeMeta   dashboardtypes.TypeMeta   `json:"typeMeta"`
 
 	// Backup specific fields
-	Status       string `json:"status"`
-	Phase        string `json:"phase"`
+	Status       velero.Status `json:"status"`
+	Phase        velero.BackupPhase `json:"phase"`
 	StartTime    string `json:"startTime,omitempty"`
 	CompletionTime string `json:"completionTime,omitempty"`
 	Expiration   string `json:"expiration,omitempty"`
@@ -53,10 +53,44 @@ type BackupDetail struct {
 	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
 	IncludedResources  []string `json:"includedResources,omitempty"`
 	ExcludedResources  []string `json:"excludedResources,omitempty"`
+
+	// Label selectors limiting the backed up objects
+	LabelSelector    *metav1.LabelSelector   `json:"labelSelector,omitempty"`
+	OrLabelSelectors []*metav1.LabelSelector `json:"orLabelSelectors,omitempty"`
+
+	// Config map with the resource policies applied to the backup, e.g. skipping volumes
+	ResourcePolicy *v1.TypedLocalObjectReference `json:"resourcePolicy,omitempty"`
+
+	// Commands run in pods before and after backing them up
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+
+	// Cluster the backup was taken in, when recorded
+	SourceCluster *velero.SourceCluster `json:"sourceCluster,omitempty"`
+
+	// Pod volumes backed up by the file system uploader and the uploaders they used
+	VolumeBackups []VolumeBackup `json:"volumeBackups"`
+	UploaderTypes []string       `json:"uploaderTypes"`
+
+	// CSI volume snapshots Velero attempted and completed, and the snapshots it created as far as they can be read
+	CSIVolumeSnapshotsAttempted int           `json:"csiVolumeSnapshotsAttempted"`
+	CSIVolumeSnapshotsCompleted int           `json:"csiVolumeSnapshotsCompleted"`
+	CSISnapshots                []CSISnapshot `json:"csiSnapshots"`
+
+	// Latest request to delete the backup, nil when there is none
+	Deletion *deletebackuprequest.DeleteBackupRequest `json:"deletion,omitempty"`
+
+	// Volume data moved to object storage, nil when the backup has none
+	Size *BackupSize `json:"size,omitempty"`
 	
-	// Errors and warnings
-	Errors   []string `json:"errors,omitempty"`
-	Warnings []string `json:"warnings,omitempty"`
+	// Asynchronous item operations started by plugins, nil when there are none
+	ItemOperations *velero.ItemOperationsStatus `json:"itemOperations,omitempty"`
+
+	// Number of errors and warnings Velero encountered, details are in the backup results
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+
+	// SuggestedPollSeconds tells clients how often the backup is worth refreshing in its current phase
+	SuggestedPollSeconds int `json:"suggestedPollSeconds"`
 }
 
 // BackupProgress represents the progress of a backup operation.
@@ -68,20 +102,16 @@ type BackupProgress struct {
 
 // GetBackupDetail returns detailed information about a specific Velero backup.
 func GetBackupDetail(request *http.Request, namespace *common.NamespaceQuery, name string) (*BackupDetail, error) {
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
-	if err != nil {
-		return nil, err
-	}
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
 
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get the raw JSON data that contains the actual Velero backup information
-	rawBackupData, err := getRawBackupData(apiExtClient, config, namespace, name)
+	rawBackupData, err := getRawBackupData(ctx, dynamicClient, namespace, name)
 	if err != nil {
 		return nil, err
 	}
@@ -91,36 +121,31 @@ func GetBackupDetail(request *http.Request, namespace *common.NamespaceQuery, na
 	if err != nil {
 		return nil, err
 	}
-	
-	return backupDetail, nil
-}
 
-// getRawBackupData gets the raw JSON data for a specific Velero backup
-func getRawBackupData(apiExtClient apiextensionsclientset.Interface, config *rest.Config, namespace *common.NamespaceQuery, name string) ([]byte, error) {
-	// Get the backup CRD definition
-	customResourceDefinition, err := apiExtClient.ApiextensionsV1().
-		CustomResourceDefinitions().
-		Get(context.TODO(), "backups.velero.io", metav1.GetOptions{})
+	backupDetail.VolumeBackups, err = getVolumeBackups(ctx, dynamicClient, backupDetail.ObjectMeta.Namespace, name)
 	if err != nil {
 		return nil, err
 	}
+	backupDetail.UploaderTypes = getUploaderTypes(backupDetail.VolumeBackups)
+	backupDetail.CSISnapshots = getCSISnapshots(ctx, dynamicClient, name)
+	backupDetail.Deletion = getDeletion(ctx, dynamicClient, backupDetail.ObjectMeta.Namespace, name)
 
-	// Create REST client for the backup CRD
-	restClient, err := crdv1.NewRESTClient(config, customResourceDefinition)
+	backupDetail.Size, err = getBackupSize(ctx, dynamicClient, backupDetail.ObjectMeta.Namespace, name)
 	if err != nil {
 		return nil, err
 	}
+	
+	return backupDetail, nil
+}
 
-	// Get the raw backup data
-	raw, err := restClient.Get().
-		NamespaceIfScoped(namespace.ToRequestParam(), customResourceDefinition.Spec.Scope == apiextensionsv1.NamespaceScoped).
-		Resource(customResourceDefinition.Spec.Names.Plural).
-		Name(name).Do(context.TODO()).Raw()
+// getRawBackupData gets the raw JSON data for a specific Velero backup
+func getRawBackupData(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery, name string) ([]byte, error) {
+	item, err := client.Resource(velero.BackupGVR).Namespace(namespace.ToRequestParam()).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	return raw, nil
+	return item.MarshalJSON()
 }
 
 // parseBackupDetail parses raw JSON data into a BackupDetail struct
@@ -132,7 +157,7 @@ func parseBackupDetail(rawData []byte) (*BackupDetail, error) {
 	}
 
 	// Extract metadata
-	metadata := extractMetadata(rawBackup)
+	metadata := velero.NewObjectMeta(rawBackup)
 	
 	// Create backup detail with basic info
 	detail := &BackupDetail{
@@ -140,13 +165,13 @@ func parseBackupDetail(rawData []byte) (*BackupDetail, error) {
 		TypeMeta: dashboardtypes.TypeMeta{
 			Kind: "Backup",
 		},
+		SourceCluster: velero.NewSourceCluster(metadata.Annotations),
 	}
 
 	// Extract Velero-specific status information
 	if status, ok := rawBackup["status"].(map[string]interface{}); ok {
 		if phase, ok := status["phase"].(string); ok {
-			detail.Phase = phase
-			detail.Status = phase
+			detail.Phase = velero.BackupPhase(phase)
 		}
 		
 		if startTime, ok := status["startTimestamp"].(string); ok {
@@ -172,40 +197,77 @@ func parseBackupDetail(rawData []byte) (*BackupDetail, error) {
 				detail.ItemsBackedUp = int(itemsBackedUp)
 			}
 		}
+
+		if errors, ok := status["errors"].(float64); ok {
+			detail.Errors = int(errors)
+		}
+
+		if warnings, ok := status["warnings"].(float64); ok {
+			detail.Warnings = int(warnings)
+		}
+
+		if attempted, ok := status["csiVolumeSnapshotsAttempted"].(float64); ok {
+			detail.CSIVolumeSnapshotsAttempted = int(attempted)
+		}
+
+		if completed, ok := status["csiVolumeSnapshotsCompleted"].(float64); ok {
+			detail.CSIVolumeSnapshotsCompleted = int(completed)
+		}
+
+		detail.ItemOperations = velero.NewItemOperationsStatus(status, "backup")
 	}
 
+	detail.Status = detail.Phase.Status()
+	detail.SuggestedPollSeconds = suggestedPollSeconds(detail.Phase)
+
 	// Extract spec information
 	if spec, ok := rawBackup["spec"].(map[string]interface{}); ok {
 		if storageLocation, ok := spec["storageLocation"].(string); ok {
 			detail.StorageLocation = storageLocation
 		}
 		
-		// Extract included/excluded namespaces
-		if includedNS, ok := spec["includedNamespaces"].([]interface{}); ok {
-			for _, ns := range includedNS {
-				if nsStr, ok := ns.(string); ok {
-					detail.IncludedNamespaces = append(detail.IncludedNamespaces, nsStr)
+		// Extract included/excluded namespaces and resources
+		detail.IncludedNamespaces = velero.NewStringSlice(spec["includedNamespaces"])
+		detail.ExcludedNamespaces = velero.NewStringSlice(spec["excludedNamespaces"])
+		detail.IncludedResources = velero.NewStringSlice(spec["includedResources"])
+		detail.ExcludedResources = velero.NewStringSlice(spec["excludedResources"])
+
+		// Extract label selectors, objects match either the label selector or any of the or label selectors
+		detail.LabelSelector = toLabelSelector(spec["labelSelector"])
+		if orLabelSelectors, ok := spec["orLabelSelectors"].([]interface{}); ok {
+			for _, raw := range orLabelSelectors {
+				if selector := toLabelSelector(raw); selector != nil {
+					detail.OrLabelSelectors = append(detail.OrLabelSelectors, selector)
 				}
 			}
 		}
+
+		if resourcePolicy, ok := spec["resourcePolicy"].(map[string]interface{}); ok {
+			detail.ResourcePolicy = &v1.TypedLocalObjectReference{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resourcePolicy, detail.ResourcePolicy); err != nil {
+				detail.ResourcePolicy = nil
+			}
+		}
+
+		if hooks, ok := spec["hooks"].(map[string]interface{}); ok {
+			detail.Hooks = toBackupHooks(hooks)
+		}
 	}
 
 	return detail, nil
 }
 
-// extractMetadata extracts ObjectMeta from raw JSON
-func extractMetadata(rawBackup map[string]interface{}) dashboardtypes.ObjectMeta {
-	metadata := dashboardtypes.ObjectMeta{}
-	
-	if meta, ok := rawBackup["metadata"].(map[string]interface{}); ok {
-		if name, ok := meta["name"].(string); ok {
-			metadata.Name = name
-		}
-		if namespace, ok := meta["namespace"].(string); ok {
-			metadata.Namespace = namespace
-		}
-		// Add more metadata fields as needed
+// toLabelSelector converts a raw JSON label selector, nil when it is missing or malformed.
+func toLabelSelector(raw interface{}) *metav1.LabelSelector {
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
 	}
-	
-	return metadata
+
+	selector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, selector); err != nil {
+		return nil
+	}
+
+	return selector
 }
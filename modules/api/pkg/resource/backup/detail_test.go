@@ -0,0 +1,101 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseBackupDetailResults(t *testing.T) {
+	cases := []struct {
+		rawData          string
+		expectedErrors   int
+		expectedWarnings int
+	}{
+		{`{"metadata": {"name": "daily"}, "status": {"phase": "InProgress"}}`, 0, 0},
+		{`{"metadata": {"name": "daily"}, "status": {"phase": "PartiallyFailed", "errors": 3, "warnings": 12}}`, 3, 12},
+	}
+
+	for _, c := range cases {
+		actual, err := parseBackupDetail([]byte(c.rawData))
+		if err != nil {
+			t.Errorf("parseBackupDetail(%s) returned error: %s", c.rawData, err.Error())
+			continue
+		}
+
+		if actual.Errors != c.expectedErrors || actual.Warnings != c.expectedWarnings {
+			t.Errorf("parseBackupDetail(%s) == %d errors, %d warnings, expected %d errors, %d warnings", c.rawData,
+				actual.Errors, actual.Warnings, c.expectedErrors, c.expectedWarnings)
+		}
+	}
+}
+
+func TestParseBackupDetailCSIVolumeSnapshots(t *testing.T) {
+	rawData := `{"metadata": {"name": "daily"}, "status": {"phase": "Completed",
+		"csiVolumeSnapshotsAttempted": 3, "csiVolumeSnapshotsCompleted": 2}}`
+
+	actual, err := parseBackupDetail([]byte(rawData))
+	if err != nil {
+		t.Fatalf("parseBackupDetail(%s) returned error: %s", rawData, err.Error())
+	}
+
+	if actual.CSIVolumeSnapshotsAttempted != 3 || actual.CSIVolumeSnapshotsCompleted != 2 {
+		t.Errorf("parseBackupDetail(%s) == %d attempted, %d completed CSI volume snapshots, expected 3 attempted, "+
+			"2 completed", rawData, actual.CSIVolumeSnapshotsAttempted, actual.CSIVolumeSnapshotsCompleted)
+	}
+}
+
+func TestParseBackupDetailFilters(t *testing.T) {
+	rawData := `{"metadata": {"name": "daily"}, "spec": {
+		"includedNamespaces": ["shop"], "excludedNamespaces": ["kube-system"],
+		"includedResources": ["deployments", "secrets"], "excludedResources": ["events"],
+		"labelSelector": {"matchLabels": {"app": "db"}},
+		"orLabelSelectors": [{"matchLabels": {"tier": "web"}}, {"matchExpressions": [{"key": "env", "operator": "Exists"}]}],
+		"resourcePolicy": {"kind": "configmap", "name": "skip-volumes"}}}`
+	expected := &BackupDetail{
+		IncludedNamespaces: []string{"shop"},
+		ExcludedNamespaces: []string{"kube-system"},
+		IncludedResources:  []string{"deployments", "secrets"},
+		ExcludedResources:  []string{"events"},
+		LabelSelector:      &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+		OrLabelSelectors: []*metav1.LabelSelector{
+			{MatchLabels: map[string]string{"tier": "web"}},
+			{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: metav1.LabelSelectorOpExists}}},
+		},
+		ResourcePolicy: &v1.TypedLocalObjectReference{Kind: "configmap", Name: "skip-volumes"},
+	}
+
+	actual, err := parseBackupDetail([]byte(rawData))
+	if err != nil {
+		t.Fatalf("parseBackupDetail(%s) returned error: %s", rawData, err.Error())
+	}
+
+	actualFilters := &BackupDetail{
+		IncludedNamespaces: actual.IncludedNamespaces,
+		ExcludedNamespaces: actual.ExcludedNamespaces,
+		IncludedResources:  actual.IncludedResources,
+		ExcludedResources:  actual.ExcludedResources,
+		LabelSelector:      actual.LabelSelector,
+		OrLabelSelectors:   actual.OrLabelSelectors,
+		ResourcePolicy:     actual.ResourcePolicy,
+	}
+	if !reflect.DeepEqual(actualFilters, expected) {
+		t.Errorf("parseBackupDetail(%s) == %#v, expected %#v", rawData, actualFilters, expected)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// ignoredMetadataFields are set by the API server and differ between backups of an unchanged resource.
+var ignoredMetadataFields = []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields",
+	"selfLink", "ownerReferences"}
+
+// BackupDiff tells how the resources stored in a backup differ from the ones of an earlier backup, e.g. two runs of
+// the same schedule.
+type BackupDiff struct {
+	// Base is the earlier backup the backup named Target is compared to.
+	Base   string `json:"base"`
+	Target string `json:"target"`
+
+	Added   []DiffItem `json:"added"`
+	Removed []DiffItem `json:"removed"`
+	Changed []DiffItem `json:"changed"`
+
+	// Unchanged is the number of resources stored the same way in both backups.
+	Unchanged int `json:"unchanged"`
+}
+
+// DiffItem is a resource that differs between two backups.
+type DiffItem struct {
+	// Resource with its group, e.g. deployments.apps.
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// Fields that differ, e.g. spec or metadata.labels. Only set for changed resources.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// diffKey identifies a resource in the contents of a backup.
+type diffKey struct {
+	resource  string
+	namespace string
+	name      string
+}
+
+// DiffBackups downloads the contents of both backups and compares the resources stored in them. Fields set by the API
+// server and the status are left out of the comparison.
+func DiffBackups(request *http.Request, namespace, base, target string) (*BackupDiff, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	if base == "" {
+		return nil, errors.NewBadRequest("name of the base backup is required")
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	baseContents, err := getBackupContents(ctx, dynamicClient, namespace, base)
+	if err != nil {
+		return nil, err
+	}
+
+	targetContents, err := getBackupContents(ctx, dynamicClient, namespace, target)
+	if err != nil {
+		return nil, err
+	}
+
+	result := diffContents(baseContents, targetContents)
+	result.Base, result.Target = base, target
+	return result, nil
+}
+
+func getBackupContents(ctx context.Context, client dynamic.Interface, namespace, name string) (map[diffKey]map[string]interface{}, error) {
+	data, err := velero.Download(ctx, client, namespace, velero.DownloadTargetKindBackupContents, name)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := parseBackupContents(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read contents of backup %s: %w", name, err)
+	}
+
+	return contents, nil
+}
+
+// parseBackupContents reads the resources of a backup tarball, stored as resources/<resource>/namespaces/<namespace>/
+// <name>.json or resources/<resource>/cluster/<name>.json. Copies of resources stored at other API versions, in
+// directories named after the version, are skipped.
+func parseBackupContents(data []byte) (map[diffKey]map[string]interface{}, error) {
+	result := make(map[diffKey]map[string]interface{})
+	reader := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := reader.Next()
+		if stderrors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := toDiffKey(header.Name)
+		if !ok || header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		object := make(map[string]interface{})
+		if err := json.NewDecoder(reader).Decode(&object); err != nil {
+			return nil, fmt.Errorf("%s: %s", header.Name, err.Error())
+		}
+		result[key] = normalizeObject(object)
+	}
+
+	return result, nil
+}
+
+func toDiffKey(name string) (diffKey, bool) {
+	parts := strings.Split(strings.TrimPrefix(path.Clean(name), "/"), "/")
+	if len(parts) < 4 || parts[0] != "resources" || path.Ext(name) != ".json" {
+		return diffKey{}, false
+	}
+
+	file := strings.TrimSuffix(parts[len(parts)-1], ".json")
+	switch {
+	case len(parts) == 5 && parts[2] == "namespaces":
+		return diffKey{resource: parts[1], namespace: parts[3], name: file}, true
+	case len(parts) == 4 && parts[2] == "cluster":
+		return diffKey{resource: parts[1], name: file}, true
+	default:
+		return diffKey{}, false
+	}
+}
+
+func normalizeObject(object map[string]interface{}) map[string]interface{} {
+	delete(object, "status")
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		for _, field := range ignoredMetadataFields {
+			delete(metadata, field)
+		}
+	}
+
+	return object
+}
+
+func diffContents(base, target map[diffKey]map[string]interface{}) *BackupDiff {
+	result := &BackupDiff{Added: make([]DiffItem, 0), Removed: make([]DiffItem, 0), Changed: make([]DiffItem, 0)}
+	for key, object := range target {
+		baseObject, found := base[key]
+		switch {
+		case !found:
+			result.Added = append(result.Added, toDiffItem(key, nil))
+		case !reflect.DeepEqual(baseObject, object):
+			result.Changed = append(result.Changed, toDiffItem(key, getChangedFields(baseObject, object)))
+		default:
+			result.Unchanged++
+		}
+	}
+
+	for key := range base {
+		if _, found := target[key]; !found {
+			result.Removed = append(result.Removed, toDiffItem(key, nil))
+		}
+	}
+
+	for _, items := range [][]DiffItem{result.Added, result.Removed, result.Changed} {
+		sortDiffItems(items)
+	}
+
+	return result
+}
+
+// getChangedFields returns the top level fields that differ, with metadata fields named individually.
+func getChangedFields(base, target map[string]interface{}) []string {
+	result := make([]string, 0)
+	for _, field := range unionKeys(base, target) {
+		baseMetadata, baseOk := base[field].(map[string]interface{})
+		targetMetadata, targetOk := target[field].(map[string]interface{})
+		if field == "metadata" && baseOk && targetOk {
+			for _, metadataField := range unionKeys(baseMetadata, targetMetadata) {
+				if !reflect.DeepEqual(baseMetadata[metadataField], targetMetadata[metadataField]) {
+					result = append(result, "metadata."+metadataField)
+				}
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(base[field], target[field]) {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, found := a[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func toDiffItem(key diffKey, fields []string) DiffItem {
+	return DiffItem{Resource: key.resource, Namespace: key.namespace, Name: key.name, Fields: fields}
+}
+
+func sortDiffItems(items []DiffItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Resource != items[j].Resource {
+			return items[i].Resource < items[j].Resource
+		}
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func newTestBackupContents(t *testing.T, files map[string]string) []byte {
+	buffer := &bytes.Buffer{}
+	writer := tar.NewWriter(buffer)
+	for name, content := range files {
+		if err := writer.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestDiffBackupContents(t *testing.T) {
+	base, err := parseBackupContents(newTestBackupContents(t, map[string]string{
+		"metadata/version": "1",
+		"resources/deployments.apps/namespaces/shop/web.json": `{"metadata": {"name": "web", "uid": "1", "labels": {"tier": "web"}},
+			"spec": {"replicas": 2}, "status": {"readyReplicas": 2}}`,
+		"resources/deployments.apps/v1-preferredversion/namespaces/shop/web.json": `{"metadata": {"name": "web"}}`,
+		"resources/configmaps/namespaces/shop/flags.json":                         `{"metadata": {"name": "flags", "resourceVersion": "10"}, "data": {"a": "1"}}`,
+		"resources/configmaps/namespaces/shop/legacy.json":                        `{"metadata": {"name": "legacy"}}`,
+		"resources/namespaces/cluster/shop.json":                                  `{"metadata": {"name": "shop", "uid": "2"}}`,
+	}))
+	if err != nil {
+		t.Fatalf("parseBackupContents() returned error: %s", err.Error())
+	}
+
+	target, err := parseBackupContents(newTestBackupContents(t, map[string]string{
+		"resources/deployments.apps/namespaces/shop/web.json": `{"metadata": {"name": "web", "uid": "3", "labels": {"tier": "frontend"}},
+			"spec": {"replicas": 3}, "status": {"readyReplicas": 1}}`,
+		"resources/configmaps/namespaces/shop/flags.json": `{"metadata": {"name": "flags", "resourceVersion": "12"}, "data": {"a": "1"}}`,
+		"resources/configmaps/namespaces/shop/new.json":   `{"metadata": {"name": "new"}}`,
+		"resources/namespaces/cluster/shop.json":          `{"metadata": {"name": "shop", "uid": "4"}}`,
+	}))
+	if err != nil {
+		t.Fatalf("parseBackupContents() returned error: %s", err.Error())
+	}
+
+	expected := &BackupDiff{
+		Added:   []DiffItem{{Resource: "configmaps", Namespace: "shop", Name: "new"}},
+		Removed: []DiffItem{{Resource: "configmaps", Namespace: "shop", Name: "legacy"}},
+		Changed: []DiffItem{
+			{Resource: "deployments.apps", Namespace: "shop", Name: "web", Fields: []string{"metadata.labels", "spec"}},
+		},
+		Unchanged: 2,
+	}
+
+	actual := diffContents(base, target)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("diffContents() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestParseBackupContentsInvalidJSON(t *testing.T) {
+	data := newTestBackupContents(t, map[string]string{"resources/configmaps/namespaces/shop/flags.json": "{"})
+	if _, err := parseBackupContents(data); err == nil {
+		t.Errorf("parseBackupContents() of invalid JSON returned no error")
+	}
+}
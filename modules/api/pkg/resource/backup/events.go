@@ -0,0 +1,28 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"net/http"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// GetBackupEvents returns events of the Velero backup with the given name, e.g. validation failures and controller errors.
+func GetBackupEvents(request *http.Request, namespace, name string, dsQuery *dataselect.DataSelectQuery) (*common.EventList, error) {
+	return velero.GetEvents(request, "Backup", namespace, name, dsQuery)
+}
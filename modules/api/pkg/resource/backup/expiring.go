@@ -0,0 +1,80 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// DefaultExpiringWithin is how soon backups have to expire to be listed when the caller does not ask for a specific
+// window.
+const DefaultExpiringWithin = "72h"
+
+// ExpiringBackupList contains backups expiring within the window, the ones expiring first at the top.
+type ExpiringBackupList struct {
+	Within string   `json:"within"`
+	Items  []Backup `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetExpiringBackups returns backups in namespaces matching the query whose expiration falls within the window, e.g.
+// "72h", so that their TTL can be extended or new backups taken before they are garbage collected.
+func GetExpiringBackups(request *http.Request, namespace *common.NamespaceQuery, within string) (*ExpiringBackupList, error) {
+	duration, err := ParseWindow(within)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := GetBackupList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &ExpiringBackupList{
+		Within: within,
+		Items:  FilterExpiringBackups(backups.Items, now, now.Add(duration)),
+		Errors: backups.Errors,
+	}, nil
+}
+
+// FilterExpiringBackups returns backups expiring after now and before until, sorted by expiration. Backups already
+// being deleted are left out.
+func FilterExpiringBackups(backups []Backup, now, until time.Time) []Backup {
+	result := make([]Backup, 0)
+	for _, item := range backups {
+		if item.Expiration == nil || item.Phase.Status() == velero.StatusDeleting {
+			continue
+		}
+
+		if item.Expiration.Time.After(now) && !item.Expiration.Time.After(until) {
+			result = append(result, item)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Expiration.Before(result[j].Expiration)
+	})
+
+	return result
+}
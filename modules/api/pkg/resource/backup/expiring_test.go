@@ -0,0 +1,47 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+func TestFilterExpiringBackups(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	newExpiringBackup := func(name string, phase velero.BackupPhase, expiresIn time.Duration) Backup {
+		expiration := metav1.NewTime(now.Add(expiresIn))
+		return Backup{ObjectMeta: types.ObjectMeta{Name: name, Namespace: "velero"}, Phase: phase, Expiration: &expiration}
+	}
+
+	soon := newExpiringBackup("soon", velero.BackupPhaseCompleted, 24*time.Hour)
+	sooner := newExpiringBackup("sooner", velero.BackupPhaseCompleted, time.Hour)
+	later := newExpiringBackup("later", velero.BackupPhaseCompleted, 30*24*time.Hour)
+	expired := newExpiringBackup("expired", velero.BackupPhaseCompleted, -time.Hour)
+	deleting := newExpiringBackup("deleting", velero.BackupPhaseDeleting, time.Hour)
+	unset := Backup{ObjectMeta: types.ObjectMeta{Name: "unset", Namespace: "velero"}, Phase: velero.BackupPhaseCompleted}
+
+	expected := []Backup{sooner, soon}
+	actual := FilterExpiringBackups([]Backup{soon, later, expired, deleting, unset, sooner}, now, now.Add(72*time.Hour))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("FilterExpiringBackups() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// ExportFormat is the file format of the backup inventory.
+type ExportFormat string
+
+const (
+	// ExportFormatCSV writes a header row followed by a row per backup.
+	ExportFormatCSV ExportFormat = "csv"
+	// ExportFormatNDJSON writes a JSON object per backup and line.
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+// ContentType returns the media type of the format.
+func (in ExportFormat) ContentType() string {
+	if in == ExportFormatNDJSON {
+		return "application/x-ndjson"
+	}
+
+	return "text/csv"
+}
+
+// inventoryColumns are the CSV columns, in the order of the fields of BackupInventoryItem.
+var inventoryColumns = []string{"namespace", "name", "phase", "schedule", "storageLocation", "startTime",
+	"completionTime", "durationSeconds", "expiration", "itemsBackedUp", "totalItems", "errors", "warnings"}
+
+// BackupInventoryItem is a single backup of the exported inventory. Times are in RFC 3339 and empty when unset.
+type BackupInventoryItem struct {
+	Namespace       string             `json:"namespace"`
+	Name            string             `json:"name"`
+	Phase           velero.BackupPhase `json:"phase"`
+	Schedule        string             `json:"schedule"`
+	StorageLocation string             `json:"storageLocation"`
+	StartTime       string             `json:"startTime"`
+	CompletionTime  string             `json:"completionTime"`
+	DurationSeconds int64              `json:"durationSeconds"`
+	Expiration      string             `json:"expiration"`
+	ItemsBackedUp   int64              `json:"itemsBackedUp"`
+	TotalItems      int64              `json:"totalItems"`
+	Errors          int64              `json:"errors"`
+	Warnings        int64              `json:"warnings"`
+}
+
+// ExportBackups writes the backups in namespaces matching the query in the given format, filtered and sorted by the
+// data select query. Non-critical errors, e.g. the list being truncated, are returned once the inventory is written.
+func ExportBackups(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery, format ExportFormat, writer io.Writer) ([]error, error) {
+	if format != ExportFormatCSV && format != ExportFormatNDJSON {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid export format %q, expected %s or %s", format,
+			ExportFormatCSV, ExportFormatNDJSON))
+	}
+
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	items, nonCriticalErrors, err := getBackups(ctx, dynamicClient, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeInventory(writer, toInventory(items, dsQuery), format); err != nil {
+		return nil, err
+	}
+
+	return nonCriticalErrors, nil
+}
+
+// toInventory selects backups the same way the backup list does and returns them as inventory items.
+func toInventory(items []unstructured.Unstructured, dsQuery *dataselect.DataSelectQuery) []BackupInventoryItem {
+	raw := make(map[string]unstructured.Unstructured, len(items))
+	for _, item := range items {
+		raw[item.GetNamespace()+"/"+item.GetName()] = item
+	}
+
+	selected := toBackupList(items, nil, dsQuery).Items
+	inventory := make([]BackupInventoryItem, 0, len(selected))
+	for _, backup := range selected {
+		inventory = append(inventory, toInventoryItem(raw[backup.ObjectMeta.Namespace+"/"+backup.ObjectMeta.Name]))
+	}
+
+	return inventory
+}
+
+func toInventoryItem(item unstructured.Unstructured) BackupInventoryItem {
+	backup := toBackup(item)
+	result := BackupInventoryItem{
+		Namespace:      item.GetNamespace(),
+		Name:           item.GetName(),
+		Phase:          backup.Phase,
+		Schedule:       item.GetLabels()[ScheduleNameLabel],
+		StartTime:      formatTime(backup.StartTime),
+		CompletionTime: formatTime(backup.CompletionTime),
+		Expiration:     formatTime(backup.Expiration),
+	}
+
+	result.StorageLocation, _, _ = unstructured.NestedString(item.Object, "spec", "storageLocation")
+	result.ItemsBackedUp, _, _ = unstructured.NestedInt64(item.Object, "status", "progress", "itemsBackedUp")
+	result.TotalItems, _, _ = unstructured.NestedInt64(item.Object, "status", "progress", "totalItems")
+	result.Errors, _, _ = unstructured.NestedInt64(item.Object, "status", "errors")
+	result.Warnings, _, _ = unstructured.NestedInt64(item.Object, "status", "warnings")
+
+	if backup.StartTime != nil && backup.CompletionTime != nil {
+		result.DurationSeconds = int64(backup.CompletionTime.Sub(backup.StartTime.Time) / time.Second)
+	}
+
+	return result
+}
+
+func formatTime(value *metav1.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.UTC().Format(time.RFC3339)
+}
+
+func writeInventory(writer io.Writer, inventory []BackupInventoryItem, format ExportFormat) error {
+	if format == ExportFormatNDJSON {
+		encoder := json.NewEncoder(writer)
+		for _, item := range inventory {
+			if err := encoder.Encode(item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(inventoryColumns); err != nil {
+		return err
+	}
+
+	for _, item := range inventory {
+		err := csvWriter.Write([]string{
+			item.Namespace, item.Name, string(item.Phase), item.Schedule, item.StorageLocation, item.StartTime,
+			item.CompletionTime, strconv.FormatInt(item.DurationSeconds, 10), item.Expiration,
+			strconv.FormatInt(item.ItemsBackedUp, 10), strconv.FormatInt(item.TotalItems, 10),
+			strconv.FormatInt(item.Errors, 10), strconv.FormatInt(item.Warnings, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+)
+
+func newInventoryTestBackups() []unstructured.Unstructured {
+	return []unstructured.Unstructured{
+		{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":      "daily-20261017010000",
+				"namespace": "velero",
+				"labels":    map[string]interface{}{ScheduleNameLabel: "daily"},
+			},
+			"spec": map[string]interface{}{"storageLocation": "default"},
+			"status": map[string]interface{}{
+				"phase":               "Completed",
+				"startTimestamp":      "2026-10-17T01:00:00Z",
+				"completionTimestamp": "2026-10-17T01:02:30Z",
+				"expiration":          "2026-11-16T01:00:00Z",
+				"progress":            map[string]interface{}{"itemsBackedUp": int64(120), "totalItems": int64(120)},
+				"warnings":            int64(2),
+			},
+		}},
+		{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "manual", "namespace": "velero"},
+			"status":   map[string]interface{}{"phase": "New"},
+		}},
+	}
+}
+
+func TestWriteInventory(t *testing.T) {
+	inventory := toInventory(newInventoryTestBackups(), dataselect.NoDataSelect)
+
+	cases := []struct {
+		format   ExportFormat
+		expected string
+	}{
+		{
+			ExportFormatCSV,
+			"namespace,name,phase,schedule,storageLocation,startTime,completionTime,durationSeconds,expiration," +
+				"itemsBackedUp,totalItems,errors,warnings\n" +
+				"velero,daily-20261017010000,Completed,daily,default,2026-10-17T01:00:00Z,2026-10-17T01:02:30Z,150," +
+				"2026-11-16T01:00:00Z,120,120,0,2\n" +
+				"velero,manual,New,,,,,0,,0,0,0,0\n",
+		},
+		{
+			ExportFormatNDJSON,
+			`{"namespace":"velero","name":"daily-20261017010000","phase":"Completed","schedule":"daily",` +
+				`"storageLocation":"default","startTime":"2026-10-17T01:00:00Z","completionTime":"2026-10-17T01:02:30Z",` +
+				`"durationSeconds":150,"expiration":"2026-11-16T01:00:00Z","itemsBackedUp":120,"totalItems":120,` +
+				`"errors":0,"warnings":2}` + "\n" +
+				`{"namespace":"velero","name":"manual","phase":"New","schedule":"","storageLocation":"","startTime":"",` +
+				`"completionTime":"","durationSeconds":0,"expiration":"","itemsBackedUp":0,"totalItems":0,"errors":0,` +
+				`"warnings":0}` + "\n",
+		},
+	}
+
+	for _, c := range cases {
+		var buffer bytes.Buffer
+		if err := writeInventory(&buffer, inventory, c.format); err != nil {
+			t.Fatalf("writeInventory(%s) returned error: %s", c.format, err.Error())
+		}
+
+		if actual := buffer.String(); actual != c.expected {
+			t.Errorf("writeInventory(%s) == \n%s\nexpected \n%s\n", c.format, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// DefaultHeatmapPeriod is the period covered by the heatmap when the caller does not ask for a specific one.
+const DefaultHeatmapPeriod = "30d"
+
+// BackupHeatmap contains backup activity bucketed by day of week and hour of day.
+type BackupHeatmap struct {
+	Period   string      `json:"period"`
+	Since    metav1.Time `json:"since"`
+	TimeZone string      `json:"timeZone"`
+	Total    int         `json:"total"`
+
+	// Cells holds one entry per day of week and hour, ordered from Sunday 00:00 to Saturday 23:00.
+	Cells []HeatmapCell `json:"cells"`
+}
+
+// HeatmapCell contains number of backups started in a single hour of a day of week, split by outcome.
+type HeatmapCell struct {
+	// DayOfWeek is 0 for Sunday, as in time.Weekday.
+	DayOfWeek       int `json:"dayOfWeek"`
+	Hour            int `json:"hour"`
+	Total           int `json:"total"`
+	Completed       int `json:"completed"`
+	PartiallyFailed int `json:"partiallyFailed"`
+	Failed          int `json:"failed"`
+	Other           int `json:"other"`
+}
+
+// GetBackupHeatmap returns backup counts and outcomes over the period bucketed by hour of day and day of week in
+// the given time zone.
+func GetBackupHeatmap(request *http.Request, namespace *common.NamespaceQuery, period, timeZone string) (*BackupHeatmap, error) {
+	duration, err := ParseWindow(period)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid time zone %q: %s", timeZone, err.Error()))
+	}
+
+	backups, err := GetBackupList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := toBackupHeatmap(backups.Items, time.Now().Add(-duration), location)
+	heatmap.Period = period
+	return heatmap, nil
+}
+
+func toBackupHeatmap(backups []Backup, since time.Time, location *time.Location) *BackupHeatmap {
+	heatmap := &BackupHeatmap{
+		Since:    metav1.NewTime(since),
+		TimeZone: location.String(),
+		Cells:    make([]HeatmapCell, 7*24),
+	}
+
+	for i := range heatmap.Cells {
+		heatmap.Cells[i].DayOfWeek = i / 24
+		heatmap.Cells[i].Hour = i % 24
+	}
+
+	for _, backup := range backups {
+		started := backup.ObjectMeta.CreationTimestamp.Time
+		if backup.StartTime != nil {
+			started = backup.StartTime.Time
+		}
+		if started.Before(since) {
+			continue
+		}
+
+		started = started.In(location)
+		cell := &heatmap.Cells[int(started.Weekday())*24+started.Hour()]
+		switch backup.Phase {
+		case velero.BackupPhaseCompleted:
+			cell.Completed++
+		case velero.BackupPhasePartiallyFailed:
+			cell.PartiallyFailed++
+		case velero.BackupPhaseFailed, velero.BackupPhaseFailedValidation:
+			cell.Failed++
+		default:
+			cell.Other++
+		}
+		cell.Total++
+		heatmap.Total++
+	}
+
+	return heatmap
+}
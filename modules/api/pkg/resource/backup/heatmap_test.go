@@ -0,0 +1,77 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/types"
+)
+
+func TestToBackupHeatmap(t *testing.T) {
+	// 2024-03-04 is a Monday.
+	monday := time.Date(2024, 3, 4, 1, 30, 0, 0, time.UTC)
+	started := metav1.NewTime(monday.Add(time.Hour))
+	backups := []Backup{
+		{ObjectMeta: types.ObjectMeta{CreationTimestamp: metav1.NewTime(monday)}, Phase: "Completed"},
+		{ObjectMeta: types.ObjectMeta{CreationTimestamp: metav1.NewTime(monday)}, Phase: "Failed"},
+		{ObjectMeta: types.ObjectMeta{CreationTimestamp: metav1.NewTime(monday)}, Phase: "PartiallyFailed", StartTime: &started},
+		{ObjectMeta: types.ObjectMeta{CreationTimestamp: metav1.NewTime(monday.Add(-48 * time.Hour))}, Phase: "Completed"},
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		location *time.Location
+		expected map[int]HeatmapCell
+	}{
+		{
+			time.UTC,
+			map[int]HeatmapCell{
+				1*24 + 1: {DayOfWeek: 1, Hour: 1, Total: 2, Completed: 1, Failed: 1},
+				1*24 + 2: {DayOfWeek: 1, Hour: 2, Total: 1, PartiallyFailed: 1},
+			},
+		},
+		{
+			berlin,
+			map[int]HeatmapCell{
+				1*24 + 2: {DayOfWeek: 1, Hour: 2, Total: 2, Completed: 1, Failed: 1},
+				1*24 + 3: {DayOfWeek: 1, Hour: 3, Total: 1, PartiallyFailed: 1},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toBackupHeatmap(backups, monday.Add(-time.Hour), c.location)
+		if actual.Total != 3 || len(actual.Cells) != 7*24 {
+			t.Errorf("toBackupHeatmap() returned %d backups in %d cells, expected 3 in %d", actual.Total, len(actual.Cells), 7*24)
+		}
+		for i, cell := range actual.Cells {
+			expected, ok := c.expected[i]
+			if !ok {
+				expected = HeatmapCell{DayOfWeek: i / 24, Hour: i % 24}
+			}
+			if !reflect.DeepEqual(cell, expected) {
+				t.Errorf("toBackupHeatmap() cell %d in %s == %#v, expected %#v", i, c.location, cell, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// BackupHooks are commands Velero runs in pods before and after backing them up, e.g. to make a database flush its
+// data to disk. Field names follow the Velero backup spec.
+type BackupHooks struct {
+	Resources []BackupResourceHookSpec `json:"resources,omitempty"`
+}
+
+// BackupResourceHookSpec selects the pods hooks are run in.
+type BackupResourceHookSpec struct {
+	Name               string                `json:"name"`
+	IncludedNamespaces []string              `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string              `json:"excludedNamespaces,omitempty"`
+	IncludedResources  []string              `json:"includedResources,omitempty"`
+	ExcludedResources  []string              `json:"excludedResources,omitempty"`
+	LabelSelector      *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	PreHooks           []BackupResourceHook  `json:"pre,omitempty"`
+	PostHooks          []BackupResourceHook  `json:"post,omitempty"`
+}
+
+// BackupResourceHook is a single hook. Velero only supports exec hooks for backups.
+type BackupResourceHook struct {
+	Exec *ExecHook `json:"exec"`
+}
+
+// ExecHook runs a command in a container of the pod. The first container is used when none is set.
+type ExecHook struct {
+	Container string               `json:"container,omitempty"`
+	Command   []string             `json:"command"`
+	OnError   velero.HookErrorMode `json:"onError,omitempty"`
+	Timeout   string               `json:"timeout,omitempty"`
+}
+
+// validateHooks rejects hooks Velero would fail to run, as Velero only validates them when the backup starts.
+func validateHooks(hooks *BackupHooks) error {
+	for _, resource := range hooks.Resources {
+		if resource.Name == "" {
+			return errors.NewBadRequest("backup hook name is required")
+		}
+
+		for _, hook := range append(resource.PreHooks, resource.PostHooks...) {
+			if err := validateExecHook(resource.Name, hook.Exec); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateExecHook(name string, hook *ExecHook) error {
+	if hook == nil || len(hook.Command) == 0 {
+		return errors.NewBadRequest(fmt.Sprintf("backup hook %s requires an exec command", name))
+	}
+
+	if !hook.OnError.IsValid() {
+		return errors.NewBadRequest(fmt.Sprintf("invalid onError %q of backup hook %s, expected %s or %s", hook.OnError,
+			name, velero.HookErrorModeContinue, velero.HookErrorModeFail))
+	}
+
+	if hook.Timeout != "" {
+		if _, err := time.ParseDuration(hook.Timeout); err != nil {
+			return errors.NewBadRequest(fmt.Sprintf("invalid timeout %q of backup hook %s: %s", hook.Timeout, name,
+				err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// toBackupHooks reads hooks from the spec of a backup, returning nil when there are none.
+func toBackupHooks(rawHooks map[string]interface{}) *BackupHooks {
+	hooks := &BackupHooks{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawHooks, hooks); err != nil || len(hooks.Resources) == 0 {
+		return nil
+	}
+
+	return hooks
+}
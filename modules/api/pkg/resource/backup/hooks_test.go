@@ -0,0 +1,93 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+func TestValidateHooks(t *testing.T) {
+	fsfreeze := []string{"/sbin/fsfreeze", "--freeze", "/var/lib/postgresql"}
+	cases := []struct {
+		info    string
+		hooks   *BackupHooks
+		isError bool
+	}{
+		{
+			"valid hooks",
+			&BackupHooks{Resources: []BackupResourceHookSpec{{
+				Name:      "postgres",
+				PreHooks:  []BackupResourceHook{{Exec: &ExecHook{Container: "db", Command: fsfreeze, OnError: velero.HookErrorModeFail, Timeout: "30s"}}},
+				PostHooks: []BackupResourceHook{{Exec: &ExecHook{Command: []string{"/sbin/fsfreeze", "--unfreeze", "/var/lib/postgresql"}}}},
+			}}},
+			false,
+		},
+		{
+			"missing name",
+			&BackupHooks{Resources: []BackupResourceHookSpec{{PreHooks: []BackupResourceHook{{Exec: &ExecHook{Command: fsfreeze}}}}}},
+			true,
+		},
+		{
+			"missing command",
+			&BackupHooks{Resources: []BackupResourceHookSpec{{Name: "postgres", PostHooks: []BackupResourceHook{{Exec: &ExecHook{}}}}}},
+			true,
+		},
+		{
+			"invalid error mode",
+			&BackupHooks{Resources: []BackupResourceHookSpec{{Name: "postgres", PreHooks: []BackupResourceHook{{Exec: &ExecHook{Command: fsfreeze, OnError: "Ignore"}}}}}},
+			true,
+		},
+		{
+			"invalid timeout",
+			&BackupHooks{Resources: []BackupResourceHookSpec{{Name: "postgres", PreHooks: []BackupResourceHook{{Exec: &ExecHook{Command: fsfreeze, Timeout: "soon"}}}}}},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		err := validateHooks(c.hooks)
+		if (err != nil) != c.isError {
+			t.Errorf("%s: validateHooks() == %v, expected error: %t", c.info, err, c.isError)
+		}
+	}
+}
+
+func TestParseBackupDetailHooks(t *testing.T) {
+	rawData := `{"metadata": {"name": "daily"}, "spec": {"hooks": {"resources": [{"name": "postgres",
+		"includedNamespaces": ["shop"], "pre": [{"exec": {"container": "db", "command": ["psql", "-c", "CHECKPOINT"],
+		"onError": "Fail", "timeout": "1m"}}]}]}}}`
+	expected := &BackupHooks{Resources: []BackupResourceHookSpec{{
+		Name:               "postgres",
+		IncludedNamespaces: []string{"shop"},
+		PreHooks: []BackupResourceHook{{Exec: &ExecHook{
+			Container: "db",
+			Command:   []string{"psql", "-c", "CHECKPOINT"},
+			OnError:   velero.HookErrorModeFail,
+			Timeout:   "1m",
+		}}},
+	}}}
+
+	actual, err := parseBackupDetail([]byte(rawData))
+	if err != nil {
+		t.Fatalf("parseBackupDetail() returned error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(actual.Hooks, expected) {
+		t.Errorf("parseBackupDetail() hooks == \n%#v\nexpected \n%#v\n", actual.Hooks, expected)
+	}
+}
@@ -15,70 +15,170 @@
 package backup
 
 import (
+	"context"
 	"net/http"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
 	"k8s.io/dashboard/api/pkg/resource/common"
-	"k8s.io/dashboard/api/pkg/resource/customresourcedefinition"
 	"k8s.io/dashboard/api/pkg/resource/dataselect"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/deletebackuprequest"
+	"k8s.io/dashboard/api/pkg/resource/subscription"
+	"k8s.io/dashboard/api/pkg/resource/velero"
 	"k8s.io/dashboard/types"
 )
 
 // BackupList contains a list of Backup resources in the cluster.
 type BackupList struct {
 	ListMeta types.ListMeta `json:"listMeta"`
-	Items    []Backup       `json:"items"`
+
+	// Basic information about resources status on the list.
+	Status velero.ListStatus `json:"status"`
+	Items  []Backup          `json:"items"`
+
+	// MetadataOnly is set when the backups were listed without their spec and status, leaving out the phase, times
+	// and status of every backup.
+	MetadataOnly bool `json:"metadataOnly,omitempty"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
 }
 
 // Backup represents a Velero backup resource.
 type Backup struct {
 	ObjectMeta types.ObjectMeta `json:"objectMeta"`
 	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	Phase          velero.BackupPhase `json:"phase,omitempty"`
+	StartTime      *metav1.Time       `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time       `json:"completionTime,omitempty"`
+	Expiration     *metav1.Time       `json:"expiration,omitempty"`
+
+	// Size is only set by GetBackupListWithSizes, for backups with volume data.
+	Size *BackupSize `json:"size,omitempty"`
+
+	// Deletion is the latest request to delete the backup, nil when there is none.
+	Deletion *deletebackuprequest.DeleteBackupRequest `json:"deletion,omitempty"`
+
+	// Errors is only set by CreateBackup, when the creation could not be recorded in the audit log.
+	Errors []error `json:"errors,omitempty"`
 }
 
 // GetBackupList returns a list of all Backup resources in the cluster.
 func GetBackupList(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*BackupList, error) {
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
+	return GetBackupListWithSelector(request, namespace, "", dsQuery)
+}
+
+// GetBackupListWithSelector returns a list of Backup resources matching the given label selector.
+func GetBackupListWithSelector(request *http.Request, namespace *common.NamespaceQuery, selector string, dsQuery *dataselect.DataSelectQuery) (*BackupList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get REST config for custom resource operations  
-	config, err := client.Config(request)
+	items, nonCriticalErrors, err := getBackups(ctx, dynamicClient, namespace, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return nil, err
 	}
 
-	// Use dashboard's CRD framework to get Velero backup objects
-	crdObjects, err := customresourcedefinition.GetCustomResourceObjectList(
-		apiExtClient, 
-		config, 
-		namespace, 
-		dsQuery, 
-		"backups.velero.io",
-	)
+	list := toBackupList(items, nonCriticalErrors, dsQuery)
+	list.Errors = append(list.Errors, addDeletions(ctx, dynamicClient, namespace, list.Items)...)
+	return list, nil
+}
+
+// GetBackupMetadataList returns a list of all Backup resources in the cluster, read without their spec and status.
+// Only names, namespaces, labels and creation times are set, which is enough to page through very large backup sets.
+func GetBackupMetadataList(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*BackupList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	metadataClient, err := velero.MetadataClient(request)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert CRD objects to Backup structs
-	items := make([]Backup, 0, len(crdObjects.Items))
-	for _, item := range crdObjects.Items {
-		backup := Backup{
-			ObjectMeta: types.ObjectMeta{
-				Name:      item.ObjectMeta.Name,
-				Namespace: item.ObjectMeta.Namespace,
-			},
-			TypeMeta: types.TypeMeta{
-				Kind: "Backup",
-			},
-		}
-		items = append(items, backup)
+	items, nonCriticalErrors, err := getBackups(ctx, metadataClient, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toBackupMetadataList(items, nonCriticalErrors, dsQuery), nil
+}
+
+// getBackups lists Velero backups matching the list options. Backups beyond the configured limit and namespaces the
+// user may not read are left out and reported as non-critical errors.
+func getBackups(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery, options metav1.ListOptions) ([]unstructured.Unstructured, []error, error) {
+	return velero.ListInNamespaces(ctx, client, velero.BackupGVR, namespace, options,
+		args.VeleroMaxListItems())
+}
+
+func toBackupList(items []unstructured.Unstructured, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *BackupList {
+	backups := make([]Backup, 0, len(items))
+	for _, item := range items {
+		backups = append(backups, toBackup(item))
+	}
+
+	backupCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(backups), dsQuery)
+	return &BackupList{
+		ListMeta: types.ListMeta{TotalItems: filteredTotal},
+		Status:   getBackupListStatus(backups),
+		Items:    fromCells(backupCells),
+		Errors:   nonCriticalErrors,
 	}
+}
 
+func toBackupMetadataList(items []unstructured.Unstructured, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *BackupList {
+	backups := make([]Backup, 0, len(items))
+	for _, item := range items {
+		backups = append(backups, toBackup(item))
+	}
+
+	backupCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(backups), dsQuery)
 	return &BackupList{
-		ListMeta: types.ListMeta{TotalItems: len(items)},
-		Items:    items,
-	}, nil
+		ListMeta:     types.ListMeta{TotalItems: filteredTotal},
+		Items:        fromCells(backupCells),
+		MetadataOnly: true,
+		Errors:       nonCriticalErrors,
+	}
+}
+
+func getBackupListStatus(backups []Backup) velero.ListStatus {
+	status := velero.ListStatus{}
+	for _, backup := range backups {
+		status.Add(backup.Phase.Status())
+	}
+
+	return status
+}
+
+func toBackup(item unstructured.Unstructured) Backup {
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	return Backup{
+		ObjectMeta: types.ObjectMeta{
+			Name:              item.GetName(),
+			Namespace:         item.GetNamespace(),
+			Labels:            item.GetLabels(),
+			CreationTimestamp: item.GetCreationTimestamp(),
+		},
+		TypeMeta: types.TypeMeta{
+			Kind: "Backup",
+		},
+		Phase:          velero.BackupPhase(phase),
+		StartTime:      nestedTime(item.Object, "status", "startTimestamp"),
+		CompletionTime: nestedTime(item.Object, "status", "completionTimestamp"),
+		Expiration:     nestedTime(item.Object, "status", "expiration"),
+	}
+}
+
+// SubscribeBackupList subscribes to backups being added, modified or deleted in namespaces matching the query.
+func SubscribeBackupList(request *http.Request, namespace *common.NamespaceQuery) (*subscription.Subscription, error) {
+	return subscription.Subscribe(request, velero.BackupGVR, namespace, func(item *unstructured.Unstructured) interface{} {
+		return toBackup(*item)
+	})
 }
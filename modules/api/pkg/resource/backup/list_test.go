@@ -0,0 +1,183 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+func newRawBackup(name string, created time.Time) unstructured.Unstructured {
+	item := unstructured.Unstructured{Object: map[string]interface{}{}}
+	item.SetName(name)
+	item.SetNamespace("velero")
+	item.SetLabels(map[string]string{ScheduleNameLabel: "daily"})
+	item.SetCreationTimestamp(metav1.NewTime(created))
+	return item
+}
+
+func TestToBackupList(t *testing.T) {
+	older := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+	newestFirst := dataselect.NewDataSelectQuery(dataselect.NoPagination,
+		dataselect.NewSortQuery([]string{"d", dataselect.CreationTimestampProperty}), dataselect.NoFilter, dataselect.NoMetrics)
+
+	cases := []struct {
+		items    []unstructured.Unstructured
+		dsQuery  *dataselect.DataSelectQuery
+		expected *BackupList
+	}{
+		{nil, dataselect.NoDataSelect, &BackupList{Items: []Backup{}}},
+		{
+			[]unstructured.Unstructured{
+				newRawBackup("daily-20240101020000", older),
+				newRawBackup("daily-20240102020000", newer),
+			},
+			newestFirst,
+			&BackupList{
+				ListMeta: types.ListMeta{TotalItems: 2},
+				Status:   velero.ListStatus{Pending: 2},
+				Items: []Backup{
+					{
+						ObjectMeta: types.ObjectMeta{Name: "daily-20240102020000", Namespace: "velero",
+							Labels: map[string]string{ScheduleNameLabel: "daily"}, CreationTimestamp: metav1.NewTime(newer.Local())},
+						TypeMeta: types.TypeMeta{Kind: "Backup"},
+					},
+					{
+						ObjectMeta: types.ObjectMeta{Name: "daily-20240101020000", Namespace: "velero",
+							Labels: map[string]string{ScheduleNameLabel: "daily"}, CreationTimestamp: metav1.NewTime(older.Local())},
+						TypeMeta: types.TypeMeta{Kind: "Backup"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toBackupList(c.items, nil, c.dsQuery)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toBackupList(%#v) == \n%#v\nexpected \n%#v\n", c.items, actual, c.expected)
+		}
+	}
+}
+
+func TestToBackupMetadataList(t *testing.T) {
+	created := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	item := newRawBackup("daily-20240101020000", created)
+	item.SetAPIVersion("meta.k8s.io/v1")
+	item.SetKind("PartialObjectMetadata")
+
+	expected := &BackupList{
+		ListMeta: types.ListMeta{TotalItems: 1},
+		Items: []Backup{
+			{
+				ObjectMeta: types.ObjectMeta{Name: "daily-20240101020000", Namespace: "velero",
+					Labels: map[string]string{ScheduleNameLabel: "daily"}, CreationTimestamp: metav1.NewTime(created.Local())},
+				TypeMeta: types.TypeMeta{Kind: "Backup"},
+			},
+		},
+		MetadataOnly: true,
+	}
+
+	actual := toBackupMetadataList([]unstructured.Unstructured{item}, nil, dataselect.NoDataSelect)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toBackupMetadataList() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestBackupCellSortByTime(t *testing.T) {
+	older := metav1.NewTime(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(older.Add(24 * time.Hour))
+	backups := []Backup{
+		{ObjectMeta: types.ObjectMeta{Name: "pending"}},
+		{ObjectMeta: types.ObjectMeta{Name: "older"}, StartTime: &older, CompletionTime: &newer, Expiration: &older},
+		{ObjectMeta: types.ObjectMeta{Name: "newer"}, StartTime: &newer, CompletionTime: &older, Expiration: &newer},
+	}
+
+	cases := []struct {
+		sortBy   []string
+		expected []string
+	}{
+		{[]string{"d", dataselect.StartTimeProperty}, []string{"newer", "older", "pending"}},
+		{[]string{"a", dataselect.StartTimeProperty}, []string{"pending", "older", "newer"}},
+		{[]string{"d", dataselect.CompletionTimeProperty}, []string{"older", "newer", "pending"}},
+		{[]string{"a", dataselect.ExpirationProperty}, []string{"pending", "older", "newer"}},
+	}
+
+	for _, c := range cases {
+		dsQuery := dataselect.NewDataSelectQuery(dataselect.NoPagination, dataselect.NewSortQuery(c.sortBy),
+			dataselect.NoFilter, dataselect.NoMetrics)
+		cells, _ := dataselect.GenericDataSelectWithFilter(toCells(backups), dsQuery)
+		actual := make([]string, 0, len(cells))
+		for _, item := range fromCells(cells) {
+			actual = append(actual, item.ObjectMeta.Name)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("sort by %v == %v, expected %v", c.sortBy, actual, c.expected)
+		}
+	}
+}
+
+// newRawBackupList returns count backups in various phases encoded the same way the API server lists them.
+func newRawBackupList(count int) []byte {
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "velero.io/v1", "kind": "BackupList"}}
+	phases := []string{"Completed", "PartiallyFailed", "Failed", "InProgress"}
+	created := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		started := created.Add(time.Duration(i) * time.Hour)
+		item := newRawBackup(fmt.Sprintf("daily-%s", started.Format("20060102150405")), started)
+		item.SetAPIVersion("velero.io/v1")
+		item.SetKind("Backup")
+		_ = unstructured.SetNestedField(item.Object, map[string]interface{}{
+			"phase":               phases[i%len(phases)],
+			"startTimestamp":      started.Format(time.RFC3339),
+			"completionTimestamp": started.Add(10 * time.Minute).Format(time.RFC3339),
+			"expiration":          started.Add(720 * time.Hour).Format(time.RFC3339),
+			"progress":            map[string]interface{}{"totalItems": int64(250), "itemsBackedUp": int64(250)},
+		}, "status")
+		list.Items = append(list.Items, item)
+	}
+
+	raw, _ := list.MarshalJSON()
+	return raw
+}
+
+func benchmarkGetBackupList(count int, b *testing.B) {
+	raw := newRawBackupList(count)
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NewPaginationQuery(10, 0),
+		dataselect.NewSortQuery([]string{"d", dataselect.StartTimeProperty}), dataselect.NoFilter, dataselect.NoMetrics)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		list := &unstructured.UnstructuredList{}
+		if err := list.UnmarshalJSON(raw); err != nil {
+			b.Fatal(err)
+		}
+		toBackupList(list.Items, nil, dsQuery)
+	}
+}
+
+func BenchmarkGetBackupList1000(b *testing.B)  { benchmarkGetBackupList(1000, b) }
+func BenchmarkGetBackupList5000(b *testing.B)  { benchmarkGetBackupList(5000, b) }
+func BenchmarkGetBackupList10000(b *testing.B) { benchmarkGetBackupList(10000, b) }
@@ -0,0 +1,211 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+)
+
+// OrphanReason tells why a backup is considered orphaned.
+type OrphanReason string
+
+const (
+	// OrphanReasonScheduleDeleted means the schedule named by the schedule label of the backup does not exist anymore.
+	OrphanReasonScheduleDeleted OrphanReason = "ScheduleDeleted"
+	// OrphanReasonNamespacesGone means some of the namespaces the backup explicitly included do not exist anymore.
+	OrphanReasonNamespacesGone OrphanReason = "NamespacesGone"
+)
+
+// OrphanedBackupList contains backups left behind by deleted schedules or namespaces, oldest first.
+type OrphanedBackupList struct {
+	Items []OrphanedBackup `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// OrphanedBackup is a backup together with the reasons it is orphaned.
+type OrphanedBackup struct {
+	Backup  Backup         `json:"backup"`
+	Reasons []OrphanReason `json:"reasons"`
+
+	// ScheduleName is the deleted schedule the backup was created by, empty unless the schedule is deleted.
+	ScheduleName string `json:"scheduleName,omitempty"`
+
+	// MissingNamespaces are included namespaces, or patterns, that no namespace of the cluster matches.
+	MissingNamespaces []string `json:"missingNamespaces,omitempty"`
+}
+
+// GetOrphanedBackups returns backups in namespaces matching the query whose schedule was deleted or whose included
+// namespaces no longer exist. Backups including all namespaces are never orphaned by namespaces. When namespaces of the
+// cluster can not be listed, only schedules are checked.
+func GetOrphanedBackups(request *http.Request, namespace *common.NamespaceQuery) (*OrphanedBackupList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	items, nonCriticalErrors, err := getBackups(ctx, dynamicClient, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	deletedSchedules, err := getDeletedSchedules(ctx, dynamicClient, items)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, namespaceErrors, err := getNamespaceNames(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrphanedBackupList{
+		Items:  toOrphanedBackups(items, deletedSchedules, namespaces),
+		Errors: append(nonCriticalErrors, namespaceErrors...),
+	}, nil
+}
+
+// scheduleKey identifies a schedule by namespace and name.
+type scheduleKey struct {
+	namespace, name string
+}
+
+// getDeletedSchedules looks up every schedule named by the schedule label of the backups and returns those that do not
+// exist.
+func getDeletedSchedules(ctx context.Context, client dynamic.Interface, items []unstructured.Unstructured) (map[scheduleKey]bool, error) {
+	result := make(map[scheduleKey]bool)
+	checked := make(map[scheduleKey]bool)
+	for _, item := range items {
+		name, ok := item.GetLabels()[ScheduleNameLabel]
+		if !ok || name == "" {
+			continue
+		}
+
+		key := scheduleKey{item.GetNamespace(), name}
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+
+		_, err := client.Resource(velero.ScheduleGVR).Namespace(key.namespace).Get(ctx, key.name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			result[key] = true
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// getNamespaceNames returns the names of the namespaces of the cluster, nil along a non-critical error when they can not
+// be listed.
+func getNamespaceNames(ctx context.Context, client kubernetes.Interface) ([]string, []error, error) {
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		nonCriticalErrors, criticalError := errors.ExtractErrors(err)
+		return nil, nonCriticalErrors, criticalError
+	}
+
+	result := make([]string, 0, len(namespaces.Items))
+	for _, item := range namespaces.Items {
+		result = append(result, item.Name)
+	}
+
+	return result, nil, nil
+}
+
+// toOrphanedBackups checks the backups against deleted schedules and existing namespaces. Namespaces are not checked
+// when they are nil. Backups being deleted are left out.
+func toOrphanedBackups(items []unstructured.Unstructured, deletedSchedules map[scheduleKey]bool, namespaces []string) []OrphanedBackup {
+	result := make([]OrphanedBackup, 0)
+	for _, item := range items {
+		orphaned := OrphanedBackup{Backup: toBackup(item), Reasons: make([]OrphanReason, 0)}
+		if orphaned.Backup.Phase.Status() == velero.StatusDeleting {
+			continue
+		}
+
+		name := item.GetLabels()[ScheduleNameLabel]
+		if deletedSchedules[scheduleKey{item.GetNamespace(), name}] {
+			orphaned.Reasons = append(orphaned.Reasons, OrphanReasonScheduleDeleted)
+			orphaned.ScheduleName = name
+		}
+
+		if namespaces != nil {
+			included, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "includedNamespaces")
+			orphaned.MissingNamespaces = getMissingNamespaces(included, namespaces)
+			if len(orphaned.MissingNamespaces) > 0 {
+				orphaned.Reasons = append(orphaned.Reasons, OrphanReasonNamespacesGone)
+			}
+		}
+
+		if len(orphaned.Reasons) > 0 {
+			result = append(result, orphaned)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Backup.ObjectMeta.CreationTimestamp.Before(&result[j].Backup.ObjectMeta.CreationTimestamp)
+	})
+
+	return result
+}
+
+// getMissingNamespaces returns the included namespaces no existing namespace matches. Names may be glob patterns, as in
+// Velero, and "*" includes all namespaces.
+func getMissingNamespaces(included, namespaces []string) []string {
+	var result []string
+	for _, pattern := range included {
+		if pattern == "*" {
+			return nil
+		}
+
+		exists := slices.ContainsFunc(namespaces, func(name string) bool {
+			matched, _ := path.Match(pattern, name)
+			return matched
+		})
+		if !exists {
+			result = append(result, pattern)
+		}
+	}
+
+	return result
+}
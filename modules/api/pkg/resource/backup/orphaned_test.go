@@ -0,0 +1,146 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newOrphanCandidate(name, scheduleName, phase string, created time.Time, includedNamespaces ...interface{}) unstructured.Unstructured {
+	item := velerofake.NewObject(velero.BackupGVR, "velero", name)
+	item.SetCreationTimestamp(metav1.NewTime(created))
+	if scheduleName != "" {
+		item.SetLabels(map[string]string{ScheduleNameLabel: scheduleName})
+	}
+	if len(includedNamespaces) > 0 {
+		_ = unstructured.SetNestedSlice(item.Object, includedNamespaces, "spec", "includedNamespaces")
+	}
+	if phase != "" {
+		_ = unstructured.SetNestedField(item.Object, phase, "status", "phase")
+	}
+
+	return *item
+}
+
+func TestGetDeletedSchedules(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	items := []unstructured.Unstructured{
+		newOrphanCandidate("nightly-1", "nightly", "Completed", now),
+		newOrphanCandidate("nightly-2", "nightly", "Completed", now),
+		newOrphanCandidate("weekly-1", "weekly", "Completed", now),
+		newOrphanCandidate("manual", "", "Completed", now),
+	}
+	dynamicClient := velerofake.NewDynamicClient(nil, velerofake.NewObject(velero.ScheduleGVR, "velero", "nightly"))
+
+	actual, err := getDeletedSchedules(context.Background(), dynamicClient, items)
+	if err != nil {
+		t.Fatalf("getDeletedSchedules() returned error: %v", err)
+	}
+
+	expected := map[scheduleKey]bool{{"velero", "weekly"}: true}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getDeletedSchedules() == %v, expected %v", actual, expected)
+	}
+}
+
+func TestGetNamespaceNames(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}})
+	actual, nonCriticalErrors, err := getNamespaceNames(context.Background(), k8sClient)
+	if err != nil || len(nonCriticalErrors) > 0 || !reflect.DeepEqual(actual, []string{"shop"}) {
+		t.Errorf("getNamespaceNames() == %v, %v, %v, expected [shop] without errors", actual, nonCriticalErrors, err)
+	}
+
+	k8sClient.PrependReactor("list", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewForbidden(v1.Resource("namespaces"), "", nil)
+	})
+	actual, nonCriticalErrors, err = getNamespaceNames(context.Background(), k8sClient)
+	if err != nil || len(nonCriticalErrors) != 1 || actual != nil {
+		t.Errorf("getNamespaceNames() == %v, %v, %v, expected nil names and a non-critical error", actual, nonCriticalErrors, err)
+	}
+}
+
+func TestToOrphanedBackups(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	items := []unstructured.Unstructured{
+		newOrphanCandidate("weekly-2", "weekly", "Completed", now, "shop"),
+		newOrphanCandidate("weekly-1", "weekly", "Completed", now.Add(-time.Hour), "shop", "legacy", "team-*"),
+		newOrphanCandidate("nightly-1", "nightly", "Completed", now, "shop", "team-a"),
+		newOrphanCandidate("legacy", "", "PartiallyFailed", now, "legacy"),
+		newOrphanCandidate("everything", "", "Completed", now, "*", "legacy"),
+		newOrphanCandidate("deleting", "weekly", "Deleting", now, "legacy"),
+	}
+	deletedSchedules := map[scheduleKey]bool{{"velero", "weekly"}: true}
+	namespaces := []string{"shop", "team-a"}
+
+	cases := []struct {
+		info       string
+		namespaces []string
+		expected   map[string]OrphanedBackup
+	}{
+		{
+			"schedules and namespaces",
+			namespaces,
+			map[string]OrphanedBackup{
+				"weekly-1": {Reasons: []OrphanReason{OrphanReasonScheduleDeleted, OrphanReasonNamespacesGone},
+					ScheduleName: "weekly", MissingNamespaces: []string{"legacy"}},
+				"weekly-2": {Reasons: []OrphanReason{OrphanReasonScheduleDeleted}, ScheduleName: "weekly"},
+				"legacy":   {Reasons: []OrphanReason{OrphanReasonNamespacesGone}, MissingNamespaces: []string{"legacy"}},
+			},
+		},
+		{
+			"only schedules",
+			nil,
+			map[string]OrphanedBackup{
+				"weekly-1": {Reasons: []OrphanReason{OrphanReasonScheduleDeleted}, ScheduleName: "weekly"},
+				"weekly-2": {Reasons: []OrphanReason{OrphanReasonScheduleDeleted}, ScheduleName: "weekly"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toOrphanedBackups(items, deletedSchedules, c.namespaces)
+		if len(actual) != len(c.expected) {
+			t.Errorf("%s: toOrphanedBackups() returned %d backups, expected %d", c.info, len(actual), len(c.expected))
+			continue
+		}
+
+		if actual[0].Backup.ObjectMeta.Name != "weekly-1" {
+			t.Errorf("%s: toOrphanedBackups() returned %s first, expected the oldest backup weekly-1", c.info,
+				actual[0].Backup.ObjectMeta.Name)
+		}
+
+		for _, item := range actual {
+			expected := c.expected[item.Backup.ObjectMeta.Name]
+			expected.Backup = item.Backup
+			if !reflect.DeepEqual(item, expected) {
+				t.Errorf("%s: toOrphanedBackups() returned %#v, expected %#v", c.info, item, expected)
+			}
+		}
+	}
+}
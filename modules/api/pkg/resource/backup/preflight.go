@@ -0,0 +1,196 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/storagelocation"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+)
+
+// PreflightSeverity tells whether a preflight issue prevents the backup from being created.
+type PreflightSeverity string
+
+const (
+	// PreflightSeverityError issues would make Velero fail the backup, it is not created with them.
+	PreflightSeverityError PreflightSeverity = "Error"
+
+	// PreflightSeverityWarning issues let the backup be created, but it may not contain what is expected.
+	PreflightSeverityWarning PreflightSeverity = "Warning"
+)
+
+// BackupPreflight lists the issues found in a backup spec before it is created, so that UIs can show them next to the
+// fields they are about.
+type BackupPreflight struct {
+	// Valid is true when there is no issue of the Error severity.
+	Valid  bool             `json:"valid"`
+	Issues []PreflightIssue `json:"issues"`
+}
+
+// PreflightIssue is an issue found in a field of the backup spec.
+type PreflightIssue struct {
+	// Field is the JSON path of the field in the spec, e.g. includedNamespaces[1].
+	Field    string            `json:"field"`
+	Severity PreflightSeverity `json:"severity"`
+	Message  string            `json:"message"`
+}
+
+// GetBackupPreflight checks a backup spec against the cluster without creating the backup. The included namespaces
+// should exist, the storage location has to exist and be available, and the label selectors have to be valid.
+func GetBackupPreflight(request *http.Request, spec *BackupSpec) (*BackupPreflight, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	spec.Namespace = velero.NamespaceOrDefault(request, spec.Namespace)
+	issues, err := getPreflightIssues(ctx, request, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBackupPreflight(issues), nil
+}
+
+func toBackupPreflight(issues []PreflightIssue) *BackupPreflight {
+	result := &BackupPreflight{Valid: true, Issues: issues}
+	for _, issue := range issues {
+		if issue.Severity == PreflightSeverityError {
+			result.Valid = false
+		}
+	}
+
+	return result
+}
+
+// checkPreflight rejects a backup spec with preflight issues of the Error severity. Warnings are left for the preflight
+// endpoint to show.
+func checkPreflight(ctx context.Context, request *http.Request, spec *BackupSpec) error {
+	issues, err := getPreflightIssues(ctx, request, spec)
+	if err != nil {
+		return err
+	}
+
+	allErrs := field.ErrorList{}
+	for _, issue := range issues {
+		if issue.Severity == PreflightSeverityError {
+			allErrs = append(allErrs, &field.Error{Type: field.ErrorTypeInvalid, Field: issue.Field, Detail: issue.Message})
+		}
+	}
+	if len(allErrs) > 0 {
+		return k8serrors.NewInvalid(schema.GroupKind{Group: velero.GroupName, Kind: "Backup"}, spec.Name, allErrs)
+	}
+
+	return nil
+}
+
+func getPreflightIssues(ctx context.Context, request *http.Request, spec *BackupSpec) ([]PreflightIssue, error) {
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := checkLabelSelectors(&spec.BackupTemplate)
+	issues = append(issues, checkIncludedNamespaces(ctx, k8sClient, spec.IncludedNamespaces)...)
+	issues = append(issues, checkStorageLocation(ctx, dynamicClient, spec.Namespace, spec.StorageLocation)...)
+
+	return issues, nil
+}
+
+// checkLabelSelectors reports label selectors the API server would accept but Velero could not parse.
+func checkLabelSelectors(template *BackupTemplate) []PreflightIssue {
+	issues := make([]PreflightIssue, 0)
+	check := func(path *field.Path, labelSelector *metav1.LabelSelector) {
+		if _, err := metav1.LabelSelectorAsSelector(labelSelector); err != nil {
+			issues = append(issues, PreflightIssue{Field: path.String(), Severity: PreflightSeverityError,
+				Message: fmt.Sprintf("invalid label selector: %s", err.Error())})
+		}
+	}
+
+	if template.LabelSelector != nil {
+		check(field.NewPath("labelSelector"), template.LabelSelector)
+	}
+	for i, labelSelector := range template.OrLabelSelectors {
+		check(field.NewPath("orLabelSelectors").Index(i), labelSelector)
+	}
+
+	return issues
+}
+
+// checkIncludedNamespaces warns about included namespaces that do not exist, as Velero backs up nothing from them
+// without failing. Glob patterns are not checked, and namespaces that can not be read are not reported.
+func checkIncludedNamespaces(ctx context.Context, client kubernetes.Interface, namespaces []string) []PreflightIssue {
+	issues := make([]PreflightIssue, 0)
+	for i, namespace := range namespaces {
+		if strings.ContainsAny(namespace, "*?[") {
+			continue
+		}
+
+		_, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			issues = append(issues, PreflightIssue{Field: field.NewPath("includedNamespaces").Index(i).String(),
+				Severity: PreflightSeverityWarning, Message: fmt.Sprintf("namespace %s does not exist", namespace)})
+		}
+	}
+
+	return issues
+}
+
+// checkStorageLocation reports a storage location that does not exist or that Velero found unavailable, as Velero
+// fails the validation of backups using it. The default location is used when none is set and is not checked.
+// Locations that can not be read are not reported.
+func checkStorageLocation(ctx context.Context, client dynamic.Interface, namespace, name string) []PreflightIssue {
+	if name == "" {
+		return nil
+	}
+
+	path := field.NewPath("storageLocation").String()
+	location, err := client.Resource(velero.BackupStorageLocationGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return []PreflightIssue{{Field: path, Severity: PreflightSeverityError,
+			Message: fmt.Sprintf("storage location %s does not exist in namespace %s", name, namespace)}}
+	}
+	if err != nil {
+		return nil
+	}
+
+	phase, _, _ := unstructured.NestedString(location.Object, "status", "phase")
+	if storagelocation.StorageLocationPhase(phase) != storagelocation.StorageLocationPhaseUnavailable {
+		return nil
+	}
+
+	message := fmt.Sprintf("storage location %s is unavailable", name)
+	if detail, _, _ := unstructured.NestedString(location.Object, "status", "message"); detail != "" {
+		message = fmt.Sprintf("%s: %s", message, detail)
+	}
+
+	return []PreflightIssue{{Field: path, Severity: PreflightSeverityError, Message: message}}
+}
@@ -0,0 +1,119 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func TestCheckLabelSelectors(t *testing.T) {
+	valid := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}}
+	invalid := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "app", Operator: metav1.LabelSelectorOpIn},
+	}}
+	cases := []struct {
+		template       *BackupTemplate
+		expectedFields []string
+	}{
+		{&BackupTemplate{}, []string{}},
+		{&BackupTemplate{LabelSelector: valid, OrLabelSelectors: []*metav1.LabelSelector{valid}}, []string{}},
+		{&BackupTemplate{LabelSelector: invalid}, []string{"labelSelector"}},
+		{&BackupTemplate{OrLabelSelectors: []*metav1.LabelSelector{valid, invalid}}, []string{"orLabelSelectors[1]"}},
+	}
+
+	for _, c := range cases {
+		actual := make([]string, 0)
+		for _, issue := range checkLabelSelectors(c.template) {
+			if issue.Severity != PreflightSeverityError {
+				t.Errorf("checkLabelSelectors() returned %s issue %v, expected errors only", issue.Severity, issue)
+			}
+			actual = append(actual, issue.Field)
+		}
+		if !reflect.DeepEqual(actual, c.expectedFields) {
+			t.Errorf("checkLabelSelectors() reported fields %v, expected %v", actual, c.expectedFields)
+		}
+	}
+}
+
+func TestCheckIncludedNamespaces(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}})
+	expected := []PreflightIssue{{
+		Field:    "includedNamespaces[1]",
+		Severity: PreflightSeverityWarning,
+		Message:  "namespace missing does not exist",
+	}}
+
+	actual := checkIncludedNamespaces(context.Background(), k8sClient, []string{"shop", "missing", "shop-*", "*"})
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("checkIncludedNamespaces() == %#v, expected %#v", actual, expected)
+	}
+}
+
+func TestCheckStorageLocation(t *testing.T) {
+	available := velerofake.NewObject(velero.BackupStorageLocationGVR, "velero", "default")
+	_ = unstructured.SetNestedField(available.Object, "Available", "status", "phase")
+	unavailable := velerofake.NewObject(velero.BackupStorageLocationGVR, "velero", "offline")
+	_ = unstructured.SetNestedField(unavailable.Object, "Unavailable", "status", "phase")
+	_ = unstructured.SetNestedField(unavailable.Object, "bucket not found", "status", "message")
+	dynamicClient := velerofake.NewDynamicClient(nil, available, unavailable)
+
+	cases := []struct {
+		name     string
+		expected []PreflightIssue
+	}{
+		{"", nil},
+		{"default", nil},
+		{"offline", []PreflightIssue{{Field: "storageLocation", Severity: PreflightSeverityError,
+			Message: "storage location offline is unavailable: bucket not found"}}},
+		{"missing", []PreflightIssue{{Field: "storageLocation", Severity: PreflightSeverityError,
+			Message: "storage location missing does not exist in namespace velero"}}},
+	}
+
+	for _, c := range cases {
+		actual := checkStorageLocation(context.Background(), dynamicClient, "velero", c.name)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("checkStorageLocation(%q) == %#v, expected %#v", c.name, actual, c.expected)
+		}
+	}
+}
+
+func TestToBackupPreflight(t *testing.T) {
+	warning := PreflightIssue{Field: "includedNamespaces[0]", Severity: PreflightSeverityWarning}
+	failure := PreflightIssue{Field: "storageLocation", Severity: PreflightSeverityError}
+	cases := []struct {
+		issues   []PreflightIssue
+		expected bool
+	}{
+		{[]PreflightIssue{}, true},
+		{[]PreflightIssue{warning}, true},
+		{[]PreflightIssue{warning, failure}, false},
+	}
+
+	for _, c := range cases {
+		if actual := toBackupPreflight(c.issues).Valid; actual != c.expected {
+			t.Errorf("toBackupPreflight(%v).Valid == %t, expected %t", c.issues, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+const (
+	// ExcludeFromBackupLabel makes Velero skip the labeled resource in every backup.
+	ExcludeFromBackupLabel = "velero.io/exclude-from-backup"
+
+	// ExclusionPresetsAnnotation records on a backup which exclusion presets were expanded into its spec.
+	ExclusionPresetsAnnotation = "dashboard.kubernetes.io/exclusion-presets"
+
+	// exclusionPresetsConfigMapKey is the key of the Dashboard settings config map holding exclusion presets.
+	exclusionPresetsConfigMapKey = "backupExclusionPresets"
+)
+
+// applyExclusionPresets expands exclusion presets referenced by the spec into its excluded resources and excludes
+// completed Jobs when any of the presets asks for it.
+func applyExclusionPresets(ctx context.Context, request *http.Request, spec *BackupSpec) error {
+	presets, err := getExclusionPresets(ctx, client.InClusterClient())
+	if err != nil {
+		return err
+	}
+
+	excludeCompletedJobs, err := expandExclusionPresets(spec, presets)
+	if err != nil || !excludeCompletedJobs {
+		return err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return err
+	}
+
+	return excludeCompletedJobsFromBackup(ctx, k8sClient, spec)
+}
+
+// getExclusionPresets reads exclusion presets from the Dashboard settings config map. The defaults are used until
+// presets are saved in settings.
+func getExclusionPresets(ctx context.Context, client kubernetes.Interface) ([]types.BackupExclusionPreset, error) {
+	configMap, err := client.CoreV1().ConfigMaps(args.Namespace()).
+		Get(ctx, args.SettingsConfigMapName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return types.DefaultBackupExclusionPresets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := configMap.Data[exclusionPresetsConfigMapKey]
+	if !ok {
+		return types.DefaultBackupExclusionPresets, nil
+	}
+
+	presets := make([]types.BackupExclusionPreset, 0)
+	if err := json.Unmarshal([]byte(raw), &presets); err != nil {
+		return nil, errors.NewInternal(fmt.Sprintf("could not parse backup exclusion presets: %s", err.Error()))
+	}
+
+	if len(presets) == 0 {
+		return types.DefaultBackupExclusionPresets, nil
+	}
+
+	return presets, nil
+}
+
+// expandExclusionPresets adds resources excluded by the referenced presets to the spec. It reports whether completed
+// Jobs should be excluded as well.
+func expandExclusionPresets(spec *BackupSpec, presets []types.BackupExclusionPreset) (bool, error) {
+	byName := make(map[string]types.BackupExclusionPreset, len(presets))
+	for _, preset := range presets {
+		byName[preset.Name] = preset
+	}
+
+	excluded := make(map[string]bool, len(spec.ExcludedResources))
+	for _, resource := range spec.ExcludedResources {
+		excluded[resource] = true
+	}
+
+	excludeCompletedJobs := false
+	for _, name := range spec.ExclusionPresets {
+		preset, ok := byName[name]
+		if !ok {
+			return false, errors.NewBadRequest(fmt.Sprintf("unknown backup exclusion preset %q", name))
+		}
+
+		for _, resource := range preset.ExcludedResources {
+			if !excluded[resource] {
+				excluded[resource] = true
+				spec.ExcludedResources = append(spec.ExcludedResources, resource)
+			}
+		}
+		excludeCompletedJobs = excludeCompletedJobs || preset.ExcludeCompletedJobs
+	}
+
+	return excludeCompletedJobs, nil
+}
+
+// excludeCompletedJobsFromBackup labels completed Jobs in the backed up namespaces and their pods with
+// ExcludeFromBackupLabel.
+func excludeCompletedJobsFromBackup(ctx context.Context, client kubernetes.Interface, spec *BackupSpec) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{ExcludeFromBackupLabel: "true"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	jobs, err := getCompletedJobs(ctx, client, spec)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		_, err := client.BatchV1().Jobs(job.Namespace).Patch(ctx, job.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("Failed to exclude job %s/%s from backup: %w", job.Namespace, job.Name, err)
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err != nil {
+			return err
+		}
+
+		pods, err := client.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return err
+		}
+
+		for _, pod := range pods.Items {
+			_, err := client.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return fmt.Errorf("Failed to exclude pod %s/%s from backup: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func getCompletedJobs(ctx context.Context, client kubernetes.Interface, spec *BackupSpec) ([]batch.Job, error) {
+	namespaces := spec.IncludedNamespaces
+	if len(namespaces) == 0 || (len(namespaces) == 1 && namespaces[0] == "*") {
+		namespaces = []string{v1.NamespaceAll}
+	}
+
+	excluded := make(map[string]bool, len(spec.ExcludedNamespaces))
+	for _, namespace := range spec.ExcludedNamespaces {
+		excluded[namespace] = true
+	}
+
+	result := make([]batch.Job, 0)
+	for _, namespace := range namespaces {
+		jobs, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, job := range jobs.Items {
+			if !excluded[job.Namespace] && isJobComplete(&job) {
+				result = append(result, job)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func isJobComplete(job *batch.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batch.JobComplete && condition.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
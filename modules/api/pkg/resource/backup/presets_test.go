@@ -0,0 +1,160 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/types"
+)
+
+func TestGetExclusionPresets(t *testing.T) {
+	custom := []types.BackupExclusionPreset{{Name: "skip-events", ExcludedResources: []string{"events"}}}
+	newSettings := func(data map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: args.SettingsConfigMapName(), Namespace: args.Namespace()},
+			Data:       data,
+		}
+	}
+
+	cases := []struct {
+		configMap *v1.ConfigMap
+		expected  []types.BackupExclusionPreset
+	}{
+		{nil, types.DefaultBackupExclusionPresets},
+		{newSettings(map[string]string{"settings": `{"itemsPerPage":10}`}), types.DefaultBackupExclusionPresets},
+		{newSettings(map[string]string{"backupExclusionPresets": `[]`}), types.DefaultBackupExclusionPresets},
+		{
+			newSettings(map[string]string{"backupExclusionPresets": `[{"name":"skip-events","excludedResources":["events"]}]`}),
+			custom,
+		},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset()
+		if c.configMap != nil {
+			client = fake.NewSimpleClientset(c.configMap)
+		}
+
+		actual, err := getExclusionPresets(context.TODO(), client)
+		if err != nil {
+			t.Fatalf("getExclusionPresets(%#v) returned error: %s", c.configMap, err.Error())
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getExclusionPresets(%#v) == \n%#v\nexpected \n%#v\n", c.configMap, actual, c.expected)
+		}
+	}
+}
+
+func TestExpandExclusionPresets(t *testing.T) {
+	presets := []types.BackupExclusionPreset{
+		{Name: "skip-events", ExcludedResources: []string{"events", "events.events.k8s.io"}},
+		{Name: "skip-jobs", ExcludedResources: []string{"events"}, ExcludeCompletedJobs: true},
+	}
+
+	cases := []struct {
+		spec                 *BackupSpec
+		expectedExcluded     []string
+		expectedCompletedJob bool
+		expectedErr          bool
+	}{
+		{
+			&BackupSpec{BackupTemplate: BackupTemplate{ExcludedResources: []string{"leases.coordination.k8s.io"}}, ExclusionPresets: []string{"skip-events"}},
+			[]string{"leases.coordination.k8s.io", "events", "events.events.k8s.io"}, false, false,
+		},
+		{
+			&BackupSpec{BackupTemplate: BackupTemplate{ExcludedResources: []string{"events"}}, ExclusionPresets: []string{"skip-jobs"}},
+			[]string{"events"}, true, false,
+		},
+		{
+			&BackupSpec{ExclusionPresets: []string{"unknown"}},
+			nil, false, true,
+		},
+	}
+
+	for _, c := range cases {
+		excludeCompletedJobs, err := expandExclusionPresets(c.spec, presets)
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("expandExclusionPresets(%#v) returned error %v, expected error: %v", c.spec, err, c.expectedErr)
+		}
+		if !reflect.DeepEqual(c.spec.ExcludedResources, c.expectedExcluded) || excludeCompletedJobs != c.expectedCompletedJob {
+			t.Errorf("expandExclusionPresets(%#v) == %#v, %v\nexpected %#v, %v", c.spec.ExclusionPresets,
+				c.spec.ExcludedResources, excludeCompletedJobs, c.expectedExcluded, c.expectedCompletedJob)
+		}
+	}
+}
+
+func TestExcludeCompletedJobsFromBackup(t *testing.T) {
+	newJob := func(name, namespace string, condition batch.JobConditionType) *batch.Job {
+		return &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       batch.JobSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": name}}},
+			Status: batch.JobStatus{Conditions: []batch.JobCondition{
+				{Type: condition, Status: v1.ConditionTrue},
+			}},
+		}
+	}
+	newPod := func(name, namespace, job string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace,
+			Labels: map[string]string{"job-name": job}}}
+	}
+
+	client := fake.NewSimpleClientset(
+		newJob("migrate", "shop", batch.JobComplete), newPod("migrate-x", "shop", "migrate"),
+		newJob("import", "shop", batch.JobFailed), newPod("import-x", "shop", "import"),
+		newJob("cleanup", "other", batch.JobComplete),
+	)
+	spec := &BackupSpec{BackupTemplate: BackupTemplate{IncludedNamespaces: []string{"shop"}}}
+
+	if err := excludeCompletedJobsFromBackup(context.TODO(), client, spec); err != nil {
+		t.Fatalf("excludeCompletedJobsFromBackup(%#v) returned error: %s", spec, err.Error())
+	}
+
+	cases := []struct {
+		kind      string
+		namespace string
+		name      string
+		excluded  bool
+	}{
+		{"job", "shop", "migrate", true},
+		{"pod", "shop", "migrate-x", true},
+		{"job", "shop", "import", false},
+		{"pod", "shop", "import-x", false},
+		{"job", "other", "cleanup", false},
+	}
+
+	for _, c := range cases {
+		var labels map[string]string
+		if c.kind == "job" {
+			job, _ := client.BatchV1().Jobs(c.namespace).Get(context.TODO(), c.name, metav1.GetOptions{})
+			labels = job.Labels
+		} else {
+			pod, _ := client.CoreV1().Pods(c.namespace).Get(context.TODO(), c.name, metav1.GetOptions{})
+			labels = pod.Labels
+		}
+
+		if excluded := labels[ExcludeFromBackupLabel] == "true"; excluded != c.excluded {
+			t.Errorf("%s %s/%s excluded == %v, expected %v", c.kind, c.namespace, c.name, excluded, c.excluded)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// BackupProgressEvent is pushed to the client every time the watched backup changes.
+type BackupProgressEvent struct {
+	Name      string             `json:"name"`
+	Namespace string             `json:"namespace"`
+	Phase     velero.BackupPhase `json:"phase"`
+	Progress  BackupProgress     `json:"progress"`
+	Errors    int                `json:"errors"`
+	Warnings  int                `json:"warnings"`
+
+	// Done is set on the last event, once the backup reached a final phase or was deleted.
+	Done bool `json:"done"`
+}
+
+// WatchBackupProgress watches a single backup and calls send with its progress on every change until the backup
+// finishes, gets deleted, the context is cancelled or send returns an error.
+func WatchBackupProgress(ctx context.Context, request *http.Request, namespace, name string, send func(*BackupProgressEvent) error) error {
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return err
+	}
+
+	// The API server ends watches after a timeout, so keep watching from the last seen version until done.
+	resourceVersion := ""
+	for {
+		options := metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion: resourceVersion,
+		}
+
+		watcher, err := dynamicClient.Resource(velero.BackupGVR).Namespace(namespace).Watch(ctx, options)
+		if err != nil {
+			return fmt.Errorf("Failed to watch backup: %w", err)
+		}
+
+		done, err := readBackupProgress(watcher.ResultChan(), &resourceVersion, send)
+		watcher.Stop()
+		if err != nil || done || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// readBackupProgress forwards progress events received from a watch. It reports whether the backup is done.
+func readBackupProgress(events <-chan watch.Event, resourceVersion *string, send func(*BackupProgressEvent) error) (bool, error) {
+	for event := range events {
+		if event.Type == watch.Error {
+			if status, ok := event.Object.(*metav1.Status); ok {
+				return false, errors.NewInternal(status.Message)
+			}
+			return false, errors.NewInternal("backup watch failed")
+		}
+
+		item, ok := event.Object.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+
+		progress := toBackupProgressEvent(event.Type, item)
+		if err := send(progress); err != nil {
+			return false, err
+		}
+
+		*resourceVersion = item.GetResourceVersion()
+		if progress.Done {
+			return true, nil
+		}
+	}
+
+	// The channel is closed when the watch times out or the request is cancelled.
+	return false, nil
+}
+
+func toBackupProgressEvent(eventType watch.EventType, item *unstructured.Unstructured) *BackupProgressEvent {
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	totalItems, _, _ := unstructured.NestedInt64(item.Object, "status", "progress", "totalItems")
+	itemsBackedUp, _, _ := unstructured.NestedInt64(item.Object, "status", "progress", "itemsBackedUp")
+	errorCount, _, _ := unstructured.NestedInt64(item.Object, "status", "errors")
+	warningCount, _, _ := unstructured.NestedInt64(item.Object, "status", "warnings")
+
+	return &BackupProgressEvent{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Phase:     velero.BackupPhase(phase),
+		Progress: BackupProgress{
+			TotalItems:    int(totalItems),
+			ItemsBackedUp: int(itemsBackedUp),
+		},
+		Errors:   int(errorCount),
+		Warnings: int(warningCount),
+		Done:     eventType == watch.Deleted || velero.BackupPhase(phase).IsFinal(),
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+func TestReadBackupProgress(t *testing.T) {
+	newEvent := func(phase string, itemsBackedUp int, done bool) BackupProgressEvent {
+		return BackupProgressEvent{Name: "nightly", Namespace: "velero", Phase: velero.BackupPhase(phase),
+			Progress: BackupProgress{TotalItems: 10, ItemsBackedUp: itemsBackedUp}, Done: done}
+	}
+	newWatchEvent := func(eventType watch.EventType, resourceVersion, phase string, itemsBackedUp int64) watch.Event {
+		item := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "velero.io/v1",
+			"kind":       "Backup",
+			"metadata":   map[string]interface{}{"name": "nightly", "namespace": "velero", "resourceVersion": resourceVersion},
+			"status": map[string]interface{}{
+				"phase":    phase,
+				"progress": map[string]interface{}{"totalItems": int64(10), "itemsBackedUp": itemsBackedUp},
+			},
+		}}
+		return watch.Event{Type: eventType, Object: item}
+	}
+
+	cases := []struct {
+		events          []watch.Event
+		expected        []BackupProgressEvent
+		done            bool
+		resourceVersion string
+	}{
+		{
+			[]watch.Event{newWatchEvent(watch.Added, "1", "InProgress", 2), newWatchEvent(watch.Modified, "2", "InProgress", 6)},
+			[]BackupProgressEvent{newEvent("InProgress", 2, false), newEvent("InProgress", 6, false)},
+			false, "2",
+		},
+		{
+			[]watch.Event{newWatchEvent(watch.Modified, "3", "Completed", 10), newWatchEvent(watch.Modified, "4", "Completed", 10)},
+			[]BackupProgressEvent{newEvent("Completed", 10, true)},
+			true, "3",
+		},
+		{
+			[]watch.Event{newWatchEvent(watch.Deleted, "5", "InProgress", 4)},
+			[]BackupProgressEvent{newEvent("InProgress", 4, true)},
+			true, "5",
+		},
+	}
+
+	for _, c := range cases {
+		events := make(chan watch.Event, len(c.events))
+		for _, event := range c.events {
+			events <- event
+		}
+		close(events)
+
+		actual := make([]BackupProgressEvent, 0)
+		resourceVersion := ""
+		done, err := readBackupProgress(events, &resourceVersion, func(event *BackupProgressEvent) error {
+			actual = append(actual, *event)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("readBackupProgress(%#v) returned error: %s", c.events, err.Error())
+		}
+		if !reflect.DeepEqual(actual, c.expected) || done != c.done || resourceVersion != c.resourceVersion {
+			t.Errorf("readBackupProgress(%#v) == \n%#v, %v, %q\nexpected \n%#v, %v, %q\n",
+				c.events, actual, done, resourceVersion, c.expected, c.done, c.resourceVersion)
+		}
+	}
+}
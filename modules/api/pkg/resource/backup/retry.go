@@ -0,0 +1,78 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// RetryOfAnnotation is put on backups created by retrying a failed backup and holds the name of the failed one.
+const RetryOfAnnotation = "dashboard.kubernetes.io/retry-of"
+
+// RetryBackup creates a new backup with the spec of a failed, partially failed or invalid backup. The new backup is
+// named after the failed one with a timestamp suffix and links back to it with RetryOfAnnotation. In dry run it is
+// only validated by the API server.
+func RetryBackup(request *http.Request, namespace, name string, dryRun bool) (*Backup, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := dynamicClient.Resource(velero.BackupGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := toRetrySpec(*item, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return createBackup(request, spec, map[string]string{RetryOfAnnotation: name}, dryRun)
+}
+
+// toRetrySpec returns the spec of the failed backup under the name of its retry.
+func toRetrySpec(item unstructured.Unstructured, now time.Time) (*BackupSpec, error) {
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	switch velero.BackupPhase(phase).Status() {
+	case velero.StatusFailed, velero.StatusPartiallyFailed, velero.StatusFailedValidation:
+	default:
+		return nil, errors.NewBadRequest(fmt.Sprintf("backup %s can not be retried in phase %q, only failed backups can",
+			item.GetName(), phase))
+	}
+
+	spec, err := toBackupSpec(item)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.Name = toRetryName(item.GetName(), now)
+	return spec, nil
+}
+
+func toRetryName(name string, now time.Time) string {
+	return toSuffixedName(name, "-retry-"+now.UTC().Format(nameTimeFormat))
+}
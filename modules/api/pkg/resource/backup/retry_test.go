@@ -0,0 +1,78 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestToRetrySpec(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 30, 5, 0, time.UTC)
+	newFailedBackup := func(name, phase string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name, "namespace": "velero"},
+			"spec": map[string]interface{}{
+				"includedNamespaces": []interface{}{"shop"},
+				"excludedResources":  []interface{}{"events"},
+			},
+			"status": map[string]interface{}{"phase": phase},
+		}}
+	}
+
+	cases := []struct {
+		info     string
+		backup   unstructured.Unstructured
+		expected *BackupSpec
+	}{
+		{
+			"failed backup",
+			newFailedBackup("shop", "Failed"),
+			&BackupSpec{
+				Name:      "shop-retry-20261017123005",
+				Namespace: "velero",
+				BackupTemplate: BackupTemplate{
+					IncludedNamespaces: []string{"shop"},
+					ExcludedResources:  []string{"events"},
+				},
+			},
+		},
+		{"completed backup", newFailedBackup("shop", "Completed"), nil},
+		{"backup in progress", newFailedBackup("shop", "InProgress"), nil},
+	}
+
+	for _, c := range cases {
+		actual, err := toRetrySpec(c.backup, now)
+		if (err != nil) != (c.expected == nil) {
+			t.Errorf("%s: toRetrySpec() returned error %v", c.info, err)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: toRetrySpec() == \n%#v\nexpected \n%#v\n", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestToRetryName(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 30, 5, 0, time.UTC)
+	name := toRetryName(strings.Repeat("a", 250), now)
+	if len(name) != 253 || !strings.HasSuffix(name, "-retry-20261017123005") {
+		t.Errorf("toRetryName() == %q, expected 253 characters ending with the retry suffix", name)
+	}
+}
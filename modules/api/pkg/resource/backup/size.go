@@ -0,0 +1,159 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"net/http"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// BackupSize is the amount of volume data a backup moved to object storage, either with the file system uploader or
+// by moving CSI snapshot data. Velero does not report the size of backed up resources, nor of snapshots kept by the
+// storage provider.
+type BackupSize struct {
+	TotalBytes int64 `json:"totalBytes"`
+	BytesDone  int64 `json:"bytesDone"`
+
+	// Volumes is the number of pod volume backups and data uploads the size is summed up from.
+	Volumes int `json:"volumes"`
+}
+
+// add counts a pod volume backup or a data upload in the size. Both report their progress the same way.
+func (in *BackupSize) add(item unstructured.Unstructured) {
+	totalBytes, _, _ := unstructured.NestedInt64(item.Object, "status", "progress", "totalBytes")
+	bytesDone, _, _ := unstructured.NestedInt64(item.Object, "status", "progress", "bytesDone")
+	in.TotalBytes += totalBytes
+	in.BytesDone += bytesDone
+	in.Volumes++
+}
+
+// GetBackupListWithSizes returns the backup list with the size of each backup on the page. Sizes are left out when
+// volume data of the backups can not be listed, which is reported as a non-critical error.
+func GetBackupListWithSizes(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*BackupList, error) {
+	list, err := GetBackupList(request, namespace, dsQuery)
+	if err != nil || len(list.Items) == 0 {
+		return list, err
+	}
+
+	nonCriticalErrors, err := AddBackupSizes(request, namespace.ToRequestParam(), list.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Errors = append(list.Errors, nonCriticalErrors...)
+	return list, nil
+}
+
+// AddBackupSizes sets the size of the given backups from volume data in the namespace, empty for all namespaces.
+// Failing to list volume data leaves the sizes out and is returned as a non-critical error.
+func AddBackupSizes(request *http.Request, namespace string, backups []Backup) ([]error, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeData, nonCriticalErrors, err := getVolumeData(ctx, dynamicClient, namespace, metav1.ListOptions{})
+	if err != nil {
+		return []error{err}, nil
+	}
+
+	addBackupSizes(backups, volumeData)
+	return nonCriticalErrors, nil
+}
+
+// getBackupSize returns the size of a single backup, or nil when it has no volume data.
+func getBackupSize(ctx context.Context, client dynamic.Interface, namespace, name string) (*BackupSize, error) {
+	selector := labels.SelectorFromSet(labels.Set{BackupNameLabel: toLabelValue(name)}).String()
+	volumeData, _, err := getVolumeData(ctx, client, namespace, metav1.ListOptions{LabelSelector: selector})
+	if err != nil || len(volumeData) == 0 {
+		return nil, err
+	}
+
+	size := new(BackupSize)
+	for _, item := range volumeData {
+		size.add(item)
+	}
+
+	return size, nil
+}
+
+// volumeDataResources are the resources reporting the volume data moved by backups.
+var volumeDataResources = []schema.GroupVersionResource{velero.PodVolumeBackupGVR, velero.DataUploadGVR}
+
+// getVolumeData lists pod volume backups and data uploads concurrently. Data uploads are missing from Velero versions
+// before 1.12, which is not an error.
+func getVolumeData(ctx context.Context, client dynamic.Interface, namespace string, options metav1.ListOptions) ([]unstructured.Unstructured, []error, error) {
+	lists := make([][]unstructured.Unstructured, len(volumeDataResources))
+	truncated := make([]bool, len(volumeDataResources))
+	err := velero.ForEach(ctx, len(volumeDataResources), func(ctx context.Context, i int) error {
+		resource := volumeDataResources[i]
+		items, resourceTruncated, err := velero.List(ctx, client.Resource(resource).Namespace(namespace), options,
+			args.VeleroMaxListItems())
+		if k8serrors.IsNotFound(err) && resource == velero.DataUploadGVR {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		lists[i], truncated[i] = items, resourceTruncated
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonCriticalErrors := make([]error, 0)
+	result := make([]unstructured.Unstructured, 0)
+	for i, resource := range volumeDataResources {
+		if truncated[i] {
+			nonCriticalErrors = append(nonCriticalErrors, velero.NewListTruncatedError(resource.Resource, args.VeleroMaxListItems()))
+		}
+		result = append(result, lists[i]...)
+	}
+
+	return result, nonCriticalErrors, nil
+}
+
+// addBackupSizes sets the size of each backup with volume data, matched by the backup name label of the data.
+func addBackupSizes(backups []Backup, volumeData []unstructured.Unstructured) {
+	sizes := make(map[string]*BackupSize)
+	for _, item := range volumeData {
+		key := item.GetNamespace() + "/" + item.GetLabels()[BackupNameLabel]
+		if sizes[key] == nil {
+			sizes[key] = new(BackupSize)
+		}
+		sizes[key].add(item)
+	}
+
+	for i := range backups {
+		backups[i].Size = sizes[backups[i].ObjectMeta.Namespace+"/"+toLabelValue(backups[i].ObjectMeta.Name)]
+	}
+}
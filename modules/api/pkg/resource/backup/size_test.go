@@ -0,0 +1,101 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+	"k8s.io/dashboard/types"
+)
+
+func newVolumeData(kind, name, backupName string, totalBytes, bytesDone int64) *unstructured.Unstructured {
+	apiVersion := "velero.io/v1"
+	if kind == "DataUpload" {
+		apiVersion = "velero.io/v2alpha1"
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "velero",
+			"labels":    map[string]interface{}{BackupNameLabel: toLabelValue(backupName)},
+		},
+		"status": map[string]interface{}{
+			"progress": map[string]interface{}{"totalBytes": totalBytes, "bytesDone": bytesDone},
+		},
+	}}
+}
+
+func TestAddBackupSizes(t *testing.T) {
+	longName := strings.Repeat("a", 70)
+	backups := []Backup{
+		{ObjectMeta: types.ObjectMeta{Name: "daily", Namespace: "velero"}},
+		{ObjectMeta: types.ObjectMeta{Name: longName, Namespace: "velero"}},
+		{ObjectMeta: types.ObjectMeta{Name: "manifests-only", Namespace: "velero"}},
+	}
+	volumeData := []unstructured.Unstructured{
+		*newVolumeData("PodVolumeBackup", "daily-1", "daily", 1000, 1000),
+		*newVolumeData("DataUpload", "daily-2", "daily", 4000, 2500),
+		*newVolumeData("DataUpload", "long-1", longName, 300, 300),
+		*newVolumeData("PodVolumeBackup", "weekly-1", "weekly", 9000, 9000),
+	}
+
+	addBackupSizes(backups, volumeData)
+
+	expected := []*BackupSize{
+		{TotalBytes: 5000, BytesDone: 3500, Volumes: 2},
+		{TotalBytes: 300, BytesDone: 300, Volumes: 1},
+		nil,
+	}
+	for i, backup := range backups {
+		if !reflect.DeepEqual(backup.Size, expected[i]) {
+			t.Errorf("addBackupSizes() set size of %s to %#v, expected %#v", backup.ObjectMeta.Name, backup.Size, expected[i])
+		}
+	}
+}
+
+func TestGetBackupSize(t *testing.T) {
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newVolumeData("PodVolumeBackup", "daily-1", "daily", 1000, 1000),
+		newVolumeData("DataUpload", "daily-2", "daily", 4000, 4000),
+		newVolumeData("DataUpload", "weekly-1", "weekly", 9000, 9000))
+
+	cases := []struct {
+		name     string
+		expected *BackupSize
+	}{
+		{"daily", &BackupSize{TotalBytes: 5000, BytesDone: 5000, Volumes: 2}},
+		{"manifests-only", nil},
+	}
+
+	for _, c := range cases {
+		actual, err := getBackupSize(context.TODO(), dynamicClient, "velero", c.name)
+		if err != nil {
+			t.Fatalf("getBackupSize(%s) returned error: %s", c.name, err.Error())
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getBackupSize(%s) == %#v, expected %#v", c.name, actual, c.expected)
+		}
+	}
+}
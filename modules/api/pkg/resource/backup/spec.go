@@ -0,0 +1,53 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// GetBackupSpec returns the spec an existing backup was created with, so that it can be edited and created again
+// under a different name.
+func GetBackupSpec(request *http.Request, namespace, name string) (*BackupSpec, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := dynamicClient.Resource(velero.BackupGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toBackupSpec(*item)
+}
+
+func toBackupSpec(item unstructured.Unstructured) (*BackupSpec, error) {
+	spec, _, _ := unstructured.NestedMap(item.Object, "spec")
+	template, err := NewBackupTemplate(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackupSpec{Name: item.GetName(), Namespace: item.GetNamespace(), BackupTemplate: template}, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestToBackupSpec(t *testing.T) {
+	item := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "shop", "namespace": "velero"},
+		"spec": map[string]interface{}{
+			"includedNamespaces": []interface{}{"shop"},
+			"excludedResources":  []interface{}{"events"},
+			"labelSelector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "shop"},
+			},
+			"storageLocation":          "default",
+			"ttl":                      "720h0m0s",
+			"defaultVolumesToFsBackup": true,
+			"itemOperationTimeout":     "4h0m0s",
+			"hooks": map[string]interface{}{
+				"resources": []interface{}{
+					map[string]interface{}{"name": "freeze", "includedNamespaces": []interface{}{"shop"}},
+				},
+			},
+		},
+	}}
+
+	fsBackup := true
+	expected := &BackupSpec{
+		Name:      "shop",
+		Namespace: "velero",
+		BackupTemplate: BackupTemplate{
+			IncludedNamespaces:       []string{"shop"},
+			ExcludedResources:        []string{"events"},
+			LabelSelector:            &metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}},
+			StorageLocation:          "default",
+			TTL:                      "720h0m0s",
+			DefaultVolumesToFsBackup: &fsBackup,
+			Hooks: &BackupHooks{Resources: []BackupResourceHookSpec{
+				{Name: "freeze", IncludedNamespaces: []string{"shop"}},
+			}},
+		},
+	}
+
+	actual, err := toBackupSpec(item)
+	if err != nil {
+		t.Fatalf("toBackupSpec() returned error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toBackupSpec() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
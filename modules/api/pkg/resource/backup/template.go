@@ -0,0 +1,195 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"k8s.io/dashboard/api/pkg/resource/resourcepolicy"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+)
+
+// BackupTemplate describes what a backup contains and how it is taken. It is shared by backups and schedules, so that
+// scheduled backups can be configured the same way as ones created on demand.
+type BackupTemplate struct {
+	IncludedNamespaces []string                `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string                `json:"excludedNamespaces,omitempty"`
+	IncludedResources  []string                `json:"includedResources,omitempty"`
+	ExcludedResources  []string                `json:"excludedResources,omitempty"`
+	LabelSelector      *metav1.LabelSelector   `json:"labelSelector,omitempty"`
+	OrLabelSelectors   []*metav1.LabelSelector `json:"orLabelSelectors,omitempty"`
+	StorageLocation    string                  `json:"storageLocation,omitempty"`
+	TTL                string                  `json:"ttl,omitempty"`
+
+	// Cluster scoped resources are included with IncludeClusterResources together with IncludedResources and
+	// ExcludedResources, or with the cluster scoped resource filters, but not both.
+	IncludeClusterResources        *bool    `json:"includeClusterResources,omitempty"`
+	IncludedClusterScopedResources []string `json:"includedClusterScopedResources,omitempty"`
+	ExcludedClusterScopedResources []string `json:"excludedClusterScopedResources,omitempty"`
+
+	// OrderedResources maps a resource to names of its instances, e.g. "pods": "shop/db-0,shop/db-1", to back them up
+	// in that order.
+	OrderedResources map[string]string `json:"orderedResources,omitempty"`
+
+	// Volume data options, Velero defaults apply to the ones left unset.
+	SnapshotVolumes          *bool  `json:"snapshotVolumes,omitempty"`
+	DefaultVolumesToFsBackup *bool  `json:"defaultVolumesToFsBackup,omitempty"`
+	SnapshotMoveData         *bool  `json:"snapshotMoveData,omitempty"`
+	CSISnapshotTimeout       string `json:"csiSnapshotTimeout,omitempty"`
+
+	// ResourcePolicy references a ConfigMap in the backup namespace with the volume policies of the backup, see the
+	// resourcepolicy package. The kind defaults to configmap.
+	ResourcePolicy *v1.TypedLocalObjectReference `json:"resourcePolicy,omitempty"`
+
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+}
+
+// Validate rejects templates Velero would fail backups for.
+func (in *BackupTemplate) Validate() error {
+	if err := velero.ValidateDuration(field.NewPath("ttl"), in.TTL); err != nil {
+		return err
+	}
+
+	if err := velero.ValidateDuration(field.NewPath("csiSnapshotTimeout"), in.CSISnapshotTimeout); err != nil {
+		return err
+	}
+
+	if err := validateResourceFilters(in); err != nil {
+		return err
+	}
+
+	if in.ResourcePolicy != nil && in.ResourcePolicy.Name == "" {
+		return errors.NewBadRequest("resourcePolicy needs the name of a ConfigMap")
+	}
+
+	if in.Hooks != nil {
+		if err := validateHooks(in.Hooks); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateResourcePolicy rejects templates referencing a resource policy ConfigMap Velero would fail backups for. It
+// is kept apart from Validate, as it reads the ConfigMap from the namespace of the backup.
+func (in *BackupTemplate) ValidateResourcePolicy(ctx context.Context, request *http.Request, namespace string) error {
+	if in.ResourcePolicy == nil {
+		return nil
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return err
+	}
+
+	return resourcepolicy.ValidateReference(ctx, k8sClient, namespace, in.ResourcePolicy)
+}
+
+// ToUnstructured returns the template as the spec of a Velero backup, or the template of a Velero schedule.
+func (in *BackupTemplate) ToUnstructured() map[string]interface{} {
+	spec := map[string]interface{}{
+		"includedNamespaces": in.IncludedNamespaces,
+		"storageLocation":    in.StorageLocation,
+		"ttl":                in.TTL,
+	}
+
+	// Add optional fields if provided
+	if len(in.ExcludedNamespaces) > 0 {
+		spec["excludedNamespaces"] = in.ExcludedNamespaces
+	}
+	if len(in.IncludedResources) > 0 {
+		spec["includedResources"] = in.IncludedResources
+	}
+	if len(in.ExcludedResources) > 0 {
+		spec["excludedResources"] = in.ExcludedResources
+	}
+	if in.IncludeClusterResources != nil {
+		spec["includeClusterResources"] = *in.IncludeClusterResources
+	}
+	if len(in.IncludedClusterScopedResources) > 0 {
+		spec["includedClusterScopedResources"] = in.IncludedClusterScopedResources
+	}
+	if len(in.ExcludedClusterScopedResources) > 0 {
+		spec["excludedClusterScopedResources"] = in.ExcludedClusterScopedResources
+	}
+	if len(in.OrderedResources) > 0 {
+		spec["orderedResources"] = in.OrderedResources
+	}
+	if in.LabelSelector != nil {
+		spec["labelSelector"] = in.LabelSelector
+	}
+	if len(in.OrLabelSelectors) > 0 {
+		spec["orLabelSelectors"] = in.OrLabelSelectors
+	}
+	if in.SnapshotVolumes != nil {
+		spec["snapshotVolumes"] = *in.SnapshotVolumes
+	}
+	if in.DefaultVolumesToFsBackup != nil {
+		spec["defaultVolumesToFsBackup"] = *in.DefaultVolumesToFsBackup
+	}
+	if in.SnapshotMoveData != nil {
+		spec["snapshotMoveData"] = *in.SnapshotMoveData
+	}
+	if in.CSISnapshotTimeout != "" {
+		spec["csiSnapshotTimeout"] = in.CSISnapshotTimeout
+	}
+	if in.ResourcePolicy != nil {
+		kind := in.ResourcePolicy.Kind
+		if kind == "" {
+			kind = resourcepolicy.ConfigMapKind
+		}
+		spec["resourcePolicy"] = map[string]interface{}{"kind": kind, "name": in.ResourcePolicy.Name}
+	}
+	if in.Hooks != nil && len(in.Hooks.Resources) > 0 {
+		spec["hooks"] = in.Hooks
+	}
+
+	return spec
+}
+
+// NewBackupTemplate reads the template back from the spec of a Velero backup, or the template of a Velero schedule.
+// Fields the template does not support are dropped.
+func NewBackupTemplate(spec map[string]interface{}) (BackupTemplate, error) {
+	var template BackupTemplate
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &template); err != nil {
+		return template, errors.NewInvalid(fmt.Sprintf("invalid backup template: %s", err.Error()))
+	}
+
+	return template, nil
+}
+
+// validateResourceFilters rejects templates mixing the old resource filters with the cluster scoped ones, which
+// Velero refuses to run.
+func validateResourceFilters(template *BackupTemplate) error {
+	newFilters := len(template.IncludedClusterScopedResources) > 0 || len(template.ExcludedClusterScopedResources) > 0
+	oldFilters := template.IncludeClusterResources != nil || len(template.IncludedResources) > 0 ||
+		len(template.ExcludedResources) > 0
+	if newFilters && oldFilters {
+		return errors.NewBadRequest("cluster scoped resource filters cannot be combined with includeClusterResources, " +
+			"includedResources or excludedResources")
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateResourceFilters(t *testing.T) {
+	include := true
+	cases := []struct {
+		template *BackupTemplate
+		isError  bool
+	}{
+		{&BackupTemplate{IncludedResources: []string{"deployments"}, IncludeClusterResources: &include}, false},
+		{&BackupTemplate{IncludedClusterScopedResources: []string{"persistentvolumes"}}, false},
+		{&BackupTemplate{IncludedClusterScopedResources: []string{"persistentvolumes"}, IncludeClusterResources: &include}, true},
+		{&BackupTemplate{ExcludedClusterScopedResources: []string{"nodes"}, ExcludedResources: []string{"events"}}, true},
+	}
+
+	for _, c := range cases {
+		err := validateResourceFilters(c.template)
+		if (err != nil) != c.isError {
+			t.Errorf("validateResourceFilters(%#v) == %v, expected error: %t", c.template, err, c.isError)
+		}
+	}
+}
+
+func TestBackupTemplateValidate(t *testing.T) {
+	cases := []struct {
+		template *BackupTemplate
+		isError  bool
+	}{
+		{&BackupTemplate{}, false},
+		{&BackupTemplate{TTL: "720h", CSISnapshotTimeout: "10m"}, false},
+		{&BackupTemplate{TTL: "30d"}, true},
+		{&BackupTemplate{TTL: "-24h"}, true},
+		{&BackupTemplate{CSISnapshotTimeout: "soon"}, true},
+	}
+
+	for _, c := range cases {
+		err := c.template.Validate()
+		if (err != nil) != c.isError {
+			t.Errorf("Validate(%#v) == %v, expected error: %t", c.template, err, c.isError)
+		}
+	}
+}
+
+func TestBackupTemplateToUnstructured(t *testing.T) {
+	snapshot := false
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}
+	hooks := &BackupHooks{Resources: []BackupResourceHookSpec{{Name: "postgres"}}}
+	cases := []struct {
+		template *BackupTemplate
+		expected map[string]interface{}
+	}{
+		{
+			&BackupTemplate{},
+			map[string]interface{}{"includedNamespaces": []string(nil), "storageLocation": "", "ttl": ""},
+		},
+		{
+			&BackupTemplate{
+				IncludedNamespaces: []string{"shop"},
+				LabelSelector:      selector,
+				StorageLocation:    "default",
+				TTL:                "720h",
+				OrderedResources:   map[string]string{"pods": "shop/db-0,shop/db-1"},
+				SnapshotVolumes:    &snapshot,
+				CSISnapshotTimeout: "10m",
+				Hooks:              hooks,
+			},
+			map[string]interface{}{
+				"includedNamespaces": []string{"shop"},
+				"storageLocation":    "default",
+				"ttl":                "720h",
+				"labelSelector":      selector,
+				"orderedResources":   map[string]string{"pods": "shop/db-0,shop/db-1"},
+				"snapshotVolumes":    false,
+				"csiSnapshotTimeout": "10m",
+				"hooks":              hooks,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := c.template.ToUnstructured()
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ToUnstructured(%#v) == \n%#v\nexpected \n%#v\n", c.template, actual, c.expected)
+		}
+	}
+}
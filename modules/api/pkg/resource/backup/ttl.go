@@ -0,0 +1,124 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// BackupTTLSpec is the new time to live of a backup, counted from the time the backup started.
+type BackupTTLSpec struct {
+	TTL string `json:"ttl"`
+}
+
+// UpdateBackupTTL replaces the TTL of a backup and moves its expiration accordingly. Velero computes the expiration
+// only once the backup starts, so changing the TTL alone would not keep the backup from being garbage collected. In
+// dry run the patched backup is only validated by the API server.
+func UpdateBackupTTL(request *http.Request, namespace, name string, spec *BackupTTLSpec, dryRun bool) (*Backup, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	if spec.TTL == "" {
+		return nil, errors.NewBadRequest(field.Required(field.NewPath("ttl"), "").Error())
+	}
+
+	if err := velero.ValidateDuration(field.NewPath("ttl"), spec.TTL); err != nil {
+		return nil, err
+	}
+
+	if err := velero.CheckAccess(request, velero.VerbPatch, velero.BackupGVR, namespace, name); err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateBackupTTL(ctx, dynamicClient, namespace, name, spec.TTL, dryRun, time.Now())
+}
+
+func updateBackupTTL(ctx context.Context, client dynamic.Interface, namespace, name, ttl string, dryRun bool, now time.Time) (*Backup, error) {
+	resource := client.Resource(velero.BackupGVR).Namespace(namespace)
+	current, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	expiration, err := getExpiration(*current, ttl, now)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec":   map[string]interface{}{"ttl": ttl},
+		"status": map[string]interface{}{"expiration": expiration},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := resource.Patch(ctx, name, k8stypes.MergePatchType, patch, velero.PatchOptions(dryRun))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update backup TTL: %w", err)
+	}
+
+	// Status changes are dropped from patches of the main resource when the Backup CRD has the status subresource
+	// enabled, in which case they have to be sent to it separately.
+	if updated, _, _ := unstructured.NestedString(patched.Object, "status", "expiration"); updated != expiration {
+		patched, err = resource.Patch(ctx, name, k8stypes.MergePatchType, patch, velero.PatchOptions(dryRun), "status")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to update backup expiration: %w", err)
+		}
+	}
+
+	result := toBackup(*patched)
+	return &result, nil
+}
+
+// getExpiration returns the expiration Velero would have set for the TTL, rejecting ones that already passed, as
+// Velero would garbage collect the backup right away.
+func getExpiration(backup unstructured.Unstructured, ttl string, now time.Time) (string, error) {
+	duration, err := time.ParseDuration(ttl)
+	if err != nil {
+		return "", err
+	}
+
+	started := backup.GetCreationTimestamp().Time
+	if startTime := nestedTime(backup.Object, "status", "startTimestamp"); startTime != nil {
+		started = startTime.Time
+	}
+
+	expiration := started.Add(duration)
+	if !expiration.After(now) {
+		return "", errors.NewBadRequest(fmt.Sprintf("ttl %s would expire backup %s at %s, which has already passed", ttl,
+			backup.GetName(), expiration.UTC().Format(time.RFC3339)))
+	}
+
+	return expiration.UTC().Format(time.RFC3339), nil
+}
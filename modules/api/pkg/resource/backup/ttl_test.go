@@ -0,0 +1,86 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newTTLTestBackup(started time.Time) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Backup",
+		"metadata":   map[string]interface{}{"name": "daily", "namespace": "velero"},
+		"spec":       map[string]interface{}{"ttl": "72h0m0s"},
+		"status": map[string]interface{}{
+			"phase":          "Completed",
+			"startTimestamp": started.Format(time.RFC3339),
+			"expiration":     started.Add(72 * time.Hour).Format(time.RFC3339),
+		},
+	}}
+}
+
+func TestUpdateBackupTTL(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-48 * time.Hour)
+	dynamicClient := velerofake.NewDynamicClient(nil, newTTLTestBackup(started))
+
+	actual, err := updateBackupTTL(context.TODO(), dynamicClient, "velero", "daily", "720h", false, now)
+	if err != nil {
+		t.Fatalf("updateBackupTTL() returned error: %s", err.Error())
+	}
+
+	expiration := metav1.NewTime(started.Add(720 * time.Hour))
+	if !reflect.DeepEqual(actual.Expiration, &expiration) {
+		t.Errorf("updateBackupTTL() expiration == %v, expected %v", actual.Expiration, expiration)
+	}
+
+	updated, err := dynamicClient.Resource(velero.BackupGVR).Namespace("velero").Get(context.TODO(), "daily", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() returned error: %s", err.Error())
+	}
+
+	if ttl, _, _ := unstructured.NestedString(updated.Object, "spec", "ttl"); ttl != "720h" {
+		t.Errorf("updateBackupTTL() set ttl %q, expected %q", ttl, "720h")
+	}
+}
+
+func TestUpdateBackupTTLPassedExpiration(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-48 * time.Hour)
+	dynamicClient := velerofake.NewDynamicClient(nil, newTTLTestBackup(started))
+
+	if _, err := updateBackupTTL(context.TODO(), dynamicClient, "velero", "daily", "24h", false, now); err == nil {
+		t.Errorf("updateBackupTTL() with a passed expiration returned no error")
+	}
+
+	current, err := dynamicClient.Resource(velero.BackupGVR).Namespace("velero").Get(context.TODO(), "daily", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() returned error: %s", err.Error())
+	}
+
+	if ttl, _, _ := unstructured.NestedString(current.Object, "spec", "ttl"); ttl != "72h0m0s" {
+		t.Errorf("updateBackupTTL() changed ttl to %q, expected it to be kept", ttl)
+	}
+}
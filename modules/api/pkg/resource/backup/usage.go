@@ -0,0 +1,194 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+)
+
+// bytesPerGiB converts backup sizes to the unit prices are given in.
+const bytesPerGiB = 1 << 30
+
+// BackupUsageReport sums up the sizes of backups per value of a label, e.g. team or cost-center, so that protection
+// costs can be charged back. Sizes are the volume data moved to object storage, see BackupSize.
+type BackupUsageReport struct {
+	GroupBy string `json:"groupBy"`
+
+	// PricePerGiB of stored volume data, costs are only reported when it is set.
+	PricePerGiB float64 `json:"pricePerGiB,omitempty"`
+
+	// Groups are sorted by total bytes, largest first.
+	Groups []BackupUsageGroup `json:"groups"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// BackupUsageGroup is the usage of backups sharing the value of the label.
+type BackupUsageGroup struct {
+	// Value of the label, empty for backups it could not be told for.
+	Value string `json:"value"`
+
+	Backups    int   `json:"backups"`
+	TotalBytes int64 `json:"totalBytes"`
+	Volumes    int   `json:"volumes"`
+
+	// Cost of the total bytes at the price per GiB, nil without a price.
+	Cost *float64 `json:"cost,omitempty"`
+}
+
+// GetBackupUsageReport groups backups in namespaces matching the query by the value of the label key. The label is read
+// from the backup, or else from the namespaces it includes when they all have the same value. Namespaces that can not
+// be listed are reported as a non-critical error and leave such backups unassigned.
+func GetBackupUsageReport(request *http.Request, namespace *common.NamespaceQuery, groupBy string, pricePerGiB float64) (*BackupUsageReport, error) {
+	if errs := validation.IsQualifiedName(groupBy); len(errs) > 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid label key %q to group by: %s", groupBy, strings.Join(errs, ", ")))
+	}
+	if pricePerGiB < 0 {
+		return nil, errors.NewBadRequest("price per GiB can not be negative")
+	}
+
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	items, nonCriticalErrors, err := getBackups(ctx, dynamicClient, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	volumeData, volumeErrors, err := getVolumeData(ctx, dynamicClient, namespace.ToRequestParam(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nonCriticalErrors = append(nonCriticalErrors, volumeErrors...)
+
+	namespaceLabels, namespaceErrors, err := getNamespaceLabels(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	nonCriticalErrors = append(nonCriticalErrors, namespaceErrors...)
+
+	report := toBackupUsageReport(items, volumeData, namespaceLabels, groupBy, pricePerGiB)
+	report.Errors = nonCriticalErrors
+	return report, nil
+}
+
+// getNamespaceLabels returns the labels of the namespaces of the cluster by name, nil along a non-critical error when
+// they can not be listed.
+func getNamespaceLabels(ctx context.Context, client kubernetes.Interface) (map[string]map[string]string, []error, error) {
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		nonCriticalErrors, criticalError := errors.ExtractErrors(err)
+		return nil, nonCriticalErrors, criticalError
+	}
+
+	result := make(map[string]map[string]string, len(namespaces.Items))
+	for _, item := range namespaces.Items {
+		result[item.Name] = item.Labels
+	}
+
+	return result, nil, nil
+}
+
+func toBackupUsageReport(items, volumeData []unstructured.Unstructured, namespaceLabels map[string]map[string]string,
+	groupBy string, pricePerGiB float64) *BackupUsageReport {
+	backups := make([]Backup, 0, len(items))
+	for _, item := range items {
+		backups = append(backups, toBackup(item))
+	}
+	addBackupSizes(backups, volumeData)
+
+	groups := make(map[string]*BackupUsageGroup)
+	for i, item := range items {
+		value := getUsageValue(item, namespaceLabels, groupBy)
+		if groups[value] == nil {
+			groups[value] = &BackupUsageGroup{Value: value}
+		}
+
+		group := groups[value]
+		group.Backups++
+		if size := backups[i].Size; size != nil {
+			group.TotalBytes += size.TotalBytes
+			group.Volumes += size.Volumes
+		}
+	}
+
+	report := &BackupUsageReport{GroupBy: groupBy, PricePerGiB: pricePerGiB, Groups: make([]BackupUsageGroup, 0, len(groups))}
+	for _, group := range groups {
+		if pricePerGiB > 0 {
+			cost := float64(group.TotalBytes) / bytesPerGiB * pricePerGiB
+			group.Cost = &cost
+		}
+		report.Groups = append(report.Groups, *group)
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].TotalBytes != report.Groups[j].TotalBytes {
+			return report.Groups[i].TotalBytes > report.Groups[j].TotalBytes
+		}
+		return report.Groups[i].Value < report.Groups[j].Value
+	})
+
+	return report
+}
+
+// getUsageValue returns the value of the label of the backup, or else the one its included namespaces agree on. Backups
+// of all namespaces or of glob patterns are only assigned by their own label.
+func getUsageValue(item unstructured.Unstructured, namespaceLabels map[string]map[string]string, groupBy string) string {
+	if value := item.GetLabels()[groupBy]; value != "" {
+		return value
+	}
+
+	included, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "includedNamespaces")
+	if len(included) == 0 || namespaceLabels == nil || slices.ContainsFunc(included, func(namespace string) bool {
+		return strings.ContainsAny(namespace, "*?[")
+	}) {
+		return ""
+	}
+
+	value := namespaceLabels[included[0]][groupBy]
+	for _, namespace := range included[1:] {
+		if namespaceLabels[namespace][groupBy] != value {
+			return ""
+		}
+	}
+
+	return value
+}
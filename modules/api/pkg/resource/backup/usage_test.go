@@ -0,0 +1,87 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newUsageBackup(name string, labels map[string]string, includedNamespaces ...string) unstructured.Unstructured {
+	namespaces := make([]interface{}, 0, len(includedNamespaces))
+	for _, namespace := range includedNamespaces {
+		namespaces = append(namespaces, namespace)
+	}
+
+	item := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"includedNamespaces": namespaces},
+	}}
+	item.SetName(name)
+	item.SetNamespace("velero")
+	item.SetLabels(labels)
+	return item
+}
+
+func TestToBackupUsageReport(t *testing.T) {
+	items := []unstructured.Unstructured{
+		newUsageBackup("shop-daily", map[string]string{"team": "shop"}, "*"),
+		newUsageBackup("billing-daily", nil, "billing"),
+		newUsageBackup("mixed", nil, "shop-eu", "billing"),
+		newUsageBackup("shop-weekly", nil, "shop-eu", "shop-us"),
+		newUsageBackup("cluster", nil),
+	}
+	volumeData := []unstructured.Unstructured{
+		*newVolumeData("PodVolumeBackup", "shop-daily-1", "shop-daily", 1<<30, 1<<30),
+		*newVolumeData("DataUpload", "billing-daily-1", "billing-daily", 1<<29, 1<<29),
+	}
+	namespaceLabels := map[string]map[string]string{
+		"billing": {"team": "billing"},
+		"shop-eu": {"team": "shop"},
+		"shop-us": {"team": "shop"},
+	}
+	cost := func(value float64) *float64 { return &value }
+
+	cases := []struct {
+		info            string
+		namespaceLabels map[string]map[string]string
+		pricePerGiB     float64
+		expected        []BackupUsageGroup
+	}{
+		{
+			"groups by backup and namespace labels with costs", namespaceLabels, 0.02,
+			[]BackupUsageGroup{
+				{Value: "shop", Backups: 2, TotalBytes: 1 << 30, Volumes: 1, Cost: cost(0.02)},
+				{Value: "billing", Backups: 1, TotalBytes: 1 << 29, Volumes: 1, Cost: cost(0.01)},
+				{Value: "", Backups: 2, Cost: cost(0)},
+			},
+		},
+		{
+			"groups by backup labels only without namespaces", nil, 0,
+			[]BackupUsageGroup{
+				{Value: "shop", Backups: 1, TotalBytes: 1 << 30, Volumes: 1},
+				{Value: "", Backups: 4, TotalBytes: 1 << 29, Volumes: 1},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toBackupUsageReport(items, volumeData, c.namespaceLabels, "team", c.pricePerGiB)
+		if !reflect.DeepEqual(actual.Groups, c.expected) {
+			t.Errorf("%s: toBackupUsageReport() == \n%#v\nexpected \n%#v\n", c.info, actual.Groups, c.expected)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+const (
+	// BackupNameLabel is the label Velero puts on pod volume backups and other resources created for a backup.
+	BackupNameLabel = "velero.io/backup-name"
+	// BackupUIDLabel is the label Velero puts on delete backup requests to tell which backup they delete.
+	BackupUIDLabel = "velero.io/backup-uid"
+
+	// Labels Velero puts on a backup repository to tell which volumes it stores.
+	repositoryVolumeNamespaceLabel = "velero.io/volume-namespace"
+	repositoryStorageLocationLabel = "velero.io/storage-location"
+	repositoryRepositoryTypeLabel  = "velero.io/repository-type"
+)
+
+// VolumeBackup describes a pod volume backed up by the file system uploader and the repository its data went to.
+type VolumeBackup struct {
+	Name         string `json:"name"`
+	PodNamespace string `json:"podNamespace"`
+	PodName      string `json:"podName"`
+	Volume       string `json:"volume"`
+	Phase        string `json:"phase,omitempty"`
+
+	// UploaderType is kopia or restic.
+	UploaderType string `json:"uploaderType"`
+
+	// Repository is the name of the backup repository, empty when it does not exist anymore.
+	Repository string `json:"repository,omitempty"`
+
+	TotalBytes int64 `json:"totalBytes"`
+	BytesDone  int64 `json:"bytesDone"`
+}
+
+// repositoryKey identifies the backup repository of a namespace's volumes in a storage location. Velero keeps one
+// repository per uploader type.
+type repositoryKey struct {
+	volumeNamespace, storageLocation, repositoryType string
+}
+
+// getVolumeBackups returns pod volume backups of the backup, sorted by pod and volume.
+func getVolumeBackups(ctx context.Context, client dynamic.Interface, namespace, backupName string) ([]VolumeBackup, error) {
+	selector := labels.SelectorFromSet(labels.Set{BackupNameLabel: backupName}).String()
+	podVolumeBackups, err := client.Resource(velero.PodVolumeBackupGVR).Namespace(namespace).
+		List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(podVolumeBackups.Items) == 0 {
+		return make([]VolumeBackup, 0), nil
+	}
+
+	repositories, err := client.Resource(velero.BackupRepositoryGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toVolumeBackups(podVolumeBackups.Items, repositories.Items), nil
+}
+
+func toVolumeBackups(podVolumeBackups, repositories []unstructured.Unstructured) []VolumeBackup {
+	repositoryNames := make(map[repositoryKey]string, len(repositories))
+	for _, item := range repositories {
+		itemLabels := item.GetLabels()
+		key := repositoryKey{
+			itemLabels[repositoryVolumeNamespaceLabel],
+			itemLabels[repositoryStorageLocationLabel],
+			itemLabels[repositoryRepositoryTypeLabel],
+		}
+		repositoryNames[key] = item.GetName()
+	}
+
+	result := make([]VolumeBackup, 0, len(podVolumeBackups))
+	for _, item := range podVolumeBackups {
+		podNamespace, _, _ := unstructured.NestedString(item.Object, "spec", "pod", "namespace")
+		podName, _, _ := unstructured.NestedString(item.Object, "spec", "pod", "name")
+		volume, _, _ := unstructured.NestedString(item.Object, "spec", "volume")
+		storageLocation, _, _ := unstructured.NestedString(item.Object, "spec", "backupStorageLocation")
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		uploaderType, _, _ := unstructured.NestedString(item.Object, "spec", "uploaderType")
+		totalBytes, _, _ := unstructured.NestedInt64(item.Object, "status", "progress", "totalBytes")
+		bytesDone, _, _ := unstructured.NestedInt64(item.Object, "status", "progress", "bytesDone")
+		if uploaderType == "" {
+			// Velero versions before 1.10 only had restic and did not record the uploader.
+			uploaderType = "restic"
+		}
+
+		result = append(result, VolumeBackup{
+			Name:         item.GetName(),
+			PodNamespace: podNamespace,
+			PodName:      podName,
+			Volume:       volume,
+			Phase:        phase,
+			UploaderType: uploaderType,
+			Repository:   repositoryNames[repositoryKey{podNamespace, storageLocation, uploaderType}],
+			TotalBytes:   totalBytes,
+			BytesDone:    bytesDone,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PodNamespace != result[j].PodNamespace {
+			return result[i].PodNamespace < result[j].PodNamespace
+		}
+		if result[i].PodName != result[j].PodName {
+			return result[i].PodName < result[j].PodName
+		}
+		return result[i].Volume < result[j].Volume
+	})
+
+	return result
+}
+
+// getUploaderTypes returns the distinct uploader types used by the volume backups, sorted.
+func getUploaderTypes(volumeBackups []VolumeBackup) []string {
+	result := make([]string, 0)
+	for _, item := range volumeBackups {
+		if !slices.Contains(result, item.UploaderType) {
+			result = append(result, item.UploaderType)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
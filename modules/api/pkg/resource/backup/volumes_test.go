@@ -0,0 +1,82 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newPodVolumeBackup(name, podName, volume, uploaderType string) unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"pod":                   map[string]interface{}{"namespace": "shop", "name": podName},
+		"volume":                volume,
+		"backupStorageLocation": "default",
+	}
+	if uploaderType != "" {
+		spec["uploaderType"] = uploaderType
+	}
+
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": "velero"},
+		"spec":     spec,
+		"status":   map[string]interface{}{"phase": "Completed"},
+	}}
+}
+
+func newBackupRepository(name, repositoryType string) unstructured.Unstructured {
+	item := unstructured.Unstructured{Object: map[string]interface{}{}}
+	item.SetName(name)
+	item.SetLabels(map[string]string{
+		repositoryVolumeNamespaceLabel: "shop",
+		repositoryStorageLocationLabel: "default",
+		repositoryRepositoryTypeLabel:  repositoryType,
+	})
+	return item
+}
+
+func TestToVolumeBackups(t *testing.T) {
+	podVolumeBackups := []unstructured.Unstructured{
+		newPodVolumeBackup("daily-x7k2p", "web-1", "uploads", "kopia"),
+		newPodVolumeBackup("daily-9fq4z", "db-0", "data", ""),
+	}
+	repositories := []unstructured.Unstructured{
+		newBackupRepository("shop-default-kopia-5v8tb", "kopia"),
+	}
+
+	expected := []VolumeBackup{
+		{Name: "daily-9fq4z", PodNamespace: "shop", PodName: "db-0", Volume: "data", Phase: "Completed", UploaderType: "restic"},
+		{
+			Name:         "daily-x7k2p",
+			PodNamespace: "shop",
+			PodName:      "web-1",
+			Volume:       "uploads",
+			Phase:        "Completed",
+			UploaderType: "kopia",
+			Repository:   "shop-default-kopia-5v8tb",
+		},
+	}
+
+	actual := toVolumeBackups(podVolumeBackups, repositories)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toVolumeBackups() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+
+	if uploaderTypes := getUploaderTypes(actual); !reflect.DeepEqual(uploaderTypes, []string{"kopia", "restic"}) {
+		t.Errorf("getUploaderTypes() == %v, expected [kopia restic]", uploaderTypes)
+	}
+}
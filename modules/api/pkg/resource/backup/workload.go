@@ -0,0 +1,357 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+// WorkloadLabel is put on a workload and its dependencies so that a single backup can select all of them.
+const WorkloadLabel = "dashboard.kubernetes.io/backup-workload"
+
+// WorkloadReference points to a Deployment or StatefulSet that should be backed up.
+type WorkloadReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// WorkloadBackupSpec represents the specification for backing up a single workload.
+type WorkloadBackupSpec struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	Workload        WorkloadReference `json:"workload"`
+	StorageLocation string            `json:"storageLocation,omitempty"`
+	TTL             string            `json:"ttl,omitempty"`
+}
+
+// WorkloadBackupPlan contains the backup spec generated for a workload and the dependencies it selects.
+type WorkloadBackupPlan struct {
+	Spec         BackupSpec           `json:"spec"`
+	Dependencies []WorkloadDependency `json:"dependencies"`
+}
+
+// WorkloadDependency is a ConfigMap, Secret or PersistentVolumeClaim used by pods of the workload.
+type WorkloadDependency struct {
+	Kind types.ResourceKind `json:"kind"`
+	Name string             `json:"name"`
+}
+
+// workload holds what is needed from a Deployment or StatefulSet to plan its backup.
+type workload struct {
+	kind     types.ResourceKind
+	uid      k8stypes.UID
+	selector *metav1.LabelSelector
+	template v1.PodSpec
+}
+
+// GetWorkloadBackupPlan generates a backup spec selecting the workload, its pods and the ConfigMaps, Secrets and
+// PersistentVolumeClaims they use. Nothing is changed in the cluster.
+func GetWorkloadBackupPlan(request *http.Request, spec *WorkloadBackupSpec) (*WorkloadBackupPlan, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getWorkloadBackupPlan(ctx, k8sClient, spec)
+}
+
+// CreateWorkloadBackup labels the workload and its dependencies with WorkloadLabel and creates a backup selecting
+// them. In dry run neither the labels nor the backup are persisted.
+func CreateWorkloadBackup(request *http.Request, spec *WorkloadBackupSpec, dryRun bool) (*Backup, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := getWorkloadBackupPlan(ctx, k8sClient, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checked before labelling, which would otherwise be left behind by a forbidden backup.
+	if err := velero.CheckAccess(request, velero.VerbCreate, velero.BackupGVR, plan.Spec.Namespace, plan.Spec.Name); err != nil {
+		return nil, err
+	}
+
+	ref := spec.Workload
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{WorkloadLabel: ref.Name},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := labelResource(ctx, k8sClient, types.ResourceKind(strings.ToLower(ref.Kind)), ref.Namespace, ref.Name, patch,
+		dryRun); err != nil {
+		return nil, fmt.Errorf("Failed to label workload: %w", err)
+	}
+
+	for _, dependency := range plan.Dependencies {
+		err := labelResource(ctx, k8sClient, dependency.Kind, ref.Namespace, dependency.Name, patch, dryRun)
+		// Optional references may point to resources that do not exist.
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("Failed to label %s %s: %w", dependency.Kind, dependency.Name, err)
+		}
+	}
+
+	return CreateBackup(request, &plan.Spec, dryRun)
+}
+
+func getWorkloadBackupPlan(ctx context.Context, client kubernetes.Interface, spec *WorkloadBackupSpec) (*WorkloadBackupPlan, error) {
+	ref := spec.Workload
+	if ref.Namespace == "" || ref.Name == "" {
+		return nil, errors.NewBadRequest("workload namespace and name are required")
+	}
+	if msgs := validation.IsValidLabelValue(ref.Name); len(msgs) > 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("workload name %q can not be used as a label value: %s", ref.Name, strings.Join(msgs, ", ")))
+	}
+
+	target, err := getWorkload(ctx, client, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := getWorkloadPods(ctx, client, ref.Namespace, target)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := []v1.PodSpec{target.template}
+	for _, pod := range pods {
+		specs = append(specs, pod.Spec)
+	}
+
+	return toWorkloadBackupPlan(spec, target, specs), nil
+}
+
+func getWorkload(ctx context.Context, client kubernetes.Interface, ref WorkloadReference) (*workload, error) {
+	switch types.ResourceKind(strings.ToLower(ref.Kind)) {
+	case types.ResourceKindDeployment:
+		deployment, err := client.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{kind: types.ResourceKindDeployment, uid: deployment.UID,
+			selector: deployment.Spec.Selector, template: deployment.Spec.Template.Spec}, nil
+	case types.ResourceKindStatefulSet:
+		statefulSet, err := client.AppsV1().StatefulSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{kind: types.ResourceKindStatefulSet, uid: statefulSet.UID,
+			selector: statefulSet.Spec.Selector, template: statefulSet.Spec.Template.Spec}, nil
+	default:
+		return nil, errors.NewBadRequest(fmt.Sprintf("unsupported workload kind %q, expected Deployment or StatefulSet", ref.Kind))
+	}
+}
+
+// getWorkloadPods returns pods owned by the workload, directly for StatefulSets and through ReplicaSets for
+// Deployments.
+func getWorkloadPods(ctx context.Context, client kubernetes.Interface, namespace string, target *workload) ([]v1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(target.selector)
+	if err != nil {
+		return nil, err
+	}
+	options := metav1.ListOptions{LabelSelector: selector.String()}
+
+	owners := map[k8stypes.UID]bool{target.uid: true}
+	if target.kind == types.ResourceKindDeployment {
+		replicaSets, err := client.AppsV1().ReplicaSets(namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		owners = make(map[k8stypes.UID]bool)
+		for _, replicaSet := range replicaSets.Items {
+			if isOwnedBy(replicaSet.OwnerReferences, target.uid) {
+				owners[replicaSet.UID] = true
+			}
+		}
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]v1.Pod, 0)
+	for _, pod := range pods.Items {
+		if controller := metav1.GetControllerOf(&pod); controller != nil && owners[controller.UID] {
+			result = append(result, pod)
+		}
+	}
+
+	return result, nil
+}
+
+func isOwnedBy(references []metav1.OwnerReference, uid k8stypes.UID) bool {
+	for _, reference := range references {
+		if reference.UID == uid {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toWorkloadBackupPlan(spec *WorkloadBackupSpec, target *workload, podSpecs []v1.PodSpec) *WorkloadBackupPlan {
+	resources := []string{string(target.kind) + "s"}
+	if target.kind == types.ResourceKindDeployment {
+		resources = append(resources, "replicasets")
+	}
+	resources = append(resources, "pods", "configmaps", "secrets", "persistentvolumeclaims", "persistentvolumes")
+
+	return &WorkloadBackupPlan{
+		Spec: BackupSpec{
+			Name:      spec.Name,
+			Namespace: spec.Namespace,
+			BackupTemplate: BackupTemplate{
+				IncludedNamespaces: []string{spec.Workload.Namespace},
+				IncludedResources:  resources,
+				// Pods and ReplicaSets are recreated by their controllers, so they are selected by the workload
+				// selector instead of being labeled.
+				OrLabelSelectors: []*metav1.LabelSelector{
+					{MatchLabels: map[string]string{WorkloadLabel: spec.Workload.Name}},
+					target.selector,
+				},
+				StorageLocation: spec.StorageLocation,
+				TTL:             spec.TTL,
+			},
+		},
+		Dependencies: getPodSpecDependencies(podSpecs),
+	}
+}
+
+// getPodSpecDependencies returns sorted ConfigMaps, Secrets and PersistentVolumeClaims referenced from pod specs
+// through volumes, environment variables and image pull secrets.
+func getPodSpecDependencies(podSpecs []v1.PodSpec) []WorkloadDependency {
+	found := make(map[WorkloadDependency]bool)
+	add := func(kind types.ResourceKind, name string) {
+		if name != "" {
+			found[WorkloadDependency{Kind: kind, Name: name}] = true
+		}
+	}
+
+	for _, podSpec := range podSpecs {
+		for _, volume := range podSpec.Volumes {
+			if volume.ConfigMap != nil {
+				add(types.ResourceKindConfigMap, volume.ConfigMap.Name)
+			}
+			if volume.Secret != nil {
+				add(types.ResourceKindSecret, volume.Secret.SecretName)
+			}
+			if volume.PersistentVolumeClaim != nil {
+				add(types.ResourceKindPersistentVolumeClaim, volume.PersistentVolumeClaim.ClaimName)
+			}
+			if volume.Projected != nil {
+				for _, source := range volume.Projected.Sources {
+					if source.ConfigMap != nil {
+						add(types.ResourceKindConfigMap, source.ConfigMap.Name)
+					}
+					if source.Secret != nil {
+						add(types.ResourceKindSecret, source.Secret.Name)
+					}
+				}
+			}
+		}
+
+		containers := append(append([]v1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+		for _, container := range containers {
+			for _, envFrom := range container.EnvFrom {
+				if envFrom.ConfigMapRef != nil {
+					add(types.ResourceKindConfigMap, envFrom.ConfigMapRef.Name)
+				}
+				if envFrom.SecretRef != nil {
+					add(types.ResourceKindSecret, envFrom.SecretRef.Name)
+				}
+			}
+			for _, env := range container.Env {
+				if env.ValueFrom == nil {
+					continue
+				}
+				if env.ValueFrom.ConfigMapKeyRef != nil {
+					add(types.ResourceKindConfigMap, env.ValueFrom.ConfigMapKeyRef.Name)
+				}
+				if env.ValueFrom.SecretKeyRef != nil {
+					add(types.ResourceKindSecret, env.ValueFrom.SecretKeyRef.Name)
+				}
+			}
+		}
+
+		for _, pullSecret := range podSpec.ImagePullSecrets {
+			add(types.ResourceKindSecret, pullSecret.Name)
+		}
+	}
+
+	result := make([]WorkloadDependency, 0, len(found))
+	for dependency := range found {
+		result = append(result, dependency)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func labelResource(ctx context.Context, client kubernetes.Interface, kind types.ResourceKind, namespace, name string, patch []byte,
+	dryRun bool) error {
+	options := velero.PatchOptions(dryRun)
+	var err error
+	switch kind {
+	case types.ResourceKindDeployment:
+		_, err = client.AppsV1().Deployments(namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, options)
+	case types.ResourceKindStatefulSet:
+		_, err = client.AppsV1().StatefulSets(namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, options)
+	case types.ResourceKindConfigMap:
+		_, err = client.CoreV1().ConfigMaps(namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, options)
+	case types.ResourceKindSecret:
+		_, err = client.CoreV1().Secrets(namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, options)
+	case types.ResourceKindPersistentVolumeClaim:
+		_, err = client.CoreV1().PersistentVolumeClaims(namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, options)
+	default:
+		err = fmt.Errorf("unsupported kind %q", kind)
+	}
+
+	return err
+}
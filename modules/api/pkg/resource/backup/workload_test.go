@@ -0,0 +1,129 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/samber/lo"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/dashboard/types"
+)
+
+func TestGetWorkloadBackupPlan(t *testing.T) {
+	podLabels := map[string]string{"app": "web"}
+	selector := &metav1.LabelSelector{MatchLabels: podLabels}
+	deployment := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: "deployment-uid"},
+		Spec: apps.DeploymentSpec{
+			Selector: selector,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Volumes: []v1.Volume{
+						{Name: "config", VolumeSource: v1.VolumeSource{
+							ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-config"}}}},
+						{Name: "data", VolumeSource: v1.VolumeSource{
+							PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "web-data"}}},
+					},
+					Containers: []v1.Container{{
+						Name: "web",
+						EnvFrom: []v1.EnvFromSource{
+							{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-credentials"}}},
+						},
+					}},
+					ImagePullSecrets: []v1.LocalObjectReference{{Name: "registry"}},
+				},
+			},
+		},
+	}
+	replicaSet := &apps.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", UID: "replicaset-uid", Labels: podLabels,
+			OwnerReferences: []metav1.OwnerReference{{UID: "deployment-uid", Controller: lo.ToPtr(true)}}},
+	}
+	ownedPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1-a", Namespace: "shop", Labels: podLabels,
+			OwnerReferences: []metav1.OwnerReference{{UID: "replicaset-uid", Controller: lo.ToPtr(true)}}},
+		Spec: v1.PodSpec{
+			Volumes: []v1.Volume{
+				{Name: "token", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{
+					Sources: []v1.VolumeProjection{{Secret: &v1.SecretProjection{LocalObjectReference: v1.LocalObjectReference{Name: "web-token"}}}}}}},
+			},
+		},
+	}
+	strayPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "shop", Labels: podLabels},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name: "debug",
+				Env: []v1.EnvVar{{Name: "KEY", ValueFrom: &v1.EnvVarSource{
+					SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "unrelated"}}}}},
+			}},
+		},
+	}
+
+	client := fake.NewSimpleClientset(deployment, replicaSet, ownedPod, strayPod)
+	spec := &WorkloadBackupSpec{
+		Name:      "web-backup",
+		Namespace: "velero",
+		Workload:  WorkloadReference{Kind: "Deployment", Namespace: "shop", Name: "web"},
+		TTL:       "72h0m0s",
+	}
+
+	expected := &WorkloadBackupPlan{
+		Spec: BackupSpec{
+			Name:      "web-backup",
+			Namespace: "velero",
+			BackupTemplate: BackupTemplate{
+				IncludedNamespaces: []string{"shop"},
+				IncludedResources: []string{"deployments", "replicasets", "pods", "configmaps", "secrets",
+					"persistentvolumeclaims", "persistentvolumes"},
+				OrLabelSelectors: []*metav1.LabelSelector{
+					{MatchLabels: map[string]string{WorkloadLabel: "web"}},
+					selector,
+				},
+				TTL: "72h0m0s",
+			},
+		},
+		Dependencies: []WorkloadDependency{
+			{Kind: types.ResourceKindConfigMap, Name: "web-config"},
+			{Kind: types.ResourceKindPersistentVolumeClaim, Name: "web-data"},
+			{Kind: types.ResourceKindSecret, Name: "registry"},
+			{Kind: types.ResourceKindSecret, Name: "web-credentials"},
+			{Kind: types.ResourceKindSecret, Name: "web-token"},
+		},
+	}
+
+	actual, err := getWorkloadBackupPlan(context.TODO(), client, spec)
+	if err != nil {
+		t.Fatalf("getWorkloadBackupPlan(%#v) returned error: %s", spec, err.Error())
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getWorkloadBackupPlan(%#v) == \n%#v\nexpected \n%#v\n", spec, actual, expected)
+	}
+}
+
+func TestGetWorkloadBackupPlanInvalidKind(t *testing.T) {
+	spec := &WorkloadBackupSpec{Workload: WorkloadReference{Kind: "DaemonSet", Namespace: "shop", Name: "web"}}
+	if _, err := getWorkloadBackupPlan(context.TODO(), fake.NewSimpleClientset(), spec); err == nil {
+		t.Errorf("getWorkloadBackupPlan(%#v) expected error", spec)
+	}
+}
@@ -35,6 +35,11 @@ var NoMetrics = NewMetricQuery(nil, nil)
 var StandardMetrics = NewMetricQuery([]string{metricapi.CpuUsage, metricapi.MemoryUsage},
 	metricapi.OnlySumAggregation)
 
+// VeleroMetrics query results in the Velero backup metrics being returned, summed over all schedules.
+var VeleroMetrics = NewMetricQuery([]string{metricapi.VeleroBackupDurationSeconds, metricapi.VeleroBackupAttemptTotal,
+	metricapi.VeleroBackupSuccessTotal, metricapi.VeleroBackupPartialFailureTotal, metricapi.VeleroBackupFailureTotal},
+	metricapi.OnlySumAggregation)
+
 // MetricQuery holds parameters for metric extraction process.
 // It accepts list of metrics to be downloaded and a list of aggregations that should be performed for each metric.
 // Query has this format  metrics=metric1,metric2,...&aggregations=aggregation1,aggregation2,...
@@ -28,4 +28,8 @@ const (
 	FirstSeenProperty         = "firstSeen"
 	LastSeenProperty          = "lastSeen"
 	ReasonProperty            = "reason"
+	StartTimeProperty         = "startTime"
+	CompletionTimeProperty    = "completionTime"
+	ExpirationProperty        = "expiration"
+	LastBackupProperty        = "lastBackup"
 )
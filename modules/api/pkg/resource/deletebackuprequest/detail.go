@@ -0,0 +1,65 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deletebackuprequest
+
+import (
+	"context"
+	"net/http"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// DeleteBackupRequestDetail contains a delete backup request and whether its backup is gone.
+type DeleteBackupRequestDetail struct {
+	DeleteBackupRequest `json:",inline"`
+
+	// BackupDeleted is true once the backup does not exist anymore. Together with a processed request without errors
+	// it means the backup data was removed.
+	BackupDeleted bool `json:"backupDeleted"`
+}
+
+// GetDeleteBackupRequestDetail returns a delete backup request in the namespace.
+func GetDeleteBackupRequestDetail(request *http.Request, namespace, name string) (*DeleteBackupRequestDetail, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getDeleteBackupRequestDetail(ctx, dynamicClient, namespace, name)
+}
+
+func getDeleteBackupRequestDetail(ctx context.Context, client dynamic.Interface, namespace, name string) (*DeleteBackupRequestDetail, error) {
+	item, err := client.Resource(velero.DeleteBackupRequestGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &DeleteBackupRequestDetail{DeleteBackupRequest: toDeleteBackupRequest(*item)}
+	_, err = client.Resource(velero.BackupGVR).Namespace(namespace).Get(ctx, detail.BackupName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		detail.BackupDeleted = true
+	} else if err != nil {
+		return nil, err
+	}
+
+	return detail, nil
+}
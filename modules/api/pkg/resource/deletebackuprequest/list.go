@@ -0,0 +1,142 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deletebackuprequest
+
+import (
+	"context"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+// DeleteBackupRequestList contains a list of Velero delete backup requests.
+type DeleteBackupRequestList struct {
+	ListMeta types.ListMeta        `json:"listMeta"`
+	Status   velero.ListStatus     `json:"status"`
+	Items    []DeleteBackupRequest `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// DeleteBackupRequest asks Velero to delete a backup together with its data in object storage and its volume
+// snapshots. Velero keeps processed requests for a day, so that the outcome of a deletion can be checked.
+type DeleteBackupRequest struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	BackupName string                          `json:"backupName"`
+	Phase      velero.DeleteBackupRequestPhase `json:"phase,omitempty"`
+
+	// Status is failed when Velero processed the request but could not delete all of the backup data.
+	Status velero.Status `json:"status"`
+
+	// DeletionErrors are the errors Velero ran into while deleting the backup data.
+	DeletionErrors []string `json:"deletionErrors,omitempty"`
+}
+
+// GetDeleteBackupRequestList returns the delete backup requests in the namespaces matching the query.
+func GetDeleteBackupRequestList(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*DeleteBackupRequestList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	items, nonCriticalErrors, err := GetDeleteBackupRequests(ctx, dynamicClient, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toDeleteBackupRequestList(items, nonCriticalErrors, dsQuery), nil
+}
+
+// GetDeleteBackupRequests lists the delete backup requests in the namespaces matching the query.
+func GetDeleteBackupRequests(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery, options metav1.ListOptions) ([]DeleteBackupRequest, []error, error) {
+	items, nonCriticalErrors, err := velero.ListInNamespaces(ctx, client, velero.DeleteBackupRequestGVR, namespace,
+		options, args.VeleroMaxListItems())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requests := make([]DeleteBackupRequest, 0, len(items))
+	for _, item := range items {
+		requests = append(requests, toDeleteBackupRequest(item))
+	}
+
+	return requests, nonCriticalErrors, nil
+}
+
+// GetLatestPerBackup returns the most recent request for each backup, keyed by the namespace and name of the backup.
+func GetLatestPerBackup(requests []DeleteBackupRequest) map[k8stypes.NamespacedName]*DeleteBackupRequest {
+	result := make(map[k8stypes.NamespacedName]*DeleteBackupRequest)
+	for i := range requests {
+		item := &requests[i]
+		key := k8stypes.NamespacedName{Namespace: item.ObjectMeta.Namespace, Name: item.BackupName}
+		if latest, ok := result[key]; !ok || latest.ObjectMeta.CreationTimestamp.Before(&item.ObjectMeta.CreationTimestamp) {
+			result[key] = item
+		}
+	}
+
+	return result
+}
+
+func toDeleteBackupRequestList(requests []DeleteBackupRequest, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *DeleteBackupRequestList {
+	status := velero.ListStatus{}
+	for _, item := range requests {
+		status.Add(item.Status)
+	}
+
+	requestCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(requests), dsQuery)
+	return &DeleteBackupRequestList{
+		ListMeta: types.ListMeta{TotalItems: filteredTotal},
+		Status:   status,
+		Items:    fromCells(requestCells),
+		Errors:   nonCriticalErrors,
+	}
+}
+
+func toDeleteBackupRequest(item unstructured.Unstructured) DeleteBackupRequest {
+	backupName, _, _ := unstructured.NestedString(item.Object, "spec", "backupName")
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	deletionErrors, _, _ := unstructured.NestedStringSlice(item.Object, "status", "errors")
+
+	request := DeleteBackupRequest{
+		ObjectMeta: velero.NewObjectMeta(item.Object),
+		TypeMeta: types.TypeMeta{
+			Kind: "DeleteBackupRequest",
+		},
+		BackupName:     backupName,
+		Phase:          velero.DeleteBackupRequestPhase(phase),
+		Status:         velero.DeleteBackupRequestPhase(phase).Status(),
+		DeletionErrors: deletionErrors,
+	}
+	if request.Status == velero.StatusSucceeded && len(deletionErrors) > 0 {
+		request.Status = velero.StatusFailed
+	}
+
+	return request
+}
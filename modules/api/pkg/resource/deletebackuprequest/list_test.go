@@ -0,0 +1,118 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deletebackuprequest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newDeleteBackupRequest(name, backupName, phase string, created time.Time, deletionErrors ...interface{}) *unstructured.Unstructured {
+	item := velerofake.NewObject(velero.DeleteBackupRequestGVR, "velero", name)
+	item.SetCreationTimestamp(metav1.NewTime(created))
+	_ = unstructured.SetNestedField(item.Object, backupName, "spec", "backupName")
+	if phase != "" {
+		_ = unstructured.SetNestedField(item.Object, phase, "status", "phase")
+	}
+	if len(deletionErrors) > 0 {
+		_ = unstructured.SetNestedSlice(item.Object, deletionErrors, "status", "errors")
+	}
+
+	return item
+}
+
+func TestToDeleteBackupRequest(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		item                   *unstructured.Unstructured
+		expectedStatus         velero.Status
+		expectedDeletionErrors []string
+	}{
+		{newDeleteBackupRequest("daily-new", "daily", "", now), velero.StatusPending, nil},
+		{newDeleteBackupRequest("daily-running", "daily", "InProgress", now), velero.StatusRunning, nil},
+		{newDeleteBackupRequest("daily-done", "daily", "Processed", now), velero.StatusSucceeded, nil},
+		{
+			newDeleteBackupRequest("daily-failed", "daily", "Processed", now, "error deleting snapshot snap-1"),
+			velero.StatusFailed,
+			[]string{"error deleting snapshot snap-1"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toDeleteBackupRequest(*c.item)
+		if actual.BackupName != "daily" || actual.Status != c.expectedStatus ||
+			!reflect.DeepEqual(actual.DeletionErrors, c.expectedDeletionErrors) {
+			t.Errorf("toDeleteBackupRequest(%s) == %#v, expected status %q and deletion errors %v", c.item.GetName(),
+				actual, c.expectedStatus, c.expectedDeletionErrors)
+		}
+	}
+}
+
+func TestGetLatestPerBackup(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	requests := []DeleteBackupRequest{
+		toDeleteBackupRequest(*newDeleteBackupRequest("daily-2", "daily", "InProgress", now)),
+		toDeleteBackupRequest(*newDeleteBackupRequest("daily-1", "daily", "Processed", now.Add(-time.Hour), "failed")),
+		toDeleteBackupRequest(*newDeleteBackupRequest("weekly-1", "weekly", "Processed", now)),
+	}
+
+	actual := GetLatestPerBackup(requests)
+	expected := map[k8stypes.NamespacedName]*DeleteBackupRequest{
+		{Namespace: "velero", Name: "daily"}:  &requests[0],
+		{Namespace: "velero", Name: "weekly"}: &requests[2],
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetLatestPerBackup() == %v, expected %v", actual, expected)
+	}
+}
+
+func TestGetDeleteBackupRequestDetail(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newDeleteBackupRequest("daily-1", "daily", "Processed", now),
+		newDeleteBackupRequest("weekly-1", "weekly", "InProgress", now),
+		velerofake.NewObject(velero.BackupGVR, "velero", "weekly"),
+	)
+
+	cases := []struct {
+		name                  string
+		expectedBackupDeleted bool
+	}{
+		{"daily-1", true},
+		{"weekly-1", false},
+	}
+
+	for _, c := range cases {
+		actual, err := getDeleteBackupRequestDetail(context.Background(), dynamicClient, "velero", c.name)
+		if err != nil {
+			t.Errorf("getDeleteBackupRequestDetail(%s) returned error: %v", c.name, err)
+			continue
+		}
+
+		if actual.BackupDeleted != c.expectedBackupDeleted {
+			t.Errorf("getDeleteBackupRequestDetail(%s).BackupDeleted == %t, expected %t", c.name, actual.BackupDeleted,
+				c.expectedBackupDeleted)
+		}
+	}
+}
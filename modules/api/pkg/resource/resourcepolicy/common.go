@@ -0,0 +1,197 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resourcepolicy manages ConfigMaps with Velero resource policies, which tell Velero per volume whether to
+// skip it, snapshot it or back it up with the file system uploader.
+package resourcepolicy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+const (
+	// volumePoliciesKey is the top level key of the volume policies Velero reads from a ConfigMap.
+	volumePoliciesKey = "volumePolicies"
+
+	// PoliciesVersion is the only version of resource policies Velero supports.
+	PoliciesVersion = "v1"
+
+	// ConfigMapKind is the kind backups reference resource policy ConfigMaps with.
+	ConfigMapKind = "configmap"
+
+	// defaultDataKey is the key of the ConfigMap data policies created by the dashboard are stored under.
+	defaultDataKey = "policies.yaml"
+)
+
+// VolumeActionType is what Velero does with the volumes matching the conditions of a policy.
+type VolumeActionType string
+
+const (
+	// VolumeActionSkip leaves the volume out of the backup.
+	VolumeActionSkip VolumeActionType = "skip"
+	// VolumeActionSnapshot takes a snapshot of the volume, either with the volume snapshotter or a CSI snapshot.
+	VolumeActionSnapshot VolumeActionType = "snapshot"
+	// VolumeActionFsBackup backs the volume up with the file system uploader.
+	VolumeActionFsBackup VolumeActionType = "fs-backup"
+)
+
+// volumeConditions are the conditions Velero matches volumes against.
+var volumeConditions = []string{"capacity", "storageClass", "nfs", "csi", "volumeTypes", "pvcLabels"}
+
+// ResourcePolicy is a ConfigMap holding resource policies, which backups and schedules reference by name.
+type ResourcePolicy struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	// Key of the ConfigMap data the policies are stored under.
+	Key string `json:"key"`
+
+	// Errors is only set by CreateResourcePolicy, when the creation could not be recorded in the audit log.
+	Errors []error `json:"errors,omitempty"`
+}
+
+// Policies are the resource policies of a ConfigMap.
+type Policies struct {
+	Version        string         `json:"version"`
+	VolumePolicies []VolumePolicy `json:"volumePolicies"`
+}
+
+// VolumePolicy applies the action to the volumes matching all of its conditions. Velero applies the first policy
+// a volume matches.
+type VolumePolicy struct {
+	// Conditions by name, e.g. capacity "0,10Gi", storageClass ["gp2"] or csi {"driver": "ebs.csi.aws.com"}.
+	Conditions map[string]interface{} `json:"conditions"`
+	Action     VolumeAction           `json:"action"`
+}
+
+// VolumeAction is what Velero does with a volume.
+type VolumeAction struct {
+	Type       VolumeActionType       `json:"type"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// Validate rejects policies Velero would fail backups for.
+func (in *Policies) Validate() error {
+	if in.Version != PoliciesVersion {
+		return errors.NewBadRequest(fmt.Sprintf("unsupported resource policies version %q, expected %s", in.Version,
+			PoliciesVersion))
+	}
+
+	if len(in.VolumePolicies) == 0 {
+		return errors.NewBadRequest("resource policies need at least one volume policy")
+	}
+
+	for i, policy := range in.VolumePolicies {
+		switch policy.Action.Type {
+		case VolumeActionSkip, VolumeActionSnapshot, VolumeActionFsBackup:
+		default:
+			return errors.NewBadRequest(fmt.Sprintf("volumePolicies[%d]: unsupported action %q, expected one of %s, %s, %s",
+				i, policy.Action.Type, VolumeActionSkip, VolumeActionSnapshot, VolumeActionFsBackup))
+		}
+
+		if len(policy.Conditions) == 0 {
+			return errors.NewBadRequest(fmt.Sprintf("volumePolicies[%d]: at least one condition is required", i))
+		}
+
+		names := make([]string, 0, len(policy.Conditions))
+		for name := range policy.Conditions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if !slices.Contains(volumeConditions, name) {
+				return errors.NewBadRequest(fmt.Sprintf("volumePolicies[%d]: unknown condition %q, expected one of %s",
+					i, name, strings.Join(volumeConditions, ", ")))
+			}
+		}
+	}
+
+	return nil
+}
+
+// getPoliciesKey returns the key of the resource policies. Velero only reads ConfigMaps with a single data entry.
+func getPoliciesKey(configMap *v1.ConfigMap) (string, bool) {
+	if len(configMap.Data) != 1 {
+		return "", false
+	}
+
+	for key, value := range configMap.Data {
+		return key, strings.Contains(value, volumePoliciesKey)
+	}
+
+	return "", false
+}
+
+// parsePolicies reads the resource policies of a ConfigMap.
+func parsePolicies(configMap *v1.ConfigMap) (*Policies, error) {
+	key, ok := getPoliciesKey(configMap)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("ConfigMap %s does not hold resource policies in a single data entry",
+			configMap.Name))
+	}
+
+	policies := new(Policies)
+	if err := yaml.Unmarshal([]byte(configMap.Data[key]), policies); err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("ConfigMap %s holds invalid resource policies: %s", configMap.Name,
+			err.Error()))
+	}
+
+	return policies, nil
+}
+
+func toResourcePolicy(configMap *v1.ConfigMap, key string) ResourcePolicy {
+	return ResourcePolicy{
+		ObjectMeta: types.NewObjectMeta(configMap.ObjectMeta),
+		TypeMeta:   types.NewTypeMeta(types.ResourceKindConfigMap),
+		Key:        key,
+	}
+}
+
+// ValidateReference rejects references to ConfigMaps Velero would fail backups for. Backups only reference ConfigMaps
+// from their own namespace.
+func ValidateReference(ctx context.Context, k8sClient kubernetes.Interface, namespace string, reference *v1.TypedLocalObjectReference) error {
+	if !strings.EqualFold(reference.Kind, ConfigMapKind) {
+		return errors.NewBadRequest(fmt.Sprintf("resource policy must reference a %s, not %s", ConfigMapKind, reference.Kind))
+	}
+
+	configMap, err := k8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, reference.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return errors.NewBadRequest(fmt.Sprintf("resource policy ConfigMap %s not found in namespace %s", reference.Name,
+			namespace))
+	}
+	if err != nil {
+		return err
+	}
+
+	policies, err := parsePolicies(configMap)
+	if err != nil {
+		return err
+	}
+
+	return policies.Validate()
+}
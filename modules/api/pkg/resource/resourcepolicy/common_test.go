@@ -0,0 +1,167 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcepolicy
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const policiesYAML = `version: v1
+volumePolicies:
+- conditions:
+    capacity: "0,10Gi"
+    storageClass:
+    - gp2
+  action:
+    type: skip
+- conditions:
+    csi:
+      driver: ebs.csi.aws.com
+  action:
+    type: fs-backup
+`
+
+func newConfigMap(name string, data map[string]string) *v1.ConfigMap {
+	return &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "velero"}, Data: data}
+}
+
+func TestPoliciesValidate(t *testing.T) {
+	cases := []struct {
+		info     string
+		policies Policies
+		valid    bool
+	}{
+		{
+			"valid",
+			Policies{Version: "v1", VolumePolicies: []VolumePolicy{
+				{Conditions: map[string]interface{}{"pvcLabels": map[string]interface{}{"app": "db"}}, Action: VolumeAction{Type: VolumeActionSnapshot}},
+			}},
+			true,
+		},
+		{"unsupported version", Policies{Version: "v2", VolumePolicies: []VolumePolicy{
+			{Conditions: map[string]interface{}{"capacity": "0,1Gi"}, Action: VolumeAction{Type: VolumeActionSkip}},
+		}}, false},
+		{"no policies", Policies{Version: "v1"}, false},
+		{"unsupported action", Policies{Version: "v1", VolumePolicies: []VolumePolicy{
+			{Conditions: map[string]interface{}{"capacity": "0,1Gi"}, Action: VolumeAction{Type: "delete"}},
+		}}, false},
+		{"no conditions", Policies{Version: "v1", VolumePolicies: []VolumePolicy{
+			{Action: VolumeAction{Type: VolumeActionSkip}},
+		}}, false},
+		{"unknown condition", Policies{Version: "v1", VolumePolicies: []VolumePolicy{
+			{Conditions: map[string]interface{}{"namespace": "shop"}, Action: VolumeAction{Type: VolumeActionSkip}},
+		}}, false},
+	}
+
+	for _, c := range cases {
+		if err := c.policies.Validate(); (err == nil) != c.valid {
+			t.Errorf("%s: Validate() returned error %v, expected valid == %t", c.info, err, c.valid)
+		}
+	}
+}
+
+func TestParsePolicies(t *testing.T) {
+	expected := &Policies{Version: "v1", VolumePolicies: []VolumePolicy{
+		{
+			Conditions: map[string]interface{}{"capacity": "0,10Gi", "storageClass": []interface{}{"gp2"}},
+			Action:     VolumeAction{Type: VolumeActionSkip},
+		},
+		{
+			Conditions: map[string]interface{}{"csi": map[string]interface{}{"driver": "ebs.csi.aws.com"}},
+			Action:     VolumeAction{Type: VolumeActionFsBackup},
+		},
+	}}
+
+	actual, err := parsePolicies(newConfigMap("skip-small", map[string]string{"policies.yaml": policiesYAML}))
+	if err != nil {
+		t.Fatalf("parsePolicies() returned error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("parsePolicies() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+
+	if _, err := parsePolicies(newConfigMap("two-keys", map[string]string{"a": policiesYAML, "b": policiesYAML})); err == nil {
+		t.Error("parsePolicies() returned no error for a ConfigMap with two data entries")
+	}
+}
+
+func TestToConfigMap(t *testing.T) {
+	spec := &ResourcePolicySpec{Name: "skip-small", Namespace: "velero", Policies: Policies{Version: "v1",
+		VolumePolicies: []VolumePolicy{
+			{Conditions: map[string]interface{}{"capacity": "0,10Gi"}, Action: VolumeAction{Type: VolumeActionSkip}},
+		}}}
+
+	configMap, err := toConfigMap(spec)
+	if err != nil {
+		t.Fatalf("toConfigMap() returned error: %v", err)
+	}
+
+	actual, err := parsePolicies(configMap)
+	if err != nil {
+		t.Fatalf("parsePolicies() of the created ConfigMap returned error: %v", err)
+	}
+	if !reflect.DeepEqual(*actual, spec.Policies) {
+		t.Errorf("parsePolicies() of the created ConfigMap == %#v, expected %#v", *actual, spec.Policies)
+	}
+
+	spec.Policies.VolumePolicies[0].Action.Type = "delete"
+	if _, err := toConfigMap(spec); err == nil {
+		t.Error("toConfigMap() returned no error for an unsupported action")
+	}
+}
+
+func TestToResourcePolicyList(t *testing.T) {
+	configMaps := []v1.ConfigMap{
+		*newConfigMap("skip-small", map[string]string{"policies.yaml": policiesYAML}),
+		*newConfigMap("settings", map[string]string{"settings": "{}"}),
+		*newConfigMap("modifier", map[string]string{"rules.yaml": "resourceModifierRules: []"}),
+	}
+
+	actual := toResourcePolicyList(configMaps)
+	if actual.ListMeta.TotalItems != 1 || actual.Items[0].ObjectMeta.Name != "skip-small" || actual.Items[0].Key != "policies.yaml" {
+		t.Errorf("toResourcePolicyList() == %#v, expected only skip-small", actual.Items)
+	}
+}
+
+func TestValidateReference(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(
+		newConfigMap("skip-small", map[string]string{"policies.yaml": policiesYAML}),
+		newConfigMap("invalid", map[string]string{"policies.yaml": "version: v2\nvolumePolicies: []"}),
+	)
+
+	cases := []struct {
+		reference v1.TypedLocalObjectReference
+		valid     bool
+	}{
+		{v1.TypedLocalObjectReference{Kind: "configmap", Name: "skip-small"}, true},
+		{v1.TypedLocalObjectReference{Kind: "ConfigMap", Name: "skip-small"}, true},
+		{v1.TypedLocalObjectReference{Kind: "Secret", Name: "skip-small"}, false},
+		{v1.TypedLocalObjectReference{Kind: "configmap", Name: "missing"}, false},
+		{v1.TypedLocalObjectReference{Kind: "configmap", Name: "invalid"}, false},
+	}
+
+	for _, c := range cases {
+		err := ValidateReference(context.Background(), k8sClient, "velero", &c.reference)
+		if (err == nil) != c.valid {
+			t.Errorf("ValidateReference(%v) returned error %v, expected valid == %t", c.reference, err, c.valid)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcepolicy
+
+import (
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
+	"k8s.io/dashboard/client"
+)
+
+// ResourcePolicySpec is the specification of a ConfigMap with resource policies.
+type ResourcePolicySpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	Policies Policies `json:"policies"`
+}
+
+// CreateResourcePolicy creates a ConfigMap holding the resource policies, which backups and schedules in its
+// namespace can reference. In dry run it is only validated by the API server.
+func CreateResourcePolicy(request *http.Request, spec *ResourcePolicySpec, dryRun bool) (*ResourcePolicy, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	spec.Namespace = velero.NamespaceOrDefault(request, spec.Namespace)
+	if spec.Policies.Version == "" {
+		spec.Policies.Version = PoliciesVersion
+	}
+
+	configMap, err := toConfigMap(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := k8sClient.CoreV1().ConfigMaps(spec.Namespace).Create(ctx, configMap, velero.CreateOptions(dryRun))
+	if err != nil {
+		return nil, err
+	}
+
+	result := toResourcePolicy(created, defaultDataKey)
+	if err := audit.Record(request, audit.ActionCreated, v1.SchemeGroupVersion.WithKind("ConfigMap"), result.ObjectMeta, dryRun); err != nil {
+		result.Errors = []error{err}
+	}
+	return &result, nil
+}
+
+// toConfigMap returns the ConfigMap holding the validated policies as YAML, the way Velero documents them.
+func toConfigMap(spec *ResourcePolicySpec) (*v1.ConfigMap, error) {
+	if err := spec.Policies.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.Marshal(spec.Policies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: spec.Name, Namespace: spec.Namespace},
+		Data:       map[string]string{defaultDataKey: string(data)},
+	}, nil
+}
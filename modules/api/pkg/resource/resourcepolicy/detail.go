@@ -0,0 +1,59 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcepolicy
+
+import (
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+)
+
+// ResourcePolicyDetail is a ConfigMap with its parsed resource policies.
+type ResourcePolicyDetail struct {
+	ResourcePolicy `json:",inline"`
+
+	Policies Policies `json:"policies"`
+}
+
+// GetResourcePolicyDetail returns the resource policies of the ConfigMap. ConfigMaps that do not hold resource
+// policies are rejected as a bad request.
+func GetResourcePolicyDetail(request *http.Request, namespace, name string) (*ResourcePolicyDetail, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := k8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := parsePolicies(configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	key, _ := getPoliciesKey(configMap)
+	return &ResourcePolicyDetail{
+		ResourcePolicy: toResourcePolicy(configMap, key),
+		Policies:       *policies,
+	}, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcepolicy
+
+import (
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/types"
+)
+
+// ResourcePolicyList contains ConfigMaps that can be referenced as resource policies of a backup.
+type ResourcePolicyList struct {
+	ListMeta types.ListMeta   `json:"listMeta"`
+	Items    []ResourcePolicy `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetResourcePolicyList returns ConfigMaps in the namespace that hold resource policies. Backups only reference
+// ConfigMaps from their own namespace, which is the one Velero is installed in.
+func GetResourcePolicyList(request *http.Request, namespace string) (*ResourcePolicyList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	configMaps, err := k8sClient.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toResourcePolicyList(configMaps.Items), nil
+}
+
+func toResourcePolicyList(configMaps []v1.ConfigMap) *ResourcePolicyList {
+	result := &ResourcePolicyList{Items: make([]ResourcePolicy, 0), Errors: make([]error, 0)}
+	for _, configMap := range configMaps {
+		key, ok := getPoliciesKey(&configMap)
+		if !ok {
+			continue
+		}
+
+		result.Items = append(result.Items, toResourcePolicy(&configMap, key))
+	}
+	result.ListMeta = types.ListMeta{TotalItems: len(result.Items)}
+
+	return result
+}
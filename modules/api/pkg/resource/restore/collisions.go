@@ -0,0 +1,286 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+)
+
+// DefaultCollisionSamples is the number of resources of each kind checked in a namespace when the caller does not ask
+// for a specific number.
+const DefaultCollisionSamples = 20
+
+// namespacesResource is restored for the namespaces a restore includes, under their mapped names.
+var namespacesResource = schema.GroupResource{Resource: "namespaces"}
+
+// unrestorableResources are never restored by Velero, so they cannot collide.
+var unrestorableResources = []string{"events", "events.events.k8s.io", "backups.velero.io", "restores.velero.io",
+	"backuprepositories.velero.io"}
+
+// CollisionReport tells which namespaces a proposed restore writes to already contain resources with the same names
+// as resources in the backup.
+type CollisionReport struct {
+	BackupName string                `json:"backupName"`
+	Namespaces []NamespaceCollisions `json:"namespaces"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// NamespaceCollisions contains collisions of resources from a backed up namespace in the namespace they are restored
+// to.
+type NamespaceCollisions struct {
+	SourceNamespace string `json:"sourceNamespace"`
+	TargetNamespace string `json:"targetNamespace"`
+
+	// Exists is false when the target namespace does not exist yet, so nothing in it can collide.
+	Exists bool             `json:"exists"`
+	Kinds  []KindCollisions `json:"kinds"`
+}
+
+// KindCollisions counts collisions of a single kind. Only a sample of the resources is checked, the share of
+// collisions among the checked ones estimates the risk for all of them.
+type KindCollisions struct {
+	// Kind as listed in the backup, e.g. apps/v1/Deployment.
+	Kind       string `json:"kind"`
+	Total      int    `json:"total"`
+	Checked    int    `json:"checked"`
+	Collisions int    `json:"collisions"`
+
+	// Names of the checked resources that already exist in the target namespace.
+	Names []string `json:"names"`
+}
+
+// collisionCandidates are resources of a single kind from a backed up namespace.
+type collisionCandidates struct {
+	sourceNamespace string
+	kind            string
+	resource        schema.GroupVersionResource
+	names           []string
+}
+
+// GetCollisionReport checks a sample of the resources of each kind the proposed restore would bring back against the
+// resources already present in the target namespaces. Nothing is changed in the cluster.
+func GetCollisionReport(request *http.Request, spec *RestoreSpec, samples int) (*CollisionReport, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceList, err := getResourceList(ctx, dynamicClient, spec.Namespace, spec.BackupName)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(k8sClient.Discovery()))
+	candidates, nonCriticalErrors := getCollisionCandidates(resourceList, spec, mapper)
+
+	namespaces, err := checkCollisions(ctx, k8sClient, dynamicClient, spec, candidates, samples)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CollisionReport{
+		BackupName: spec.BackupName,
+		Namespaces: namespaces,
+		Errors:     nonCriticalErrors,
+	}, nil
+}
+
+// getResourceList downloads the resource list of the backup, which maps kinds, e.g. apps/v1/Deployment, to names
+// prefixed with their namespace.
+func getResourceList(ctx context.Context, client dynamic.Interface, namespace, backupName string) (map[string][]string, error) {
+	data, err := velero.Download(ctx, client, namespace, velero.DownloadTargetKindBackupResourceList, backupName)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceList := make(map[string][]string)
+	if err := json.Unmarshal(data, &resourceList); err != nil {
+		return nil, fmt.Errorf("Failed to read resource list of backup %s: %w", backupName, err)
+	}
+
+	return resourceList, nil
+}
+
+// getCollisionCandidates selects namespaced resources from the backup resource list that the restore spec would bring
+// back.
+func getCollisionCandidates(resourceList map[string][]string, spec *RestoreSpec, mapper meta.RESTMapper) ([]collisionCandidates, []error) {
+	return getRestoredResources(resourceList, spec, mapper, false)
+}
+
+// getRestoredResources selects resources from the backup resource list that the restore spec would bring back, with
+// cluster-scoped ones only when asked for. Cluster-scoped resources have no source namespace. Kinds unknown to the
+// cluster are reported as non-critical errors.
+func getRestoredResources(resourceList map[string][]string, spec *RestoreSpec, mapper meta.RESTMapper, clusterScoped bool) ([]collisionCandidates, []error) {
+	nonCriticalErrors := make([]error, 0)
+	result := make([]collisionCandidates, 0)
+	for kind, items := range resourceList {
+		separator := strings.LastIndex(kind, "/")
+		gv, err := schema.ParseGroupVersion(kind[:max(separator, 0)])
+		if separator < 0 || err != nil {
+			nonCriticalErrors = append(nonCriticalErrors, fmt.Errorf("invalid kind %q in backup resource list", kind))
+			continue
+		}
+
+		mapping, err := mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kind[separator+1:]}, gv.Version)
+		if err != nil {
+			nonCriticalErrors = append(nonCriticalErrors, err)
+			continue
+		}
+
+		namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+		if (!namespaced && !clusterScoped) || !isResourceRestored(mapping.Resource, spec) {
+			continue
+		}
+
+		byNamespace := make(map[string][]string)
+		for _, item := range items {
+			if !namespaced {
+				if mapping.Resource.GroupResource() == namespacesResource && !isNamespaceRestored(item, spec) {
+					continue
+				}
+				byNamespace[""] = append(byNamespace[""], item)
+				continue
+			}
+
+			namespace, name, found := strings.Cut(item, "/")
+			if !found || !isNamespaceRestored(namespace, spec) {
+				continue
+			}
+			byNamespace[namespace] = append(byNamespace[namespace], name)
+		}
+
+		for namespace, names := range byNamespace {
+			sort.Strings(names)
+			result = append(result, collisionCandidates{namespace, kind, mapping.Resource, names})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].sourceNamespace != result[j].sourceNamespace {
+			return result[i].sourceNamespace < result[j].sourceNamespace
+		}
+		return result[i].kind < result[j].kind
+	})
+
+	return result, nonCriticalErrors
+}
+
+// checkCollisions looks up at most samples resources of each candidate kind in its target namespace.
+func checkCollisions(ctx context.Context, k8sClient kubernetes.Interface, dynamicClient dynamic.Interface, spec *RestoreSpec, candidates []collisionCandidates, samples int) ([]NamespaceCollisions, error) {
+	result := make([]NamespaceCollisions, 0)
+	for _, candidate := range candidates {
+		if len(result) == 0 || result[len(result)-1].SourceNamespace != candidate.sourceNamespace {
+			target := getTargetNamespace(candidate.sourceNamespace, spec)
+			_, err := k8sClient.CoreV1().Namespaces().Get(ctx, target, metav1.GetOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return nil, err
+			}
+
+			result = append(result, NamespaceCollisions{
+				SourceNamespace: candidate.sourceNamespace,
+				TargetNamespace: target,
+				Exists:          err == nil,
+				Kinds:           make([]KindCollisions, 0),
+			})
+		}
+
+		namespace := &result[len(result)-1]
+		collisions := KindCollisions{Kind: candidate.kind, Total: len(candidate.names), Names: make([]string, 0)}
+		if namespace.Exists {
+			for _, name := range candidate.names[:min(samples, len(candidate.names))] {
+				_, err := dynamicClient.Resource(candidate.resource).Namespace(namespace.TargetNamespace).
+					Get(ctx, name, metav1.GetOptions{})
+				if err != nil && !k8serrors.IsNotFound(err) {
+					return nil, err
+				}
+
+				collisions.Checked++
+				if err == nil {
+					collisions.Collisions++
+					collisions.Names = append(collisions.Names, name)
+				}
+			}
+		}
+		namespace.Kinds = append(namespace.Kinds, collisions)
+	}
+
+	return result, nil
+}
+
+func getTargetNamespace(namespace string, spec *RestoreSpec) string {
+	if target, ok := spec.NamespaceMapping[namespace]; ok {
+		return target
+	}
+
+	return namespace
+}
+
+// isNamespaceRestored tells whether the restore includes the backed up namespace. No included namespaces and the
+// "*" wildcard include all of them.
+func isNamespaceRestored(namespace string, spec *RestoreSpec) bool {
+	return matchesFilter(spec.IncludedNamespaces, spec.ExcludedNamespaces, namespace)
+}
+
+// isResourceRestored tells whether the restore includes the resource, which may be named by its plural name alone,
+// e.g. deployments, or together with its group, e.g. deployments.apps.
+func isResourceRestored(resource schema.GroupVersionResource, spec *RestoreSpec) bool {
+	groupResource := resource.GroupResource().String()
+	if slices.Contains(unrestorableResources, groupResource) {
+		return false
+	}
+
+	return matchesFilter(spec.IncludedResources, spec.ExcludedResources, resource.Resource, groupResource)
+}
+
+// matchesFilter tells whether any of the names is included and none of them is excluded.
+func matchesFilter(included, excluded []string, names ...string) bool {
+	isIncluded := len(included) == 0 || slices.Contains(included, "*")
+	for _, name := range names {
+		if slices.Contains(excluded, name) {
+			return false
+		}
+		isIncluded = isIncluded || slices.Contains(included, name)
+	}
+
+	return isIncluded
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	configMapGVR  = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+)
+
+func newCollisionMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Event"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	return mapper
+}
+
+func TestGetCollisionCandidates(t *testing.T) {
+	resourceList := map[string][]string{
+		"v1/ConfigMap":       {"shop/settings", "shop/flags", "blog/theme"},
+		"v1/Event":           {"shop/settings.17a"},
+		"v1/Namespace":       {"shop", "blog"},
+		"apps/v1/Deployment": {"shop/web", "kube-system/coredns"},
+	}
+
+	cases := []struct {
+		info     string
+		spec     *RestoreSpec
+		expected []collisionCandidates
+	}{
+		{
+			"all namespaces and resources",
+			&RestoreSpec{},
+			[]collisionCandidates{
+				{"blog", "v1/ConfigMap", configMapGVR, []string{"theme"}},
+				{"kube-system", "apps/v1/Deployment", deploymentGVR, []string{"coredns"}},
+				{"shop", "apps/v1/Deployment", deploymentGVR, []string{"web"}},
+				{"shop", "v1/ConfigMap", configMapGVR, []string{"flags", "settings"}},
+			},
+		},
+		{
+			"filtered namespaces and resources",
+			&RestoreSpec{
+				IncludedNamespaces: []string{"*"},
+				ExcludedNamespaces: []string{"kube-system"},
+				IncludedResources:  []string{"deployments.apps", "configmaps"},
+				ExcludedResources:  []string{"deployments"},
+			},
+			[]collisionCandidates{
+				{"blog", "v1/ConfigMap", configMapGVR, []string{"theme"}},
+				{"shop", "v1/ConfigMap", configMapGVR, []string{"flags", "settings"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual, nonCriticalErrors := getCollisionCandidates(resourceList, c.spec, newCollisionMapper())
+		if len(nonCriticalErrors) > 0 {
+			t.Errorf("%s: getCollisionCandidates() returned errors: %v", c.info, nonCriticalErrors)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: getCollisionCandidates() == \n%#v\nexpected \n%#v\n", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestGetCollisionCandidatesUnknownKind(t *testing.T) {
+	resourceList := map[string][]string{"example.com/v1/Widget": {"shop/gear"}}
+
+	actual, nonCriticalErrors := getCollisionCandidates(resourceList, &RestoreSpec{}, newCollisionMapper())
+	if len(actual) != 0 || len(nonCriticalErrors) != 1 {
+		t.Errorf("getCollisionCandidates() == %#v with %d errors, expected no candidates and 1 error", actual,
+			len(nonCriticalErrors))
+	}
+}
+
+func TestCheckCollisions(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop-copy"}})
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion("v1")
+	existing.SetKind("ConfigMap")
+	existing.SetNamespace("shop-copy")
+	existing.SetName("flags")
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMapGVR: "ConfigMapList"}, existing)
+
+	spec := &RestoreSpec{NamespaceMapping: map[string]string{"shop": "shop-copy"}}
+	candidates := []collisionCandidates{
+		{"blog", "v1/ConfigMap", configMapGVR, []string{"theme"}},
+		{"shop", "v1/ConfigMap", configMapGVR, []string{"flags", "settings", "zz-unchecked"}},
+	}
+
+	expected := []NamespaceCollisions{
+		{
+			SourceNamespace: "blog",
+			TargetNamespace: "blog",
+			Exists:          false,
+			Kinds:           []KindCollisions{{Kind: "v1/ConfigMap", Total: 1, Names: []string{}}},
+		},
+		{
+			SourceNamespace: "shop",
+			TargetNamespace: "shop-copy",
+			Exists:          true,
+			Kinds: []KindCollisions{
+				{Kind: "v1/ConfigMap", Total: 3, Checked: 2, Collisions: 1, Names: []string{"flags"}},
+			},
+		},
+	}
+
+	actual, err := checkCollisions(context.TODO(), k8sClient, dynamicClient, spec, candidates, 2)
+	if err != nil {
+		t.Fatalf("checkCollisions() returned error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("checkCollisions() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
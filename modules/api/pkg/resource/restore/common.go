@@ -0,0 +1,98 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// suggestedPollSeconds returns how often a restore in the phase is worth refreshing. Restores do not change once
+// Velero finished them.
+func suggestedPollSeconds(phase velero.RestorePhase) int {
+	if phase.IsFinal() {
+		return common.StablePollSeconds
+	}
+
+	return common.ActivePollSeconds
+}
+
+// The code below allows to perform complex data section on []Restore
+
+type RestoreCell Restore
+
+func (in RestoreCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(in.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(in.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(in.ObjectMeta.Namespace)
+	case dataselect.StartTimeProperty:
+		return comparableTime(in.StartTime)
+	case dataselect.CompletionTimeProperty:
+		return comparableTime(in.CompletionTime)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func comparableTime(t *metav1.Time) dataselect.ComparableValue {
+	if t == nil {
+		return dataselect.StdComparableTime(time.Time{})
+	}
+
+	return dataselect.StdComparableTime(t.Time)
+}
+
+func toCells(std []Restore) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = RestoreCell(std[i])
+	}
+	return cells
+}
+
+func fromCells(cells []dataselect.DataCell) []Restore {
+	std := make([]Restore, len(cells))
+	for i := range std {
+		std[i] = Restore(cells[i].(RestoreCell))
+	}
+	return std
+}
+
+// nestedTime reads an RFC3339 timestamp from the object, returning nil when it is not set.
+func nestedTime(obj map[string]interface{}, fields ...string) *metav1.Time {
+	value, found, err := unstructured.NestedString(obj, fields...)
+	if err != nil || !found {
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	result := metav1.NewTime(parsed)
+	return &result
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+)
+
+func TestParseRestoreDetailSuggestedPollSeconds(t *testing.T) {
+	cases := []struct {
+		raw      string
+		expected int
+	}{
+		{`{"metadata":{"name":"restore-1"}}`, common.ActivePollSeconds},
+		{`{"metadata":{"name":"restore-1"},"status":{"phase":"InProgress"}}`, common.ActivePollSeconds},
+		{`{"metadata":{"name":"restore-1"},"status":{"phase":"Completed"}}`, common.StablePollSeconds},
+		{`{"metadata":{"name":"restore-1"},"status":{"phase":"Failed"}}`, common.StablePollSeconds},
+	}
+
+	for _, c := range cases {
+		detail, err := parseRestoreDetail([]byte(c.raw))
+		if err != nil {
+			t.Fatalf("parseRestoreDetail(%s) returned error: %s", c.raw, err.Error())
+		}
+		if detail.SuggestedPollSeconds != c.expected {
+			t.Errorf("parseRestoreDetail(%s).SuggestedPollSeconds == %d, expected %d", c.raw, detail.SuggestedPollSeconds, c.expected)
+		}
+	}
+}
+
+func TestParseRestoreDetailNamespaceMapping(t *testing.T) {
+	raw := `{"metadata":{"name":"restore-1"},"spec":{"backupName":"daily","namespaceMapping":{"shop":"shop-staging"}}}`
+	expected := map[string]string{"shop": "shop-staging"}
+
+	detail, err := parseRestoreDetail([]byte(raw))
+	if err != nil {
+		t.Fatalf("parseRestoreDetail(%s) returned error: %s", raw, err.Error())
+	}
+	if !reflect.DeepEqual(detail.NamespaceMapping, expected) {
+		t.Errorf("parseRestoreDetail(%s).NamespaceMapping == %v, expected %v", raw, detail.NamespaceMapping, expected)
+	}
+}
+
+func TestValidateNamespaceMapping(t *testing.T) {
+	cases := []struct {
+		mapping map[string]string
+		isError bool
+	}{
+		{nil, false},
+		{map[string]string{"shop": "shop-staging"}, false},
+		{map[string]string{"shop": "Shop_Staging"}, true},
+		{map[string]string{"shop": ""}, true},
+	}
+
+	for _, c := range cases {
+		err := validateNamespaceMapping(c.mapping)
+		if (err != nil) != c.isError {
+			t.Errorf("validateNamespaceMapping(%v) == %v, expected error: %t", c.mapping, err, c.isError)
+		}
+	}
+}
+
+func TestValidateExistingResourcePolicy(t *testing.T) {
+	cases := []struct {
+		policy  ExistingResourcePolicy
+		isError bool
+	}{
+		{"", false},
+		{ExistingResourcePolicyNone, false},
+		{ExistingResourcePolicyUpdate, false},
+		{"overwrite", true},
+	}
+
+	for _, c := range cases {
+		err := validateExistingResourcePolicy(c.policy)
+		if (err != nil) != c.isError {
+			t.Errorf("validateExistingResourcePolicy(%q) == %v, expected error: %t", c.policy, err, c.isError)
+		}
+	}
+}
@@ -16,21 +16,96 @@ package restore
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 
-	crdv1 "k8s.io/dashboard/api/pkg/resource/customresourcedefinition/v1"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
 	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
 	"k8s.io/dashboard/types"
 )
 
-// CreateRestore creates a new Velero restore
-func CreateRestore(request *http.Request, spec *RestoreSpec) (*Restore, error) {
+// CreateRestore creates a new Velero restore. In dry run it is only validated by the API server, which returns it without
+// persisting it. The backup is checked to be restorable first. When asked to, missing target namespaces of the
+// namespace mapping are created beforehand and kept even if creating the restore fails. They are annotated with their
+// creator, but not recorded in the audit log, as events of cluster-scoped objects cannot be kept in the namespace of the
+// restore.
+func CreateRestore(request *http.Request, spec *RestoreSpec, dryRun bool) (*Restore, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	spec.Namespace = velero.NamespaceOrDefault(request, spec.Namespace)
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := velero.CheckAccess(request, velero.VerbCreate, velero.RestoreGVR, spec.Namespace, spec.Name); err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []targetNamespace
+	if spec.CreateNamespaces {
+		targets, err = getMissingTargetNamespaces(ctx, k8sClient, spec.NamespaceMapping)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range targets {
+			if err := velero.CheckAccess(request, velero.VerbCreate, namespaceGVR, "", item.target); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result, err := createRestore(ctx, dynamicClient, k8sClient, spec, targets, audit.GetSubject(request), dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := audit.Record(request, audit.ActionCreated, velero.RestoreGVR.GroupVersion().WithKind("Restore"), result.ObjectMeta, dryRun); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+	return result, nil
+}
+
+// createRestore creates the restore after creating the missing target namespaces, both annotated with the subject.
+func createRestore(ctx context.Context, dynamicClient dynamic.Interface, k8sClient kubernetes.Interface, spec *RestoreSpec,
+	targets []targetNamespace, subject string, dryRun bool) (*Restore, error) {
+	if err := checkBackup(ctx, dynamicClient, spec, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if spec.ResourceModifier != "" {
+		if err := validateResourceModifier(ctx, k8sClient, spec.Namespace, spec.ResourceModifier); err != nil {
+			return nil, err
+		}
+	}
+
+	createdNamespaces, err := createTargetNamespaces(ctx, k8sClient, targets, spec.CopyNamespaceLabels, subject, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create unstructured object for the restore
 	restore := &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -62,74 +137,47 @@ func CreateRestore(request *http.Request, spec *RestoreSpec) (*Restore, error) {
 	if spec.LabelSelector != nil {
 		restore.Object["spec"].(map[string]interface{})["labelSelector"] = spec.LabelSelector
 	}
-
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
-	if err != nil {
-		return nil, err
+	if len(spec.NamespaceMapping) > 0 {
+		// Unstructured objects hold JSON values only, so that they can be deep copied.
+		mapping := make(map[string]interface{}, len(spec.NamespaceMapping))
+		for source, target := range spec.NamespaceMapping {
+			mapping[source] = target
+		}
+		restore.Object["spec"].(map[string]interface{})["namespaceMapping"] = mapping
 	}
-
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
-	if err != nil {
-		return nil, err
+	if spec.RestorePVs != nil {
+		restore.Object["spec"].(map[string]interface{})["restorePVs"] = *spec.RestorePVs
 	}
-
-	// Get the restore CRD definition
-	customResourceDefinition, err := apiExtClient.ApiextensionsV1().
-		CustomResourceDefinitions().
-		Get(context.TODO(), "restores.velero.io", metav1.GetOptions{})
-	if err != nil {
-		return nil, err
+	if spec.PreserveNodePorts != nil {
+		restore.Object["spec"].(map[string]interface{})["preserveNodePorts"] = *spec.PreserveNodePorts
 	}
-
-	// Create REST client for the restore CRD
-	restClient, err := crdv1.NewRESTClient(config, customResourceDefinition)
-	if err != nil {
-		return nil, err
+	if spec.ExistingResourcePolicy != "" {
+		restore.Object["spec"].(map[string]interface{})["existingResourcePolicy"] = string(spec.ExistingResourcePolicy)
 	}
-
-	// Convert our restore object to JSON
-	restoreJSON, err := json.Marshal(restore.Object)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to marshal restore: %s", err.Error())
+	if spec.Hooks != nil && len(spec.Hooks.Resources) > 0 {
+		restore.Object["spec"].(map[string]interface{})["hooks"] = spec.Hooks
 	}
-
-	// Create the restore via REST client
-	result := restClient.Post().
-		NamespaceIfScoped(spec.Namespace, customResourceDefinition.Spec.Scope == apiextensionsv1.NamespaceScoped).
-		Resource(customResourceDefinition.Spec.Names.Plural).
-		Body(restoreJSON).
-		Do(context.TODO())
-
-	if result.Error() != nil {
-		return nil, fmt.Errorf("Failed to create restore: %s", result.Error().Error())
+	if spec.ResourceModifier != "" {
+		restore.Object["spec"].(map[string]interface{})["resourceModifier"] = map[string]interface{}{
+			"kind": "ConfigMap",
+			"name": spec.ResourceModifier,
+		}
 	}
 
-	// Get the raw response
-	raw, err := result.Raw()
+	audit.SetCreatedByUser(restore, subject)
+	created, err := dynamicClient.Resource(velero.RestoreGVR).Namespace(spec.Namespace).
+		Create(ctx, restore, velero.CreateOptions(dryRun))
 	if err != nil {
-		return nil, fmt.Errorf("Failed to get restore response: %s", err.Error())
+		return nil, fmt.Errorf("Failed to create restore: %w", err)
 	}
 
-	// Parse the response to extract basic info
-	var createdRestore map[string]interface{}
-	if err := json.Unmarshal(raw, &createdRestore); err != nil {
-		return nil, fmt.Errorf("Failed to parse restore response: %s", err.Error())
-	}
-
-	// Extract metadata
-	metadata := createdRestore["metadata"].(map[string]interface{})
-
 	// Convert to our Restore struct
 	createdRestoreResult := &Restore{
-		ObjectMeta: types.ObjectMeta{
-			Name:      metadata["name"].(string),
-			Namespace: metadata["namespace"].(string),
-		},
+		ObjectMeta: velero.NewObjectMeta(created.Object),
 		TypeMeta: types.TypeMeta{
 			Kind: "Restore",
 		},
+		CreatedNamespaces: createdNamespaces,
 	}
 
 	return createdRestoreResult, nil
@@ -145,4 +193,82 @@ type RestoreSpec struct {
 	IncludedResources  []string              `json:"includedResources,omitempty"`
 	ExcludedResources  []string              `json:"excludedResources,omitempty"`
 	LabelSelector      *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// NamespaceMapping restores resources of a backed up namespace into a different one.
+	NamespaceMapping map[string]string `json:"namespaceMapping,omitempty"`
+
+	// CreateNamespaces creates target namespaces of the mapping that do not exist before the restore, instead of
+	// letting Velero create them from the backup. CopyNamespaceLabels copies the labels of the source namespace to
+	// them, when it exists in the cluster.
+	CreateNamespaces    bool `json:"createNamespaces,omitempty"`
+	CopyNamespaceLabels bool `json:"copyNamespaceLabels,omitempty"`
+
+	// Velero defaults apply to the options left unset.
+	RestorePVs             *bool                  `json:"restorePVs,omitempty"`
+	PreserveNodePorts      *bool                  `json:"preserveNodePorts,omitempty"`
+	ExistingResourcePolicy ExistingResourcePolicy `json:"existingResourcePolicy,omitempty"`
+
+	Hooks *RestoreHooks `json:"hooks,omitempty"`
+
+	// ResourceModifier is the name of a ConfigMap in the restore namespace with JSON patches applied to restored
+	// resources.
+	ResourceModifier string `json:"resourceModifier,omitempty"`
+
+	// AllowIncomplete skips the check that the backup completed, leaving it to Velero whether a backup in any other
+	// phase can be restored from.
+	AllowIncomplete bool `json:"allowIncomplete,omitempty"`
+}
+
+// Validate rejects restore specs Velero would fail the restore for. The resource modifier is checked separately, as
+// it requires reading the referenced ConfigMap.
+func (in *RestoreSpec) Validate() error {
+	if err := validateNamespaceMapping(in.NamespaceMapping); err != nil {
+		return err
+	}
+
+	if in.CopyNamespaceLabels && !in.CreateNamespaces {
+		return errors.NewBadRequest("copying namespace labels requires creating namespaces")
+	}
+
+	if err := validateExistingResourcePolicy(in.ExistingResourcePolicy); err != nil {
+		return err
+	}
+
+	if in.Hooks != nil {
+		return validateHooks(in.Hooks)
+	}
+
+	return nil
+}
+
+// ExistingResourcePolicy tells Velero what to do with resources that already exist in the cluster.
+type ExistingResourcePolicy string
+
+const (
+	// ExistingResourcePolicyNone keeps existing resources as they are and reports a warning.
+	ExistingResourcePolicyNone ExistingResourcePolicy = "none"
+	// ExistingResourcePolicyUpdate patches existing resources to match the backup.
+	ExistingResourcePolicyUpdate ExistingResourcePolicy = "update"
+)
+
+// validateNamespaceMapping rejects mappings into names that are not valid namespace names, which Velero would only
+// report once the restore fails.
+func validateNamespaceMapping(mapping map[string]string) error {
+	for source, target := range mapping {
+		if errs := validation.IsDNS1123Label(target); len(errs) > 0 {
+			return errors.NewBadRequest(fmt.Sprintf("invalid target namespace %q for namespace %s: %s", target, source,
+				strings.Join(errs, ", ")))
+		}
+	}
+
+	return nil
+}
+
+func validateExistingResourcePolicy(policy ExistingResourcePolicy) error {
+	if policy != "" && policy != ExistingResourcePolicyNone && policy != ExistingResourcePolicyUpdate {
+		return errors.NewBadRequest(fmt.Sprintf("invalid existing resource policy %q, expected %s or %s", policy,
+			ExistingResourcePolicyNone, ExistingResourcePolicyUpdate))
+	}
+
+	return nil
 }
@@ -15,55 +15,35 @@
 package restore
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	crdv1 "k8s.io/dashboard/api/pkg/resource/customresourcedefinition/v1"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
 )
 
-// DeleteRestore deletes a Velero restore
-func DeleteRestore(request *http.Request, namespace, name string) error {
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
-	if err != nil {
-		return err
-	}
+// DeleteRestore deletes a Velero restore. In dry run the deletion is only validated by the API server. Restored
+// resources are not affected by deleting the restore.
+func DeleteRestore(request *http.Request, namespace, name string, dryRun bool) (*velero.DeletionImpact, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
 
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
-	if err != nil {
-		return err
+	if err := velero.CheckAccess(request, velero.VerbDelete, velero.RestoreGVR, namespace, name); err != nil {
+		return nil, err
 	}
 
-	// Get the restore CRD definition
-	customResourceDefinition, err := apiExtClient.ApiextensionsV1().
-		CustomResourceDefinitions().
-		Get(context.TODO(), "restores.velero.io", metav1.GetOptions{})
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Create REST client for the restore CRD
-	restClient, err := crdv1.NewRESTClient(config, customResourceDefinition)
+	impact, err := velero.Delete(ctx, dynamicClient, velero.RestoreGVR, "Restore", namespace, name, dryRun)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("Failed to delete restore: %w", err)
 	}
-
-	// Delete the restore via REST client
-	result := restClient.Delete().
-		NamespaceIfScoped(namespace, customResourceDefinition.Spec.Scope == apiextensionsv1.NamespaceScoped).
-		Resource(customResourceDefinition.Spec.Names.Plural).
-		Name(name).
-		Do(context.TODO())
-
-	if result.Error() != nil {
-		return fmt.Errorf("Failed to delete restore: %s", result.Error().Error())
+	if err := audit.Record(request, audit.ActionDeleted, velero.RestoreGVR.GroupVersion().WithKind("Restore"), impact.ObjectMeta, dryRun); err != nil {
+		impact.Errors = append(impact.Errors, err)
 	}
 
-	return nil
+	return impact, nil
 }
@@ -19,13 +19,11 @@ import (
 	"encoding/json"
 	"net/http"
 
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/rest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/dashboard/api/pkg/resource/common"
-	crdv1 "k8s.io/dashboard/api/pkg/resource/customresourcedefinition/v1"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/velero"
 	dashboardtypes "k8s.io/dashboard/types"
 )
 
@@ -35,51 +33,73 @@ type RestoreDetail struct {
 	TypeMeta   dashboardtypes.TypeMeta   `json:"typeMeta"`
 
 	// Restore specific fields
-	Status         string `json:"status"`
-	Phase          string `json:"phase"`
-	StartTime      string `json:"startTime,omitempty"`
-	CompletionTime string `json:"completionTime,omitempty"`
-	BackupName     string `json:"backupName,omitempty"`
-	
+	Status         velero.Status       `json:"status"`
+	Phase          velero.RestorePhase `json:"phase"`
+	StartTime      string              `json:"startTime,omitempty"`
+	CompletionTime string              `json:"completionTime,omitempty"`
+	BackupName     string              `json:"backupName,omitempty"`
+	ScheduleName   string              `json:"scheduleName,omitempty"`
+
 	// Progress and results
-	TotalItems     int `json:"totalItems"`
-	ItemsRestored  int `json:"itemsRestored"`
-	Progress       RestoreProgress `json:"progress"`
-	
+	TotalItems    int             `json:"totalItems"`
+	ItemsRestored int             `json:"itemsRestored"`
+	Progress      RestoreProgress `json:"progress"`
+
 	// Resource inclusion/exclusion
 	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
 	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
 	IncludedResources  []string `json:"includedResources,omitempty"`
 	ExcludedResources  []string `json:"excludedResources,omitempty"`
-	
-	// Errors and warnings
-	Errors   []string `json:"errors,omitempty"`
-	Warnings []string `json:"warnings,omitempty"`
+
+	// Backed up namespaces restored into different ones
+	NamespaceMapping map[string]string `json:"namespaceMapping,omitempty"`
+
+	// Hooks run for restored pods and how many of them Velero ran
+	Hooks      *RestoreHooks      `json:"hooks,omitempty"`
+	HookStatus *RestoreHookStatus `json:"hookStatus,omitempty"`
+
+	// Name of the ConfigMap with JSON patches applied to restored resources
+	ResourceModifier string `json:"resourceModifier,omitempty"`
+
+	// Cluster the restored backup was taken in, when recorded
+	SourceCluster *velero.SourceCluster `json:"sourceCluster,omitempty"`
+
+	// Backup and schedule the restore came from, for navigation
+	Links *RestoreLinks `json:"links,omitempty"`
+
+	// Asynchronous item operations started by plugins, nil when there are none
+	ItemOperations *velero.ItemOperationsStatus `json:"itemOperations,omitempty"`
+
+	// Number of errors and warnings Velero encountered, details are in the restore results
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+
+	// Readiness of the restored workloads, only set when the readiness check is enabled
+	Readiness *RestoreReadiness `json:"readiness,omitempty"`
+
+	// SuggestedPollSeconds tells clients how often the restore is worth refreshing in its current phase
+	SuggestedPollSeconds int `json:"suggestedPollSeconds"`
 }
 
 // RestoreProgress represents the progress of a restore operation.
 type RestoreProgress struct {
-	TotalItems     int `json:"totalItems"`
-	ItemsRestored  int `json:"itemsRestored"`
-	ItemsFailed    int `json:"itemsFailed"`
+	TotalItems    int `json:"totalItems"`
+	ItemsRestored int `json:"itemsRestored"`
+	ItemsFailed   int `json:"itemsFailed"`
 }
 
 // GetRestoreDetail returns detailed information about a specific Velero restore.
 func GetRestoreDetail(request *http.Request, namespace *common.NamespaceQuery, name string) (*RestoreDetail, error) {
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
-	if err != nil {
-		return nil, err
-	}
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
 
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get the raw JSON data that contains the actual Velero restore information
-	rawRestoreData, err := getRawRestoreData(apiExtClient, config, namespace, name)
+	rawRestoreData, err := getRawRestoreData(ctx, dynamicClient, namespace, name)
 	if err != nil {
 		return nil, err
 	}
@@ -89,36 +109,37 @@ func GetRestoreDetail(request *http.Request, namespace *common.NamespaceQuery, n
 	if err != nil {
 		return nil, err
 	}
-	
+
+	restoreBackup, err := getBackup(ctx, dynamicClient, restoreDetail.ObjectMeta.Namespace, restoreDetail.BackupName)
+	if err != nil {
+		restoreBackup = nil
+	}
+
+	restoreDetail.SourceCluster = getSourceCluster(restoreBackup)
+	restoreDetail.Links = getRestoreLinks(ctx, dynamicClient, restoreDetail, restoreBackup, err)
+	restoreDetail.Readiness = getRestoreReadiness(ctx, request, rawRestoreData)
+
 	return restoreDetail, nil
 }
 
 // getRawRestoreData gets the raw JSON data for a specific Velero restore
-func getRawRestoreData(apiExtClient apiextensionsclientset.Interface, config *rest.Config, namespace *common.NamespaceQuery, name string) ([]byte, error) {
-	// Get the restore CRD definition
-	customResourceDefinition, err := apiExtClient.ApiextensionsV1().
-		CustomResourceDefinitions().
-		Get(context.TODO(), "restores.velero.io", metav1.GetOptions{})
+func getRawRestoreData(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery, name string) ([]byte, error) {
+	item, err := client.Resource(velero.RestoreGVR).Namespace(namespace.ToRequestParam()).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	// Create REST client for the restore CRD
-	restClient, err := crdv1.NewRESTClient(config, customResourceDefinition)
-	if err != nil {
-		return nil, err
-	}
+	return item.MarshalJSON()
+}
 
-	// Get the raw restore data
-	raw, err := restClient.Get().
-		NamespaceIfScoped(namespace.ToRequestParam(), customResourceDefinition.Spec.Scope == apiextensionsv1.NamespaceScoped).
-		Resource(customResourceDefinition.Spec.Names.Plural).
-		Name(name).Do(context.TODO()).Raw()
-	if err != nil {
-		return nil, err
+// getSourceCluster returns the cluster the restored backup was taken in. It is left out when the backup has been
+// deleted since.
+func getSourceCluster(backup *unstructured.Unstructured) *velero.SourceCluster {
+	if backup == nil {
+		return nil
 	}
 
-	return raw, nil
+	return velero.NewSourceCluster(backup.GetAnnotations())
 }
 
 // parseRestoreDetail parses raw JSON data into a RestoreDetail struct
@@ -130,8 +151,8 @@ func parseRestoreDetail(rawData []byte) (*RestoreDetail, error) {
 	}
 
 	// Extract metadata
-	metadata := extractMetadata(rawRestore)
-	
+	metadata := velero.NewObjectMeta(rawRestore)
+
 	// Create restore detail with basic info
 	detail := &RestoreDetail{
 		ObjectMeta: metadata,
@@ -143,18 +164,17 @@ func parseRestoreDetail(rawData []byte) (*RestoreDetail, error) {
 	// Extract Velero-specific status information
 	if status, ok := rawRestore["status"].(map[string]interface{}); ok {
 		if phase, ok := status["phase"].(string); ok {
-			detail.Phase = phase
-			detail.Status = phase
+			detail.Phase = velero.RestorePhase(phase)
 		}
-		
+
 		if startTime, ok := status["startTimestamp"].(string); ok {
 			detail.StartTime = startTime
 		}
-		
+
 		if completionTime, ok := status["completionTimestamp"].(string); ok {
 			detail.CompletionTime = completionTime
 		}
-		
+
 		// Extract progress information
 		if progress, ok := status["progress"].(map[string]interface{}); ok {
 			if totalItems, ok := progress["totalItems"].(float64); ok {
@@ -166,40 +186,65 @@ func parseRestoreDetail(rawData []byte) (*RestoreDetail, error) {
 				detail.ItemsRestored = int(itemsRestored)
 			}
 		}
+
+		if errors, ok := status["errors"].(float64); ok {
+			detail.Errors = int(errors)
+		}
+
+		if warnings, ok := status["warnings"].(float64); ok {
+			detail.Warnings = int(warnings)
+		}
+
+		if hookStatus, ok := status["hookStatus"].(map[string]interface{}); ok {
+			detail.HookStatus = toRestoreHookStatus(hookStatus)
+		}
+
+		detail.ItemOperations = velero.NewItemOperationsStatus(status, "restore")
+	}
+
+	// Velero does not count failed items, so items a finished restore never got to are counted instead
+	if detail.Phase.IsFinal() && detail.TotalItems > detail.ItemsRestored {
+		detail.Progress.ItemsFailed = detail.TotalItems - detail.ItemsRestored
 	}
 
+	detail.Status = detail.Phase.Status()
+	detail.SuggestedPollSeconds = suggestedPollSeconds(detail.Phase)
+
 	// Extract spec information
 	if spec, ok := rawRestore["spec"].(map[string]interface{}); ok {
 		if backupName, ok := spec["backupName"].(string); ok {
 			detail.BackupName = backupName
 		}
-		
-		// Extract included/excluded namespaces
-		if includedNS, ok := spec["includedNamespaces"].([]interface{}); ok {
-			for _, ns := range includedNS {
-				if nsStr, ok := ns.(string); ok {
-					detail.IncludedNamespaces = append(detail.IncludedNamespaces, nsStr)
-				}
-			}
+
+		if scheduleName, ok := spec["scheduleName"].(string); ok {
+			detail.ScheduleName = scheduleName
 		}
-	}
 
-	return detail, nil
-}
+		// Extract included/excluded namespaces and resources
+		detail.IncludedNamespaces = velero.NewStringSlice(spec["includedNamespaces"])
+		detail.ExcludedNamespaces = velero.NewStringSlice(spec["excludedNamespaces"])
+		detail.IncludedResources = velero.NewStringSlice(spec["includedResources"])
+		detail.ExcludedResources = velero.NewStringSlice(spec["excludedResources"])
 
-// extractMetadata extracts ObjectMeta from raw JSON
-func extractMetadata(rawRestore map[string]interface{}) dashboardtypes.ObjectMeta {
-	metadata := dashboardtypes.ObjectMeta{}
-	
-	if meta, ok := rawRestore["metadata"].(map[string]interface{}); ok {
-		if name, ok := meta["name"].(string); ok {
-			metadata.Name = name
+		if hooks, ok := spec["hooks"].(map[string]interface{}); ok {
+			detail.Hooks = toRestoreHooks(hooks)
 		}
-		if namespace, ok := meta["namespace"].(string); ok {
-			metadata.Namespace = namespace
+
+		if modifier, ok := spec["resourceModifier"].(map[string]interface{}); ok {
+			if name, ok := modifier["name"].(string); ok {
+				detail.ResourceModifier = name
+			}
+		}
+
+		if mapping, ok := spec["namespaceMapping"].(map[string]interface{}); ok {
+			detail.NamespaceMapping = make(map[string]string, len(mapping))
+			for source, target := range mapping {
+				if targetStr, ok := target.(string); ok {
+					detail.NamespaceMapping[source] = targetStr
+				}
+			}
 		}
-		// Add more metadata fields as needed
 	}
-	
-	return metadata
+
+	return detail, nil
 }
@@ -0,0 +1,85 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func TestGetSourceCluster(t *testing.T) {
+	backup := velerofake.NewObject(velero.BackupGVR, "velero", "shop-daily")
+	backup.SetAnnotations(map[string]string{
+		velero.SourceClusterNameAnnotation:    "eu-west-prod",
+		velero.SourceClusterVersionAnnotation: "v1.30.4",
+	})
+
+	cases := []struct {
+		backup   *unstructured.Unstructured
+		expected *velero.SourceCluster
+	}{
+		{backup, &velero.SourceCluster{Name: "eu-west-prod", KubernetesVersion: "v1.30.4"}},
+		{velerofake.NewObject(velero.BackupGVR, "velero", "unannotated"), nil},
+		{nil, nil},
+	}
+
+	for _, c := range cases {
+		actual := getSourceCluster(c.backup)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getSourceCluster(%v) == \n%#v\nexpected \n%#v\n", c.backup, actual, c.expected)
+		}
+	}
+}
+
+func TestParseRestoreDetail(t *testing.T) {
+	rawData := `{"metadata": {"name": "shop-restore"}, "spec": {"backupName": "shop-daily",
+		"includedNamespaces": ["shop"], "excludedNamespaces": ["shop-cache"],
+		"includedResources": ["deployments", "secrets"], "excludedResources": ["events"]},
+		"status": {"phase": "PartiallyFailed", "errors": 2, "warnings": 5,
+		"progress": {"totalItems": 40, "itemsRestored": 37},
+		"restoreItemOperationsAttempted": 3, "restoreItemOperationsCompleted": 2, "restoreItemOperationsFailed": 1}}`
+	expected := &RestoreDetail{
+		Status:             velero.StatusPartiallyFailed,
+		Phase:              velero.RestorePhasePartiallyFailed,
+		BackupName:         "shop-daily",
+		TotalItems:         40,
+		ItemsRestored:      37,
+		Progress:           RestoreProgress{TotalItems: 40, ItemsRestored: 37, ItemsFailed: 3},
+		IncludedNamespaces: []string{"shop"},
+		ExcludedNamespaces: []string{"shop-cache"},
+		IncludedResources:  []string{"deployments", "secrets"},
+		ExcludedResources:  []string{"events"},
+		ItemOperations:     &velero.ItemOperationsStatus{Attempted: 3, Completed: 2, Failed: 1},
+		Errors:             2,
+		Warnings:           5,
+	}
+
+	actual, err := parseRestoreDetail([]byte(rawData))
+	if err != nil {
+		t.Fatalf("parseRestoreDetail(%s) returned error: %s", rawData, err.Error())
+	}
+
+	actual.ObjectMeta = expected.ObjectMeta
+	actual.TypeMeta = expected.TypeMeta
+	actual.SuggestedPollSeconds = expected.SuggestedPollSeconds
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("parseRestoreDetail(%s) == \n%#v\nexpected \n%#v\n", rawData, actual, expected)
+	}
+}
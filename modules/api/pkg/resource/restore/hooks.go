@@ -0,0 +1,149 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// RestoreHooks are run for restored pods, either as init containers added to the pod or as commands executed in its
+// containers once they are running. Field names follow the Velero restore spec.
+type RestoreHooks struct {
+	Resources []RestoreResourceHookSpec `json:"resources,omitempty"`
+}
+
+// RestoreResourceHookSpec selects the restored pods hooks are run for.
+type RestoreResourceHookSpec struct {
+	Name               string                `json:"name"`
+	IncludedNamespaces []string              `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string              `json:"excludedNamespaces,omitempty"`
+	IncludedResources  []string              `json:"includedResources,omitempty"`
+	ExcludedResources  []string              `json:"excludedResources,omitempty"`
+	LabelSelector      *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	PostHooks          []RestoreResourceHook `json:"postHooks,omitempty"`
+}
+
+// RestoreResourceHook is a single hook, exactly one of Exec and Init is set.
+type RestoreResourceHook struct {
+	Exec *ExecRestoreHook `json:"exec,omitempty"`
+	Init *InitRestoreHook `json:"init,omitempty"`
+}
+
+// ExecRestoreHook runs a command in a container of the restored pod. The first container is used when none is set.
+type ExecRestoreHook struct {
+	Container    string               `json:"container,omitempty"`
+	Command      []string             `json:"command"`
+	OnError      velero.HookErrorMode `json:"onError,omitempty"`
+	ExecTimeout  string               `json:"execTimeout,omitempty"`
+	WaitTimeout  string               `json:"waitTimeout,omitempty"`
+	WaitForReady *bool                `json:"waitForReady,omitempty"`
+}
+
+// InitRestoreHook adds init containers to the restored pod.
+type InitRestoreHook struct {
+	InitContainers []v1.Container `json:"initContainers,omitempty"`
+	Timeout        string         `json:"timeout,omitempty"`
+}
+
+// RestoreHookStatus counts hooks Velero ran for the restore.
+type RestoreHookStatus struct {
+	HooksAttempted int `json:"hooksAttempted"`
+	HooksFailed    int `json:"hooksFailed"`
+}
+
+// validateHooks rejects hooks Velero would fail to run, as Velero only validates them when the restore starts.
+func validateHooks(hooks *RestoreHooks) error {
+	for _, resource := range hooks.Resources {
+		if resource.Name == "" {
+			return errors.NewBadRequest("restore hook name is required")
+		}
+
+		for _, hook := range resource.PostHooks {
+			if err := validateHook(resource.Name, hook); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateHook(name string, hook RestoreResourceHook) error {
+	if (hook.Exec == nil) == (hook.Init == nil) {
+		return errors.NewBadRequest(fmt.Sprintf("restore hook %s requires either an exec command or init containers", name))
+	}
+
+	if hook.Init != nil {
+		if len(hook.Init.InitContainers) == 0 {
+			return errors.NewBadRequest(fmt.Sprintf("restore hook %s requires init containers", name))
+		}
+		return validateDurations(name, hook.Init.Timeout)
+	}
+
+	if len(hook.Exec.Command) == 0 {
+		return errors.NewBadRequest(fmt.Sprintf("restore hook %s requires an exec command", name))
+	}
+
+	if !hook.Exec.OnError.IsValid() {
+		return errors.NewBadRequest(fmt.Sprintf("invalid onError %q of restore hook %s, expected %s or %s",
+			hook.Exec.OnError, name, velero.HookErrorModeContinue, velero.HookErrorModeFail))
+	}
+
+	return validateDurations(name, hook.Exec.ExecTimeout, hook.Exec.WaitTimeout)
+}
+
+func validateDurations(name string, durations ...string) error {
+	for _, duration := range durations {
+		if duration == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(duration); err != nil {
+			return errors.NewBadRequest(fmt.Sprintf("invalid timeout %q of restore hook %s: %s", duration, name,
+				err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// toRestoreHooks reads hooks from the spec of a restore, returning nil when there are none.
+func toRestoreHooks(rawHooks map[string]interface{}) *RestoreHooks {
+	hooks := &RestoreHooks{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawHooks, hooks); err != nil || len(hooks.Resources) == 0 {
+		return nil
+	}
+
+	return hooks
+}
+
+// toRestoreHookStatus reads hook counts from the status of a restore, returning nil when no hooks were run.
+func toRestoreHookStatus(rawStatus map[string]interface{}) *RestoreHookStatus {
+	attempted, _ := rawStatus["hooksAttempted"].(float64)
+	if attempted == 0 {
+		return nil
+	}
+
+	failed, _ := rawStatus["hooksFailed"].(float64)
+	return &RestoreHookStatus{HooksAttempted: int(attempted), HooksFailed: int(failed)}
+}
@@ -0,0 +1,122 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+func TestValidateRestoreHooks(t *testing.T) {
+	migrate := []string{"/app/migrate", "--up"}
+	initContainers := []v1.Container{{Name: "restore-wait", Image: "busybox", Command: []string{"sleep", "10"}}}
+	cases := []struct {
+		info    string
+		hooks   *RestoreHooks
+		isError bool
+	}{
+		{
+			"valid hooks",
+			&RestoreHooks{Resources: []RestoreResourceHookSpec{{
+				Name: "app",
+				PostHooks: []RestoreResourceHook{
+					{Init: &InitRestoreHook{InitContainers: initContainers, Timeout: "2m"}},
+					{Exec: &ExecRestoreHook{Container: "app", Command: migrate, OnError: velero.HookErrorModeContinue, ExecTimeout: "1m", WaitTimeout: "5m"}},
+				},
+			}}},
+			false,
+		},
+		{
+			"missing name",
+			&RestoreHooks{Resources: []RestoreResourceHookSpec{{PostHooks: []RestoreResourceHook{{Exec: &ExecRestoreHook{Command: migrate}}}}}},
+			true,
+		},
+		{
+			"neither exec nor init",
+			&RestoreHooks{Resources: []RestoreResourceHookSpec{{Name: "app", PostHooks: []RestoreResourceHook{{}}}}},
+			true,
+		},
+		{
+			"both exec and init",
+			&RestoreHooks{Resources: []RestoreResourceHookSpec{{Name: "app", PostHooks: []RestoreResourceHook{{
+				Exec: &ExecRestoreHook{Command: migrate},
+				Init: &InitRestoreHook{InitContainers: initContainers},
+			}}}}},
+			true,
+		},
+		{
+			"missing init containers",
+			&RestoreHooks{Resources: []RestoreResourceHookSpec{{Name: "app", PostHooks: []RestoreResourceHook{{Init: &InitRestoreHook{}}}}}},
+			true,
+		},
+		{
+			"missing command",
+			&RestoreHooks{Resources: []RestoreResourceHookSpec{{Name: "app", PostHooks: []RestoreResourceHook{{Exec: &ExecRestoreHook{}}}}}},
+			true,
+		},
+		{
+			"invalid error mode",
+			&RestoreHooks{Resources: []RestoreResourceHookSpec{{Name: "app", PostHooks: []RestoreResourceHook{{Exec: &ExecRestoreHook{Command: migrate, OnError: "Ignore"}}}}}},
+			true,
+		},
+		{
+			"invalid wait timeout",
+			&RestoreHooks{Resources: []RestoreResourceHookSpec{{Name: "app", PostHooks: []RestoreResourceHook{{Exec: &ExecRestoreHook{Command: migrate, WaitTimeout: "later"}}}}}},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		err := validateHooks(c.hooks)
+		if (err != nil) != c.isError {
+			t.Errorf("%s: validateHooks() == %v, expected error: %t", c.info, err, c.isError)
+		}
+	}
+}
+
+func TestParseRestoreDetailHooks(t *testing.T) {
+	rawData := `{"metadata": {"name": "shop-restore"}, "spec": {"hooks": {"resources": [{"name": "app",
+		"includedNamespaces": ["shop"], "postHooks": [{"exec": {"container": "app", "command": ["/app/migrate"],
+		"onError": "Continue", "waitTimeout": "5m"}}]}]}}, "status": {"phase": "Completed",
+		"hookStatus": {"hooksAttempted": 3, "hooksFailed": 1}}}`
+	expectedHooks := &RestoreHooks{Resources: []RestoreResourceHookSpec{{
+		Name:               "app",
+		IncludedNamespaces: []string{"shop"},
+		PostHooks: []RestoreResourceHook{{Exec: &ExecRestoreHook{
+			Container:   "app",
+			Command:     []string{"/app/migrate"},
+			OnError:     velero.HookErrorModeContinue,
+			WaitTimeout: "5m",
+		}}},
+	}}}
+	expectedStatus := &RestoreHookStatus{HooksAttempted: 3, HooksFailed: 1}
+
+	actual, err := parseRestoreDetail([]byte(rawData))
+	if err != nil {
+		t.Fatalf("parseRestoreDetail() returned error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(actual.Hooks, expectedHooks) {
+		t.Errorf("parseRestoreDetail() hooks == \n%#v\nexpected \n%#v\n", actual.Hooks, expectedHooks)
+	}
+
+	if !reflect.DeepEqual(actual.HookStatus, expectedStatus) {
+		t.Errorf("parseRestoreDetail() hook status == \n%#v\nexpected \n%#v\n", actual.HookStatus, expectedStatus)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// RestoreLinks references the backup a restore was made from and the schedule that created the backup, so that
+// clients can link to them without reading them.
+type RestoreLinks struct {
+	Backup   *BackupLink   `json:"backup,omitempty"`
+	Schedule *ScheduleLink `json:"schedule,omitempty"`
+}
+
+// BackupLink references the backup a restore was made from.
+type BackupLink struct {
+	Name       string             `json:"name"`
+	Namespace  string             `json:"namespace"`
+	Phase      velero.BackupPhase `json:"phase,omitempty"`
+	Expiration string             `json:"expiration,omitempty"`
+
+	// Deleted is set when the backup no longer exists
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// ScheduleLink references the schedule that created the backup a restore was made from.
+type ScheduleLink struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Deleted is set when the schedule no longer exists
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// getBackup returns the backup a restore was made from, or nil when the restore names no backup.
+func getBackup(ctx context.Context, client dynamic.Interface, namespace, name string) (*unstructured.Unstructured, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	return client.Resource(velero.BackupGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// getRestoreLinks returns the links of the restore. The backup is the one the restore was made from, with the error
+// of reading it. The schedule is the one the restore names, or the one the backup was created by. Objects that cannot
+// be read for other reasons than being deleted are linked by name only.
+func getRestoreLinks(ctx context.Context, client dynamic.Interface, detail *RestoreDetail,
+	restoreBackup *unstructured.Unstructured, backupErr error) *RestoreLinks {
+	namespace := detail.ObjectMeta.Namespace
+	links := &RestoreLinks{}
+
+	if detail.BackupName != "" {
+		links.Backup = &BackupLink{
+			Name:      detail.BackupName,
+			Namespace: namespace,
+			Deleted:   errors.IsNotFound(backupErr),
+		}
+	}
+
+	scheduleName := detail.ScheduleName
+	if restoreBackup != nil {
+		phase, _, _ := unstructured.NestedString(restoreBackup.Object, "status", "phase")
+		links.Backup.Phase = velero.BackupPhase(phase)
+		links.Backup.Expiration, _, _ = unstructured.NestedString(restoreBackup.Object, "status", "expiration")
+
+		if scheduleName == "" {
+			scheduleName = restoreBackup.GetLabels()[backup.ScheduleNameLabel]
+		}
+	}
+
+	if scheduleName != "" {
+		_, err := client.Resource(velero.ScheduleGVR).Namespace(namespace).Get(ctx, scheduleName, metav1.GetOptions{})
+		links.Schedule = &ScheduleLink{
+			Name:      scheduleName,
+			Namespace: namespace,
+			Deleted:   errors.IsNotFound(err),
+		}
+	}
+
+	if links.Backup == nil && links.Schedule == nil {
+		return nil
+	}
+
+	return links
+}
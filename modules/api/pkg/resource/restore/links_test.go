@@ -0,0 +1,104 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+	dashboardtypes "k8s.io/dashboard/types"
+)
+
+func TestGetRestoreLinks(t *testing.T) {
+	scheduled := velerofake.NewObject(velero.BackupGVR, "velero", "shop-daily-20240101020000")
+	scheduled.SetLabels(map[string]string{backup.ScheduleNameLabel: "shop-daily"})
+	_ = unstructured.SetNestedField(scheduled.Object, "Completed", "status", "phase")
+	_ = unstructured.SetNestedField(scheduled.Object, "2024-01-31T02:00:00Z", "status", "expiration")
+
+	manual := velerofake.NewObject(velero.BackupGVR, "velero", "shop-manual")
+	orphaned := velerofake.NewObject(velero.BackupGVR, "velero", "legacy-20230101020000")
+	orphaned.SetLabels(map[string]string{backup.ScheduleNameLabel: "legacy"})
+
+	dynamicClient := velerofake.NewDynamicClient(nil, scheduled, manual, orphaned,
+		velerofake.NewObject(velero.ScheduleGVR, "velero", "shop-daily"))
+
+	cases := []struct {
+		info         string
+		backupName   string
+		scheduleName string
+		expected     *RestoreLinks
+	}{
+		{
+			"backup of a schedule",
+			"shop-daily-20240101020000", "",
+			&RestoreLinks{
+				Backup: &BackupLink{Name: "shop-daily-20240101020000", Namespace: "velero",
+					Phase: velero.BackupPhaseCompleted, Expiration: "2024-01-31T02:00:00Z"},
+				Schedule: &ScheduleLink{Name: "shop-daily", Namespace: "velero"},
+			},
+		},
+		{
+			"backup without a schedule",
+			"shop-manual", "",
+			&RestoreLinks{Backup: &BackupLink{Name: "shop-manual", Namespace: "velero"}},
+		},
+		{
+			"deleted schedule",
+			"legacy-20230101020000", "",
+			&RestoreLinks{
+				Backup:   &BackupLink{Name: "legacy-20230101020000", Namespace: "velero"},
+				Schedule: &ScheduleLink{Name: "legacy", Namespace: "velero", Deleted: true},
+			},
+		},
+		{
+			"deleted backup of a named schedule",
+			"shop-daily-20230101020000", "shop-daily",
+			&RestoreLinks{
+				Backup:   &BackupLink{Name: "shop-daily-20230101020000", Namespace: "velero", Deleted: true},
+				Schedule: &ScheduleLink{Name: "shop-daily", Namespace: "velero"},
+			},
+		},
+		{
+			"no backup",
+			"", "",
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		detail := &RestoreDetail{
+			ObjectMeta:   dashboardtypes.ObjectMeta{Name: "shop-restore", Namespace: "velero"},
+			BackupName:   c.backupName,
+			ScheduleName: c.scheduleName,
+		}
+
+		restoreBackup, err := getBackup(context.Background(), dynamicClient, "velero", c.backupName)
+		if err != nil && !errors.IsNotFound(err) {
+			t.Fatalf("%s: getBackup() returned error: %v", c.info, err)
+		}
+
+		actual := getRestoreLinks(context.Background(), dynamicClient, detail, restoreBackup, err)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: getRestoreLinks() == \n%#v\nexpected \n%#v\n", c.info, actual, c.expected)
+		}
+	}
+}
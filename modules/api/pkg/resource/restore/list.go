@@ -15,70 +15,145 @@
 package restore
 
 import (
+	"context"
 	"net/http"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/args"
 	"k8s.io/dashboard/api/pkg/resource/common"
-	"k8s.io/dashboard/api/pkg/resource/customresourcedefinition"
 	"k8s.io/dashboard/api/pkg/resource/dataselect"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/subscription"
+	"k8s.io/dashboard/api/pkg/resource/velero"
 	"k8s.io/dashboard/types"
 )
 
-// RestoreList contains a list of Restore resources in the cluster.
 type RestoreList struct {
-        ListMeta types.ListMeta `json:"listMeta"`
-        Items    []Restore      `json:"items"`
+	ListMeta types.ListMeta `json:"listMeta"`
+
+	// Basic information about resources status on the list.
+	Status velero.ListStatus `json:"status"`
+	Items  []Restore         `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
 }
 
-// Restore represents a Velero restore resource.
 type Restore struct {
-        ObjectMeta types.ObjectMeta `json:"objectMeta"`
-        TypeMeta   types.TypeMeta   `json:"typeMeta"`
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	Phase          velero.RestorePhase `json:"phase,omitempty"`
+	BackupName     string              `json:"backupName,omitempty"`
+	StartTime      *metav1.Time        `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time        `json:"completionTime,omitempty"`
+
+	// CreatedNamespaces is only set by CreateRestore, for target namespaces it created before the restore.
+	CreatedNamespaces []string `json:"createdNamespaces,omitempty"`
+
+	// Errors is only set by CreateRestore, when the creation could not be recorded in the audit log.
+	Errors []error `json:"errors,omitempty"`
 }
 
-// GetRestoreList returns a list of all Restore resources in the cluster.
 func GetRestoreList(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*RestoreList, error) {
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	items, nonCriticalErrors, err := getRestores(ctx, request, namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get REST config for custom resource operations  
-	config, err := client.Config(request)
+	return toRestoreList(items, nonCriticalErrors, dsQuery), nil
+}
+
+// GetBackupRestores returns restores created from the given backup. Unless the query asks for a different order,
+// the newest restores come first.
+func GetBackupRestores(request *http.Request, namespace *common.NamespaceQuery, backupName string, dsQuery *dataselect.DataSelectQuery) (*RestoreList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	items, nonCriticalErrors, err := getRestores(ctx, request, namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	// Use dashboard's CRD framework to get Velero restore objects
-	crdObjects, err := customresourcedefinition.GetCustomResourceObjectList(
-			apiExtClient,
-			config,
-			namespace,
-			dsQuery,
-			"restores.velero.io",
-	)
+	fromBackup := make([]unstructured.Unstructured, 0)
+	for _, item := range items {
+		name, _, _ := unstructured.NestedString(item.Object, "spec", "backupName")
+		if name == backupName {
+			fromBackup = append(fromBackup, item)
+		}
+	}
+
+	query := *dsQuery
+	if query.SortQuery == nil || len(query.SortQuery.SortByList) == 0 {
+		query.SortQuery = dataselect.NewSortQuery([]string{"d", dataselect.CreationTimestampProperty})
+	}
+
+	return toRestoreList(fromBackup, nonCriticalErrors, &query), nil
+}
+
+// getRestores lists Velero restores. Restores beyond the configured limit and namespaces the user may not read are
+// left out and reported as non-critical errors.
+func getRestores(ctx context.Context, request *http.Request, namespace *common.NamespaceQuery) ([]unstructured.Unstructured, []error, error) {
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Convert CRD objects to Restore structs
-	items := make([]Restore, 0, len(crdObjects.Items))
-	for _, item := range crdObjects.Items {
-			restore := Restore{
-					ObjectMeta: types.ObjectMeta{
-							Name:      item.ObjectMeta.Name,
-							Namespace: item.ObjectMeta.Namespace,
-					},
-					TypeMeta: types.TypeMeta{
-							Kind: "Restore",
-					},
-			}
-			items = append(items, restore)
+	return velero.ListInNamespaces(ctx, dynamicClient, velero.RestoreGVR, namespace, metav1.ListOptions{},
+		args.VeleroMaxListItems())
+}
+
+func toRestoreList(items []unstructured.Unstructured, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *RestoreList {
+	restores := make([]Restore, 0, len(items))
+	for _, item := range items {
+		restores = append(restores, toRestore(item))
 	}
 
+	restoreCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(restores), dsQuery)
 	return &RestoreList{
-			ListMeta: types.ListMeta{TotalItems: len(items)},
-			Items:    items,
-	}, nil
+		ListMeta: types.ListMeta{TotalItems: filteredTotal},
+		Status:   getRestoreListStatus(restores),
+		Items:    fromCells(restoreCells),
+		Errors:   nonCriticalErrors,
+	}
+}
+
+func getRestoreListStatus(restores []Restore) velero.ListStatus {
+	status := velero.ListStatus{}
+	for _, restore := range restores {
+		status.Add(restore.Phase.Status())
+	}
+
+	return status
+}
+
+func toRestore(item unstructured.Unstructured) Restore {
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	backupName, _, _ := unstructured.NestedString(item.Object, "spec", "backupName")
+	return Restore{
+		ObjectMeta: types.ObjectMeta{
+			Name:              item.GetName(),
+			Namespace:         item.GetNamespace(),
+			Labels:            item.GetLabels(),
+			CreationTimestamp: item.GetCreationTimestamp(),
+		},
+		TypeMeta: types.TypeMeta{
+			Kind: "Restore",
+		},
+		Phase:          velero.RestorePhase(phase),
+		BackupName:     backupName,
+		StartTime:      nestedTime(item.Object, "status", "startTimestamp"),
+		CompletionTime: nestedTime(item.Object, "status", "completionTimestamp"),
+	}
+}
+
+// SubscribeRestoreList subscribes to restores being added, modified or deleted in namespaces matching the query.
+func SubscribeRestoreList(request *http.Request, namespace *common.NamespaceQuery) (*subscription.Subscription, error) {
+	return subscription.Subscribe(request, velero.RestoreGVR, namespace, func(item *unstructured.Unstructured) interface{} {
+		return toRestore(*item)
+	})
 }
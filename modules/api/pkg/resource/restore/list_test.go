@@ -0,0 +1,115 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/types"
+)
+
+func TestToRestore(t *testing.T) {
+	started := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	cases := []struct {
+		object   map[string]interface{}
+		expected Restore
+	}{
+		{
+			map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "restore-1", "namespace": "velero"},
+			},
+			Restore{
+				ObjectMeta: types.ObjectMeta{Name: "restore-1", Namespace: "velero"},
+				TypeMeta:   types.TypeMeta{Kind: "Restore"},
+			},
+		},
+		{
+			map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "restore-2", "namespace": "velero"},
+				"spec":     map[string]interface{}{"backupName": "daily-20240101020000"},
+				"status": map[string]interface{}{
+					"phase":          "Completed",
+					"startTimestamp": "2024-01-01T02:00:00Z",
+				},
+			},
+			Restore{
+				ObjectMeta: types.ObjectMeta{Name: "restore-2", Namespace: "velero"},
+				TypeMeta:   types.TypeMeta{Kind: "Restore"},
+				Phase:      "Completed",
+				BackupName: "daily-20240101020000",
+				StartTime:  &metav1.Time{Time: started},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toRestore(unstructured.Unstructured{Object: c.object})
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toRestore(%#v) == \n%#v\nexpected \n%#v\n", c.object, actual, c.expected)
+		}
+	}
+}
+
+// newRawRestoreList returns count completed restores encoded the same way the API server lists them.
+func newRawRestoreList(count int) []byte {
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "velero.io/v1", "kind": "RestoreList"}}
+	created := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		started := created.Add(time.Duration(i) * time.Hour)
+		item := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"backupName": fmt.Sprintf("daily-%s", started.Format("20060102150405"))},
+			"status": map[string]interface{}{
+				"phase":               "Completed",
+				"startTimestamp":      started.Format(time.RFC3339),
+				"completionTimestamp": started.Add(5 * time.Minute).Format(time.RFC3339),
+			},
+		}}
+		item.SetAPIVersion("velero.io/v1")
+		item.SetKind("Restore")
+		item.SetName(fmt.Sprintf("restore-%05d", i))
+		item.SetNamespace("velero")
+		item.SetCreationTimestamp(metav1.NewTime(started))
+		list.Items = append(list.Items, item)
+	}
+
+	raw, _ := list.MarshalJSON()
+	return raw
+}
+
+func benchmarkGetRestoreList(count int, b *testing.B) {
+	raw := newRawRestoreList(count)
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NewPaginationQuery(10, 0),
+		dataselect.NewSortQuery([]string{"d", dataselect.StartTimeProperty}), dataselect.NoFilter, dataselect.NoMetrics)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		list := &unstructured.UnstructuredList{}
+		if err := list.UnmarshalJSON(raw); err != nil {
+			b.Fatal(err)
+		}
+		toRestoreList(list.Items, nil, dsQuery)
+	}
+}
+
+func BenchmarkGetRestoreList1000(b *testing.B)  { benchmarkGetRestoreList(1000, b) }
+func BenchmarkGetRestoreList5000(b *testing.B)  { benchmarkGetRestoreList(5000, b) }
+func BenchmarkGetRestoreList10000(b *testing.B) { benchmarkGetRestoreList(10000, b) }
@@ -0,0 +1,128 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
+)
+
+// namespaceGVR is the resource access is reviewed for before target namespaces of a restore are created.
+var namespaceGVR = v1.SchemeGroupVersion.WithResource("namespaces")
+
+// targetNamespace is a target namespace of a namespace mapping together with the backed up namespace mapped to it.
+type targetNamespace struct {
+	source, target string
+}
+
+// getMissingTargetNamespaces returns the target namespaces of the mapping that do not exist, sorted by name. A target
+// mapped from several namespaces is returned once, with the first of them as its source.
+func getMissingTargetNamespaces(ctx context.Context, client kubernetes.Interface, mapping map[string]string) ([]targetNamespace, error) {
+	sources := make([]string, 0, len(mapping))
+	for source := range mapping {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	result := make([]targetNamespace, 0)
+	seen := make(map[string]bool, len(mapping))
+	for _, source := range sources {
+		target := mapping[source]
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+
+		_, err := client.CoreV1().Namespaces().Get(ctx, target, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			result = append(result, targetNamespace{source: source, target: target})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].target < result[j].target
+	})
+
+	return result, nil
+}
+
+// createTargetNamespaces creates the target namespaces, optionally with the labels of their source namespace when it
+// exists in the cluster, and annotated with the subject creating them. Namespaces created in the meantime by someone
+// else are left as they are. The names of the created namespaces are returned. In dry run each namespace is only
+// validated by the API server.
+func createTargetNamespaces(ctx context.Context, client kubernetes.Interface, targets []targetNamespace, copyLabels bool, subject string, dryRun bool) ([]string, error) {
+	result := make([]string, 0, len(targets))
+	for _, item := range targets {
+		namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: item.target}}
+		audit.SetCreatedByUser(namespace, subject)
+		if copyLabels {
+			labels, err := getNamespaceLabels(ctx, client, item.source)
+			if err != nil {
+				return nil, err
+			}
+			namespace.Labels = labels
+		}
+
+		_, err := client.CoreV1().Namespaces().Create(ctx, namespace, velero.CreateOptions(dryRun))
+		if k8serrors.IsAlreadyExists(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create namespace %s: %w", item.target, err)
+		}
+
+		result = append(result, item.target)
+	}
+
+	return result, nil
+}
+
+// getNamespaceLabels returns the labels of the namespace, nil when it does not exist. The name label the API server
+// sets on every namespace is left out.
+func getNamespaceLabels(ctx context.Context, client kubernetes.Interface, name string) (map[string]string, error) {
+	namespace, err := client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]string
+	for key, value := range namespace.Labels {
+		if key == v1.LabelMetadataName {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string, len(namespace.Labels))
+		}
+		result[key] = value
+	}
+
+	return result, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newNamespace(name string, labels map[string]string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestGetMissingTargetNamespaces(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(newNamespace("shop", nil), newNamespace("shop-staging", nil))
+	mapping := map[string]string{
+		"shop":    "shop-staging",
+		"blog":    "blog-clone",
+		"billing": "shared-clone",
+		"orders":  "shared-clone",
+	}
+
+	actual, err := getMissingTargetNamespaces(context.Background(), k8sClient, mapping)
+	if err != nil {
+		t.Fatalf("getMissingTargetNamespaces() returned error: %v", err)
+	}
+
+	expected := []targetNamespace{{source: "blog", target: "blog-clone"}, {source: "billing", target: "shared-clone"}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getMissingTargetNamespaces() == %v, expected %v", actual, expected)
+	}
+}
+
+func TestCreateTargetNamespaces(t *testing.T) {
+	sourceLabels := map[string]string{v1.LabelMetadataName: "shop", "team": "payments", "env": "prod"}
+	cases := []struct {
+		info           string
+		copyLabels     bool
+		expectedLabels map[string]string
+	}{
+		{"without labels", false, nil},
+		{"with labels of the source", true, map[string]string{"team": "payments", "env": "prod"}},
+	}
+
+	for _, c := range cases {
+		k8sClient := fake.NewSimpleClientset(newNamespace("shop", sourceLabels), newNamespace("taken", nil))
+		targets := []targetNamespace{
+			{source: "shop", target: "shop-clone"},
+			{source: "gone", target: "gone-clone"},
+			{source: "shop", target: "taken"},
+		}
+
+		actual, err := createTargetNamespaces(context.Background(), k8sClient, targets, c.copyLabels, "alice", false)
+		if err != nil {
+			t.Errorf("%s: createTargetNamespaces() returned error: %v", c.info, err)
+			continue
+		}
+
+		if expected := []string{"shop-clone", "gone-clone"}; !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: createTargetNamespaces() == %v, expected %v", c.info, actual, expected)
+		}
+
+		created, err := k8sClient.CoreV1().Namespaces().Get(context.Background(), "shop-clone", metav1.GetOptions{})
+		if err != nil {
+			t.Errorf("%s: namespace shop-clone was not created: %v", c.info, err)
+			continue
+		}
+		if !reflect.DeepEqual(created.Labels, c.expectedLabels) {
+			t.Errorf("%s: namespace shop-clone has labels %v, expected %v", c.info, created.Labels, c.expectedLabels)
+		}
+		if created.Annotations[audit.CreatedByAnnotation] != "alice" {
+			t.Errorf("%s: namespace shop-clone has annotations %v, expected it created by alice", c.info, created.Annotations)
+		}
+	}
+}
+
+func TestCreateRestoreWithMissingTargetNamespace(t *testing.T) {
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newPreflightBackup("nightly", "Completed", "default", time.Now().Add(24*time.Hour)),
+		newPreflightStorageLocation("default", "Available"))
+	k8sClient := fake.NewSimpleClientset(newNamespace("shop", map[string]string{"team": "payments"}))
+	spec := &RestoreSpec{
+		Name:                "nightly-clone",
+		Namespace:           "velero",
+		BackupName:          "nightly",
+		NamespaceMapping:    map[string]string{"shop": "shop-clone"},
+		CreateNamespaces:    true,
+		CopyNamespaceLabels: true,
+	}
+
+	targets, err := getMissingTargetNamespaces(context.Background(), k8sClient, spec.NamespaceMapping)
+	if err != nil {
+		t.Fatalf("getMissingTargetNamespaces() returned error: %v", err)
+	}
+
+	actual, err := createRestore(context.Background(), dynamicClient, k8sClient, spec, targets, "alice", false)
+	if err != nil {
+		t.Fatalf("createRestore() returned error: %v", err)
+	}
+	if expected := []string{"shop-clone"}; !reflect.DeepEqual(actual.CreatedNamespaces, expected) {
+		t.Errorf("createRestore() created namespaces %v, expected %v", actual.CreatedNamespaces, expected)
+	}
+
+	namespace, err := k8sClient.CoreV1().Namespaces().Get(context.Background(), "shop-clone", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("namespace shop-clone was not created: %v", err)
+	}
+	if namespace.Labels["team"] != "payments" {
+		t.Errorf("namespace shop-clone has labels %v, expected the labels of shop", namespace.Labels)
+	}
+
+	restore, err := dynamicClient.Resource(velero.RestoreGVR).Namespace("velero").Get(context.Background(),
+		"nightly-clone", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("restore nightly-clone was not created: %v", err)
+	}
+	if restore.GetAnnotations()[audit.CreatedByAnnotation] != "alice" {
+		t.Errorf("restore nightly-clone has annotations %v, expected it created by alice", restore.GetAnnotations())
+	}
+}
+
+func TestRestoreSpecValidateNamespaceLabels(t *testing.T) {
+	spec := &RestoreSpec{NamespaceMapping: map[string]string{"shop": "shop-clone"}, CopyNamespaceLabels: true}
+	if err := spec.Validate(); err == nil {
+		t.Error("Validate() returned no error for copying namespace labels without creating namespaces")
+	}
+
+	spec.CreateNamespaces = true
+	if err := spec.Validate(); err != nil {
+		t.Errorf("Validate() returned error: %v", err)
+	}
+}
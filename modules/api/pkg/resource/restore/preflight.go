@@ -0,0 +1,110 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/storagelocation"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// checkBackup makes sure the backup of the restore can be restored from, as Velero would only fail the restore
+// validation afterwards. The backup has to exist, must not be expired or being deleted, and its storage location must
+// not be unavailable. Its phase has to be Completed or PartiallyFailed, unless the spec allows incomplete backups. All
+// failed checks are returned as causes of a single invalid error.
+func checkBackup(ctx context.Context, client dynamic.Interface, spec *RestoreSpec, now time.Time) error {
+	path := field.NewPath("backupName")
+	if spec.BackupName == "" {
+		return newInvalidRestore(spec, field.ErrorList{field.Required(path, "backup to restore from is required")})
+	}
+
+	backup, err := client.Resource(velero.BackupGVR).Namespace(spec.Namespace).Get(ctx, spec.BackupName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return newInvalidRestore(spec, field.ErrorList{field.NotFound(path, spec.BackupName)})
+	}
+	if err != nil {
+		return err
+	}
+
+	allErrs := field.ErrorList{}
+	phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+	switch backupPhase := velero.BackupPhase(phase); {
+	case backupPhase == velero.BackupPhaseDeleting:
+		allErrs = append(allErrs, field.Invalid(path, spec.BackupName, "backup is being deleted"))
+	case backupPhase != velero.BackupPhaseCompleted && backupPhase != velero.BackupPhasePartiallyFailed && !spec.AllowIncomplete:
+		if backupPhase == "" {
+			backupPhase = velero.BackupPhaseNew
+		}
+		allErrs = append(allErrs, field.Invalid(path, spec.BackupName, fmt.Sprintf("backup phase is %s, only Completed "+
+			"and PartiallyFailed backups can be restored unless incomplete backups are allowed", backupPhase)))
+	}
+
+	if expiration := nestedTime(backup.Object, "status", "expiration"); expiration != nil && expiration.Time.Before(now) {
+		allErrs = append(allErrs, field.Invalid(path, spec.BackupName, fmt.Sprintf("backup expired at %s and is about to "+
+			"be garbage collected", expiration.UTC().Format(time.RFC3339))))
+	}
+
+	allErrs = append(allErrs, checkStorageLocation(ctx, client, backup, path)...)
+	if len(allErrs) > 0 {
+		return newInvalidRestore(spec, allErrs)
+	}
+
+	return nil
+}
+
+// checkStorageLocation reports a storage location of the backup that does not exist or that Velero found unavailable.
+// Locations that can not be read, e.g. for lack of permissions, are not reported.
+func checkStorageLocation(ctx context.Context, client dynamic.Interface, backup *unstructured.Unstructured, path *field.Path) field.ErrorList {
+	name, _, _ := unstructured.NestedString(backup.Object, "spec", "storageLocation")
+	if name == "" {
+		return nil
+	}
+
+	location, err := client.Resource(velero.BackupStorageLocationGVR).Namespace(backup.GetNamespace()).
+		Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return field.ErrorList{field.Invalid(path, backup.GetName(), fmt.Sprintf("storage location %s of the backup "+
+			"does not exist", name))}
+	}
+	if err != nil {
+		return nil
+	}
+
+	phase, _, _ := unstructured.NestedString(location.Object, "status", "phase")
+	if storagelocation.StorageLocationPhase(phase) != storagelocation.StorageLocationPhaseUnavailable {
+		return nil
+	}
+
+	detail := fmt.Sprintf("storage location %s of the backup is unavailable", name)
+	if message, _, _ := unstructured.NestedString(location.Object, "status", "message"); message != "" {
+		detail = fmt.Sprintf("%s: %s", detail, message)
+	}
+
+	return field.ErrorList{field.Invalid(path, backup.GetName(), detail)}
+}
+
+func newInvalidRestore(spec *RestoreSpec, allErrs field.ErrorList) error {
+	return k8serrors.NewInvalid(schema.GroupKind{Group: velero.GroupName, Kind: "Restore"}, spec.Name, allErrs)
+}
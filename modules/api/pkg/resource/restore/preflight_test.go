@@ -0,0 +1,98 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newPreflightBackup(name, phase, storageLocation string, expiration time.Time) *unstructured.Unstructured {
+	item := velerofake.NewObject(velero.BackupGVR, "velero", name)
+	_ = unstructured.SetNestedField(item.Object, storageLocation, "spec", "storageLocation")
+	_ = unstructured.SetNestedField(item.Object, phase, "status", "phase")
+	_ = unstructured.SetNestedField(item.Object, expiration.Format(time.RFC3339), "status", "expiration")
+	return item
+}
+
+func newPreflightStorageLocation(name, phase string) *unstructured.Unstructured {
+	item := velerofake.NewObject(velero.BackupStorageLocationGVR, "velero", name)
+	_ = unstructured.SetNestedField(item.Object, phase, "status", "phase")
+	return item
+}
+
+func TestCheckBackup(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	valid := now.Add(24 * time.Hour)
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newPreflightBackup("completed", "Completed", "default", valid),
+		newPreflightBackup("partial", "PartiallyFailed", "default", valid),
+		newPreflightBackup("failed", "Failed", "default", valid),
+		newPreflightBackup("deleting", "Deleting", "default", valid),
+		newPreflightBackup("expired", "Completed", "default", now.Add(-time.Hour)),
+		newPreflightBackup("offline", "Completed", "offline", valid),
+		newPreflightBackup("orphaned", "Completed", "removed", valid),
+		newPreflightBackup("failed-offline", "Failed", "offline", valid),
+		newPreflightStorageLocation("default", "Available"),
+		newPreflightStorageLocation("offline", "Unavailable"),
+	)
+
+	cases := []struct {
+		backupName      string
+		allowIncomplete bool
+		expectedCauses  int
+	}{
+		{"completed", false, 0},
+		{"partial", false, 0},
+		{"failed", false, 1},
+		{"failed", true, 0},
+		{"deleting", true, 1},
+		{"expired", false, 1},
+		{"offline", false, 1},
+		{"orphaned", false, 1},
+		{"failed-offline", false, 2},
+		{"missing", true, 1},
+		{"", false, 1},
+	}
+
+	for _, c := range cases {
+		spec := &RestoreSpec{Name: "restore", Namespace: "velero", BackupName: c.backupName, AllowIncomplete: c.allowIncomplete}
+		err := checkBackup(context.Background(), dynamicClient, spec, now)
+		if c.expectedCauses == 0 {
+			if err != nil {
+				t.Errorf("checkBackup(%q) returned error: %v", c.backupName, err)
+			}
+			continue
+		}
+
+		if !k8serrors.IsInvalid(err) {
+			t.Errorf("checkBackup(%q) == %v, expected an invalid error", c.backupName, err)
+			continue
+		}
+
+		status := err.(k8serrors.APIStatus).Status()
+		if actual := len(status.Details.Causes); actual != c.expectedCauses {
+			t.Errorf("checkBackup(%q) returned %d causes %v, expected %d", c.backupName, actual, status.Details.Causes,
+				c.expectedCauses)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+)
+
+// RestorePreview lists the resources a proposed restore would bring back.
+type RestorePreview struct {
+	BackupName string        `json:"backupName"`
+	Items      []PreviewItem `json:"items"`
+
+	// Existing is the number of items that already exist in the cluster. Velero keeps them as they are, or patches
+	// them with the update existing resource policy.
+	Existing int `json:"existing"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// PreviewItem is a resource of the backup the restore would bring back.
+type PreviewItem struct {
+	// Kind as listed in the backup, e.g. apps/v1/Deployment.
+	Kind string `json:"kind"`
+
+	// SourceNamespace is the backed up namespace of the resource and Namespace the one it is restored to. Both are
+	// empty for cluster-scoped resources, except for namespaces, which are restored from their source namespace.
+	SourceNamespace string `json:"sourceNamespace,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	Name            string `json:"name"`
+
+	// Exists tells whether the resource already exists in the cluster, which makes it a potential conflict.
+	Exists bool `json:"exists"`
+}
+
+// PreviewRestore lists every resource the proposed restore would bring back, after applying its resource and
+// namespace filters and namespace mapping, and tells which ones already exist. Cluster-scoped resources are included
+// when all namespaces are restored, as Velero does by default. The label selector is not applied, as the resource list
+// of the backup has no labels. Nothing is changed in the cluster.
+func PreviewRestore(request *http.Request, spec *RestoreSpec) (*RestorePreview, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceList, err := getResourceList(ctx, dynamicClient, spec.Namespace, spec.BackupName)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(k8sClient.Discovery()))
+	resources, nonCriticalErrors := getRestoredResources(resourceList, spec, mapper, restoresAllNamespaces(spec))
+
+	items, err := previewResources(ctx, dynamicClient, spec, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RestorePreview{BackupName: spec.BackupName, Items: items, Errors: nonCriticalErrors}
+	for _, item := range items {
+		if item.Exists {
+			result.Existing++
+		}
+	}
+
+	return result, nil
+}
+
+// previewResources looks up the resources of each kind in the namespace they are restored to, listing the namespace
+// once instead of getting every resource.
+func previewResources(ctx context.Context, client dynamic.Interface, spec *RestoreSpec, resources []collisionCandidates) ([]PreviewItem, error) {
+	result := make([]PreviewItem, 0)
+	for _, resource := range resources {
+		var target dynamic.ResourceInterface = client.Resource(resource.resource)
+		namespace := ""
+		if resource.sourceNamespace != "" {
+			namespace = getTargetNamespace(resource.sourceNamespace, spec)
+			target = client.Resource(resource.resource).Namespace(namespace)
+		}
+
+		existing, _, err := velero.List(ctx, target, metav1.ListOptions{}, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		names := make(map[string]bool, len(existing))
+		for _, item := range existing {
+			names[item.GetName()] = true
+		}
+
+		for _, name := range resource.names {
+			item := PreviewItem{Kind: resource.kind, SourceNamespace: resource.sourceNamespace, Namespace: namespace, Name: name}
+			if resource.resource.GroupResource() == namespacesResource {
+				item.SourceNamespace, item.Name = name, getTargetNamespace(name, spec)
+			}
+			item.Exists = names[item.Name]
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+// restoresAllNamespaces tells whether the restore includes every backed up namespace.
+func restoresAllNamespaces(spec *RestoreSpec) bool {
+	return len(spec.IncludedNamespaces) == 0 || slices.Contains(spec.IncludedNamespaces, "*")
+}
@@ -0,0 +1,105 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+)
+
+func TestGetRestoredResourcesClusterScoped(t *testing.T) {
+	resourceList := map[string][]string{
+		"v1/ConfigMap": {"shop/settings", "blog/theme"},
+		"v1/Namespace": {"shop", "blog"},
+	}
+	spec := &RestoreSpec{ExcludedNamespaces: []string{"blog"}}
+
+	expected := []collisionCandidates{
+		{"", "v1/Namespace", namespaceGVR, []string{"shop"}},
+		{"shop", "v1/ConfigMap", configMapGVR, []string{"settings"}},
+	}
+
+	actual, nonCriticalErrors := getRestoredResources(resourceList, spec, newCollisionMapper(), true)
+	if len(nonCriticalErrors) > 0 {
+		t.Errorf("getRestoredResources() returned errors: %v", nonCriticalErrors)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getRestoredResources() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestPreviewResources(t *testing.T) {
+	newObject := func(kind, namespace, name string) *unstructured.Unstructured {
+		object := &unstructured.Unstructured{}
+		object.SetAPIVersion("v1")
+		object.SetKind(kind)
+		object.SetNamespace(namespace)
+		object.SetName(name)
+		return object
+	}
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMapGVR: "ConfigMapList", namespaceGVR: "NamespaceList"},
+		newObject("ConfigMap", "shop-copy", "flags"),
+		newObject("ConfigMap", "shop", "settings"),
+		newObject("Namespace", "", "shop-copy"),
+	)
+
+	spec := &RestoreSpec{NamespaceMapping: map[string]string{"shop": "shop-copy"}}
+	resources := []collisionCandidates{
+		{"", "v1/Namespace", namespaceGVR, []string{"blog", "shop"}},
+		{"blog", "v1/ConfigMap", configMapGVR, []string{"theme"}},
+		{"shop", "v1/ConfigMap", configMapGVR, []string{"flags", "settings"}},
+	}
+
+	expected := []PreviewItem{
+		{Kind: "v1/Namespace", SourceNamespace: "blog", Name: "blog"},
+		{Kind: "v1/Namespace", SourceNamespace: "shop", Name: "shop-copy", Exists: true},
+		{Kind: "v1/ConfigMap", SourceNamespace: "blog", Namespace: "blog", Name: "theme"},
+		{Kind: "v1/ConfigMap", SourceNamespace: "shop", Namespace: "shop-copy", Name: "flags", Exists: true},
+		{Kind: "v1/ConfigMap", SourceNamespace: "shop", Namespace: "shop-copy", Name: "settings"},
+	}
+
+	actual, err := previewResources(context.TODO(), dynamicClient, spec, resources)
+	if err != nil {
+		t.Fatalf("previewResources() returned error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("previewResources() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestRestoresAllNamespaces(t *testing.T) {
+	cases := []struct {
+		included []string
+		expected bool
+	}{
+		{nil, true},
+		{[]string{"*"}, true},
+		{[]string{"shop"}, false},
+	}
+
+	for _, c := range cases {
+		actual := restoresAllNamespaces(&RestoreSpec{IncludedNamespaces: c.included})
+		if actual != c.expected {
+			t.Errorf("restoresAllNamespaces(%#v) == %t, expected %t", c.included, actual, c.expected)
+		}
+	}
+}
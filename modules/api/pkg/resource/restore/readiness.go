@@ -0,0 +1,198 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+)
+
+const (
+	// RestoreNameLabel is the label Velero puts on every resource it restores.
+	RestoreNameLabel = "velero.io/restore-name"
+
+	// ReadinessAnnotation stores the last readiness summary recorded on the restore.
+	ReadinessAnnotation = "dashboard.kubernetes.io/restore-readiness"
+)
+
+// RestoreReadiness summarizes readiness of the workloads brought back by a restore.
+type RestoreReadiness struct {
+	CheckedAt    metav1.Time       `json:"checkedAt"`
+	Namespaces   []string          `json:"namespaces"`
+	Deployments  WorkloadReadiness `json:"deployments"`
+	StatefulSets WorkloadReadiness `json:"statefulSets"`
+	Ready        bool              `json:"ready"`
+}
+
+// WorkloadReadiness tells how many of the restored workloads of a kind are available, e.g. 3/4 Deployments.
+type WorkloadReadiness struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+}
+
+// getRestoreReadiness returns readiness of the restored workloads. While the restore finished less than the
+// configured readiness window ago, readiness is checked live and recorded on the restore, afterwards the last
+// recorded summary is returned.
+func getRestoreReadiness(ctx context.Context, request *http.Request, rawData []byte) *RestoreReadiness {
+	rawRestore := &unstructured.Unstructured{}
+	if err := rawRestore.UnmarshalJSON(rawData); err != nil {
+		return nil
+	}
+
+	recorded := getRecordedReadiness(rawRestore.GetAnnotations())
+	phase, _, _ := unstructured.NestedString(rawRestore.Object, "status", "phase")
+	restorePhase := velero.RestorePhase(phase)
+	completionTime := nestedTime(rawRestore.Object, "status", "completionTimestamp")
+	window := args.VeleroRestoreReadinessWindow()
+	if window <= 0 || completionTime == nil || time.Since(completionTime.Time) > window ||
+		(restorePhase != velero.RestorePhaseCompleted && restorePhase != velero.RestorePhasePartiallyFailed) {
+		return recorded
+	}
+
+	// Workloads are read with the typed client, so the detail can no longer be told unchanged by Velero objects alone.
+	velero.Invalidate(request)
+	readiness, err := checkRestoreReadiness(ctx, request, rawRestore.GetName())
+	if err != nil {
+		klog.ErrorS(err, "Could not check readiness of restored workloads", "restore", rawRestore.GetName())
+		return recorded
+	}
+
+	if err := recordReadiness(ctx, request, rawRestore.GetNamespace(), rawRestore.GetName(), readiness); err != nil {
+		klog.ErrorS(err, "Could not record readiness of restored workloads", "restore", rawRestore.GetName())
+	}
+
+	return readiness
+}
+
+func getRecordedReadiness(annotations map[string]string) *RestoreReadiness {
+	value, ok := annotations[ReadinessAnnotation]
+	if !ok {
+		return nil
+	}
+
+	readiness := new(RestoreReadiness)
+	if err := json.Unmarshal([]byte(value), readiness); err != nil {
+		klog.ErrorS(err, "Could not parse recorded restore readiness", "annotation", ReadinessAnnotation)
+		return nil
+	}
+
+	return readiness
+}
+
+func checkRestoreReadiness(ctx context.Context, request *http.Request, name string) (*RestoreReadiness, error) {
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	options := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(labels.Set{RestoreNameLabel: name}).String()}
+	deployments, err := k8sClient.AppsV1().Deployments(v1.NamespaceAll).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSets, err := k8sClient.AppsV1().StatefulSets(v1.NamespaceAll).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRestoreReadiness(deployments.Items, statefulSets.Items, time.Now()), nil
+}
+
+func toRestoreReadiness(deployments []apps.Deployment, statefulSets []apps.StatefulSet, now time.Time) *RestoreReadiness {
+	readiness := &RestoreReadiness{
+		CheckedAt:  metav1.NewTime(now),
+		Namespaces: make([]string, 0),
+	}
+	namespaces := make(map[string]bool)
+
+	for _, deployment := range deployments {
+		namespaces[deployment.Namespace] = true
+		readiness.Deployments.Total++
+		if deployment.Status.AvailableReplicas >= desiredReplicas(deployment.Spec.Replicas) {
+			readiness.Deployments.Available++
+		}
+	}
+
+	for _, statefulSet := range statefulSets {
+		namespaces[statefulSet.Namespace] = true
+		readiness.StatefulSets.Total++
+		if statefulSet.Status.ReadyReplicas >= desiredReplicas(statefulSet.Spec.Replicas) {
+			readiness.StatefulSets.Available++
+		}
+	}
+
+	for namespace := range namespaces {
+		readiness.Namespaces = append(readiness.Namespaces, namespace)
+	}
+	sort.Strings(readiness.Namespaces)
+
+	readiness.Ready = readiness.Deployments.Available == readiness.Deployments.Total &&
+		readiness.StatefulSets.Available == readiness.StatefulSets.Total
+	return readiness
+}
+
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+
+	return *replicas
+}
+
+func recordReadiness(ctx context.Context, request *http.Request, namespace, name string, readiness *RestoreReadiness) error {
+	value, err := json.Marshal(readiness)
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{ReadinessAnnotation: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return err
+	}
+
+	_, err = dynamicClient.Resource(velero.RestoreGVR).Namespace(namespace).
+		Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("Failed to record restore readiness: %w", err)
+	}
+
+	return nil
+}
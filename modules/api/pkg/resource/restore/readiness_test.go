@@ -0,0 +1,73 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestToRestoreReadiness(t *testing.T) {
+	now := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	cases := []struct {
+		deployments  []apps.Deployment
+		statefulSets []apps.StatefulSet
+		expected     *RestoreReadiness
+	}{
+		{
+			nil, nil,
+			&RestoreReadiness{CheckedAt: metav1.NewTime(now), Namespaces: []string{}, Ready: true},
+		},
+		{
+			[]apps.Deployment{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+					Spec:       apps.DeploymentSpec{Replicas: lo.ToPtr[int32](2)},
+					Status:     apps.DeploymentStatus{AvailableReplicas: 2},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop"},
+					Status:     apps.DeploymentStatus{AvailableReplicas: 0},
+				},
+			},
+			[]apps.StatefulSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "data"},
+					Spec:       apps.StatefulSetSpec{Replicas: lo.ToPtr[int32](1)},
+					Status:     apps.StatefulSetStatus{ReadyReplicas: 1},
+				},
+			},
+			&RestoreReadiness{
+				CheckedAt:    metav1.NewTime(now),
+				Namespaces:   []string{"data", "shop"},
+				Deployments:  WorkloadReadiness{Total: 2, Available: 1},
+				StatefulSets: WorkloadReadiness{Total: 1, Available: 1},
+				Ready:        false,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toRestoreReadiness(c.deployments, c.statefulSets, now)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toRestoreReadiness() == \n%#v\nexpected \n%#v\n", actual, c.expected)
+		}
+	}
+}
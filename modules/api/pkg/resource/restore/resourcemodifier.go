@@ -0,0 +1,122 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+// resourceModifierRulesKey is the top level key of the resource modifier rules Velero reads from a ConfigMap.
+const resourceModifierRulesKey = "resourceModifierRules"
+
+// ResourceModifierList contains ConfigMaps that can be referenced as resource modifiers of a restore.
+type ResourceModifierList struct {
+	ListMeta types.ListMeta     `json:"listMeta"`
+	Items    []ResourceModifier `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ResourceModifier is a ConfigMap holding JSON patches Velero applies to resources it restores.
+type ResourceModifier struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	// Key of the ConfigMap data the rules are stored under.
+	Key string `json:"key"`
+}
+
+// GetResourceModifierList returns ConfigMaps in the namespace that hold resource modifier rules. Restores only
+// reference ConfigMaps from their own namespace, which is the one Velero is installed in.
+func GetResourceModifierList(request *http.Request, namespace string) (*ResourceModifierList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	configMaps, err := k8sClient.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toResourceModifierList(configMaps.Items), nil
+}
+
+func toResourceModifierList(configMaps []v1.ConfigMap) *ResourceModifierList {
+	result := &ResourceModifierList{Items: make([]ResourceModifier, 0), Errors: make([]error, 0)}
+	for _, configMap := range configMaps {
+		key, ok := getResourceModifierKey(&configMap)
+		if !ok {
+			continue
+		}
+
+		result.Items = append(result.Items, ResourceModifier{
+			ObjectMeta: types.NewObjectMeta(configMap.ObjectMeta),
+			TypeMeta:   types.NewTypeMeta(types.ResourceKindConfigMap),
+			Key:        key,
+		})
+	}
+	result.ListMeta = types.ListMeta{TotalItems: len(result.Items)}
+
+	return result
+}
+
+// getResourceModifierKey returns the key of the resource modifier rules. Velero only reads ConfigMaps with a single
+// data entry.
+func getResourceModifierKey(configMap *v1.ConfigMap) (string, bool) {
+	if len(configMap.Data) != 1 {
+		return "", false
+	}
+
+	for key, value := range configMap.Data {
+		return key, strings.Contains(value, resourceModifierRulesKey)
+	}
+
+	return "", false
+}
+
+// validateResourceModifier rejects references to ConfigMaps Velero would fail the restore for.
+func validateResourceModifier(ctx context.Context, k8sClient kubernetes.Interface, namespace, name string) error {
+	configMap, err := k8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return errors.NewBadRequest(fmt.Sprintf("resource modifier ConfigMap %s not found in namespace %s", name, namespace))
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, ok := getResourceModifierKey(configMap); !ok {
+		return errors.NewBadRequest(fmt.Sprintf("ConfigMap %s does not hold resource modifier rules in a single data entry", name))
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/dashboard/types"
+)
+
+const modifierRules = `version: v1
+resourceModifierRules:
+- conditions:
+    groupResource: deployments.apps
+  patches:
+  - operation: replace
+    path: "/spec/replicas"
+    value: "1"
+`
+
+func TestToResourceModifierList(t *testing.T) {
+	configMaps := []v1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "scale-down", Namespace: "velero"}, Data: map[string]string{"rules.yaml": modifierRules}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "plugin-config", Namespace: "velero"}, Data: map[string]string{"image": "busybox"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "two-entries", Namespace: "velero"}, Data: map[string]string{"a.yaml": modifierRules, "b.yaml": modifierRules}},
+	}
+	expected := &ResourceModifierList{
+		ListMeta: types.ListMeta{TotalItems: 1},
+		Items: []ResourceModifier{{
+			ObjectMeta: types.ObjectMeta{Name: "scale-down", Namespace: "velero"},
+			TypeMeta:   types.TypeMeta{Kind: types.ResourceKindConfigMap},
+			Key:        "rules.yaml",
+		}},
+		Errors: []error{},
+	}
+
+	actual := toResourceModifierList(configMaps)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toResourceModifierList() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestValidateResourceModifier(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "scale-down", Namespace: "velero"}, Data: map[string]string{"rules.yaml": modifierRules}},
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "plugin-config", Namespace: "velero"}, Data: map[string]string{"image": "busybox"}},
+	)
+	cases := []struct {
+		namespace, name string
+		isError         bool
+	}{
+		{"velero", "scale-down", false},
+		{"velero", "plugin-config", true},
+		{"velero", "missing", true},
+		{"default", "scale-down", true},
+	}
+
+	for _, c := range cases {
+		err := validateResourceModifier(context.Background(), k8sClient, c.namespace, c.name)
+		if (err != nil) != c.isError {
+			t.Errorf("validateResourceModifier(%s, %s) == %v, expected error: %t", c.namespace, c.name, err, c.isError)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+)
+
+// GetScheduleBackups returns the backups created by the given Velero Schedule. Unless the query
+// asks for a different order, the newest backups come first.
+func GetScheduleBackups(request *http.Request, namespace *common.NamespaceQuery, name string, dsQuery *dataselect.DataSelectQuery) (*backup.BackupList, error) {
+	query := *dsQuery
+	if query.SortQuery == nil || len(query.SortQuery.SortByList) == 0 {
+		query.SortQuery = dataselect.NewSortQuery([]string{"d", dataselect.CreationTimestampProperty})
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{backup.ScheduleNameLabel: name})
+	return backup.GetBackupListWithSelector(request, namespace, selector.String(), &query)
+}
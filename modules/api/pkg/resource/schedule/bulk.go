@@ -0,0 +1,168 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// NamespacePlaceholder is replaced by the backed up namespace in the name of schedules created in bulk.
+const NamespacePlaceholder = "{namespace}"
+
+// BulkScheduleSpec creates one schedule per namespace from a shared template, e.g. to onboard many teams at once.
+type BulkScheduleSpec struct {
+	Namespaces []string `json:"namespaces"`
+
+	// Template of the schedules. NamespacePlaceholder in its name is replaced by the namespace each schedule backs up,
+	// the namespace is appended to names without it. Included namespaces of the template are ignored.
+	Template ScheduleSpec `json:"template"`
+
+	// StaggerMinutes delays each schedule by that many minutes more than the previous one, so that backups of all
+	// namespaces do not start at once. It requires a five field cron expression with a fixed minute.
+	StaggerMinutes int `json:"staggerMinutes,omitempty"`
+}
+
+// BulkScheduleResult contains the outcome of creating each of the schedules.
+type BulkScheduleResult struct {
+	Items []BulkScheduleItem `json:"items"`
+}
+
+// BulkScheduleItem is the outcome of creating the schedule of a single namespace. Error is set when it failed.
+type BulkScheduleItem struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	Created   *Schedule `json:"created,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// CreateSchedules creates a schedule for each namespace of the spec. Creating a schedule does not stop at errors of
+// previous ones, they are reported per item instead. In dry run each schedule is only validated by the API server.
+func CreateSchedules(request *http.Request, spec *BulkScheduleSpec, dryRun bool) (*BulkScheduleResult, error) {
+	spec.Template.Namespace = velero.NamespaceOrDefault(request, spec.Template.Namespace)
+
+	specs, err := toScheduleSpecs(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkScheduleResult{Items: make([]BulkScheduleItem, 0, len(specs))}
+	for _, scheduleSpec := range specs {
+		item := BulkScheduleItem{
+			Namespace: scheduleSpec.IncludedNamespaces[0],
+			Name:      scheduleSpec.Name,
+			Schedule:  scheduleSpec.Schedule,
+		}
+
+		created, err := CreateSchedule(request, scheduleSpec, dryRun)
+		if err != nil {
+			item.Error = err.Error()
+		} else {
+			item.Created = created
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
+
+// toScheduleSpecs validates the bulk spec and expands it into the spec of each schedule, so that nothing is created
+// when the template is invalid.
+func toScheduleSpecs(spec *BulkScheduleSpec) ([]*ScheduleSpec, error) {
+	if len(spec.Namespaces) == 0 {
+		return nil, errors.NewBadRequest("at least one namespace is required")
+	}
+
+	if spec.StaggerMinutes < 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("stagger minutes must not be negative, got %d", spec.StaggerMinutes))
+	}
+
+	if err := ValidateSchedule(spec.Template.Schedule); err != nil {
+		return nil, err
+	}
+
+	if err := spec.Template.Validate(); err != nil {
+		return nil, err
+	}
+
+	if spec.Template.SLO != nil {
+		if err := validateSLO(spec.Template.SLO); err != nil {
+			return nil, err
+		}
+	}
+
+	specs := make([]*ScheduleSpec, 0, len(spec.Namespaces))
+	for i, namespace := range spec.Namespaces {
+		cron, err := staggerSchedule(spec.Template.Schedule, i*spec.StaggerMinutes)
+		if err != nil {
+			return nil, err
+		}
+
+		scheduleSpec := spec.Template
+		scheduleSpec.Name = toScheduleName(spec.Template.Name, namespace)
+		scheduleSpec.Schedule = cron
+		scheduleSpec.IncludedNamespaces = []string{namespace}
+		specs = append(specs, &scheduleSpec)
+	}
+
+	return specs, nil
+}
+
+func toScheduleName(template, namespace string) string {
+	if template == "" {
+		return namespace
+	}
+
+	if strings.Contains(template, NamespacePlaceholder) {
+		return strings.ReplaceAll(template, NamespacePlaceholder, namespace)
+	}
+
+	return template + "-" + namespace
+}
+
+// staggerSchedule delays a five field cron expression by the given minutes. Delays carry over into a fixed hour and
+// wrap around within the day.
+func staggerSchedule(schedule string, minutes int) (string, error) {
+	if minutes == 0 {
+		return schedule, nil
+	}
+
+	invalid := errors.NewBadRequest(fmt.Sprintf("schedule %q can not be staggered, it requires a five field cron "+
+		"expression with a fixed minute", schedule))
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return "", invalid
+	}
+
+	minute, err := strconv.Atoi(fields[0])
+	if err != nil || minute < 0 || minute > 59 {
+		return "", invalid
+	}
+
+	total := minute + minutes
+	fields[0] = strconv.Itoa(total % 60)
+	if hour, err := strconv.Atoi(fields[1]); err == nil {
+		fields[1] = strconv.Itoa((hour + total/60) % 24)
+	}
+
+	return strings.Join(fields, " "), nil
+}
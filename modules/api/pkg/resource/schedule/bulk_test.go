@@ -0,0 +1,122 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+)
+
+func TestStaggerSchedule(t *testing.T) {
+	cases := []struct {
+		schedule string
+		minutes  int
+		expected string
+		isError  bool
+	}{
+		{"@daily", 0, "@daily", false},
+		{"30 2 * * *", 15, "45 2 * * *", false},
+		{"30 2 * * *", 45, "15 3 * * *", false},
+		{"50 23 * * 1-5", 20, "10 0 * * 1-5", false},
+		{"45 * * * *", 30, "15 * * * *", false},
+		{"*/15 * * * *", 5, "", true},
+		{"@daily", 5, "", true},
+		{"", 5, "", true},
+	}
+
+	for _, c := range cases {
+		actual, err := staggerSchedule(c.schedule, c.minutes)
+		if (err != nil) != c.isError {
+			t.Errorf("staggerSchedule(%q, %d) returned error %v, expected error: %t", c.schedule, c.minutes, err, c.isError)
+		}
+		if actual != c.expected {
+			t.Errorf("staggerSchedule(%q, %d) == %q, expected %q", c.schedule, c.minutes, actual, c.expected)
+		}
+	}
+}
+
+func TestToScheduleSpecs(t *testing.T) {
+	spec := &BulkScheduleSpec{
+		Namespaces: []string{"shop", "blog", "wiki"},
+		Template: ScheduleSpec{
+			Name:      "daily-{namespace}",
+			Namespace: "velero",
+			Schedule:  "0 1 * * *",
+			BackupTemplate: backup.BackupTemplate{
+				IncludedNamespaces: []string{"ignored"},
+				TTL:                "720h",
+			},
+		},
+		StaggerMinutes: 20,
+	}
+	expected := []*ScheduleSpec{
+		{Name: "daily-shop", Namespace: "velero", Schedule: "0 1 * * *",
+			BackupTemplate: backup.BackupTemplate{IncludedNamespaces: []string{"shop"}, TTL: "720h"}},
+		{Name: "daily-blog", Namespace: "velero", Schedule: "20 1 * * *",
+			BackupTemplate: backup.BackupTemplate{IncludedNamespaces: []string{"blog"}, TTL: "720h"}},
+		{Name: "daily-wiki", Namespace: "velero", Schedule: "40 1 * * *",
+			BackupTemplate: backup.BackupTemplate{IncludedNamespaces: []string{"wiki"}, TTL: "720h"}},
+	}
+
+	actual, err := toScheduleSpecs(spec)
+	if err != nil {
+		t.Fatalf("toScheduleSpecs() returned error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toScheduleSpecs() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestToScheduleSpecsInvalid(t *testing.T) {
+	cases := []struct {
+		info string
+		spec *BulkScheduleSpec
+	}{
+		{"no namespaces", &BulkScheduleSpec{Template: ScheduleSpec{Schedule: "0 1 * * *"}}},
+		{"negative stagger", &BulkScheduleSpec{Namespaces: []string{"shop"}, StaggerMinutes: -5}},
+		{"unstaggerable schedule", &BulkScheduleSpec{Namespaces: []string{"shop", "blog"},
+			Template: ScheduleSpec{Schedule: "@hourly"}, StaggerMinutes: 5}},
+		{"invalid template", &BulkScheduleSpec{Namespaces: []string{"shop"},
+			Template: ScheduleSpec{Schedule: "0 1 * * *", BackupTemplate: backup.BackupTemplate{CSISnapshotTimeout: "soon"}}}},
+		{"invalid SLO", &BulkScheduleSpec{Namespaces: []string{"shop"},
+			Template: ScheduleSpec{Schedule: "0 1 * * *", SLO: &ScheduleSLO{Target: 2}}}},
+		{"invalid schedule", &BulkScheduleSpec{Namespaces: []string{"shop"}, Template: ScheduleSpec{Schedule: "0 25 * * *"}}},
+	}
+
+	for _, c := range cases {
+		if _, err := toScheduleSpecs(c.spec); err == nil {
+			t.Errorf("%s: toScheduleSpecs() returned no error", c.info)
+		}
+	}
+}
+
+func TestToScheduleName(t *testing.T) {
+	cases := []struct {
+		template, namespace, expected string
+	}{
+		{"", "shop", "shop"},
+		{"daily", "shop", "daily-shop"},
+		{"{namespace}-daily", "shop", "shop-daily"},
+	}
+
+	for _, c := range cases {
+		if actual := toScheduleName(c.template, c.namespace); actual != c.expected {
+			t.Errorf("toScheduleName(%q, %q) == %q, expected %q", c.template, c.namespace, actual, c.expected)
+		}
+	}
+}
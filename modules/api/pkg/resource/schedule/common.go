@@ -0,0 +1,111 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apimachinery "k8s.io/apimachinery/pkg/types"
+
+	metricapi "k8s.io/dashboard/api/pkg/integration/metric/api"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+// suggestedPollSeconds returns how often a schedule in the phase is worth refreshing. Only new schedules are about
+// to change, as Velero still has to validate them.
+func suggestedPollSeconds(phase velero.SchedulePhase) int {
+	if phase.Status() == velero.StatusPending {
+		return common.ActivePollSeconds
+	}
+
+	return common.StablePollSeconds
+}
+
+// The code below allows to perform complex data section on []Schedule
+
+type ScheduleCell Schedule
+
+func (in ScheduleCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(in.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(in.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(in.ObjectMeta.Namespace)
+	case dataselect.LastBackupProperty:
+		// Schedules that never ran compare as the zero time.
+		if in.LastBackup == nil {
+			return dataselect.StdComparableTime(time.Time{})
+		}
+		return dataselect.StdComparableTime(in.LastBackup.Time)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+// GetResourceSelector returns the selector of the schedule metrics. Velero labels its metrics with the schedule name
+// only, so the namespace is part of the UID to tell apart schedules listed across namespaces.
+func (in ScheduleCell) GetResourceSelector() *metricapi.ResourceSelector {
+	return &metricapi.ResourceSelector{
+		Namespace:    in.ObjectMeta.Namespace,
+		ResourceType: types.ResourceKindVeleroSchedule,
+		ResourceName: in.ObjectMeta.Name,
+		UID:          scheduleUID(in.ObjectMeta.Namespace, in.ObjectMeta.Name),
+	}
+}
+
+// scheduleUID identifies the schedule in metric labels.
+func scheduleUID(namespace, name string) apimachinery.UID {
+	return apimachinery.UID(namespace + "/" + name)
+}
+
+func toCells(std []Schedule) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = ScheduleCell(std[i])
+	}
+	return cells
+}
+
+func fromCells(cells []dataselect.DataCell) []Schedule {
+	std := make([]Schedule, len(cells))
+	for i := range std {
+		std[i] = Schedule(cells[i].(ScheduleCell))
+	}
+	return std
+}
+
+// nestedTime reads an RFC3339 timestamp from the object, returning nil when it is not set.
+func nestedTime(obj map[string]interface{}, fields ...string) *metav1.Time {
+	value, found, err := unstructured.NestedString(obj, fields...)
+	if err != nil || !found {
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	result := metav1.NewTime(parsed)
+	return &result
+}
@@ -0,0 +1,323 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/storagelocation"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+)
+
+const (
+	// DefaultConflictTolerance is how close runs of two schedules have to be to overlap when the caller does not ask
+	// for a specific tolerance.
+	DefaultConflictTolerance = "15m"
+
+	// conflictHorizon is how far ahead runs of schedules are compared.
+	conflictHorizon = 7 * 24 * time.Hour
+	// maxConflictRuns bounds the runs computed per schedule, a run every minute over the horizon.
+	maxConflictRuns = 7 * 24 * 60
+
+	// defaultStorageLocationName is the storage location Velero uses when none is marked as default.
+	defaultStorageLocationName = "default"
+)
+
+// ScheduleConflictList lists pairs of schedules whose backups would run at the same time over the same namespaces
+// into the same storage location. Such backups compete for resources and are harder to reason about in retention.
+// The list is advisory, schedules are not changed.
+type ScheduleConflictList struct {
+	Tolerance string             `json:"tolerance"`
+	Conflicts []ScheduleConflict `json:"conflicts"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ScheduleConflict is a pair of schedules with overlapping runs within the next week.
+type ScheduleConflict struct {
+	Schedules []Schedule `json:"schedules"`
+
+	// Identical is true when both schedules run at exactly the same times, e.g. "@daily" and "0 0 * * *".
+	Identical bool `json:"identical"`
+
+	// OverlappingRuns counts runs of the first schedule within the tolerance of a run of the second one.
+	OverlappingRuns int          `json:"overlappingRuns"`
+	NextOverlap     *metav1.Time `json:"nextOverlap,omitempty"`
+
+	// Namespaces backed up by both schedules. They are the included namespaces as written when the namespaces of the
+	// cluster can not be listed, "*" standing for all namespaces.
+	Namespaces      []string `json:"namespaces"`
+	StorageLocation string   `json:"storageLocation"`
+}
+
+// conflictCandidate is an active schedule with a valid cron expression, with what is needed to compare it to others.
+type conflictCandidate struct {
+	schedule           Schedule
+	runs               []time.Time
+	includedNamespaces []string
+	excludedNamespaces []string
+	storageLocation    string
+}
+
+// GetScheduleConflicts compares schedules in namespaces matching the query pairwise and returns the ones whose runs
+// are within the tolerance, e.g. "15m", of each other. Paused schedules are left out, as they do not run.
+func GetScheduleConflicts(request *http.Request, namespace *common.NamespaceQuery, tolerance string) (*ScheduleConflictList, error) {
+	duration, err := time.ParseDuration(tolerance)
+	if err != nil || duration < 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid tolerance %q, expected a duration like 15m", tolerance))
+	}
+
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	items, nonCriticalErrors, err := getSchedules(ctx, dynamicClient, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, namespaceErrors, err := getNamespaceNames(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	nonCriticalErrors = append(nonCriticalErrors, namespaceErrors...)
+
+	defaults, defaultErrors, err := getDefaultStorageLocations(request, items)
+	if err != nil {
+		return nil, err
+	}
+	nonCriticalErrors = append(nonCriticalErrors, defaultErrors...)
+
+	return &ScheduleConflictList{
+		Tolerance: tolerance,
+		Conflicts: toScheduleConflicts(toConflictCandidates(items, defaults, time.Now()), namespaces, duration),
+		Errors:    nonCriticalErrors,
+	}, nil
+}
+
+// getNamespaceNames returns the names of the namespaces of the cluster, nil along a non-critical error when they can
+// not be listed.
+func getNamespaceNames(ctx context.Context, client kubernetes.Interface) ([]string, []error, error) {
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		nonCriticalErrors, criticalError := errors.ExtractErrors(err)
+		return nil, nonCriticalErrors, criticalError
+	}
+
+	result := make([]string, 0, len(namespaces.Items))
+	for _, item := range namespaces.Items {
+		result = append(result, item.Name)
+	}
+
+	return result, nil, nil
+}
+
+// getDefaultStorageLocations returns the default storage location of each namespace with schedules not setting one.
+// Namespaces whose storage locations can not be listed are left out.
+func getDefaultStorageLocations(request *http.Request, items []unstructured.Unstructured) (map[string]string, []error, error) {
+	result := make(map[string]string)
+	nonCriticalErrors := make([]error, 0)
+	for _, item := range items {
+		location, _, _ := unstructured.NestedString(item.Object, "spec", "template", "storageLocation")
+		if _, ok := result[item.GetNamespace()]; ok || location != "" {
+			continue
+		}
+
+		defaultLocation, err := storagelocation.GetDefaultStorageLocation(request, item.GetNamespace())
+		if err != nil {
+			errs, criticalError := errors.ExtractErrors(err)
+			if criticalError != nil {
+				return nil, nil, criticalError
+			}
+			nonCriticalErrors = append(nonCriticalErrors, errs...)
+			continue
+		}
+
+		result[item.GetNamespace()] = defaultLocation.Name
+		if defaultLocation.Name == "" {
+			result[item.GetNamespace()] = defaultStorageLocationName
+		}
+	}
+
+	return result, nonCriticalErrors, nil
+}
+
+// toConflictCandidates computes runs of active schedules over the horizon after now. Schedules not setting a storage
+// location use the default one of their namespace, when it is known.
+func toConflictCandidates(items []unstructured.Unstructured, defaults map[string]string, now time.Time) []conflictCandidate {
+	result := make([]conflictCandidate, 0, len(items))
+	for _, item := range items {
+		if paused, _, _ := unstructured.NestedBool(item.Object, "spec", "paused"); paused {
+			continue
+		}
+
+		schedule := toSchedule(item)
+		parsed, err := parseCron(schedule.Schedule)
+		if err != nil {
+			continue
+		}
+
+		candidate := conflictCandidate{schedule: schedule, runs: getRuns(parsed, now, now.Add(conflictHorizon))}
+		candidate.includedNamespaces, _, _ = unstructured.NestedStringSlice(item.Object, "spec", "template", "includedNamespaces")
+		candidate.excludedNamespaces, _, _ = unstructured.NestedStringSlice(item.Object, "spec", "template", "excludedNamespaces")
+		candidate.storageLocation, _, _ = unstructured.NestedString(item.Object, "spec", "template", "storageLocation")
+		if candidate.storageLocation == "" {
+			candidate.storageLocation = defaults[item.GetNamespace()]
+		}
+		result = append(result, candidate)
+	}
+
+	return result
+}
+
+// getRuns returns the runs of the schedule after the start until the end, at most maxConflictRuns of them.
+func getRuns(schedule *cronSchedule, start, end time.Time) []time.Time {
+	runs := make([]time.Time, 0)
+	for next := schedule.next(start); !next.IsZero() && next.Before(end) && len(runs) < maxConflictRuns; next = schedule.next(next) {
+		runs = append(runs, next)
+	}
+
+	return runs
+}
+
+// toScheduleConflicts compares the candidates pairwise. Schedules in different namespaces belong to different Velero
+// installations and are not compared.
+func toScheduleConflicts(candidates []conflictCandidate, namespaces []string, tolerance time.Duration) []ScheduleConflict {
+	result := make([]ScheduleConflict, 0)
+	for i := range candidates {
+		for j := i + 1; j < len(candidates); j++ {
+			first, second := &candidates[i], &candidates[j]
+			if first.schedule.ObjectMeta.Namespace != second.schedule.ObjectMeta.Namespace ||
+				first.storageLocation == "" || first.storageLocation != second.storageLocation {
+				continue
+			}
+
+			shared := getSharedNamespaces(first, second, namespaces)
+			if len(shared) == 0 {
+				continue
+			}
+
+			overlapping, nextOverlap := getOverlappingRuns(first.runs, second.runs, tolerance)
+			if overlapping == 0 {
+				continue
+			}
+
+			result = append(result, ScheduleConflict{
+				Schedules:       []Schedule{first.schedule, second.schedule},
+				Identical:       len(first.runs) > 0 && slices.EqualFunc(first.runs, second.runs, time.Time.Equal),
+				OverlappingRuns: overlapping,
+				NextOverlap:     nextOverlap,
+				Namespaces:      shared,
+				StorageLocation: first.storageLocation,
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].NextOverlap.Before(result[j].NextOverlap)
+	})
+
+	return result
+}
+
+// getOverlappingRuns counts the first runs that are within the tolerance of a second run, and returns the earliest
+// of them. Both runs have to be sorted.
+func getOverlappingRuns(first, second []time.Time, tolerance time.Duration) (int, *metav1.Time) {
+	count := 0
+	var nextOverlap *metav1.Time
+	j := 0
+	for _, run := range first {
+		for j < len(second) && second[j].Before(run.Add(-tolerance)) {
+			j++
+		}
+		if j == len(second) {
+			break
+		}
+		if second[j].After(run.Add(tolerance)) {
+			continue
+		}
+
+		count++
+		if nextOverlap == nil {
+			overlap := metav1.NewTime(run)
+			nextOverlap = &overlap
+		}
+	}
+
+	return count, nextOverlap
+}
+
+// getSharedNamespaces returns the namespaces both schedules back up. When the namespaces of the cluster are not known,
+// included namespaces are compared as written.
+func getSharedNamespaces(first, second *conflictCandidate, namespaces []string) []string {
+	if namespaces == nil {
+		switch {
+		case first.includesAll() && second.includesAll():
+			return []string{"*"}
+		case first.includesAll():
+			namespaces = second.includedNamespaces
+		default:
+			namespaces = first.includedNamespaces
+		}
+	}
+
+	result := make([]string, 0)
+	for _, namespace := range namespaces {
+		if first.covers(namespace) && second.covers(namespace) {
+			result = append(result, namespace)
+		}
+	}
+
+	return result
+}
+
+func (in *conflictCandidate) includesAll() bool {
+	return len(in.includedNamespaces) == 0 || slices.Contains(in.includedNamespaces, "*")
+}
+
+// covers tells whether the namespace is backed up by the schedule. Names may be glob patterns, as in Velero.
+func (in *conflictCandidate) covers(namespace string) bool {
+	matches := func(patterns []string) bool {
+		return slices.ContainsFunc(patterns, func(pattern string) bool {
+			matched, _ := path.Match(pattern, namespace)
+			return matched
+		})
+	}
+
+	return (in.includesAll() || matches(in.includedNamespaces)) && !matches(in.excludedNamespaces)
+}
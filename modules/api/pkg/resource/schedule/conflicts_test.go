@@ -0,0 +1,147 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newConflictSchedule(name, cron string, includedNamespaces []string, storageLocation string, paused bool) unstructured.Unstructured {
+	template := map[string]interface{}{"storageLocation": storageLocation}
+	if includedNamespaces != nil {
+		namespaces := make([]interface{}, 0, len(includedNamespaces))
+		for _, namespace := range includedNamespaces {
+			namespaces = append(namespaces, namespace)
+		}
+		template["includedNamespaces"] = namespaces
+	}
+
+	item := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"schedule": cron, "paused": paused, "template": template},
+	}}
+	item.SetName(name)
+	item.SetNamespace("velero")
+	return item
+}
+
+func TestToScheduleConflicts(t *testing.T) {
+	type conflict struct {
+		first, second   string
+		identical       bool
+		overlappingRuns int
+		namespaces      []string
+	}
+
+	now := time.Date(2026, 10, 19, 0, 30, 0, 0, time.UTC)
+	items := []unstructured.Unstructured{
+		newConflictSchedule("all-daily", "@daily", nil, "", false),
+		newConflictSchedule("shop-midnight", "0 0 * * *", []string{"shop"}, "primary", false),
+		newConflictSchedule("shop-late", "10 0 * * *", []string{"shop"}, "primary", false),
+		newConflictSchedule("noon", "0 12 * * *", nil, "primary", false),
+		newConflictSchedule("secondary", "0 0 * * *", nil, "secondary", false),
+		newConflictSchedule("paused", "0 0 * * *", nil, "primary", true),
+		newConflictSchedule("billing", "0 0 * * *", []string{"billing"}, "primary", false),
+		newConflictSchedule("invalid", "0 0 * *", nil, "primary", false),
+	}
+	expected := []conflict{
+		{"all-daily", "shop-midnight", true, 7, []string{"shop"}},
+		{"all-daily", "shop-late", false, 7, []string{"shop"}},
+		{"all-daily", "billing", true, 7, []string{"billing"}},
+		{"shop-midnight", "shop-late", false, 7, []string{"shop"}},
+	}
+
+	candidates := toConflictCandidates(items, map[string]string{"velero": "primary"}, now)
+	conflicts := toScheduleConflicts(candidates, []string{"shop", "billing", "kube-system"}, 15*time.Minute)
+	actual := make([]conflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		if c.StorageLocation != "primary" {
+			t.Errorf("conflict of %s and %s has storage location %s, expected primary", c.Schedules[0].ObjectMeta.Name,
+				c.Schedules[1].ObjectMeta.Name, c.StorageLocation)
+		}
+		actual = append(actual, conflict{c.Schedules[0].ObjectMeta.Name, c.Schedules[1].ObjectMeta.Name, c.Identical,
+			c.OverlappingRuns, c.Namespaces})
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toScheduleConflicts() == \n%v\nexpected \n%v\n", actual, expected)
+	}
+}
+
+func TestGetOverlappingRuns(t *testing.T) {
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	runs := func(offsets ...time.Duration) []time.Time {
+		result := make([]time.Time, 0, len(offsets))
+		for _, offset := range offsets {
+			result = append(result, start.Add(offset))
+		}
+		return result
+	}
+
+	cases := []struct {
+		first, second []time.Time
+		tolerance     time.Duration
+		expected      int
+		expectedNext  time.Time
+	}{
+		{runs(0, time.Hour), runs(0, time.Hour), 0, 2, start},
+		{runs(0, time.Hour), runs(10 * time.Minute), 15 * time.Minute, 1, start},
+		{runs(0, time.Hour), runs(50 * time.Minute), 15 * time.Minute, 1, start.Add(time.Hour)},
+		{runs(0, time.Hour), runs(30 * time.Minute), 15 * time.Minute, 0, time.Time{}},
+		{runs(0), runs(), time.Hour, 0, time.Time{}},
+	}
+
+	for _, c := range cases {
+		actual, next := getOverlappingRuns(c.first, c.second, c.tolerance)
+		if actual != c.expected {
+			t.Errorf("getOverlappingRuns(%v, %v, %s) == %d, expected %d", c.first, c.second, c.tolerance, actual, c.expected)
+		}
+		if (next == nil) != c.expectedNext.IsZero() || (next != nil && !next.Time.Equal(c.expectedNext)) {
+			t.Errorf("getOverlappingRuns(%v, %v, %s) next overlap == %v, expected %v", c.first, c.second, c.tolerance,
+				next, c.expectedNext)
+		}
+	}
+}
+
+func TestGetSharedNamespaces(t *testing.T) {
+	cases := []struct {
+		first, second conflictCandidate
+		namespaces    []string
+		expected      []string
+	}{
+		{conflictCandidate{}, conflictCandidate{}, nil, []string{"*"}},
+		{conflictCandidate{}, conflictCandidate{includedNamespaces: []string{"shop", "billing"}}, nil,
+			[]string{"shop", "billing"}},
+		{conflictCandidate{excludedNamespaces: []string{"billing"}}, conflictCandidate{includedNamespaces: []string{"shop", "billing"}},
+			nil, []string{"shop"}},
+		{conflictCandidate{includedNamespaces: []string{"shop"}}, conflictCandidate{includedNamespaces: []string{"billing"}},
+			nil, []string{}},
+		{conflictCandidate{includedNamespaces: []string{"shop-*"}}, conflictCandidate{includedNamespaces: []string{"*"}},
+			[]string{"shop-eu", "shop-us", "billing"}, []string{"shop-eu", "shop-us"}},
+		{conflictCandidate{}, conflictCandidate{excludedNamespaces: []string{"kube-*"}},
+			[]string{"shop", "kube-system"}, []string{"shop"}},
+	}
+
+	for _, c := range cases {
+		actual := getSharedNamespaces(&c.first, &c.second, c.namespaces)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getSharedNamespaces(%v, %v, %v) == %v, expected %v", c.first, c.second, c.namespaces, actual,
+				c.expected)
+		}
+	}
+}
@@ -15,22 +15,42 @@
 package schedule
 
 import (
-	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
-	crdv1 "k8s.io/dashboard/api/pkg/resource/customresourcedefinition/v1"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
 	"k8s.io/dashboard/types"
 )
 
-// CreateSchedule creates a new Velero schedule
-func CreateSchedule(request *http.Request, spec *ScheduleSpec) (*Schedule, error) {
+// CreateSchedule creates a new Velero schedule. In dry run it is only validated by the API server, which returns it without
+// persisting it.
+func CreateSchedule(request *http.Request, spec *ScheduleSpec, dryRun bool) (*Schedule, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	spec.Namespace = velero.NamespaceOrDefault(request, spec.Namespace)
+
+	if err := ValidateSchedule(spec.Schedule); err != nil {
+		return nil, err
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := velero.CheckAccess(request, velero.VerbCreate, velero.ScheduleGVR, spec.Namespace, spec.Name); err != nil {
+		return nil, err
+	}
+
+	if err := spec.ValidateResourcePolicy(ctx, request, spec.Namespace); err != nil {
+		return nil, err
+	}
+
 	// Create unstructured object for the schedule
 	schedule := &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -42,111 +62,70 @@ func CreateSchedule(request *http.Request, spec *ScheduleSpec) (*Schedule, error
 			},
 			"spec": map[string]interface{}{
 				"schedule": spec.Schedule,
-				"template": map[string]interface{}{
-					"includedNamespaces": spec.IncludedNamespaces,
-					"storageLocation":    spec.StorageLocation,
-					"ttl":                spec.TTL,
-				},
+				"template": spec.ToUnstructured(),
 			},
 		},
 	}
 
-	// Add optional fields if provided
-	if len(spec.ExcludedNamespaces) > 0 {
-		schedule.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["excludedNamespaces"] = spec.ExcludedNamespaces
+	if spec.Paused {
+		schedule.Object["spec"].(map[string]interface{})["paused"] = true
 	}
-	if len(spec.IncludedResources) > 0 {
-		schedule.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["includedResources"] = spec.IncludedResources
-	}
-	if len(spec.ExcludedResources) > 0 {
-		schedule.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["excludedResources"] = spec.ExcludedResources
-	}
-	if spec.LabelSelector != nil {
-		schedule.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["labelSelector"] = spec.LabelSelector
-	}
-
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
-	if err != nil {
-		return nil, err
+	if spec.UseOwnerReferencesInBackup != nil {
+		schedule.Object["spec"].(map[string]interface{})["useOwnerReferencesInBackup"] = *spec.UseOwnerReferencesInBackup
 	}
-
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
-	if err != nil {
-		return nil, err
+	if spec.SkipImmediately != nil {
+		schedule.Object["spec"].(map[string]interface{})["skipImmediately"] = *spec.SkipImmediately
 	}
 
-	// Get the schedule CRD definition
-	customResourceDefinition, err := apiExtClient.ApiextensionsV1().
-		CustomResourceDefinitions().
-		Get(context.TODO(), "schedules.velero.io", metav1.GetOptions{})
-	if err != nil {
-		return nil, err
+	if spec.SLO != nil {
+		if err := validateSLO(spec.SLO); err != nil {
+			return nil, err
+		}
+		schedule.SetAnnotations(map[string]string{
+			SLOTargetAnnotation: strconv.FormatFloat(spec.SLO.Target, 'f', -1, 64),
+		})
 	}
 
-	// Create REST client for the schedule CRD
-	restClient, err := crdv1.NewRESTClient(config, customResourceDefinition)
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert our schedule object to JSON
-	scheduleJSON, err := json.Marshal(schedule.Object)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to marshal schedule: %s", err.Error())
-	}
-
-	// Create the schedule via REST client
-	result := restClient.Post().
-		NamespaceIfScoped(spec.Namespace, customResourceDefinition.Spec.Scope == apiextensionsv1.NamespaceScoped).
-		Resource(customResourceDefinition.Spec.Names.Plural).
-		Body(scheduleJSON).
-		Do(context.TODO())
-
-	if result.Error() != nil {
-		return nil, fmt.Errorf("Failed to create schedule: %s", result.Error().Error())
-	}
-
-	// Get the raw response
-	raw, err := result.Raw()
+	audit.SetCreatedBy(request, schedule)
+	created, err := dynamicClient.Resource(velero.ScheduleGVR).Namespace(spec.Namespace).
+		Create(ctx, schedule, velero.CreateOptions(dryRun))
 	if err != nil {
-		return nil, fmt.Errorf("Failed to get schedule response: %s", err.Error())
+		return nil, fmt.Errorf("Failed to create schedule: %w", err)
 	}
 
-	// Parse the response to extract basic info
-	var createdSchedule map[string]interface{}
-	if err := json.Unmarshal(raw, &createdSchedule); err != nil {
-		return nil, fmt.Errorf("Failed to parse schedule response: %s", err.Error())
-	}
-
-	// Extract metadata
-	metadata := createdSchedule["metadata"].(map[string]interface{})
-
 	// Convert to our Schedule struct
 	createdScheduleResult := &Schedule{
-		ObjectMeta: types.ObjectMeta{
-			Name:      metadata["name"].(string),
-			Namespace: metadata["namespace"].(string),
-		},
+		ObjectMeta: velero.NewObjectMeta(created.Object),
 		TypeMeta: types.TypeMeta{
 			Kind: "Schedule",
 		},
 	}
 
+	if err := audit.Record(request, audit.ActionCreated, velero.ScheduleGVR.GroupVersion().WithKind("Schedule"), createdScheduleResult.ObjectMeta, dryRun); err != nil {
+		createdScheduleResult.Errors = append(createdScheduleResult.Errors, err)
+	}
 	return createdScheduleResult, nil
 }
 
-// ScheduleSpec represents the specification for creating a schedule
+// ScheduleSpec represents the specification for creating a schedule. Backups created by the schedule are configured
+// the same way as backups created on demand.
 type ScheduleSpec struct {
-	Name               string                `json:"name"`
-	Namespace          string                `json:"namespace"`
-	Schedule           string                `json:"schedule"` // Cron schedule expression
-	IncludedNamespaces []string              `json:"includedNamespaces,omitempty"`
-	ExcludedNamespaces []string              `json:"excludedNamespaces,omitempty"`
-	IncludedResources  []string              `json:"includedResources,omitempty"`
-	ExcludedResources  []string              `json:"excludedResources,omitempty"`
-	LabelSelector      *metav1.LabelSelector `json:"labelSelector,omitempty"`
-	StorageLocation    string                `json:"storageLocation,omitempty"`
-	TTL                string                `json:"ttl,omitempty"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Schedule  string `json:"schedule"` // Cron schedule expression
+	backup.BackupTemplate
+
+	// Paused creates the schedule without running it until it is unpaused.
+	Paused bool `json:"paused,omitempty"`
+	// UseOwnerReferencesInBackup makes backups owned by the schedule, so that deleting it deletes them too.
+	UseOwnerReferencesInBackup *bool `json:"useOwnerReferencesInBackup,omitempty"`
+	// SkipImmediately skips the backup Velero would otherwise create right away when the schedule is due.
+	SkipImmediately *bool `json:"skipImmediately,omitempty"`
+
+	SLO *ScheduleSLO `json:"slo,omitempty"`
 }
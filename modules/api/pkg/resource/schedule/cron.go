@@ -0,0 +1,388 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/errors"
+)
+
+const (
+	// DefaultCronPreviewRuns is the number of next runs previewed when the caller does not ask for a specific number.
+	DefaultCronPreviewRuns = 5
+	// MaxCronPreviewRuns bounds the number of next runs previewed.
+	MaxCronPreviewRuns = 100
+
+	// cronSearchDays bounds the search for the next run, so that expressions matching rare or no dates, e.g. February
+	// 30th, do not search forever.
+	cronSearchDays = 5 * 366
+)
+
+// CronPreview describes when a schedule runs, so that users can check a cron expression before creating the schedule.
+type CronPreview struct {
+	Schedule    string `json:"schedule"`
+	Description string `json:"description"`
+	TimeZone    string `json:"timeZone"`
+
+	// NextRuns are empty when the expression matches no date in the next years.
+	NextRuns []metav1.Time `json:"nextRuns"`
+}
+
+// cronField is the set of values a cron field matches, as bits.
+type cronField struct {
+	bits uint64
+	// star is true for fields matching everything with "*" or "?", which changes how days of month and week combine.
+	star bool
+}
+
+func (in cronField) has(value int) bool {
+	return in.bits&(1<<uint(value)) != 0
+}
+
+func (in cronField) values() []int {
+	values := make([]int, 0, bits.OnesCount64(in.bits))
+	for value := 0; value < 64; value++ {
+		if in.has(value) {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// cronBounds are the allowed values of a cron field and the names they can be given by.
+type cronBounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = cronBounds{min: 0, max: 59}
+	hourBounds   = cronBounds{min: 0, max: 23}
+	domBounds    = cronBounds{min: 1, max: 31}
+	monthBounds  = cronBounds{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = cronBounds{min: 0, max: 6, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronDescriptors are the predefined schedules Velero accepts in place of the five fields.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSchedule is a parsed cron expression. Either every is set, or the fields are.
+type cronSchedule struct {
+	location                      *time.Location
+	every                         time.Duration
+	minute, hour, dom, month, dow cronField
+}
+
+// ValidateSchedule rejects cron expressions Velero would fail to parse.
+func ValidateSchedule(schedule string) error {
+	_, err := parseCron(schedule)
+	return err
+}
+
+// GetCronPreview describes the cron expression and computes its next runs after now.
+func GetCronPreview(schedule string, runs int) (*CronPreview, error) {
+	return getCronPreview(schedule, runs, time.Now())
+}
+
+func getCronPreview(schedule string, runs int, now time.Time) (*CronPreview, error) {
+	parsed, err := parseCron(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &CronPreview{
+		Schedule:    schedule,
+		Description: parsed.describe(),
+		TimeZone:    parsed.location.String(),
+		NextRuns:    make([]metav1.Time, 0, runs),
+	}
+
+	next := now
+	for len(preview.NextRuns) < runs {
+		next = parsed.next(next)
+		if next.IsZero() {
+			break
+		}
+		preview.NextRuns = append(preview.NextRuns, metav1.NewTime(next))
+	}
+
+	return preview, nil
+}
+
+// parseCron parses the standard five field cron expressions, descriptors like @daily and @every <duration>, with an
+// optional CRON_TZ= or TZ= time zone prefix, as Velero does. Times are in UTC unless a time zone is given.
+func parseCron(schedule string) (*cronSchedule, error) {
+	invalid := func(reason string) error {
+		return errors.NewBadRequest(fmt.Sprintf("invalid schedule %q: %s", schedule, reason))
+	}
+
+	spec := strings.TrimSpace(schedule)
+	if spec == "" {
+		return nil, invalid("schedule is required")
+	}
+
+	result := &cronSchedule{location: time.UTC}
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		zone, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(zone, "=")
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, invalid(fmt.Sprintf("unknown time zone %q", name))
+		}
+		result.location = location
+		spec = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(spec, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || every <= 0 {
+			return nil, invalid("@every requires a positive duration")
+		}
+		result.every = max(every.Truncate(time.Second), time.Second)
+		return result, nil
+	}
+
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, invalid(fmt.Sprintf("expected 5 fields, got %d", len(fields)))
+	}
+
+	var err error
+	names := []string{"minute", "hour", "day of month", "month", "day of week"}
+	for i, target := range []*cronField{&result.minute, &result.hour, &result.dom, &result.month, &result.dow} {
+		bounds := []cronBounds{minuteBounds, hourBounds, domBounds, monthBounds, dowBounds}[i]
+		if *target, err = parseCronField(fields[i], bounds); err != nil {
+			return nil, invalid(fmt.Sprintf("%s: %s", names[i], err.Error()))
+		}
+	}
+
+	return result, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps, e.g. "1-5", "*/15" or "mon,wed".
+func parseCronField(field string, bounds cronBounds) (cronField, error) {
+	result := cronField{}
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return cronField{}, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		start, end := bounds.min, bounds.max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			result.star = result.star || !hasStep
+		case strings.Contains(rangePart, "-"):
+			low, high, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseCronValue(low, bounds); err != nil {
+				return cronField{}, err
+			}
+			if end, err = parseCronValue(high, bounds); err != nil {
+				return cronField{}, err
+			}
+			if start > end {
+				return cronField{}, fmt.Errorf("range %q starts after it ends", rangePart)
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(rangePart, bounds); err != nil {
+				return cronField{}, err
+			}
+			// A single value with a step runs from the value to the end of the range, e.g. "5/15" in minutes.
+			if !hasStep {
+				end = start
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			result.bits |= 1 << uint(value)
+		}
+	}
+
+	return result, nil
+}
+
+func parseCronValue(value string, bounds cronBounds) (int, error) {
+	if number, ok := bounds.names[strings.ToLower(value)]; ok {
+		return number, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+
+	if number < bounds.min || number > bounds.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", number, bounds.min, bounds.max)
+	}
+
+	return number, nil
+}
+
+// next returns the first run after the given time, or zero time when there is none in the next years.
+func (in *cronSchedule) next(after time.Time) time.Time {
+	if in.every > 0 {
+		return after.Add(in.every).Truncate(time.Second)
+	}
+
+	start := after.In(in.location).Truncate(time.Minute).Add(time.Minute)
+	for day := 0; day < cronSearchDays; day++ {
+		date := time.Date(start.Year(), start.Month(), start.Day()+day, 0, 0, 0, 0, in.location)
+		if !in.month.has(int(date.Month())) || !in.matchesDay(date) {
+			continue
+		}
+
+		for _, hour := range in.hour.values() {
+			for _, minute := range in.minute.values() {
+				run := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, in.location)
+				// Times skipped by daylight saving time changes are normalized into another hour, they do not run.
+				if run.Hour() != hour || run.Before(start) {
+					continue
+				}
+				return run
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchesDay tells whether the schedule runs on the date. Restricted days of month and week run on days matching
+// either of them, as in cron.
+func (in *cronSchedule) matchesDay(date time.Time) bool {
+	domMatch := in.dom.has(date.Day())
+	dowMatch := in.dow.has(int(date.Weekday()))
+	if in.dom.star || in.dow.star {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// describe returns a human-readable description of the schedule, e.g. "daily at 02:00 UTC".
+func (in *cronSchedule) describe() string {
+	if in.every > 0 {
+		return fmt.Sprintf("every %s", in.every)
+	}
+
+	description := in.describeTime()
+	days := in.describeDays()
+	if days == "" && len(in.minute.values()) == 1 && len(in.hour.values()) == 1 {
+		description = "daily " + description
+	} else if days != "" {
+		description += " " + days
+	}
+
+	if !in.month.star {
+		description += " in " + joinNames(in.month.values(), func(month int) string { return time.Month(month).String() })
+	}
+
+	return description + " " + in.location.String()
+}
+
+func (in *cronSchedule) describeTime() string {
+	minutes, hours := in.minute.values(), in.hour.values()
+	switch {
+	case len(minutes) == 1 && len(hours) == 1:
+		return fmt.Sprintf("at %02d:%02d", hours[0], minutes[0])
+	case in.minute.star && in.hour.star:
+		return "every minute"
+	case in.hour.star:
+		if step, ok := getStep(minutes, minuteBounds); ok {
+			return fmt.Sprintf("every %d minutes", step)
+		}
+		if len(minutes) == 1 {
+			return fmt.Sprintf("every hour at minute %d", minutes[0])
+		}
+		return "every hour at minutes " + joinNames(minutes, strconv.Itoa)
+	case len(minutes) == 1:
+		if step, ok := getStep(hours, hourBounds); ok {
+			return fmt.Sprintf("every %d hours at minute %d", step, minutes[0])
+		}
+		return "at " + joinNames(hours, func(hour int) string { return fmt.Sprintf("%02d:%02d", hour, minutes[0]) })
+	default:
+		return fmt.Sprintf("at minutes %s past hours %s", joinNames(minutes, strconv.Itoa), joinNames(hours, strconv.Itoa))
+	}
+}
+
+func (in *cronSchedule) describeDays() string {
+	weekdays := joinNames(in.dow.values(), func(day int) string { return time.Weekday(day).String() })
+	days := "on day " + joinNames(in.dom.values(), strconv.Itoa) + " of the month"
+	switch {
+	case in.dom.star && in.dow.star:
+		return ""
+	case in.dom.star:
+		return "on " + weekdays
+	case in.dow.star:
+		return days
+	default:
+		return days + " or on " + weekdays
+	}
+}
+
+// getStep returns the step of values evenly spread over the whole range starting at its minimum, e.g. */15.
+func getStep(values []int, bounds cronBounds) (int, bool) {
+	if len(values) < 2 || values[0] != bounds.min {
+		return 0, false
+	}
+
+	step := values[1] - values[0]
+	for i := 1; i < len(values); i++ {
+		if values[i]-values[i-1] != step {
+			return 0, false
+		}
+	}
+
+	return step, values[len(values)-1]+step > bounds.max
+}
+
+func joinNames(values []int, name func(int) string) string {
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		names = append(names, name(value))
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	cases := []struct {
+		schedule string
+		isError  bool
+	}{
+		{"0 2 * * *", false},
+		{"*/15 * * * *", false},
+		{"30 1-5/2 1,15 jan-jun MON-FRI", false},
+		{"0 3 ? * sun", false},
+		{"@daily", false},
+		{"@every 6h", false},
+		{"CRON_TZ=Europe/Berlin 0 2 * * *", false},
+		{"", true},
+		{"0 2 * *", true},
+		{"60 2 * * *", true},
+		{"0 2 0 * *", true},
+		{"0 2 * * 7", true},
+		{"0 5-2 * * *", true},
+		{"*/0 * * * *", true},
+		{"0 2 * * funday", true},
+		{"@every soon", true},
+		{"TZ=Mars/Olympus 0 2 * * *", true},
+	}
+
+	for _, c := range cases {
+		err := ValidateSchedule(c.schedule)
+		if (err != nil) != c.isError {
+			t.Errorf("ValidateSchedule(%q) == %v, expected error: %t", c.schedule, err, c.isError)
+		}
+	}
+}
+
+func TestDescribeCron(t *testing.T) {
+	cases := []struct {
+		schedule string
+		expected string
+	}{
+		{"0 2 * * *", "daily at 02:00 UTC"},
+		{"@daily", "daily at 00:00 UTC"},
+		{"*/15 * * * *", "every 15 minutes UTC"},
+		{"30 * * * *", "every hour at minute 30 UTC"},
+		{"* * * * *", "every minute UTC"},
+		{"0 */6 * * *", "every 6 hours at minute 0 UTC"},
+		{"15 1,13 * * *", "at 01:15, 13:15 UTC"},
+		{"0 2 * * mon-fri", "at 02:00 on Monday, Tuesday, Wednesday, Thursday, Friday UTC"},
+		{"0 2 1 * *", "at 02:00 on day 1 of the month UTC"},
+		{"0 2 1 * sat", "at 02:00 on day 1 of the month or on Saturday UTC"},
+		{"0 0 1 1 *", "at 00:00 on day 1 of the month in January UTC"},
+		{"@every 90m", "every 1h30m0s"},
+		{"CRON_TZ=Europe/Berlin 0 2 * * *", "daily at 02:00 Europe/Berlin"},
+	}
+
+	for _, c := range cases {
+		parsed, err := parseCron(c.schedule)
+		if err != nil {
+			t.Fatalf("parseCron(%q) returned error: %s", c.schedule, err.Error())
+		}
+
+		if actual := parsed.describe(); actual != c.expected {
+			t.Errorf("describe(%q) == %q, expected %q", c.schedule, actual, c.expected)
+		}
+	}
+}
+
+func TestGetCronPreview(t *testing.T) {
+	// A Saturday.
+	now := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load location: %s", err.Error())
+	}
+
+	cases := []struct {
+		schedule string
+		runs     int
+		expected []time.Time
+	}{
+		{"0 2 * * *", 2, []time.Time{
+			time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC),
+			time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC),
+		}},
+		{"0 2 * * mon", 2, []time.Time{
+			time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC),
+			time.Date(2026, 10, 26, 2, 0, 0, 0, time.UTC),
+		}},
+		{"0 2 13 * fri", 2, []time.Time{
+			time.Date(2026, 10, 23, 2, 0, 0, 0, time.UTC),
+			time.Date(2026, 10, 30, 2, 0, 0, 0, time.UTC),
+		}},
+		{"0 20 * * *", 1, []time.Time{time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)}},
+		{"@every 12h", 2, []time.Time{
+			time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC),
+			time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC),
+		}},
+		// Clocks go back from 03:00 to 02:00 on 25 October in Berlin.
+		{"CRON_TZ=Europe/Berlin 30 2 25 10 *", 1, []time.Time{time.Date(2026, 10, 25, 2, 30, 0, 0, berlin)}},
+		{"0 0 30 2 *", 3, []time.Time{}},
+	}
+
+	for _, c := range cases {
+		preview, err := getCronPreview(c.schedule, c.runs, now)
+		if err != nil {
+			t.Fatalf("getCronPreview(%q) returned error: %s", c.schedule, err.Error())
+		}
+
+		actual := make([]time.Time, 0, len(preview.NextRuns))
+		for _, run := range preview.NextRuns {
+			actual = append(actual, run.Time)
+		}
+		if len(actual) != len(c.expected) {
+			t.Errorf("getCronPreview(%q) == %v, expected %v", c.schedule, actual, c.expected)
+			continue
+		}
+		for i := range actual {
+			if !actual[i].Equal(c.expected[i]) {
+				t.Errorf("getCronPreview(%q) == %v, expected %v", c.schedule, actual, c.expected)
+				break
+			}
+		}
+	}
+}
@@ -15,55 +15,45 @@
 package schedule
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 
-	crdv1 "k8s.io/dashboard/api/pkg/resource/customresourcedefinition/v1"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
 )
 
-// DeleteSchedule deletes a Velero schedule
-func DeleteSchedule(request *http.Request, namespace, name string) error {
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
-	if err != nil {
-		return err
-	}
+// DeleteSchedule deletes a Velero schedule. In dry run the deletion is only validated by the API server. Backups
+// created by the schedule are kept and returned as related objects.
+func DeleteSchedule(request *http.Request, namespace, name string, dryRun bool) (*velero.DeletionImpact, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
 
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
-	if err != nil {
-		return err
+	if err := velero.CheckAccess(request, velero.VerbDelete, velero.ScheduleGVR, namespace, name); err != nil {
+		return nil, err
 	}
 
-	// Get the schedule CRD definition
-	customResourceDefinition, err := apiExtClient.ApiextensionsV1().
-		CustomResourceDefinitions().
-		Get(context.TODO(), "schedules.velero.io", metav1.GetOptions{})
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Create REST client for the schedule CRD
-	restClient, err := crdv1.NewRESTClient(config, customResourceDefinition)
+	impact, err := velero.Delete(ctx, dynamicClient, velero.ScheduleGVR, "Schedule", namespace, name, dryRun)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("Failed to delete schedule: %w", err)
 	}
-
-	// Delete the schedule via REST client
-	result := restClient.Delete().
-		NamespaceIfScoped(namespace, customResourceDefinition.Spec.Scope == apiextensionsv1.NamespaceScoped).
-		Resource(customResourceDefinition.Spec.Names.Plural).
-		Name(name).
-		Do(context.TODO())
-
-	if result.Error() != nil {
-		return fmt.Errorf("Failed to delete schedule: %s", result.Error().Error())
+	if err := audit.Record(request, audit.ActionDeleted, velero.ScheduleGVR.GroupVersion().WithKind("Schedule"), impact.ObjectMeta, dryRun); err != nil {
+		impact.Errors = append(impact.Errors, err)
 	}
 
-	return nil
+	options := metav1.ListOptions{LabelSelector: labels.Set{backup.ScheduleNameLabel: name}.String()}
+	impact.AddRelated(ctx, dynamicClient, velero.BackupGVR, "Backup", options, func(unstructured.Unstructured) bool {
+		return true
+	})
+
+	return impact, nil
 }
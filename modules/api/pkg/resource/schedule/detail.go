@@ -19,14 +19,11 @@ import (
 	"encoding/json"
 	"net/http"
 
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/rest"
+	"k8s.io/client-go/dynamic"
 
 	"k8s.io/dashboard/api/pkg/resource/common"
-	crdv1 "k8s.io/dashboard/api/pkg/resource/customresourcedefinition/v1"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/velero"
 	dashboardtypes "k8s.io/dashboard/types"
 )
 
@@ -36,27 +33,26 @@ type ScheduleDetail struct {
 	TypeMeta        dashboardtypes.TypeMeta   `json:"typeMeta"`
 	Schedule        string                    `json:"schedule"`
 	LastBackupTime  string                    `json:"lastBackupTime,omitempty"`
-	Phase           string                    `json:"phase,omitempty"`
-	Status          string                    `json:"status,omitempty"`
+	Phase           velero.SchedulePhase      `json:"phase,omitempty"`
+	Status          velero.Status             `json:"status,omitempty"`
 	ValidationError string                    `json:"validationError,omitempty"`
+
+	// SuggestedPollSeconds tells clients how often the schedule is worth refreshing in its current phase
+	SuggestedPollSeconds int `json:"suggestedPollSeconds"`
 }
 
 // GetScheduleDetail returns detailed information about a specific Velero schedule
 func GetScheduleDetail(request *http.Request, namespace *common.NamespaceQuery, name string) (*ScheduleDetail, error) {
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
-	if err != nil {
-		return nil, err
-	}
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
 
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get raw schedule data
-	rawScheduleData, err := getRawScheduleData(apiExtClient, config, namespace, name)
+	rawScheduleData, err := getRawScheduleData(ctx, dynamicClient, namespace, name)
 	if err != nil {
 		return nil, err
 	}
@@ -71,31 +67,13 @@ func GetScheduleDetail(request *http.Request, namespace *common.NamespaceQuery,
 }
 
 // getRawScheduleData gets the raw JSON data for a specific Velero schedule
-func getRawScheduleData(apiExtClient apiextensionsclientset.Interface, config *rest.Config, namespace *common.NamespaceQuery, name string) ([]byte, error) {
-	// Get the schedule CRD definition
-	customResourceDefinition, err := apiExtClient.ApiextensionsV1().
-		CustomResourceDefinitions().
-		Get(context.TODO(), "schedules.velero.io", metav1.GetOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	// Create REST client for the schedule CRD
-	restClient, err := crdv1.NewRESTClient(config, customResourceDefinition)
-	if err != nil {
-		return nil, err
-	}
-
-	// Get the raw schedule data
-	raw, err := restClient.Get().
-		NamespaceIfScoped(namespace.ToRequestParam(), customResourceDefinition.Spec.Scope == apiextensionsv1.NamespaceScoped).
-		Resource(customResourceDefinition.Spec.Names.Plural).
-		Name(name).Do(context.TODO()).Raw()
+func getRawScheduleData(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery, name string) ([]byte, error) {
+	item, err := client.Resource(velero.ScheduleGVR).Namespace(namespace.ToRequestParam()).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	return raw, nil
+	return item.MarshalJSON()
 }
 
 // parseScheduleDetail parses raw JSON data into a ScheduleDetail struct
@@ -107,7 +85,7 @@ func parseScheduleDetail(rawData []byte) (*ScheduleDetail, error) {
 	}
 
 	// Extract metadata
-	metadata := extractMetadata(rawSchedule)
+	metadata := velero.NewObjectMeta(rawSchedule)
 
 	// Create schedule detail with basic info
 	detail := &ScheduleDetail{
@@ -127,8 +105,7 @@ func parseScheduleDetail(rawData []byte) (*ScheduleDetail, error) {
 	// Extract Velero-specific status information
 	if status, ok := rawSchedule["status"].(map[string]interface{}); ok {
 		if phase, ok := status["phase"].(string); ok {
-			detail.Phase = phase
-			detail.Status = phase
+			detail.Phase = velero.SchedulePhase(phase)
 		}
 		if lastBackupTime, ok := status["lastBackupTime"].(string); ok {
 			detail.LastBackupTime = lastBackupTime
@@ -140,21 +117,8 @@ func parseScheduleDetail(rawData []byte) (*ScheduleDetail, error) {
 		}
 	}
 
-	return detail, nil
-}
-
-// extractMetadata extracts standard Kubernetes metadata from raw JSON
-func extractMetadata(rawSchedule map[string]interface{}) dashboardtypes.ObjectMeta {
-	metadata := dashboardtypes.ObjectMeta{}
+	detail.Status = detail.Phase.Status()
+	detail.SuggestedPollSeconds = suggestedPollSeconds(detail.Phase)
 
-	if meta, ok := rawSchedule["metadata"].(map[string]interface{}); ok {
-		if name, ok := meta["name"].(string); ok {
-			metadata.Name = name
-		}
-		if namespace, ok := meta["namespace"].(string); ok {
-			metadata.Namespace = namespace
-		}
-	}
-
-	return metadata
+	return detail, nil
 }
@@ -0,0 +1,98 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"net/http"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+)
+
+// DefaultGroupedBackups is the number of backups nested under each schedule when the caller does not ask for a
+// specific number.
+const DefaultGroupedBackups = 5
+
+// GroupedBackupList contains schedules with their most recent backups and the backups no schedule owns, so that
+// the backup screen can be rendered from a single call.
+type GroupedBackupList struct {
+	Groups []ScheduleBackups `json:"groups"`
+
+	// AdHoc contains backups created manually or by schedules that no longer exist, newest first.
+	AdHoc []backup.Backup `json:"adHoc"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ScheduleBackups is a schedule together with its most recent backups, newest first.
+type ScheduleBackups struct {
+	Schedule Schedule        `json:"schedule"`
+	Backups  []backup.Backup `json:"backups"`
+
+	// TotalBackups counts all backups of the schedule, including those left out of Backups.
+	TotalBackups int `json:"totalBackups"`
+}
+
+// GetGroupedBackupList returns schedules in namespaces matching the query with at most limit of their most recent
+// backups each, followed by backups that do not belong to any of the schedules.
+func GetGroupedBackupList(request *http.Request, namespace *common.NamespaceQuery, limit int) (*GroupedBackupList, error) {
+	schedules, err := GetScheduleList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	newestFirst := dataselect.NewDataSelectQuery(dataselect.NoPagination,
+		dataselect.NewSortQuery([]string{"d", dataselect.CreationTimestampProperty}), dataselect.NoFilter, dataselect.NoMetrics)
+	backups, err := backup.GetBackupList(request, namespace, newestFirst)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toGroupedBackupList(schedules.Items, backups.Items, limit)
+	result.Errors = append(schedules.Errors, backups.Errors...)
+	return result, nil
+}
+
+// toGroupedBackupList nests backups under the schedule named by their schedule label in the same namespace. The
+// order of backups is kept.
+func toGroupedBackupList(schedules []Schedule, backups []backup.Backup, limit int) *GroupedBackupList {
+	type key struct{ namespace, name string }
+
+	groups := make([]ScheduleBackups, len(schedules))
+	groupIndex := make(map[key]int, len(schedules))
+	for i, item := range schedules {
+		groups[i] = ScheduleBackups{Schedule: item, Backups: make([]backup.Backup, 0)}
+		groupIndex[key{item.ObjectMeta.Namespace, item.ObjectMeta.Name}] = i
+	}
+
+	adHoc := make([]backup.Backup, 0)
+	for _, item := range backups {
+		i, ok := groupIndex[key{item.ObjectMeta.Namespace, item.ObjectMeta.Labels[backup.ScheduleNameLabel]}]
+		if !ok {
+			adHoc = append(adHoc, item)
+			continue
+		}
+
+		group := &groups[i]
+		group.TotalBackups++
+		if len(group.Backups) < limit {
+			group.Backups = append(group.Backups, item)
+		}
+	}
+
+	return &GroupedBackupList{Groups: groups, AdHoc: adHoc}
+}
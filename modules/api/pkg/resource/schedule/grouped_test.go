@@ -0,0 +1,73 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/types"
+)
+
+func newScheduledBackup(namespace, name, scheduleName string) backup.Backup {
+	item := backup.Backup{ObjectMeta: types.ObjectMeta{Namespace: namespace, Name: name}}
+	if scheduleName != "" {
+		item.ObjectMeta.Labels = map[string]string{backup.ScheduleNameLabel: scheduleName}
+	}
+
+	return item
+}
+
+func TestToGroupedBackupList(t *testing.T) {
+	daily := Schedule{ObjectMeta: types.ObjectMeta{Namespace: "velero", Name: "daily"}}
+	weekly := Schedule{ObjectMeta: types.ObjectMeta{Namespace: "velero", Name: "weekly"}}
+	backups := []backup.Backup{
+		newScheduledBackup("velero", "daily-3", "daily"),
+		newScheduledBackup("velero", "manual", ""),
+		newScheduledBackup("velero", "daily-2", "daily"),
+		newScheduledBackup("velero", "hourly-1", "hourly"),
+		newScheduledBackup("other", "daily-other", "daily"),
+		newScheduledBackup("velero", "daily-1", "daily"),
+	}
+
+	expected := &GroupedBackupList{
+		Groups: []ScheduleBackups{
+			{
+				Schedule: daily,
+				Backups: []backup.Backup{
+					newScheduledBackup("velero", "daily-3", "daily"),
+					newScheduledBackup("velero", "daily-2", "daily"),
+				},
+				TotalBackups: 3,
+			},
+			{
+				Schedule:     weekly,
+				Backups:      []backup.Backup{},
+				TotalBackups: 0,
+			},
+		},
+		AdHoc: []backup.Backup{
+			newScheduledBackup("velero", "manual", ""),
+			newScheduledBackup("velero", "hourly-1", "hourly"),
+			newScheduledBackup("other", "daily-other", "daily"),
+		},
+	}
+
+	actual := toGroupedBackupList([]Schedule{daily, weekly}, backups, 2)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toGroupedBackupList() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
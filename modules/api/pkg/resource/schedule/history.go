@@ -0,0 +1,168 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	dashboardtypes "k8s.io/dashboard/types"
+)
+
+const (
+	// DefaultRunHistory is the number of runs returned when the caller does not ask for a specific number.
+	DefaultRunHistory = 20
+	// MaxRunHistory caps the number of runs returned, the sizes of all of them are looked up.
+	MaxRunHistory = 100
+)
+
+// ScheduleRunHistory contains the most recent runs of a schedule, newest first, and the outcome of its runs so far.
+type ScheduleRunHistory struct {
+	ObjectMeta dashboardtypes.ObjectMeta `json:"objectMeta"`
+	TypeMeta   dashboardtypes.TypeMeta   `json:"typeMeta"`
+
+	Runs []ScheduleRunHistoryEntry `json:"runs"`
+
+	// TotalRuns counts all backups of the schedule, including those left out of Runs.
+	TotalRuns int `json:"totalRuns"`
+
+	// CurrentFailureStreak counts failed runs since the latest successful one. Runs that have not finished yet are
+	// not counted.
+	CurrentFailureStreak int `json:"currentFailureStreak"`
+
+	// LastSuccess is the completion time of the latest successful run, nil when no run succeeded.
+	LastSuccess *metav1.Time `json:"lastSuccess,omitempty"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ScheduleRunHistoryEntry is a single run of a schedule, i.e. a backup it created.
+type ScheduleRunHistoryEntry struct {
+	Name           string             `json:"name"`
+	Phase          velero.BackupPhase `json:"phase,omitempty"`
+	Status         velero.Status      `json:"status"`
+	StartTime      *metav1.Time       `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time       `json:"completionTime,omitempty"`
+
+	// Duration in seconds, nil until the run completes.
+	Duration *float64 `json:"duration,omitempty"`
+
+	// Size is nil when the backup has no volume data.
+	Size *backup.BackupSize `json:"size,omitempty"`
+}
+
+// GetScheduleRunHistory returns at most limit of the most recent runs of a schedule with their duration and size,
+// together with the current failure streak and the latest successful run.
+func GetScheduleRunHistory(request *http.Request, namespace *common.NamespaceQuery, name string, limit int) (*ScheduleRunHistory, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	rawScheduleData, err := getRawScheduleData(ctx, dynamicClient, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rawSchedule := &unstructured.Unstructured{}
+	if err := rawSchedule.UnmarshalJSON(rawScheduleData); err != nil {
+		return nil, err
+	}
+
+	backups, err := GetScheduleBackups(request, namespace, name, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	nonCriticalErrors := backups.Errors
+	recent := backups.Items[:min(limit, len(backups.Items))]
+	if len(recent) > 0 {
+		sizeErrors, err := backup.AddBackupSizes(request, rawSchedule.GetNamespace(), recent)
+		if err != nil {
+			return nil, err
+		}
+		nonCriticalErrors = append(nonCriticalErrors, sizeErrors...)
+	}
+
+	result := toScheduleRunHistory(backups.Items, limit)
+	result.ObjectMeta = velero.NewObjectMeta(rawSchedule.Object)
+	result.TypeMeta = dashboardtypes.TypeMeta{Kind: "Schedule"}
+	result.Errors = nonCriticalErrors
+	return result, nil
+}
+
+// toScheduleRunHistory converts backups of a schedule ordered newest first.
+func toScheduleRunHistory(backups []backup.Backup, limit int) *ScheduleRunHistory {
+	result := &ScheduleRunHistory{
+		Runs:      make([]ScheduleRunHistoryEntry, 0, min(limit, len(backups))),
+		TotalRuns: len(backups),
+	}
+
+	for i, item := range backups {
+		if i < limit {
+			result.Runs = append(result.Runs, toScheduleRunHistoryEntry(item))
+		}
+
+		if result.LastSuccess != nil {
+			continue
+		}
+
+		run, ok := toScheduleRun(item)
+		if !ok {
+			continue
+		}
+
+		if !run.succeeded {
+			result.CurrentFailureStreak++
+			continue
+		}
+
+		result.LastSuccess = item.CompletionTime
+		if result.LastSuccess == nil {
+			started := metav1.NewTime(run.started)
+			result.LastSuccess = &started
+		}
+	}
+
+	return result
+}
+
+func toScheduleRunHistoryEntry(item backup.Backup) ScheduleRunHistoryEntry {
+	entry := ScheduleRunHistoryEntry{
+		Name:           item.ObjectMeta.Name,
+		Phase:          item.Phase,
+		Status:         item.Phase.Status(),
+		StartTime:      item.StartTime,
+		CompletionTime: item.CompletionTime,
+		Size:           item.Size,
+	}
+
+	if item.StartTime != nil && item.CompletionTime != nil {
+		duration := item.CompletionTime.Sub(item.StartTime.Time).Seconds()
+		entry.Duration = &duration
+	}
+
+	return entry
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+func TestToScheduleRunHistory(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	seconds := func(duration time.Duration) *float64 {
+		result := duration.Seconds()
+		return &result
+	}
+
+	size := &backup.BackupSize{TotalBytes: 1024, BytesDone: 1024, Volumes: 1}
+	backups := []backup.Backup{
+		newStatsRun("nightly", velero.BackupPhaseInProgress, now, 0),
+		newStatsRun("nightly", velero.BackupPhaseFailed, now.Add(-day), time.Minute),
+		newStatsRun("nightly", velero.BackupPhasePartiallyFailed, now.Add(-2*day), 20*time.Minute),
+		newStatsRun("nightly", velero.BackupPhaseCompleted, now.Add(-3*day), 30*time.Minute),
+		newStatsRun("nightly", velero.BackupPhaseFailed, now.Add(-4*day), time.Minute),
+	}
+	backups[2].Size = size
+	lastSuccess := metav1.NewTime(now.Add(-3*day + 30*time.Minute))
+
+	cases := []struct {
+		info     string
+		backups  []backup.Backup
+		limit    int
+		expected *ScheduleRunHistory
+	}{
+		{
+			"streak and last success beyond the limit",
+			backups,
+			3,
+			&ScheduleRunHistory{
+				Runs: []ScheduleRunHistoryEntry{
+					{Name: backups[0].ObjectMeta.Name, Phase: velero.BackupPhaseInProgress, Status: velero.StatusRunning,
+						StartTime: backups[0].StartTime},
+					{Name: backups[1].ObjectMeta.Name, Phase: velero.BackupPhaseFailed, Status: velero.StatusFailed,
+						StartTime: backups[1].StartTime, CompletionTime: backups[1].CompletionTime, Duration: seconds(time.Minute)},
+					{Name: backups[2].ObjectMeta.Name, Phase: velero.BackupPhasePartiallyFailed, Status: velero.StatusPartiallyFailed,
+						StartTime: backups[2].StartTime, CompletionTime: backups[2].CompletionTime,
+						Duration: seconds(20 * time.Minute), Size: size},
+				},
+				TotalRuns:            5,
+				CurrentFailureStreak: 2,
+				LastSuccess:          &lastSuccess,
+			},
+		},
+		{
+			"no successful run",
+			backups[:3],
+			1,
+			&ScheduleRunHistory{
+				Runs: []ScheduleRunHistoryEntry{
+					{Name: backups[0].ObjectMeta.Name, Phase: velero.BackupPhaseInProgress, Status: velero.StatusRunning,
+						StartTime: backups[0].StartTime},
+				},
+				TotalRuns:            3,
+				CurrentFailureStreak: 2,
+			},
+		},
+		{
+			"no runs",
+			nil,
+			DefaultRunHistory,
+			&ScheduleRunHistory{Runs: []ScheduleRunHistoryEntry{}},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toScheduleRunHistory(c.backups, c.limit)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: toScheduleRunHistory() == \n%#v\nexpected \n%#v\n", c.info, actual, c.expected)
+		}
+	}
+}
@@ -15,65 +15,157 @@
 package schedule
 
 import (
+	"context"
 	"net/http"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/dashboard/api/pkg/args"
+	metricapi "k8s.io/dashboard/api/pkg/integration/metric/api"
 	"k8s.io/dashboard/api/pkg/resource/common"
-	"k8s.io/dashboard/api/pkg/resource/customresourcedefinition"
 	"k8s.io/dashboard/api/pkg/resource/dataselect"
-	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/api/pkg/resource/subscription"
+	"k8s.io/dashboard/api/pkg/resource/velero"
 	"k8s.io/dashboard/types"
 )
 
 // ScheduleList contains a list of Schedule resources in the cluster.
 type ScheduleList struct {
 	ListMeta types.ListMeta `json:"listMeta"`
-	Items    []Schedule     `json:"items"`
+
+	// Basic information about resources status on the list.
+	Status velero.ListStatus `json:"status"`
+	Items  []Schedule        `json:"items"`
+
+	// Velero backup metrics summed over the listed schedules.
+	CumulativeMetrics []metricapi.Metric `json:"cumulativeMetrics"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
 }
 
 // Schedule represents a Velero schedule resource
 type Schedule struct {
 	ObjectMeta types.ObjectMeta `json:"objectMeta"`
 	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	Schedule   string               `json:"schedule,omitempty"`
+	Phase      velero.SchedulePhase `json:"phase,omitempty"`
+	LastBackup *metav1.Time         `json:"lastBackup,omitempty"`
+
+	// Velero backup metrics of this schedule, collected by the metrics scraper.
+	Metrics []metricapi.Metric `json:"metrics,omitempty"`
+
+	// Errors is only set by CreateSchedule, when the creation could not be recorded in the audit log.
+	Errors []error `json:"errors,omitempty"`
 }
 
 // GetScheduleList returns a list of all Schedule resources in the cluster.
 func GetScheduleList(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*ScheduleList, error) {
-	// Get API extensions client for CRD operations
-	apiExtClient, err := client.APIExtensionsClient(request)
+	return GetScheduleListWithMetrics(request, nil, namespace, dsQuery)
+}
+
+// GetScheduleListWithMetrics returns a list of all Schedule resources in the cluster together with the Velero backup
+// metrics requested by the metric query. Metrics are skipped when the metric client is nil.
+func GetScheduleListWithMetrics(request *http.Request, metricClient metricapi.MetricClient, namespace *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ScheduleList, error) {
+	if metricClient != nil {
+		// Metrics change without the schedules changing, so the list can not be told unchanged by their versions.
+		velero.Invalidate(request)
+	}
+
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get REST config for custom resource operations
-	config, err := client.Config(request)
+	items, nonCriticalErrors, err := getSchedules(ctx, dynamicClient, namespace, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	// Use dashboard's CRD framework to get Velero schedule objects
-	crdList, err := customresourcedefinition.GetCustomResourceObjectList(
-		apiExtClient, config, namespace, dsQuery, "schedules.velero.io")
+	return toScheduleList(items, nonCriticalErrors, dsQuery, metricClient), nil
+}
+
+// getSchedules lists Velero schedules matching the list options. Schedules beyond the configured limit and namespaces
+// the user may not read are left out and reported as non-critical errors.
+func getSchedules(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery, options metav1.ListOptions) ([]unstructured.Unstructured, []error, error) {
+	return velero.ListInNamespaces(ctx, client, velero.ScheduleGVR, namespace, options,
+		args.VeleroMaxListItems())
+}
+
+func toScheduleList(items []unstructured.Unstructured, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery,
+	metricClient metricapi.MetricClient) *ScheduleList {
+	schedules := make([]Schedule, 0, len(items))
+	for _, item := range items {
+		schedules = append(schedules, toSchedule(item))
+	}
+
+	scheduleCells, cumulativeMetricsPromises, filteredTotal := dataselect.
+		GenericDataSelectWithFilterAndMetrics(toCells(schedules), dsQuery, metricapi.NoResourceCache, metricClient)
+	selected := fromCells(scheduleCells)
+
+	metrics, err := getMetricsPerSchedule(selected, metricClient, dsQuery)
 	if err != nil {
-		return nil, err
+		klog.ErrorS(err, "skipping metrics")
+	}
+	for i := range selected {
+		selected[i].Metrics = metrics[scheduleUID(selected[i].ObjectMeta.Namespace, selected[i].ObjectMeta.Name)]
 	}
 
-	// Convert CRD items to schedule items
-	var items []Schedule
-	for _, item := range crdList.Items {
-		schedule := Schedule{
-			ObjectMeta: types.ObjectMeta{
-				Name:      item.ObjectMeta.Name,
-				Namespace: item.ObjectMeta.Namespace,
-			},
-			TypeMeta: types.TypeMeta{
-				Kind: "Schedule",
-			},
-		}
-		items = append(items, schedule)
+	cumulativeMetrics, err := cumulativeMetricsPromises.GetMetrics()
+	if err != nil {
+		klog.ErrorS(err, "skipping metrics")
+		cumulativeMetrics = make([]metricapi.Metric, 0)
 	}
 
 	return &ScheduleList{
-		ListMeta: types.ListMeta{TotalItems: len(items)},
-		Items:    items,
-	}, nil
+		ListMeta:          types.ListMeta{TotalItems: filteredTotal},
+		Status:            getScheduleListStatus(schedules),
+		Items:             selected,
+		CumulativeMetrics: cumulativeMetrics,
+		Errors:            nonCriticalErrors,
+	}
+}
+
+func getScheduleListStatus(schedules []Schedule) velero.ListStatus {
+	status := velero.ListStatus{}
+	for _, schedule := range schedules {
+		status.Add(schedule.Phase.Status())
+	}
+
+	return status
+}
+
+func toSchedule(item unstructured.Unstructured) Schedule {
+	cron, _, _ := unstructured.NestedString(item.Object, "spec", "schedule")
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	return Schedule{
+		ObjectMeta: types.ObjectMeta{
+			Name:              item.GetName(),
+			Namespace:         item.GetNamespace(),
+			Labels:            item.GetLabels(),
+			CreationTimestamp: item.GetCreationTimestamp(),
+		},
+		TypeMeta: types.TypeMeta{
+			Kind: "Schedule",
+		},
+		Schedule:   cron,
+		Phase:      velero.SchedulePhase(phase),
+		LastBackup: nestedTime(item.Object, "status", "lastBackup"),
+	}
+}
+
+// SubscribeScheduleList subscribes to schedules being added, modified or deleted in namespaces matching the query.
+func SubscribeScheduleList(request *http.Request, namespace *common.NamespaceQuery) (*subscription.Subscription, error) {
+	return subscription.Subscribe(request, velero.ScheduleGVR, namespace, func(item *unstructured.Unstructured) interface{} {
+		return toSchedule(*item)
+	})
 }
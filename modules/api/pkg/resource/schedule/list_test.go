@@ -0,0 +1,131 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	metricapi "k8s.io/dashboard/api/pkg/integration/metric/api"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+func newRawSchedule(name string, lastBackup *time.Time) unstructured.Unstructured {
+	item := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"schedule": "0 2 * * *"},
+		"status": map[string]interface{}{"phase": "Enabled"},
+	}}
+	item.SetName(name)
+	item.SetNamespace("velero")
+	if lastBackup != nil {
+		_ = unstructured.SetNestedField(item.Object, lastBackup.Format(time.RFC3339), "status", "lastBackup")
+	}
+	return item
+}
+
+func TestToScheduleList(t *testing.T) {
+	older := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+	lastBackupFirst := dataselect.NewDataSelectQuery(dataselect.NoPagination,
+		dataselect.NewSortQuery([]string{"d", dataselect.LastBackupProperty}), dataselect.NoFilter, dataselect.NoMetrics)
+
+	newSchedule := func(name string, lastBackup *time.Time) Schedule {
+		schedule := Schedule{
+			ObjectMeta: types.ObjectMeta{Name: name, Namespace: "velero"},
+			TypeMeta:   types.TypeMeta{Kind: "Schedule"},
+			Schedule:   "0 2 * * *",
+			Phase:      "Enabled",
+		}
+		if lastBackup != nil {
+			schedule.LastBackup = &metav1.Time{Time: *lastBackup}
+		}
+		return schedule
+	}
+
+	cases := []struct {
+		items    []unstructured.Unstructured
+		dsQuery  *dataselect.DataSelectQuery
+		expected *ScheduleList
+	}{
+		{nil, dataselect.NoDataSelect, &ScheduleList{Items: []Schedule{}, CumulativeMetrics: []metricapi.Metric{}}},
+		{
+			[]unstructured.Unstructured{
+				newRawSchedule("never", nil),
+				newRawSchedule("weekly", &older),
+				newRawSchedule("daily", &newer),
+			},
+			lastBackupFirst,
+			&ScheduleList{
+				ListMeta:          types.ListMeta{TotalItems: 3},
+				Status:            velero.ListStatus{Running: 3},
+				CumulativeMetrics: []metricapi.Metric{},
+				Items: []Schedule{
+					newSchedule("daily", &newer),
+					newSchedule("weekly", &older),
+					newSchedule("never", nil),
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toScheduleList(c.items, nil, c.dsQuery, nil)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toScheduleList(%#v) == \n%#v\nexpected \n%#v\n", c.items, actual, c.expected)
+		}
+	}
+}
+
+// newRawScheduleList returns count schedules encoded the same way the API server lists them.
+func newRawScheduleList(count int) []byte {
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "velero.io/v1", "kind": "ScheduleList"}}
+	lastBackup := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		backupTime := lastBackup.Add(time.Duration(i) * time.Minute)
+		item := newRawSchedule(fmt.Sprintf("schedule-%05d", i), &backupTime)
+		item.SetAPIVersion("velero.io/v1")
+		item.SetKind("Schedule")
+		list.Items = append(list.Items, item)
+	}
+
+	raw, _ := list.MarshalJSON()
+	return raw
+}
+
+func benchmarkGetScheduleList(count int, b *testing.B) {
+	raw := newRawScheduleList(count)
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NewPaginationQuery(10, 0),
+		dataselect.NewSortQuery([]string{"d", dataselect.LastBackupProperty}), dataselect.NoFilter, dataselect.NoMetrics)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		list := &unstructured.UnstructuredList{}
+		if err := list.UnmarshalJSON(raw); err != nil {
+			b.Fatal(err)
+		}
+		toScheduleList(list.Items, nil, dsQuery, nil)
+	}
+}
+
+func BenchmarkGetScheduleList1000(b *testing.B)  { benchmarkGetScheduleList(1000, b) }
+func BenchmarkGetScheduleList5000(b *testing.B)  { benchmarkGetScheduleList(5000, b) }
+func BenchmarkGetScheduleList10000(b *testing.B) { benchmarkGetScheduleList(10000, b) }
@@ -0,0 +1,54 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	apimachinery "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"k8s.io/dashboard/api/pkg/args"
+	metricapi "k8s.io/dashboard/api/pkg/integration/metric/api"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/types"
+)
+
+// getMetricsPerSchedule downloads the Velero backup metrics of every schedule without aggregating them. Metrics are
+// keyed by the schedule UID used in metric labels.
+func getMetricsPerSchedule(schedules []Schedule, metricClient metricapi.MetricClient,
+	dsQuery *dataselect.DataSelectQuery) (map[apimachinery.UID][]metricapi.Metric, error) {
+	klog.V(args.LogLevelDebug).Info("Getting schedule metrics")
+
+	result := make(map[apimachinery.UID][]metricapi.Metric)
+	if metricClient == nil || len(schedules) == 0 {
+		return result, nil
+	}
+
+	metrics, err := dataselect.PodListMetrics(toCells(schedules), dsQuery, metricClient).GetMetrics()
+	if err != nil {
+		return result, err
+	}
+
+	for _, m := range metrics {
+		// Metrics labelled with more than one schedule were aggregated and do not belong to a single schedule.
+		uids := m.Label[types.ResourceKindVeleroSchedule]
+		if len(uids) != 1 || len(m.MetricPoints) == 0 {
+			continue
+		}
+
+		result[uids[0]] = append(result[uids[0]], m)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apimachinery "k8s.io/apimachinery/pkg/types"
+
+	integrationapi "k8s.io/dashboard/api/pkg/integration/api"
+	metricapi "k8s.io/dashboard/api/pkg/integration/metric/api"
+	metriccommon "k8s.io/dashboard/api/pkg/integration/metric/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/types"
+)
+
+// fakeMetricClient returns a single data point per selector, valued by the number of the schedule in the namespace.
+type fakeMetricClient struct{}
+
+func (fakeMetricClient) ID() integrationapi.IntegrationID { return "fake" }
+
+func (fakeMetricClient) HealthCheck() error { return nil }
+
+func (in fakeMetricClient) DownloadMetric(selectors []metricapi.ResourceSelector, metricName string,
+	_ *metricapi.CachedResources) metricapi.MetricPromises {
+	result := metricapi.NewMetricPromises(len(selectors))
+	for i, selector := range selectors {
+		point := metricapi.MetricPoint{Timestamp: time.Unix(60, 0), Value: uint64(i + 1)}
+		result[i].Metric <- &metricapi.Metric{
+			DataPoints:   metricapi.DataPoints{{X: 60, Y: int64(i + 1)}},
+			MetricPoints: []metricapi.MetricPoint{point},
+			MetricName:   metricName,
+			Label:        metricapi.Label{selector.ResourceType: []apimachinery.UID{selector.UID}},
+		}
+		result[i].Error <- nil
+	}
+
+	return result
+}
+
+func (in fakeMetricClient) DownloadMetrics(selectors []metricapi.ResourceSelector, metricNames []string,
+	cachedResources *metricapi.CachedResources) metricapi.MetricPromises {
+	result := metricapi.MetricPromises{}
+	for _, metricName := range metricNames {
+		result = append(result, in.DownloadMetric(selectors, metricName, cachedResources)...)
+	}
+
+	return result
+}
+
+func (fakeMetricClient) AggregateMetrics(metrics metricapi.MetricPromises, metricName string,
+	aggregations metricapi.AggregationModes) metricapi.MetricPromises {
+	return metriccommon.AggregateMetricPromises(metrics, metricName, aggregations, nil)
+}
+
+func TestScheduleCellGetResourceSelector(t *testing.T) {
+	cell := ScheduleCell{ObjectMeta: types.ObjectMeta{Name: "daily", Namespace: "velero"}}
+	expected := &metricapi.ResourceSelector{
+		Namespace:    "velero",
+		ResourceType: types.ResourceKindVeleroSchedule,
+		ResourceName: "daily",
+		UID:          "velero/daily",
+	}
+
+	if actual := cell.GetResourceSelector(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetResourceSelector() == %#v, expected %#v", actual, expected)
+	}
+}
+
+func TestToScheduleListMetrics(t *testing.T) {
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NoPagination, dataselect.NoSort, dataselect.NoFilter,
+		dataselect.NewMetricQuery([]string{metricapi.VeleroBackupFailureTotal}, metricapi.OnlySumAggregation))
+	items := []unstructured.Unstructured{newRawSchedule("daily", nil), newRawSchedule("weekly", nil)}
+
+	list := toScheduleList(items, nil, dsQuery, fakeMetricClient{})
+
+	for i, item := range list.Items {
+		if len(item.Metrics) != 1 {
+			t.Fatalf("Schedule %s has %d metrics, expected 1", item.ObjectMeta.Name, len(item.Metrics))
+		}
+
+		metric := item.Metrics[0]
+		if metric.MetricName != metricapi.VeleroBackupFailureTotal || metric.MetricPoints[0].Value != uint64(i+1) {
+			t.Errorf("Schedule %s has metric %#v", item.ObjectMeta.Name, metric)
+		}
+	}
+
+	if len(list.CumulativeMetrics) != 1 {
+		t.Fatalf("Got %d cumulative metrics, expected 1", len(list.CumulativeMetrics))
+	}
+
+	if actual := list.CumulativeMetrics[0].DataPoints; !reflect.DeepEqual(actual, metricapi.DataPoints{{X: 60, Y: 3}}) {
+		t.Errorf("Cumulative metric has data points %#v, expected the sum of both schedules", actual)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+	dashboardtypes "k8s.io/dashboard/types"
+)
+
+// SLOTargetAnnotation stores the availability objective of a schedule as a fraction of successful runs.
+const SLOTargetAnnotation = "dashboard.kubernetes.io/slo-target"
+
+// DefaultSLOWindows are the rolling windows reported when the caller does not ask for specific ones.
+var DefaultSLOWindows = []string{"7d", "30d"}
+
+// ScheduleSLO is an availability objective attached to a schedule, e.g. 0.99 for 99% successful runs.
+type ScheduleSLO struct {
+	Target float64 `json:"target"`
+}
+
+// ScheduleSLOReport describes how a schedule performed against its objective over rolling windows.
+type ScheduleSLOReport struct {
+	ObjectMeta dashboardtypes.ObjectMeta `json:"objectMeta"`
+	TypeMeta   dashboardtypes.TypeMeta   `json:"typeMeta"`
+
+	// SLO is nil when no objective is attached to the schedule.
+	SLO     *ScheduleSLO `json:"slo"`
+	Windows []SLOWindow  `json:"windows"`
+}
+
+// SLOWindow contains attainment of a single rolling window. Runs that have not finished yet are not counted.
+type SLOWindow struct {
+	Window         string       `json:"window"`
+	TotalRuns      int          `json:"totalRuns"`
+	SuccessfulRuns int          `json:"successfulRuns"`
+	FailedRuns     int          `json:"failedRuns"`
+	Attainment     float64      `json:"attainment"`
+	ErrorBudget    *ErrorBudget `json:"errorBudget,omitempty"`
+}
+
+// ErrorBudget tells how many failed runs the objective allows in a window and how fast it is consumed.
+type ErrorBudget struct {
+	// AllowedFailures is the number of failed runs the objective tolerates in the window.
+	AllowedFailures float64 `json:"allowedFailures"`
+	// Remaining is the fraction of the budget left, negative once the budget is exhausted.
+	Remaining float64 `json:"remaining"`
+	// BurnRate is the observed failure rate relative to the tolerated one, 1 means burning exactly on budget.
+	BurnRate float64 `json:"burnRate"`
+	Met      bool    `json:"met"`
+}
+
+// GetScheduleSLOReport computes SLO attainment and error budget burn of a schedule over the given windows.
+func GetScheduleSLOReport(request *http.Request, namespace *common.NamespaceQuery, name string, windows []string) (*ScheduleSLOReport, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	durations, err := parseWindows(windows)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	rawScheduleData, err := getRawScheduleData(ctx, dynamicClient, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rawSchedule := &unstructured.Unstructured{}
+	if err := rawSchedule.UnmarshalJSON(rawScheduleData); err != nil {
+		return nil, err
+	}
+
+	slo, err := getScheduleSLO(rawSchedule.GetAnnotations())
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := GetScheduleBackups(request, namespace, name, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScheduleSLOReport{
+		ObjectMeta: velero.NewObjectMeta(rawSchedule.Object),
+		TypeMeta:   dashboardtypes.TypeMeta{Kind: "Schedule"},
+		SLO:        slo,
+		Windows:    toSLOWindows(slo, backups.Items, windows, durations, time.Now()),
+	}, nil
+}
+
+// UpdateScheduleSLO attaches the objective to the schedule. A zero target removes it. In dry run the patched schedule is
+// only validated by the API server.
+func UpdateScheduleSLO(request *http.Request, namespace, name string, slo *ScheduleSLO, dryRun bool) (*ScheduleSLO, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	var value interface{}
+	if slo.Target != 0 {
+		if err := validateSLO(slo); err != nil {
+			return nil, err
+		}
+		value = strconv.FormatFloat(slo.Target, 'f', -1, 64)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{SLOTargetAnnotation: value},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := velero.CheckAccess(request, velero.VerbPatch, velero.ScheduleGVR, namespace, name); err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = dynamicClient.Resource(velero.ScheduleGVR).Namespace(namespace).
+		Patch(ctx, name, k8stypes.MergePatchType, patch, velero.PatchOptions(dryRun))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update schedule SLO: %w", err)
+	}
+
+	return slo, nil
+}
+
+func validateSLO(slo *ScheduleSLO) error {
+	if slo.Target <= 0 || slo.Target >= 1 {
+		return errors.NewBadRequest(fmt.Sprintf("SLO target must be a fraction between 0 and 1 (exclusive), got %v", slo.Target))
+	}
+
+	return nil
+}
+
+func getScheduleSLO(annotations map[string]string) (*ScheduleSLO, error) {
+	value, ok := annotations[SLOTargetAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	target, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, errors.NewInvalid(fmt.Sprintf("invalid %s annotation %q: %s", SLOTargetAnnotation, value, err.Error()))
+	}
+
+	return &ScheduleSLO{Target: target}, nil
+}
+
+func toSLOWindows(slo *ScheduleSLO, backups []backup.Backup, names []string, windows []time.Duration, now time.Time) []SLOWindow {
+	result := make([]SLOWindow, 0, len(windows))
+	for i, window := range windows {
+		result = append(result, toSLOWindow(slo, backups, names[i], now.Add(-window)))
+	}
+
+	return result
+}
+
+func toSLOWindow(slo *ScheduleSLO, backups []backup.Backup, name string, since time.Time) SLOWindow {
+	window := SLOWindow{Window: name}
+	for _, item := range backups {
+		runTime := item.ObjectMeta.CreationTimestamp
+		if item.StartTime != nil {
+			runTime = *item.StartTime
+		}
+		if runTime.Time.Before(since) {
+			continue
+		}
+
+		switch item.Phase {
+		case velero.BackupPhaseCompleted:
+			window.SuccessfulRuns++
+		case velero.BackupPhaseFailed, velero.BackupPhasePartiallyFailed, velero.BackupPhaseFailedValidation:
+			window.FailedRuns++
+		default:
+			continue
+		}
+		window.TotalRuns++
+	}
+
+	if window.TotalRuns > 0 {
+		window.Attainment = float64(window.SuccessfulRuns) / float64(window.TotalRuns)
+	}
+
+	if slo == nil {
+		return window
+	}
+
+	allowed := (1 - slo.Target) * float64(window.TotalRuns)
+	budget := &ErrorBudget{
+		AllowedFailures: allowed,
+		Remaining:       1,
+		Met:             window.TotalRuns == 0 || window.Attainment >= slo.Target,
+	}
+	if window.TotalRuns > 0 {
+		budget.Remaining = (allowed - float64(window.FailedRuns)) / allowed
+		budget.BurnRate = (float64(window.FailedRuns) / float64(window.TotalRuns)) / (1 - slo.Target)
+	}
+	window.ErrorBudget = budget
+
+	return window
+}
+
+func parseWindows(windows []string) ([]time.Duration, error) {
+	durations := make([]time.Duration, 0, len(windows))
+	for _, window := range windows {
+		duration, err := backup.ParseWindow(window)
+		if err != nil {
+			return nil, err
+		}
+		durations = append(durations, duration)
+	}
+
+	return durations, nil
+}
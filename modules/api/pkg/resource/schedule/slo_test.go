@@ -0,0 +1,74 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+func newBackupRun(phase velero.BackupPhase, created time.Time) backup.Backup {
+	return backup.Backup{
+		ObjectMeta: types.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+		Phase:      phase,
+	}
+}
+
+func TestToSLOWindow(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-7 * 24 * time.Hour)
+	backups := []backup.Backup{
+		newBackupRun("Completed", now.Add(-1*time.Hour)),
+		newBackupRun("Completed", now.Add(-25*time.Hour)),
+		newBackupRun("Completed", now.Add(-49*time.Hour)),
+		newBackupRun("PartiallyFailed", now.Add(-73*time.Hour)),
+		newBackupRun("InProgress", now.Add(-time.Minute)),
+		newBackupRun("Failed", now.Add(-8*24*time.Hour)),
+	}
+
+	cases := []struct {
+		slo      *ScheduleSLO
+		expected SLOWindow
+	}{
+		{
+			nil,
+			SLOWindow{Window: "7d", TotalRuns: 4, SuccessfulRuns: 3, FailedRuns: 1, Attainment: 0.75},
+		},
+		{
+			&ScheduleSLO{Target: 0.5},
+			SLOWindow{Window: "7d", TotalRuns: 4, SuccessfulRuns: 3, FailedRuns: 1, Attainment: 0.75,
+				ErrorBudget: &ErrorBudget{AllowedFailures: 2, Remaining: 0.5, BurnRate: 0.5, Met: true}},
+		},
+		{
+			&ScheduleSLO{Target: 0.875},
+			SLOWindow{Window: "7d", TotalRuns: 4, SuccessfulRuns: 3, FailedRuns: 1, Attainment: 0.75,
+				ErrorBudget: &ErrorBudget{AllowedFailures: 0.5, Remaining: -1, BurnRate: 2, Met: false}},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toSLOWindow(c.slo, backups, "7d", since)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toSLOWindow(%#v) == \n%#v\nexpected \n%#v\n", c.slo, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"context"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// CloneScheduleSpec names the copy of a schedule.
+type CloneScheduleSpec struct {
+	Name string `json:"name"`
+	// Namespace of the copy, the namespace of the original schedule when empty.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Paused creates the copy paused even when the original schedule is not, so that both do not run at once.
+	Paused bool `json:"paused,omitempty"`
+}
+
+// GetScheduleSpec returns the spec an existing schedule was created with, so that it can be edited and created again
+// under a different name.
+func GetScheduleSpec(request *http.Request, namespace, name string) (*ScheduleSpec, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getScheduleSpec(ctx, dynamicClient, namespace, name)
+}
+
+// CloneSchedule creates a copy of an existing schedule under the name of the clone spec. In dry run the copy is only
+// validated by the API server.
+func CloneSchedule(request *http.Request, namespace, name string, clone *CloneScheduleSpec, dryRun bool) (*Schedule, error) {
+	if clone.Name == "" {
+		return nil, errors.NewBadRequest("name of the copy is required")
+	}
+
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := getScheduleSpec(ctx, dynamicClient, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.Name = clone.Name
+	if clone.Namespace != "" {
+		spec.Namespace = clone.Namespace
+	}
+	spec.Paused = spec.Paused || clone.Paused
+
+	return CreateSchedule(request, spec, dryRun)
+}
+
+func getScheduleSpec(ctx context.Context, client dynamic.Interface, namespace, name string) (*ScheduleSpec, error) {
+	item, err := client.Resource(velero.ScheduleGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toScheduleSpec(*item)
+}
+
+func toScheduleSpec(item unstructured.Unstructured) (*ScheduleSpec, error) {
+	rawTemplate, _, _ := unstructured.NestedMap(item.Object, "spec", "template")
+	template, err := backup.NewBackupTemplate(rawTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	slo, err := getScheduleSLO(item.GetAnnotations())
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &ScheduleSpec{
+		Name:           item.GetName(),
+		Namespace:      item.GetNamespace(),
+		BackupTemplate: template,
+		SLO:            slo,
+	}
+	spec.Schedule, _, _ = unstructured.NestedString(item.Object, "spec", "schedule")
+	spec.Paused, _, _ = unstructured.NestedBool(item.Object, "spec", "paused")
+	if value, found, _ := unstructured.NestedBool(item.Object, "spec", "useOwnerReferencesInBackup"); found {
+		spec.UseOwnerReferencesInBackup = &value
+	}
+	if value, found, _ := unstructured.NestedBool(item.Object, "spec", "skipImmediately"); found {
+		spec.SkipImmediately = &value
+	}
+
+	return spec, nil
+}
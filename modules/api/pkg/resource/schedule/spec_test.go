@@ -0,0 +1,66 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+)
+
+func TestToScheduleSpec(t *testing.T) {
+	item := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "daily",
+			"namespace":   "velero",
+			"annotations": map[string]interface{}{SLOTargetAnnotation: "0.99"},
+		},
+		"spec": map[string]interface{}{
+			"schedule":        "0 1 * * *",
+			"paused":          true,
+			"skipImmediately": false,
+			"template": map[string]interface{}{
+				"includedNamespaces": []interface{}{"shop", "blog"},
+				"ttl":                "168h0m0s",
+			},
+		},
+	}}
+
+	skipImmediately := false
+	expected := &ScheduleSpec{
+		Name:      "daily",
+		Namespace: "velero",
+		Schedule:  "0 1 * * *",
+		BackupTemplate: backup.BackupTemplate{
+			IncludedNamespaces: []string{"shop", "blog"},
+			TTL:                "168h0m0s",
+		},
+		Paused:          true,
+		SkipImmediately: &skipImmediately,
+		SLO:             &ScheduleSLO{Target: 0.99},
+	}
+
+	actual, err := toScheduleSpec(item)
+	if err != nil {
+		t.Fatalf("toScheduleSpec() returned error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toScheduleSpec() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	dashboardtypes "k8s.io/dashboard/types"
+)
+
+// DefaultStatsPeriod is the period covered by schedule statistics when the caller does not ask for a specific one.
+const DefaultStatsPeriod = "30d"
+
+// ScheduleStatsList contains statistics of schedule runs started within the period.
+type ScheduleStatsList struct {
+	Period    string          `json:"period"`
+	Schedules []ScheduleStats `json:"schedules"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ScheduleStats summarizes finished runs of a single schedule. Runs that have not finished yet are not counted.
+type ScheduleStats struct {
+	ObjectMeta dashboardtypes.ObjectMeta `json:"objectMeta"`
+	TypeMeta   dashboardtypes.TypeMeta   `json:"typeMeta"`
+
+	TotalRuns      int     `json:"totalRuns"`
+	SuccessfulRuns int     `json:"successfulRuns"`
+	FailedRuns     int     `json:"failedRuns"`
+	SuccessRate    float64 `json:"successRate"`
+
+	// Duration of finished runs with known start and completion time, nil when there are none.
+	Duration *DurationStats `json:"duration,omitempty"`
+
+	// CurrentFailureStreak counts failed runs since the latest successful one, LongestFailureStreak the most failed
+	// runs in a row within the period.
+	CurrentFailureStreak int `json:"currentFailureStreak"`
+	LongestFailureStreak int `json:"longestFailureStreak"`
+}
+
+// DurationStats describes how long runs took, in seconds. Percentiles use the nearest rank method.
+type DurationStats struct {
+	Average float64 `json:"average"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	P99     float64 `json:"p99"`
+	Max     float64 `json:"max"`
+}
+
+// scheduleRun is a finished run of a schedule.
+type scheduleRun struct {
+	started   time.Time
+	succeeded bool
+	duration  *time.Duration
+}
+
+// GetScheduleStats computes statistics of runs of schedules in namespaces matching the query started within the
+// period, e.g. "30d", from their backups.
+func GetScheduleStats(request *http.Request, namespace *common.NamespaceQuery, period string) (*ScheduleStatsList, error) {
+	duration, err := backup.ParseWindow(period)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := GetScheduleList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := backup.GetBackupList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScheduleStatsList{
+		Period:    period,
+		Schedules: toScheduleStats(schedules.Items, backups.Items, time.Now().Add(-duration)),
+		Errors:    append(schedules.Errors, backups.Errors...),
+	}, nil
+}
+
+func toScheduleStats(schedules []Schedule, backups []backup.Backup, since time.Time) []ScheduleStats {
+	type key struct{ namespace, name string }
+
+	runs := make(map[key][]scheduleRun, len(schedules))
+	for _, item := range backups {
+		name, ok := item.ObjectMeta.Labels[backup.ScheduleNameLabel]
+		if !ok {
+			continue
+		}
+
+		run, ok := toScheduleRun(item)
+		if !ok || run.started.Before(since) {
+			continue
+		}
+
+		runKey := key{item.ObjectMeta.Namespace, name}
+		runs[runKey] = append(runs[runKey], run)
+	}
+
+	result := make([]ScheduleStats, 0, len(schedules))
+	for _, item := range schedules {
+		stats := ScheduleStats{ObjectMeta: item.ObjectMeta, TypeMeta: item.TypeMeta}
+		addRuns(&stats, runs[key{item.ObjectMeta.Namespace, item.ObjectMeta.Name}])
+		result = append(result, stats)
+	}
+
+	return result
+}
+
+// toScheduleRun returns the run of a finished backup. Runs are timed by their start, or creation when Velero did not
+// start them.
+func toScheduleRun(item backup.Backup) (scheduleRun, bool) {
+	run := scheduleRun{started: item.ObjectMeta.CreationTimestamp.Time}
+	if item.StartTime != nil {
+		run.started = item.StartTime.Time
+	}
+
+	switch item.Phase {
+	case velero.BackupPhaseCompleted:
+		run.succeeded = true
+	case velero.BackupPhaseFailed, velero.BackupPhasePartiallyFailed, velero.BackupPhaseFailedValidation:
+	default:
+		return run, false
+	}
+
+	if item.StartTime != nil && item.CompletionTime != nil {
+		duration := item.CompletionTime.Sub(item.StartTime.Time)
+		run.duration = &duration
+	}
+
+	return run, true
+}
+
+func addRuns(stats *ScheduleStats, runs []scheduleRun) {
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].started.Before(runs[j].started)
+	})
+
+	durations := make([]time.Duration, 0, len(runs))
+	streak := 0
+	for _, run := range runs {
+		stats.TotalRuns++
+		if run.succeeded {
+			stats.SuccessfulRuns++
+			streak = 0
+		} else {
+			stats.FailedRuns++
+			streak++
+			stats.LongestFailureStreak = max(stats.LongestFailureStreak, streak)
+		}
+
+		if run.duration != nil {
+			durations = append(durations, *run.duration)
+		}
+	}
+
+	stats.CurrentFailureStreak = streak
+	if stats.TotalRuns > 0 {
+		stats.SuccessRate = float64(stats.SuccessfulRuns) / float64(stats.TotalRuns)
+	}
+	stats.Duration = toDurationStats(durations)
+}
+
+func toDurationStats(durations []time.Duration) *DurationStats {
+	if len(durations) == 0 {
+		return nil
+	}
+
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+
+	return &DurationStats{
+		Average: total.Seconds() / float64(len(durations)),
+		P50:     percentile(durations, 50),
+		P90:     percentile(durations, 90),
+		P99:     percentile(durations, 99),
+		Max:     durations[len(durations)-1].Seconds(),
+	}
+}
+
+// percentile returns the nearest rank percentile of sorted durations in seconds.
+func percentile(sorted []time.Duration, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1].Seconds()
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+func newStatsRun(scheduleName string, phase velero.BackupPhase, start time.Time, duration time.Duration) backup.Backup {
+	startTime := metav1.NewTime(start)
+	item := backup.Backup{
+		ObjectMeta: types.ObjectMeta{
+			Namespace: "velero",
+			Name:      scheduleName + "-" + start.Format("20060102150405"),
+			Labels:    map[string]string{backup.ScheduleNameLabel: scheduleName},
+		},
+		Phase:     phase,
+		StartTime: &startTime,
+	}
+	if duration > 0 {
+		completionTime := metav1.NewTime(start.Add(duration))
+		item.CompletionTime = &completionTime
+	}
+
+	return item
+}
+
+func TestToScheduleStats(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	schedules := []Schedule{
+		{ObjectMeta: types.ObjectMeta{Namespace: "velero", Name: "nightly"}},
+		{ObjectMeta: types.ObjectMeta{Namespace: "velero", Name: "weekly"}},
+	}
+	backups := []backup.Backup{
+		// Listed out of order, streaks follow the start time.
+		newStatsRun("nightly", velero.BackupPhaseFailed, now.Add(-day), time.Minute),
+		newStatsRun("nightly", velero.BackupPhaseCompleted, now.Add(-5*day), 10*time.Minute),
+		newStatsRun("nightly", velero.BackupPhaseFailed, now.Add(-4*day), 0),
+		newStatsRun("nightly", velero.BackupPhasePartiallyFailed, now.Add(-3*day), 20*time.Minute),
+		newStatsRun("nightly", velero.BackupPhaseFailedValidation, now.Add(-6*day), 0),
+		newStatsRun("nightly", velero.BackupPhaseCompleted, now.Add(-2*day), 30*time.Minute),
+		newStatsRun("nightly", velero.BackupPhaseInProgress, now, 0),
+		newStatsRun("nightly", velero.BackupPhaseCompleted, now.Add(-40*day), time.Hour),
+		newStatsRun("hourly", velero.BackupPhaseCompleted, now.Add(-day), time.Minute),
+	}
+
+	expected := []ScheduleStats{
+		{
+			ObjectMeta:     schedules[0].ObjectMeta,
+			TotalRuns:      6,
+			SuccessfulRuns: 2,
+			FailedRuns:     4,
+			SuccessRate:    2.0 / 6.0,
+			Duration: &DurationStats{
+				Average: (time.Minute + 10*time.Minute + 20*time.Minute + 30*time.Minute).Seconds() / 4,
+				P50:     (10 * time.Minute).Seconds(),
+				P90:     (30 * time.Minute).Seconds(),
+				P99:     (30 * time.Minute).Seconds(),
+				Max:     (30 * time.Minute).Seconds(),
+			},
+			CurrentFailureStreak: 1,
+			LongestFailureStreak: 2,
+		},
+		{ObjectMeta: schedules[1].ObjectMeta},
+	}
+
+	actual := toScheduleStats(schedules, backups, now.Add(-30*day))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toScheduleStats() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second}
+	cases := []struct {
+		p        float64
+		expected float64
+	}{
+		{0, 1},
+		{25, 1},
+		{50, 2},
+		{75, 3},
+		{90, 4},
+		{100, 4},
+	}
+
+	for _, c := range cases {
+		if actual := percentile(sorted, c.p); actual != c.expected {
+			t.Errorf("percentile(%v) == %v, expected %v", c.p, actual, c.expected)
+		}
+	}
+}
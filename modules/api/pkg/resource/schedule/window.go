@@ -0,0 +1,221 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/errors"
+	dashboardtypes "k8s.io/dashboard/types"
+)
+
+// DefaultWindowAdherencePeriod is the period covered by the window adherence report when the caller does not ask for
+// a specific one.
+const DefaultWindowAdherencePeriod = "30d"
+
+// BackupWindow is the time of day scheduled backups are expected to run in, e.g. 01:00-05:00. Windows ending before
+// they start span midnight.
+type BackupWindow struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	TimeZone string `json:"timeZone"`
+}
+
+// WindowAdherenceReport tells which scheduled backups started or finished outside the backup window.
+type WindowAdherenceReport struct {
+	Window    BackupWindow              `json:"window"`
+	Period    string                    `json:"period"`
+	Schedules []ScheduleWindowAdherence `json:"schedules"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ScheduleWindowAdherence summarizes runs of a single schedule. A schedule has outgrown its slot when most of its
+// finished runs that started in the window finished after it closed.
+type ScheduleWindowAdherence struct {
+	ObjectMeta dashboardtypes.ObjectMeta `json:"objectMeta"`
+	TypeMeta   dashboardtypes.TypeMeta   `json:"typeMeta"`
+
+	TotalRuns       int  `json:"totalRuns"`
+	StartedOutside  int  `json:"startedOutside"`
+	FinishedOutside int  `json:"finishedOutside"`
+	Outgrown        bool `json:"outgrown"`
+
+	// Violations lists runs that started or finished outside the window in the order backups were listed.
+	Violations []WindowViolation `json:"violations"`
+}
+
+// WindowViolation is a run of a schedule that did not fit into the backup window.
+type WindowViolation struct {
+	Name            string       `json:"name"`
+	StartTime       *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime  *metav1.Time `json:"completionTime,omitempty"`
+	StartedOutside  bool         `json:"startedOutside"`
+	FinishedOutside bool         `json:"finishedOutside"`
+}
+
+// timeWindow is a parsed backup window, offsets are measured from midnight in the location.
+type timeWindow struct {
+	start, end time.Duration
+	location   *time.Location
+}
+
+// GetWindowAdherenceReport checks runs of schedules in namespaces matching the query started within the period
+// against the backup window.
+func GetWindowAdherenceReport(request *http.Request, namespace *common.NamespaceQuery, window BackupWindow, period string) (*WindowAdherenceReport, error) {
+	duration, err := backup.ParseWindow(period)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseTimeWindow(window)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := GetScheduleList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := backup.GetBackupList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WindowAdherenceReport{
+		Window:    window,
+		Period:    period,
+		Schedules: toWindowAdherence(schedules.Items, backups.Items, parsed, time.Now().Add(-duration)),
+		Errors:    append(schedules.Errors, backups.Errors...),
+	}, nil
+}
+
+func parseTimeWindow(window BackupWindow) (*timeWindow, error) {
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid time zone %q: %s", window.TimeZone, err.Error()))
+	}
+
+	start, startErr := time.Parse("15:04", window.Start)
+	end, endErr := time.Parse("15:04", window.End)
+	if startErr != nil || endErr != nil || start.Equal(end) {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid backup window %s-%s, expected distinct times such as 01:00 and 05:00",
+			window.Start, window.End))
+	}
+
+	return &timeWindow{
+		start:    time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		end:      time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute,
+		location: location,
+	}, nil
+}
+
+// contains tells whether the time of day of t falls into the window.
+func (in *timeWindow) contains(t time.Time) bool {
+	offset := in.offset(t)
+	if in.start < in.end {
+		return offset >= in.start && offset <= in.end
+	}
+
+	return offset >= in.start || offset <= in.end
+}
+
+// closesAfter returns when the window open at t closes.
+func (in *timeWindow) closesAfter(t time.Time) time.Time {
+	local := t.In(in.location)
+	closes := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, in.location).Add(in.end)
+	if closes.Before(local) {
+		closes = closes.AddDate(0, 0, 1)
+	}
+
+	return closes
+}
+
+func (in *timeWindow) offset(t time.Time) time.Duration {
+	local := t.In(in.location)
+	return time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second
+}
+
+func toWindowAdherence(schedules []Schedule, backups []backup.Backup, window *timeWindow, since time.Time) []ScheduleWindowAdherence {
+	type key struct{ namespace, name string }
+
+	result := make([]ScheduleWindowAdherence, len(schedules))
+	index := make(map[key]int, len(schedules))
+	for i, item := range schedules {
+		result[i] = ScheduleWindowAdherence{
+			ObjectMeta: item.ObjectMeta,
+			TypeMeta:   item.TypeMeta,
+			Violations: make([]WindowViolation, 0),
+		}
+		index[key{item.ObjectMeta.Namespace, item.ObjectMeta.Name}] = i
+	}
+
+	// Finished runs that started in the window and those of them that overran it, per schedule.
+	finishedInWindow := make([]int, len(schedules))
+	overran := make([]int, len(schedules))
+	for _, item := range backups {
+		i, ok := index[key{item.ObjectMeta.Namespace, item.ObjectMeta.Labels[backup.ScheduleNameLabel]}]
+		if !ok || item.StartTime == nil || item.StartTime.Time.Before(since) {
+			continue
+		}
+
+		adherence := &result[i]
+		adherence.TotalRuns++
+
+		violation := WindowViolation{
+			Name:           item.ObjectMeta.Name,
+			StartTime:      item.StartTime,
+			CompletionTime: item.CompletionTime,
+			StartedOutside: !window.contains(item.StartTime.Time),
+		}
+		if item.CompletionTime != nil {
+			if violation.StartedOutside {
+				violation.FinishedOutside = !window.contains(item.CompletionTime.Time)
+			} else {
+				finishedInWindow[i]++
+				violation.FinishedOutside = item.CompletionTime.Time.After(window.closesAfter(item.StartTime.Time))
+			}
+		}
+
+		if violation.StartedOutside {
+			adherence.StartedOutside++
+		}
+		if violation.FinishedOutside {
+			adherence.FinishedOutside++
+			if !violation.StartedOutside {
+				overran[i]++
+			}
+		}
+		if violation.StartedOutside || violation.FinishedOutside {
+			adherence.Violations = append(adherence.Violations, violation)
+		}
+	}
+
+	for i := range result {
+		result[i].Outgrown = overran[i]*2 > finishedInWindow[i]
+	}
+
+	return result
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/types"
+)
+
+func newWindowRun(name string, start, completion time.Time) backup.Backup {
+	startTime := metav1.NewTime(start)
+	item := backup.Backup{
+		ObjectMeta: types.ObjectMeta{
+			Namespace: "velero",
+			Name:      name,
+			Labels:    map[string]string{backup.ScheduleNameLabel: "nightly"},
+		},
+		StartTime: &startTime,
+	}
+	if !completion.IsZero() {
+		completionTime := metav1.NewTime(completion)
+		item.CompletionTime = &completionTime
+	}
+
+	return item
+}
+
+func TestTimeWindowContains(t *testing.T) {
+	cases := []struct {
+		start, end string
+		at         time.Time
+		expected   bool
+	}{
+		{"01:00", "05:00", time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC), true},
+		{"01:00", "05:00", time.Date(2026, 10, 17, 5, 30, 0, 0, time.UTC), false},
+		{"22:00", "02:00", time.Date(2026, 10, 17, 23, 0, 0, 0, time.UTC), true},
+		{"22:00", "02:00", time.Date(2026, 10, 17, 1, 59, 0, 0, time.UTC), true},
+		{"22:00", "02:00", time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, c := range cases {
+		window, err := parseTimeWindow(BackupWindow{Start: c.start, End: c.end, TimeZone: "UTC"})
+		if err != nil {
+			t.Fatalf("parseTimeWindow(%s-%s) returned error: %s", c.start, c.end, err.Error())
+		}
+
+		if actual := window.contains(c.at); actual != c.expected {
+			t.Errorf("window %s-%s contains %s == %t, expected %t", c.start, c.end, c.at, actual, c.expected)
+		}
+	}
+}
+
+func TestParseTimeWindowInvalid(t *testing.T) {
+	windows := []BackupWindow{
+		{Start: "1am", End: "05:00", TimeZone: "UTC"},
+		{Start: "01:00", End: "01:00", TimeZone: "UTC"},
+		{Start: "01:00", End: "05:00", TimeZone: "Mars/Olympus_Mons"},
+	}
+
+	for _, window := range windows {
+		if _, err := parseTimeWindow(window); err == nil {
+			t.Errorf("parseTimeWindow(%#v) returned no error", window)
+		}
+	}
+}
+
+func TestToWindowAdherence(t *testing.T) {
+	day := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+	window, _ := parseTimeWindow(BackupWindow{Start: "01:00", End: "05:00", TimeZone: "UTC"})
+	nightly := Schedule{ObjectMeta: types.ObjectMeta{Namespace: "velero", Name: "nightly"}}
+	backups := []backup.Backup{
+		newWindowRun("nightly-1", day.Add(time.Hour), day.Add(2*time.Hour)),
+		newWindowRun("nightly-2", day.Add(25*time.Hour), day.Add(30*time.Hour)),
+		newWindowRun("nightly-3", day.Add(49*time.Hour), day.Add(54*time.Hour)),
+		newWindowRun("nightly-late", day.Add(60*time.Hour), day.Add(61*time.Hour)),
+		newWindowRun("nightly-4", day.Add(73*time.Hour), time.Time{}),
+		newWindowRun("too-old", day.Add(-47*time.Hour), day.Add(-40*time.Hour)),
+	}
+
+	expected := []ScheduleWindowAdherence{{
+		ObjectMeta:      nightly.ObjectMeta,
+		TotalRuns:       5,
+		StartedOutside:  1,
+		FinishedOutside: 3,
+		Outgrown:        true,
+		Violations: []WindowViolation{
+			{Name: "nightly-2", StartTime: backups[1].StartTime, CompletionTime: backups[1].CompletionTime, FinishedOutside: true},
+			{Name: "nightly-3", StartTime: backups[2].StartTime, CompletionTime: backups[2].CompletionTime, FinishedOutside: true},
+			{
+				Name:            "nightly-late",
+				StartTime:       backups[3].StartTime,
+				CompletionTime:  backups[3].CompletionTime,
+				StartedOutside:  true,
+				FinishedOutside: true,
+			},
+		},
+	}}
+
+	actual := toWindowAdherence([]Schedule{nightly}, backups, window, day.Add(-24*time.Hour))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toWindowAdherence() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotclass
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+const (
+	// VeleroClassLabel marks the VolumeSnapshotClass Velero uses for the CSI volume snapshots of its driver.
+	VeleroClassLabel = "velero.io/csi-volumesnapshot-class"
+	// DefaultClassAnnotation marks the default VolumeSnapshotClass of a driver, which Velero falls back to.
+	DefaultClassAnnotation = "snapshot.storage.kubernetes.io/is-default-class"
+)
+
+// How Velero chooses the VolumeSnapshotClass of a driver when neither the backup nor the PVC names one.
+const (
+	ClassSourceLabel   = "label"
+	ClassSourceDefault = "default"
+	ClassSourceOnly    = "only"
+)
+
+// SnapshotClassList contains the VolumeSnapshotClasses of the cluster and the class Velero uses for each CSI driver.
+type SnapshotClassList struct {
+	ListMeta types.ListMeta  `json:"listMeta"`
+	Items    []SnapshotClass `json:"items"`
+
+	// Available tells whether the cluster serves the CSI snapshot API. Velero cannot take CSI snapshots without it.
+	Available bool `json:"available"`
+
+	// Drivers are the CSI drivers installed in the cluster or named by a class, sorted by name.
+	Drivers []DriverSnapshotClass `json:"drivers"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// SnapshotClass is a VolumeSnapshotClass, the parameters a CSI driver takes volume snapshots with.
+type SnapshotClass struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	Driver         string `json:"driver"`
+	DeletionPolicy string `json:"deletionPolicy"`
+
+	// Default is set for the default class of the driver.
+	Default bool `json:"default"`
+
+	// Velero is set for classes labeled for Velero use.
+	Velero bool `json:"velero"`
+}
+
+// DriverSnapshotClass is the VolumeSnapshotClass Velero uses for the CSI volume snapshots of a driver.
+type DriverSnapshotClass struct {
+	Driver string `json:"driver"`
+
+	// Class is the name of the class Velero uses, empty when it cannot choose one.
+	Class string `json:"class,omitempty"`
+
+	// Source tells how Velero chooses the class: by label, as the default class or as the only class of the driver.
+	Source string `json:"source,omitempty"`
+
+	// Warning explains why Velero cannot choose a class, which fails CSI snapshots of volumes of the driver.
+	Warning string `json:"warning,omitempty"`
+}
+
+// GetSnapshotClassList returns the VolumeSnapshotClasses of the cluster and the class Velero uses for each CSI
+// driver, so that clients can warn about backups with CSI snapshots that would fail.
+func GetSnapshotClassList(request *http.Request) (*SnapshotClassList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getSnapshotClassList(ctx, dynamicClient, k8sClient)
+}
+
+func getSnapshotClassList(ctx context.Context, dynamicClient dynamic.Interface, k8sClient kubernetes.Interface) (*SnapshotClassList, error) {
+	result := &SnapshotClassList{Items: make([]SnapshotClass, 0), Drivers: make([]DriverSnapshotClass, 0), Available: true}
+
+	classes, err := dynamicClient.Resource(velero.VolumeSnapshotClassGVR).List(ctx, metav1.ListOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		result.Available = false
+	case err != nil:
+		nonCriticalErrors, criticalError := errors.ExtractErrors(err)
+		if criticalError != nil {
+			return nil, criticalError
+		}
+		result.Errors = append(result.Errors, nonCriticalErrors...)
+	default:
+		for _, item := range classes.Items {
+			result.Items = append(result.Items, toSnapshotClass(item))
+		}
+	}
+
+	var driverNames []string
+	csiDrivers, err := k8sClient.StorageV1().CSIDrivers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		nonCriticalErrors, criticalError := errors.ExtractErrors(err)
+		if criticalError != nil {
+			return nil, criticalError
+		}
+		result.Errors = append(result.Errors, nonCriticalErrors...)
+	} else {
+		for _, item := range csiDrivers.Items {
+			driverNames = append(driverNames, item.Name)
+		}
+	}
+
+	sort.Slice(result.Items, func(i, j int) bool { return result.Items[i].ObjectMeta.Name < result.Items[j].ObjectMeta.Name })
+	result.ListMeta.TotalItems = len(result.Items)
+	result.Drivers = toDriverSnapshotClasses(driverNames, result.Items)
+	return result, nil
+}
+
+func toSnapshotClass(item unstructured.Unstructured) SnapshotClass {
+	driver, _, _ := unstructured.NestedString(item.Object, "driver")
+	deletionPolicy, _, _ := unstructured.NestedString(item.Object, "deletionPolicy")
+	_, isDefault := item.GetAnnotations()[DefaultClassAnnotation]
+	return SnapshotClass{
+		ObjectMeta: velero.NewObjectMeta(item.Object),
+		TypeMeta: types.TypeMeta{
+			Kind: "VolumeSnapshotClass",
+		},
+		Driver:         driver,
+		DeletionPolicy: deletionPolicy,
+		Default:        isDefault,
+		Velero:         item.GetLabels()[VeleroClassLabel] == "true",
+	}
+}
+
+// toDriverSnapshotClasses chooses the class of every driver the way Velero does: the class labeled for Velero, then
+// the default class and then the only class of the driver. Classes passed to backups or PVCs in annotations are not
+// taken into account.
+func toDriverSnapshotClasses(driverNames []string, classes []SnapshotClass) []DriverSnapshotClass {
+	byDriver := make(map[string][]SnapshotClass)
+	for _, name := range driverNames {
+		byDriver[name] = nil
+	}
+	for _, class := range classes {
+		byDriver[class.Driver] = append(byDriver[class.Driver], class)
+	}
+
+	result := make([]DriverSnapshotClass, 0, len(byDriver))
+	for driver, driverClasses := range byDriver {
+		result = append(result, toDriverSnapshotClass(driver, driverClasses))
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Driver < result[j].Driver })
+	return result
+}
+
+func toDriverSnapshotClass(driver string, classes []SnapshotClass) DriverSnapshotClass {
+	result := DriverSnapshotClass{Driver: driver}
+
+	var labeled []string
+	for _, class := range classes {
+		if class.Velero {
+			labeled = append(labeled, class.ObjectMeta.Name)
+		}
+	}
+
+	switch {
+	case len(labeled) == 1:
+		result.Class, result.Source = labeled[0], ClassSourceLabel
+	case len(labeled) > 1:
+		result.Warning = fmt.Sprintf("VolumeSnapshotClasses %s are all labeled with %s, Velero cannot choose one of them",
+			strings.Join(labeled, ", "), VeleroClassLabel)
+	default:
+		for _, class := range classes {
+			if class.Default {
+				result.Class, result.Source = class.ObjectMeta.Name, ClassSourceDefault
+				return result
+			}
+		}
+
+		if len(classes) == 1 {
+			result.Class, result.Source = classes[0].ObjectMeta.Name, ClassSourceOnly
+			return result
+		}
+
+		if len(classes) == 0 {
+			result.Warning = "no VolumeSnapshotClass exists for the driver"
+		} else {
+			result.Warning = fmt.Sprintf("none of the VolumeSnapshotClasses of the driver is labeled with %s or "+
+				"annotated as the default one", VeleroClassLabel)
+		}
+	}
+
+	return result
+}
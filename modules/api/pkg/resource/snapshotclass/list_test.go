@@ -0,0 +1,107 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotclass
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newSnapshotClass(name, driver string, labeled, isDefault bool) *unstructured.Unstructured {
+	object := velerofake.NewObject(velero.VolumeSnapshotClassGVR, "", name)
+	object.Object["driver"] = driver
+	object.Object["deletionPolicy"] = "Retain"
+	if labeled {
+		object.SetLabels(map[string]string{VeleroClassLabel: "true"})
+	}
+	if isDefault {
+		object.SetAnnotations(map[string]string{DefaultClassAnnotation: "true"})
+	}
+	return object
+}
+
+func TestGetSnapshotClassList(t *testing.T) {
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newSnapshotClass("ebs-velero", "ebs.csi.aws.com", true, false),
+		newSnapshotClass("ebs-default", "ebs.csi.aws.com", false, true),
+		newSnapshotClass("efs-default", "efs.csi.aws.com", false, true),
+		newSnapshotClass("efs-fast", "efs.csi.aws.com", false, false),
+		newSnapshotClass("hostpath", "hostpath.csi.k8s.io", false, false),
+		newSnapshotClass("nfs-a", "nfs.csi.k8s.io", false, false),
+		newSnapshotClass("nfs-b", "nfs.csi.k8s.io", false, false),
+		newSnapshotClass("rbd-a", "rbd.csi.ceph.com", true, false),
+		newSnapshotClass("rbd-b", "rbd.csi.ceph.com", true, false),
+	)
+	k8sClient := fake.NewSimpleClientset(
+		&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "ebs.csi.aws.com"}},
+		&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "smb.csi.k8s.io"}},
+	)
+
+	actual, err := getSnapshotClassList(context.Background(), dynamicClient, k8sClient)
+	if err != nil {
+		t.Fatalf("getSnapshotClassList() returned error: %v", err)
+	}
+
+	if !actual.Available || actual.ListMeta.TotalItems != 9 || actual.Items[0].ObjectMeta.Name != "ebs-default" ||
+		!actual.Items[0].Default || actual.Items[1].Driver != "ebs.csi.aws.com" || !actual.Items[1].Velero {
+		t.Errorf("getSnapshotClassList() == %#v, expected 9 sorted classes", actual.Items)
+	}
+
+	expected := []DriverSnapshotClass{
+		{Driver: "ebs.csi.aws.com", Class: "ebs-velero", Source: ClassSourceLabel},
+		{Driver: "efs.csi.aws.com", Class: "efs-default", Source: ClassSourceDefault},
+		{Driver: "hostpath.csi.k8s.io", Class: "hostpath", Source: ClassSourceOnly},
+		{Driver: "nfs.csi.k8s.io", Warning: "none of the VolumeSnapshotClasses of the driver is labeled with " +
+			"velero.io/csi-volumesnapshot-class or annotated as the default one"},
+		{Driver: "rbd.csi.ceph.com", Warning: "VolumeSnapshotClasses rbd-a, rbd-b are all labeled with " +
+			"velero.io/csi-volumesnapshot-class, Velero cannot choose one of them"},
+		{Driver: "smb.csi.k8s.io", Warning: "no VolumeSnapshotClass exists for the driver"},
+	}
+	if !reflect.DeepEqual(actual.Drivers, expected) {
+		t.Errorf("getSnapshotClassList() drivers == \n%#v\nexpected \n%#v\n", actual.Drivers, expected)
+	}
+}
+
+func TestGetSnapshotClassListUnavailable(t *testing.T) {
+	dynamicClient := velerofake.NewDynamicClient(nil)
+	dynamicClient.PrependReactor("list", "volumesnapshotclasses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewNotFound(velero.VolumeSnapshotClassGVR.GroupResource(), "")
+	})
+	k8sClient := fake.NewSimpleClientset()
+	k8sClient.PrependReactor("list", "csidrivers", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewForbidden(storagev1.Resource("csidrivers"), "", nil)
+	})
+
+	actual, err := getSnapshotClassList(context.Background(), dynamicClient, k8sClient)
+	if err != nil {
+		t.Fatalf("getSnapshotClassList() returned error: %v", err)
+	}
+
+	if actual.Available || len(actual.Items) != 0 || len(actual.Drivers) != 0 || len(actual.Errors) != 1 {
+		t.Errorf("getSnapshotClassList() == %#v, expected an unavailable snapshot API and a forbidden error", actual)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotlocation
+
+import (
+	"context"
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// SnapshotLocationDetail contains the configuration of a Velero volume snapshot location.
+type SnapshotLocationDetail struct {
+	SnapshotLocation `json:",inline"`
+
+	// Provider specific configuration, e.g. the region snapshots are taken in.
+	Config map[string]string `json:"config,omitempty"`
+
+	// Secret key the credentials are read from, nil when Velero uses the credentials of its server.
+	Credential *v1.SecretKeySelector `json:"credential,omitempty"`
+}
+
+// GetSnapshotLocationDetail returns a volume snapshot location in the namespace.
+func GetSnapshotLocationDetail(request *http.Request, namespace, name string) (*SnapshotLocationDetail, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getSnapshotLocationDetail(ctx, dynamicClient, namespace, name)
+}
+
+func getSnapshotLocationDetail(ctx context.Context, client dynamic.Interface, namespace, name string) (*SnapshotLocationDetail, error) {
+	item, err := client.Resource(velero.VolumeSnapshotLocationGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toSnapshotLocationDetail(*item), nil
+}
+
+func toSnapshotLocationDetail(item unstructured.Unstructured) *SnapshotLocationDetail {
+	config, _, _ := unstructured.NestedStringMap(item.Object, "spec", "config")
+	detail := &SnapshotLocationDetail{
+		SnapshotLocation: toSnapshotLocation(item),
+		Config:           config,
+	}
+
+	if credential, ok, _ := unstructured.NestedMap(item.Object, "spec", "credential"); ok {
+		detail.Credential = &v1.SecretKeySelector{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(credential, detail.Credential); err != nil {
+			detail.Credential = nil
+		}
+	}
+
+	return detail
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotlocation
+
+import (
+	"context"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+// SnapshotLocationList contains a list of Velero volume snapshot locations.
+type SnapshotLocationList struct {
+	ListMeta types.ListMeta     `json:"listMeta"`
+	Items    []SnapshotLocation `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// SnapshotLocation is a Velero volume snapshot location, where the volume snapshotter plugin of the provider takes
+// snapshots of persistent volumes.
+type SnapshotLocation struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	Provider string `json:"provider"`
+
+	// Phase is only set by Velero versions validating snapshot locations, Available or Unavailable.
+	Phase string `json:"phase,omitempty"`
+}
+
+// GetSnapshotLocationList returns the volume snapshot locations in the namespaces matching the query.
+func GetSnapshotLocationList(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*SnapshotLocationList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getSnapshotLocationList(ctx, dynamicClient, namespace, dsQuery)
+}
+
+func getSnapshotLocationList(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*SnapshotLocationList, error) {
+	items, nonCriticalErrors, err := velero.ListInNamespaces(ctx, client, velero.VolumeSnapshotLocationGVR, namespace,
+		metav1.ListOptions{}, args.VeleroMaxListItems())
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]SnapshotLocation, 0, len(items))
+	for _, item := range items {
+		locations = append(locations, toSnapshotLocation(item))
+	}
+
+	locationCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(locations), dsQuery)
+	return &SnapshotLocationList{
+		ListMeta: types.ListMeta{TotalItems: filteredTotal},
+		Items:    fromCells(locationCells),
+		Errors:   nonCriticalErrors,
+	}, nil
+}
+
+func toSnapshotLocation(item unstructured.Unstructured) SnapshotLocation {
+	provider, _, _ := unstructured.NestedString(item.Object, "spec", "provider")
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	return SnapshotLocation{
+		ObjectMeta: velero.NewObjectMeta(item.Object),
+		TypeMeta: types.TypeMeta{
+			Kind: "VolumeSnapshotLocation",
+		},
+		Provider: provider,
+		Phase:    phase,
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotlocation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newTestSnapshotLocation(namespace, name string) *unstructured.Unstructured {
+	location := velerofake.NewObject(velero.VolumeSnapshotLocationGVR, namespace, name)
+	_ = unstructured.SetNestedField(location.Object, "aws", "spec", "provider")
+	_ = unstructured.SetNestedStringMap(location.Object, map[string]string{"region": "eu-west-1"}, "spec", "config")
+	return location
+}
+
+func TestGetSnapshotLocationList(t *testing.T) {
+	client := velerofake.NewDynamicClient(nil, newTestSnapshotLocation("velero", "ebs"),
+		newTestSnapshotLocation("velero-staging", "ebs"))
+
+	actual, err := getSnapshotLocationList(context.TODO(), client, common.NewNamespaceQuery([]string{"velero"}),
+		dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("getSnapshotLocationList() returned error: %s", err.Error())
+	}
+
+	if len(actual.Items) != 1 || actual.Items[0].ObjectMeta.Name != "ebs" || actual.Items[0].Provider != "aws" ||
+		actual.Items[0].TypeMeta.Kind != "VolumeSnapshotLocation" {
+		t.Errorf("getSnapshotLocationList() == %#v, expected the ebs location in the velero namespace", actual.Items)
+	}
+}
+
+func TestGetSnapshotLocationDetail(t *testing.T) {
+	client := velerofake.NewDynamicClient(nil, newTestSnapshotLocation("velero", "ebs"))
+
+	actual, err := getSnapshotLocationDetail(context.TODO(), client, "velero", "ebs")
+	if err != nil {
+		t.Fatalf("getSnapshotLocationDetail() returned error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(actual.Config, map[string]string{"region": "eu-west-1"}) || actual.Credential != nil {
+		t.Errorf("getSnapshotLocationDetail() == %#v, expected the config and no credential", actual)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagelocation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/errors"
+)
+
+// AccessMode tells whether Velero may write backups to a storage location.
+type AccessMode string
+
+const (
+	AccessModeReadWrite AccessMode = "ReadWrite"
+	// AccessModeReadOnly keeps Velero from creating, deleting and garbage collecting backups in the storage location,
+	// while restores from it remain possible, e.g. during restore drills and migrations.
+	AccessModeReadOnly AccessMode = "ReadOnly"
+)
+
+// AccessModeSpec is the new access mode of a storage location.
+type AccessModeSpec struct {
+	AccessMode AccessMode `json:"accessMode"`
+}
+
+// StorageLocationAccessMode is the access mode of a storage location.
+type StorageLocationAccessMode struct {
+	Namespace  string     `json:"namespace"`
+	Name       string     `json:"name"`
+	AccessMode AccessMode `json:"accessMode"`
+}
+
+// UpdateAccessMode switches the storage location between read-write and read-only. In dry run the patched storage
+// location is only validated by the API server.
+func UpdateAccessMode(request *http.Request, namespace, name string, spec *AccessModeSpec, dryRun bool) (*StorageLocationAccessMode, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	if spec.AccessMode != AccessModeReadWrite && spec.AccessMode != AccessModeReadOnly {
+		return nil, errors.NewBadRequest(field.NotSupported(field.NewPath("accessMode"), spec.AccessMode,
+			[]string{string(AccessModeReadWrite), string(AccessModeReadOnly)}).Error())
+	}
+
+	if err := velero.CheckAccess(request, velero.VerbPatch, velero.BackupStorageLocationGVR, namespace, name); err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateAccessMode(ctx, dynamicClient, namespace, name, spec.AccessMode, dryRun)
+}
+
+func updateAccessMode(ctx context.Context, client dynamic.Interface, namespace, name string, accessMode AccessMode,
+	dryRun bool) (*StorageLocationAccessMode, error) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"accessMode": accessMode},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := client.Resource(velero.BackupStorageLocationGVR).Namespace(namespace).
+		Patch(ctx, name, k8stypes.MergePatchType, patch, velero.PatchOptions(dryRun))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update access mode of storage location %s: %w", name, err)
+	}
+
+	return toStorageLocationAccessMode(*patched), nil
+}
+
+// toStorageLocationAccessMode reads the access mode of the storage location, which Velero defaults to read-write.
+func toStorageLocationAccessMode(location unstructured.Unstructured) *StorageLocationAccessMode {
+	accessMode, _, _ := unstructured.NestedString(location.Object, "spec", "accessMode")
+	if accessMode == "" {
+		accessMode = string(AccessModeReadWrite)
+	}
+
+	return &StorageLocationAccessMode{
+		Namespace:  location.GetNamespace(),
+		Name:       location.GetName(),
+		AccessMode: AccessMode(accessMode),
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagelocation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateAccessMode(t *testing.T) {
+	client := newTestDynamicClient(newTestStorageLocation("default"))
+
+	cases := []struct {
+		accessMode AccessMode
+		expected   *StorageLocationAccessMode
+	}{
+		{AccessModeReadOnly, &StorageLocationAccessMode{Namespace: "velero", Name: "default", AccessMode: AccessModeReadOnly}},
+		{AccessModeReadWrite, &StorageLocationAccessMode{Namespace: "velero", Name: "default", AccessMode: AccessModeReadWrite}},
+	}
+
+	for _, c := range cases {
+		actual, err := updateAccessMode(context.TODO(), client, "velero", "default", c.accessMode, false)
+		if err != nil {
+			t.Errorf("updateAccessMode(%s) returned error: %s", c.accessMode, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("updateAccessMode(%s) == \n%#v\nexpected \n%#v\n", c.accessMode, actual, c.expected)
+		}
+	}
+
+	if _, err := updateAccessMode(context.TODO(), client, "velero", "missing", AccessModeReadOnly, false); err == nil {
+		t.Errorf("updateAccessMode() of a missing storage location returned no error")
+	}
+}
+
+func TestToStorageLocationAccessMode(t *testing.T) {
+	actual := toStorageLocationAccessMode(*newTestStorageLocation("default"))
+	expected := &StorageLocationAccessMode{Namespace: "velero", Name: "default", AccessMode: AccessModeReadWrite}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toStorageLocationAccessMode() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storagelocation manages Velero backup storage locations, which tell Velero where backups are stored.
+package storagelocation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+)
+
+var secretGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// CredentialSpec contains the credentials of the object storage provider of a storage location.
+type CredentialSpec struct {
+	// SecretName is the Secret the credentials are stored in. Defaults to velero.CredentialsSecretName, which the
+	// Velero server reads when a storage location has no credential of its own.
+	SecretName string `json:"secretName"`
+
+	// Credentials are the contents of the credentials file of the provider. They are never returned.
+	Credentials string `json:"credentials"`
+}
+
+// Credential is the Secret key a storage location reads its credentials from.
+type Credential struct {
+	StorageLocation string `json:"storageLocation"`
+	Namespace       string `json:"namespace"`
+	SecretName      string `json:"secretName"`
+	Key             string `json:"key"`
+}
+
+// UpdateCredential stores the credentials in the velero.CredentialsSecretKey key of the Secret and sets it as the
+// credential of the storage location. Both changes are validated by the API server first, and the Secret is restored
+// when the storage location can not be updated, so that neither is changed without the other. In dry run the changes
+// are only validated.
+func UpdateCredential(request *http.Request, namespace, name string, spec *CredentialSpec, dryRun bool) (*Credential, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	if spec.SecretName == "" {
+		spec.SecretName = velero.CredentialsSecretName
+	}
+
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := getSecret(ctx, k8sClient.CoreV1().Secrets(namespace), spec.SecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, check := range getCredentialAccessChecks(name, spec.SecretName, previous != nil) {
+		if err := velero.CheckAccess(request, check.verb, check.resource, namespace, check.name); err != nil {
+			return nil, err
+		}
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateCredential(ctx, k8sClient, dynamicClient, namespace, name, spec, previous, dryRun)
+}
+
+// credentialAccessCheck is a permission needed to update the credential of a storage location.
+type credentialAccessCheck struct {
+	verb     string
+	resource schema.GroupVersionResource
+	name     string
+}
+
+// getCredentialAccessChecks returns the permissions storeCredentials and the patch of the storage location need. An
+// existing Secret is updated, a missing one created.
+func getCredentialAccessChecks(name, secretName string, secretExists bool) []credentialAccessCheck {
+	secretCheck := credentialAccessCheck{velero.VerbCreate, secretGVR, ""}
+	if secretExists {
+		secretCheck = credentialAccessCheck{velero.VerbUpdate, secretGVR, secretName}
+	}
+
+	return []credentialAccessCheck{{velero.VerbPatch, velero.BackupStorageLocationGVR, name}, secretCheck}
+}
+
+// getSecret returns the Secret, nil when it does not exist.
+func getSecret(ctx context.Context, secrets corev1client.SecretInterface, name string) (*v1.Secret, error) {
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	return secret, err
+}
+
+func (in *CredentialSpec) validate() error {
+	if in.Credentials == "" {
+		return errors.NewBadRequest("credentials are required")
+	}
+
+	if messages := validation.IsDNS1123Subdomain(in.SecretName); len(messages) > 0 {
+		return errors.NewBadRequest(fmt.Sprintf("invalid secret name %q: %s", in.SecretName, messages[0]))
+	}
+
+	return nil
+}
+
+// updateCredential stores the credentials and sets them as the credential of the storage location. The previous Secret
+// is nil when it does not exist yet.
+func updateCredential(ctx context.Context, k8sClient kubernetes.Interface, dynamicClient dynamic.Interface, namespace,
+	name string, spec *CredentialSpec, previous *v1.Secret, dryRun bool) (*Credential, error) {
+	locations := dynamicClient.Resource(velero.BackupStorageLocationGVR).Namespace(namespace)
+	if _, err := locations.Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	secrets := k8sClient.CoreV1().Secrets(namespace)
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"credential": map[string]interface{}{"name": spec.SecretName, "key": velero.CredentialsSecretKey},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := storeCredentials(ctx, secrets, previous, spec, true); err != nil {
+		return nil, fmt.Errorf("Failed to validate secret %s: %w", spec.SecretName, err)
+	}
+
+	if _, err := locations.Patch(ctx, name, k8stypes.MergePatchType, patch, velero.PatchOptions(true)); err != nil {
+		return nil, fmt.Errorf("Failed to validate storage location %s: %w", name, err)
+	}
+
+	result := &Credential{StorageLocation: name, Namespace: namespace, SecretName: spec.SecretName,
+		Key: velero.CredentialsSecretKey}
+	if dryRun {
+		return result, nil
+	}
+
+	stored, err := storeCredentials(ctx, secrets, previous, spec, false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to store credentials in secret %s: %w", spec.SecretName, err)
+	}
+
+	if _, err := locations.Patch(ctx, name, k8stypes.MergePatchType, patch, velero.PatchOptions(false)); err != nil {
+		if rollbackErr := restoreSecret(ctx, secrets, previous, stored); rollbackErr != nil {
+			return nil, fmt.Errorf("Failed to set credential of storage location %s: %s, and to restore secret %s: %s",
+				name, err.Error(), spec.SecretName, rollbackErr.Error())
+		}
+		return nil, fmt.Errorf("Failed to set credential of storage location %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// storeCredentials creates the Secret, or replaces the credentials key of an existing one, keeping its other keys.
+func storeCredentials(ctx context.Context, secrets corev1client.SecretInterface, previous *v1.Secret, spec *CredentialSpec,
+	dryRun bool) (*v1.Secret, error) {
+	if previous == nil {
+		return secrets.Create(ctx, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: spec.SecretName, Labels: map[string]string{"component": "velero"}},
+			Type:       v1.SecretTypeOpaque,
+			Data:       map[string][]byte{velero.CredentialsSecretKey: []byte(spec.Credentials)},
+		}, velero.CreateOptions(dryRun))
+	}
+
+	secret := previous.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[velero.CredentialsSecretKey] = []byte(spec.Credentials)
+	return secrets.Update(ctx, secret, velero.UpdateOptions(dryRun))
+}
+
+// restoreSecret undoes storeCredentials, deleting a created Secret or restoring the data of an updated one.
+func restoreSecret(ctx context.Context, secrets corev1client.SecretInterface, previous, stored *v1.Secret) error {
+	if previous == nil {
+		return secrets.Delete(ctx, stored.Name, velero.DeleteOptions(false))
+	}
+
+	secret := stored.DeepCopy()
+	secret.Data = previous.Data
+	_, err := secrets.Update(ctx, secret, velero.UpdateOptions(false))
+	return err
+}
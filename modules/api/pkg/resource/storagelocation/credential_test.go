@@ -0,0 +1,194 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagelocation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newTestStorageLocation(name string) *unstructured.Unstructured {
+	location := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"provider":      "aws",
+			"objectStorage": map[string]interface{}{"bucket": "backups"},
+		},
+	}}
+	location.SetAPIVersion("velero.io/v1")
+	location.SetKind("BackupStorageLocation")
+	location.SetNamespace("velero")
+	location.SetName(name)
+	return location
+}
+
+func newTestDynamicClient(objects ...runtime.Object) *fakedynamic.FakeDynamicClient {
+	return velerofake.NewDynamicClient(nil, objects...)
+}
+
+// honorDryRun keeps the fake clientset from persisting dry run requests, which they do not support.
+func honorDryRun(client *k8stesting.Fake) {
+	client.PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		switch action := action.(type) {
+		case k8stesting.CreateActionImpl:
+			return len(action.CreateOptions.DryRun) > 0, action.GetObject(), nil
+		case k8stesting.UpdateActionImpl:
+			return len(action.UpdateOptions.DryRun) > 0, action.GetObject(), nil
+		}
+		return false, nil, nil
+	})
+}
+
+func TestUpdateCredential(t *testing.T) {
+	existing := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-credentials", Namespace: "velero"},
+		Data:       map[string][]byte{"cloud": []byte("old"), "other": []byte("kept")},
+	}
+
+	cases := []struct {
+		info         string
+		secrets      []runtime.Object
+		dryRun       bool
+		failPatch    bool
+		expectedData map[string][]byte
+		credential   bool
+		err          bool
+	}{
+		{"new secret", nil, false, false, map[string][]byte{"cloud": []byte("new")}, true, false},
+		{"existing secret", []runtime.Object{existing}, false, false,
+			map[string][]byte{"cloud": []byte("new"), "other": []byte("kept")}, true, false},
+		{"dry run", []runtime.Object{existing}, true, false, existing.Data, false, false},
+		{"new secret rolled back", nil, false, true, nil, false, true},
+		{"existing secret rolled back", []runtime.Object{existing}, false, true, existing.Data, false, true},
+	}
+
+	for _, c := range cases {
+		k8sClient := fake.NewSimpleClientset(c.secrets...)
+		dynamicClient := newTestDynamicClient(newTestStorageLocation("default"))
+		honorDryRun(&k8sClient.Fake)
+
+		// The fake dynamic client drops patch options, so the dry run patch validating the change is told apart by
+		// being the first one.
+		patches := 0
+		dynamicClient.PrependReactor("patch", "backupstoragelocations", func(k8stesting.Action) (bool, runtime.Object, error) {
+			patches++
+			if patches == 1 {
+				return true, &unstructured.Unstructured{}, nil
+			}
+			if c.failPatch {
+				return true, nil, fmt.Errorf("admission webhook denied the request")
+			}
+			return false, nil, nil
+		})
+
+		spec := &CredentialSpec{SecretName: "cloud-credentials", Credentials: "new"}
+		previous, err := getSecret(context.TODO(), k8sClient.CoreV1().Secrets("velero"), spec.SecretName)
+		if err != nil {
+			t.Fatalf("%s: getSecret() returned error: %s", c.info, err.Error())
+		}
+
+		actual, err := updateCredential(context.TODO(), k8sClient, dynamicClient, "velero", "default", spec, previous, c.dryRun)
+		if (err != nil) != c.err {
+			t.Errorf("%s: updateCredential() returned error %v, expected error: %t", c.info, err, c.err)
+			continue
+		}
+
+		expected := &Credential{StorageLocation: "default", Namespace: "velero", SecretName: "cloud-credentials", Key: "cloud"}
+		if !c.err && !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: updateCredential() == \n%#v\nexpected \n%#v\n", c.info, actual, expected)
+		}
+
+		secret, err := k8sClient.CoreV1().Secrets("velero").Get(context.TODO(), "cloud-credentials", metav1.GetOptions{})
+		var data map[string][]byte
+		if err == nil {
+			data = secret.Data
+		}
+		if !reflect.DeepEqual(data, c.expectedData) {
+			t.Errorf("%s: secret data == %q, expected %q", c.info, data, c.expectedData)
+		}
+
+		location, _ := dynamicClient.Resource(velero.BackupStorageLocationGVR).Namespace("velero").
+			Get(context.TODO(), "default", metav1.GetOptions{})
+		_, found, _ := unstructured.NestedMap(location.Object, "spec", "credential")
+		if found != c.credential {
+			t.Errorf("%s: storage location credential set: %t, expected %t", c.info, found, c.credential)
+		}
+	}
+}
+
+func TestUpdateCredentialMissingStorageLocation(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset()
+	spec := &CredentialSpec{SecretName: "cloud-credentials", Credentials: "new"}
+
+	if _, err := updateCredential(context.TODO(), k8sClient, newTestDynamicClient(), "velero", "default", spec, nil, false); err == nil {
+		t.Errorf("updateCredential() of a missing storage location returned no error")
+	}
+
+	if secrets, _ := k8sClient.CoreV1().Secrets("velero").List(context.TODO(), metav1.ListOptions{}); len(secrets.Items) > 0 {
+		t.Errorf("updateCredential() of a missing storage location created secrets %#v", secrets.Items)
+	}
+}
+
+func TestGetCredentialAccessChecks(t *testing.T) {
+	cases := []struct {
+		secretExists bool
+		expected     []credentialAccessCheck
+	}{
+		{false, []credentialAccessCheck{
+			{velero.VerbPatch, velero.BackupStorageLocationGVR, "default"},
+			{velero.VerbCreate, secretGVR, ""},
+		}},
+		{true, []credentialAccessCheck{
+			{velero.VerbPatch, velero.BackupStorageLocationGVR, "default"},
+			{velero.VerbUpdate, secretGVR, "cloud-credentials"},
+		}},
+	}
+
+	for _, c := range cases {
+		actual := getCredentialAccessChecks("default", "cloud-credentials", c.secretExists)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getCredentialAccessChecks(%t) == %#v, expected %#v", c.secretExists, actual, c.expected)
+		}
+	}
+}
+
+func TestCredentialSpecValidate(t *testing.T) {
+	cases := []struct {
+		spec CredentialSpec
+		err  bool
+	}{
+		{CredentialSpec{SecretName: "cloud-credentials", Credentials: "[default]"}, false},
+		{CredentialSpec{SecretName: "cloud-credentials"}, true},
+		{CredentialSpec{SecretName: "Cloud_Credentials", Credentials: "[default]"}, true},
+	}
+
+	for _, c := range cases {
+		if err := c.spec.validate(); (err != nil) != c.err {
+			t.Errorf("validate(%#v) returned error %v, expected error: %t", c.spec, err, c.err)
+		}
+	}
+}
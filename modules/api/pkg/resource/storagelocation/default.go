@@ -0,0 +1,138 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagelocation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// DefaultStorageLocation tells which storage location stores backups created without one.
+type DefaultStorageLocation struct {
+	Namespace string `json:"namespace"`
+
+	// Name of the storage location marked as default. It is empty when none is, in which case Velero uses the one
+	// named by the --default-backup-storage-location argument of the server, "default" unless set.
+	Name string `json:"name"`
+
+	// Conflicting lists further storage locations marked as default. Velero picks one of them, so setting the
+	// default again is required to tell which.
+	Conflicting []string `json:"conflicting"`
+}
+
+// GetDefaultStorageLocation returns the storage location marked as default in the namespace.
+func GetDefaultStorageLocation(request *http.Request, namespace string) (*DefaultStorageLocation, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getDefaultStorageLocation(ctx, dynamicClient, velero.NamespaceOrDefault(request, namespace))
+}
+
+// SetDefaultStorageLocation marks the storage location as default and unmarks all others in its namespace, as Velero
+// expects a single default. The storage location is marked before the others are unmarked, so that there is a default
+// at any time. In dry run the patch of the storage location is only validated by the API server.
+func SetDefaultStorageLocation(request *http.Request, namespace, name string, dryRun bool) (*DefaultStorageLocation, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	if err := velero.CheckAccess(request, velero.VerbPatch, velero.BackupStorageLocationGVR, namespace, ""); err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return setDefaultStorageLocation(ctx, dynamicClient, namespace, name, dryRun)
+}
+
+func getDefaultStorageLocation(ctx context.Context, client dynamic.Interface, namespace string) (*DefaultStorageLocation, error) {
+	locations, err := client.Resource(velero.BackupStorageLocationGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toDefaultStorageLocation(namespace, locations.Items), nil
+}
+
+func setDefaultStorageLocation(ctx context.Context, client dynamic.Interface, namespace, name string, dryRun bool) (*DefaultStorageLocation, error) {
+	resource := client.Resource(velero.BackupStorageLocationGVR).Namespace(namespace)
+	if _, err := resource.Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	if err := patchDefault(ctx, resource, name, true, dryRun); err != nil {
+		return nil, fmt.Errorf("Failed to mark storage location %s as default: %w", name, err)
+	}
+
+	if dryRun {
+		return &DefaultStorageLocation{Namespace: namespace, Name: name, Conflicting: make([]string, 0)}, nil
+	}
+
+	current, err := getDefaultStorageLocation(ctx, client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, other := range append([]string{current.Name}, current.Conflicting...) {
+		if other == name {
+			continue
+		}
+		if err := patchDefault(ctx, resource, other, false, false); err != nil {
+			return nil, fmt.Errorf("Failed to unmark storage location %s as default: %w", other, err)
+		}
+	}
+
+	return getDefaultStorageLocation(ctx, client, namespace)
+}
+
+func patchDefault(ctx context.Context, resource dynamic.ResourceInterface, name string, isDefault, dryRun bool) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"default":%t}}`, isDefault))
+	_, err := resource.Patch(ctx, name, k8stypes.MergePatchType, patch, velero.PatchOptions(dryRun))
+	return err
+}
+
+func toDefaultStorageLocation(namespace string, locations []unstructured.Unstructured) *DefaultStorageLocation {
+	names := make([]string, 0)
+	for _, location := range locations {
+		if isDefault, _, _ := unstructured.NestedBool(location.Object, "spec", "default"); isDefault {
+			names = append(names, location.GetName())
+		}
+	}
+	sort.Strings(names)
+
+	result := &DefaultStorageLocation{Namespace: namespace, Conflicting: make([]string, 0)}
+	if len(names) > 0 {
+		result.Name = names[0]
+		result.Conflicting = names[1:]
+	}
+
+	return result
+}
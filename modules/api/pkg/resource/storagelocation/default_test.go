@@ -0,0 +1,83 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagelocation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTestDefaultStorageLocation(name string, isDefault bool) *unstructured.Unstructured {
+	location := newTestStorageLocation(name)
+	_ = unstructured.SetNestedField(location.Object, isDefault, "spec", "default")
+	return location
+}
+
+func TestGetDefaultStorageLocation(t *testing.T) {
+	cases := []struct {
+		info      string
+		locations []runtime.Object
+		expected  *DefaultStorageLocation
+	}{
+		{"no storage locations", nil, &DefaultStorageLocation{Namespace: "velero", Conflicting: []string{}}},
+		{
+			"single default",
+			[]runtime.Object{newTestDefaultStorageLocation("primary", true), newTestStorageLocation("secondary")},
+			&DefaultStorageLocation{Namespace: "velero", Name: "primary", Conflicting: []string{}},
+		},
+		{
+			"conflicting defaults",
+			[]runtime.Object{newTestDefaultStorageLocation("secondary", true), newTestDefaultStorageLocation("primary", true)},
+			&DefaultStorageLocation{Namespace: "velero", Name: "primary", Conflicting: []string{"secondary"}},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := getDefaultStorageLocation(context.TODO(), newTestDynamicClient(c.locations...), "velero")
+		if err != nil {
+			t.Errorf("%s: getDefaultStorageLocation() returned error: %s", c.info, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: getDefaultStorageLocation() == \n%#v\nexpected \n%#v\n", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestSetDefaultStorageLocation(t *testing.T) {
+	client := newTestDynamicClient(
+		newTestDefaultStorageLocation("primary", true),
+		newTestDefaultStorageLocation("secondary", true),
+		newTestStorageLocation("tertiary"),
+	)
+
+	actual, err := setDefaultStorageLocation(context.TODO(), client, "velero", "tertiary", false)
+	if err != nil {
+		t.Fatalf("setDefaultStorageLocation() returned error: %s", err.Error())
+	}
+
+	expected := &DefaultStorageLocation{Namespace: "velero", Name: "tertiary", Conflicting: []string{}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("setDefaultStorageLocation() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+
+	if _, err := setDefaultStorageLocation(context.TODO(), client, "velero", "missing", false); err == nil {
+		t.Errorf("setDefaultStorageLocation() of a missing storage location returned no error")
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagelocation
+
+import (
+	"context"
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// StorageLocationDetail contains the configuration and the state of a Velero backup storage location.
+type StorageLocationDetail struct {
+	StorageLocation `json:",inline"`
+
+	// Provider specific configuration, e.g. the region of the bucket.
+	Config map[string]string `json:"config,omitempty"`
+
+	// Secret key the credentials are read from, nil when Velero uses the credentials of its server.
+	Credential *v1.SecretKeySelector `json:"credential,omitempty"`
+
+	// How often Velero syncs backups from and validates the object storage, Velero defaults apply when empty.
+	BackupSyncPeriod    string `json:"backupSyncPeriod,omitempty"`
+	ValidationFrequency string `json:"validationFrequency,omitempty"`
+
+	LastSyncedTime *metav1.Time `json:"lastSyncedTime,omitempty"`
+
+	// Message explains why Velero found the storage location unavailable.
+	Message string `json:"message,omitempty"`
+}
+
+// GetStorageLocationDetail returns a backup storage location in the namespace.
+func GetStorageLocationDetail(request *http.Request, namespace, name string) (*StorageLocationDetail, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getStorageLocationDetail(ctx, dynamicClient, namespace, name)
+}
+
+func getStorageLocationDetail(ctx context.Context, client dynamic.Interface, namespace, name string) (*StorageLocationDetail, error) {
+	item, err := client.Resource(velero.BackupStorageLocationGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toStorageLocationDetail(*item), nil
+}
+
+func toStorageLocationDetail(item unstructured.Unstructured) *StorageLocationDetail {
+	config, _, _ := unstructured.NestedStringMap(item.Object, "spec", "config")
+	syncPeriod, _, _ := unstructured.NestedString(item.Object, "spec", "backupSyncPeriod")
+	validationFrequency, _, _ := unstructured.NestedString(item.Object, "spec", "validationFrequency")
+	message, _, _ := unstructured.NestedString(item.Object, "status", "message")
+
+	detail := &StorageLocationDetail{
+		StorageLocation:     toStorageLocation(item),
+		Config:              config,
+		BackupSyncPeriod:    syncPeriod,
+		ValidationFrequency: validationFrequency,
+		LastSyncedTime:      nestedTime(item.Object, "status", "lastSyncedTime"),
+		Message:             message,
+	}
+
+	if credential, ok, _ := unstructured.NestedMap(item.Object, "spec", "credential"); ok {
+		detail.Credential = &v1.SecretKeySelector{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(credential, detail.Credential); err != nil {
+			detail.Credential = nil
+		}
+	}
+
+	return detail
+}
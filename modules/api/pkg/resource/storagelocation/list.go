@@ -0,0 +1,118 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagelocation
+
+import (
+	"context"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+// StorageLocationPhase tells whether Velero could reach the object storage of a storage location when it last
+// validated it.
+type StorageLocationPhase string
+
+const (
+	StorageLocationPhaseAvailable   StorageLocationPhase = "Available"
+	StorageLocationPhaseUnavailable StorageLocationPhase = "Unavailable"
+)
+
+// StorageLocationList contains a list of Velero backup storage locations.
+type StorageLocationList struct {
+	ListMeta types.ListMeta    `json:"listMeta"`
+	Items    []StorageLocation `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// StorageLocation is a Velero backup storage location, the object storage bucket backups are stored in.
+type StorageLocation struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	Provider   string     `json:"provider"`
+	Bucket     string     `json:"bucket"`
+	Prefix     string     `json:"prefix,omitempty"`
+	AccessMode AccessMode `json:"accessMode"`
+	Default    bool       `json:"default"`
+
+	// Phase is empty until Velero validates the storage location for the first time.
+	Phase              StorageLocationPhase `json:"phase,omitempty"`
+	LastValidationTime *metav1.Time         `json:"lastValidationTime,omitempty"`
+}
+
+// GetStorageLocationList returns the backup storage locations in the namespaces matching the query.
+func GetStorageLocationList(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*StorageLocationList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getStorageLocationList(ctx, dynamicClient, namespace, dsQuery)
+}
+
+func getStorageLocationList(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*StorageLocationList, error) {
+	items, nonCriticalErrors, err := velero.ListInNamespaces(ctx, client, velero.BackupStorageLocationGVR, namespace,
+		metav1.ListOptions{}, args.VeleroMaxListItems())
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]StorageLocation, 0, len(items))
+	for _, item := range items {
+		locations = append(locations, toStorageLocation(item))
+	}
+
+	locationCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(locations), dsQuery)
+	return &StorageLocationList{
+		ListMeta: types.ListMeta{TotalItems: filteredTotal},
+		Items:    fromCells(locationCells),
+		Errors:   nonCriticalErrors,
+	}, nil
+}
+
+func toStorageLocation(item unstructured.Unstructured) StorageLocation {
+	provider, _, _ := unstructured.NestedString(item.Object, "spec", "provider")
+	bucket, _, _ := unstructured.NestedString(item.Object, "spec", "objectStorage", "bucket")
+	prefix, _, _ := unstructured.NestedString(item.Object, "spec", "objectStorage", "prefix")
+	isDefault, _, _ := unstructured.NestedBool(item.Object, "spec", "default")
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	return StorageLocation{
+		ObjectMeta: velero.NewObjectMeta(item.Object),
+		TypeMeta: types.TypeMeta{
+			Kind: "BackupStorageLocation",
+		},
+		Provider:           provider,
+		Bucket:             bucket,
+		Prefix:             prefix,
+		AccessMode:         toStorageLocationAccessMode(item).AccessMode,
+		Default:            isDefault,
+		Phase:              StorageLocationPhase(phase),
+		LastValidationTime: nestedTime(item.Object, "status", "lastValidationTime"),
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagelocation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+)
+
+func newTestStorageLocationWithStatus(name string, isDefault bool, phase StorageLocationPhase) *unstructured.Unstructured {
+	location := newTestStorageLocation(name)
+	_ = unstructured.SetNestedField(location.Object, isDefault, "spec", "default")
+	_ = unstructured.SetNestedField(location.Object, "cluster-a", "spec", "objectStorage", "prefix")
+	_ = unstructured.SetNestedField(location.Object, string(phase), "status", "phase")
+	return location
+}
+
+func TestGetStorageLocationList(t *testing.T) {
+	client := newTestDynamicClient(
+		newTestStorageLocationWithStatus("secondary", false, StorageLocationPhaseUnavailable),
+		newTestStorageLocationWithStatus("default", true, StorageLocationPhaseAvailable))
+
+	actual, err := getStorageLocationList(context.TODO(), client, common.NewNamespaceQuery([]string{"velero"}),
+		dataselect.NewDataSelectQuery(dataselect.NoPagination, dataselect.NewSortQuery([]string{"a", "name"}),
+			dataselect.NoFilter, dataselect.NoMetrics))
+	if err != nil {
+		t.Fatalf("getStorageLocationList() returned error: %s", err.Error())
+	}
+
+	if actual.ListMeta.TotalItems != 2 || len(actual.Items) != 2 {
+		t.Fatalf("getStorageLocationList() == %d of %d items, expected 2 of 2", len(actual.Items), actual.ListMeta.TotalItems)
+	}
+
+	first := actual.Items[0]
+	if first.ObjectMeta.Name != "default" || !first.Default || first.Provider != "aws" || first.Bucket != "backups" ||
+		first.Prefix != "cluster-a" || first.AccessMode != AccessModeReadWrite || first.Phase != StorageLocationPhaseAvailable {
+		t.Errorf("getStorageLocationList() items[0] == %#v, expected the available default location", first)
+	}
+
+	if second := actual.Items[1]; second.ObjectMeta.Name != "secondary" || second.Default {
+		t.Errorf("getStorageLocationList() items[1] == %#v, expected the secondary location", second)
+	}
+}
+
+func TestToStorageLocationDetail(t *testing.T) {
+	location := newTestStorageLocationWithStatus("default", true, StorageLocationPhaseUnavailable)
+	_ = unstructured.SetNestedStringMap(location.Object, map[string]string{"region": "eu-west-1"}, "spec", "config")
+	_ = unstructured.SetNestedMap(location.Object, map[string]interface{}{"name": "cloud-credentials", "key": "cloud"},
+		"spec", "credential")
+	_ = unstructured.SetNestedField(location.Object, "access denied", "status", "message")
+
+	actual := toStorageLocationDetail(*location)
+	expectedCredential := &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "cloud-credentials"}, Key: "cloud"}
+	if !reflect.DeepEqual(actual.Config, map[string]string{"region": "eu-west-1"}) ||
+		!reflect.DeepEqual(actual.Credential, expectedCredential) || actual.Message != "access denied" {
+		t.Errorf("toStorageLocationDetail() == %#v, expected config, credential and message of the location", actual)
+	}
+}
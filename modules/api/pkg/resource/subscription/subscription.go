@@ -0,0 +1,211 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package subscription shares watches of custom resources between clients that stream changes of the same list.
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+)
+
+// Event is sent to subscribers every time a watched resource is added, modified or deleted.
+type Event struct {
+	Type   watch.EventType `json:"type"`
+	Object interface{}     `json:"object"`
+}
+
+// Converter turns a watched resource into the object sent to subscribers.
+type Converter func(*unstructured.Unstructured) interface{}
+
+// InformerFactory creates an informer watching a custom resource in a namespace on behalf of the request.
+type InformerFactory func(request *http.Request, resource schema.GroupVersionResource, namespace string) (cache.SharedIndexInformer, error)
+
+// Hub starts a single watch for all subscribers of the same custom resource and namespace using the same
+// credentials and stops it once the last of them unsubscribes.
+type Hub struct {
+	mu          sync.Mutex
+	watches     map[watchKey]*sharedWatch
+	newInformer InformerFactory
+}
+
+type watchKey struct {
+	token     string
+	resource  schema.GroupVersionResource
+	namespace string
+}
+
+type sharedWatch struct {
+	informer    cache.SharedIndexInformer
+	stop        context.CancelFunc
+	subscribers int
+}
+
+// Subscription receives events of a shared watch until it is closed. Resources existing when subscribing are
+// sent as added first.
+type Subscription struct {
+	hub          *Hub
+	key          watchKey
+	namespace    *common.NamespaceQuery
+	convert      Converter
+	registration cache.ResourceEventHandlerRegistration
+	events       chan Event
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+var defaultHub = NewHub(newDynamicInformer)
+
+// NewHub creates a hub using the factory to start new watches.
+func NewHub(newInformer InformerFactory) *Hub {
+	return &Hub{
+		watches:     make(map[watchKey]*sharedWatch),
+		newInformer: newInformer,
+	}
+}
+
+// Subscribe subscribes to changes of the custom resource in namespaces matching the query using the default hub.
+func Subscribe(request *http.Request, resource schema.GroupVersionResource, namespace *common.NamespaceQuery, convert Converter) (*Subscription, error) {
+	return defaultHub.Subscribe(request, resource, namespace, convert)
+}
+
+// Subscribe subscribes to changes of the custom resource in namespaces matching the query. Errors starting the
+// watch, e.g. missing permissions, are returned right away.
+func (in *Hub) Subscribe(request *http.Request, resource schema.GroupVersionResource, namespace *common.NamespaceQuery, convert Converter) (*Subscription, error) {
+	key := watchKey{token: client.GetBearerToken(request), resource: resource, namespace: namespace.ToRequestParam()}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	shared, ok := in.watches[key]
+	if !ok {
+		informer, err := in.newInformer(request, resource, key.namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go informer.Run(ctx.Done())
+		shared = &sharedWatch{informer: informer, stop: cancel}
+		in.watches[key] = shared
+	}
+
+	subscription := &Subscription{
+		hub:       in,
+		key:       key,
+		namespace: namespace,
+		convert:   convert,
+		events:    make(chan Event),
+		done:      make(chan struct{}),
+	}
+
+	registration, err := shared.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			subscription.send(watch.Added, obj)
+		},
+		UpdateFunc: func(oldObj, obj interface{}) {
+			// Relisting reports unchanged resources as updated.
+			if oldItem, ok := oldObj.(*unstructured.Unstructured); ok {
+				if item, ok := obj.(*unstructured.Unstructured); ok && oldItem.GetResourceVersion() == item.GetResourceVersion() {
+					return
+				}
+			}
+			subscription.send(watch.Modified, obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			subscription.send(watch.Deleted, obj)
+		},
+	})
+	if err != nil {
+		if shared.subscribers == 0 {
+			shared.stop()
+			delete(in.watches, key)
+		}
+		return nil, err
+	}
+
+	subscription.registration = registration
+	shared.subscribers++
+	return subscription, nil
+}
+
+// Events returns the channel events are delivered on.
+func (in *Subscription) Events() <-chan Event {
+	return in.events
+}
+
+// Close unsubscribes. The shared watch is stopped when no other subscriber is left.
+func (in *Subscription) Close() {
+	in.closeOnce.Do(func() {
+		close(in.done)
+
+		in.hub.mu.Lock()
+		defer in.hub.mu.Unlock()
+
+		shared, ok := in.hub.watches[in.key]
+		if !ok {
+			return
+		}
+
+		_ = shared.informer.RemoveEventHandler(in.registration)
+		shared.subscribers--
+		if shared.subscribers == 0 {
+			shared.stop()
+			delete(in.hub.watches, in.key)
+		}
+	})
+}
+
+func (in *Subscription) send(eventType watch.EventType, obj interface{}) {
+	item, ok := obj.(*unstructured.Unstructured)
+	if !ok || !in.namespace.Matches(item.GetNamespace()) {
+		return
+	}
+
+	// Every handler gets its own buffer in the informer, so a slow subscriber does not hold others back.
+	select {
+	case in.events <- Event{Type: eventType, Object: in.convert(item)}:
+	case <-in.done:
+	}
+}
+
+func newDynamicInformer(request *http.Request, resource schema.GroupVersionResource, namespace string) (cache.SharedIndexInformer, error) {
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// The informer retries failed lists forever, so list once to report missing permissions to the subscriber.
+	options := metav1.ListOptions{Limit: 1}
+	if _, err := dynamicClient.Resource(resource).Namespace(namespace).List(request.Context(), options); err != nil {
+		return nil, err
+	}
+
+	return dynamicinformer.NewFilteredDynamicInformer(dynamicClient, resource, namespace, 0, cache.Indexers{}, nil).Informer(), nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+func newBackup(namespace, name string) *unstructured.Unstructured {
+	item := &unstructured.Unstructured{}
+	item.SetAPIVersion("velero.io/v1")
+	item.SetKind("Backup")
+	item.SetNamespace(namespace)
+	item.SetName(name)
+	return item
+}
+
+func receive(t *testing.T, subscription *Subscription) Event {
+	select {
+	case event := <-subscription.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no event received")
+		return Event{}
+	}
+}
+
+func TestHubSubscribe(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{velero.BackupGVR: "BackupList"}, newBackup("velero", "existing"))
+	watching := make(chan struct{})
+	var watchOnce sync.Once
+	client.PrependWatchReactor("backups", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watchOnce.Do(func() { close(watching) })
+		return false, nil, nil
+	})
+
+	informers := 0
+	hub := NewHub(func(_ *http.Request, resource schema.GroupVersionResource, namespace string) (cache.SharedIndexInformer, error) {
+		informers++
+		return dynamicinformer.NewFilteredDynamicInformer(client, resource, namespace, 0, cache.Indexers{}, nil).Informer(), nil
+	})
+	request := &http.Request{Header: http.Header{}}
+	namespace := common.NewNamespaceQuery([]string{"velero"})
+	toName := func(item *unstructured.Unstructured) interface{} { return item.GetName() }
+
+	first, err := hub.Subscribe(request, velero.BackupGVR, namespace, toName)
+	if err != nil {
+		t.Fatalf("Subscribe() returned error: %s", err.Error())
+	}
+	second, err := hub.Subscribe(request, velero.BackupGVR, namespace, toName)
+	if err != nil {
+		t.Fatalf("Subscribe() returned error: %s", err.Error())
+	}
+
+	if informers != 1 {
+		t.Errorf("Subscribe() started %d informers, expected 1", informers)
+	}
+
+	expected := Event{Type: watch.Added, Object: "existing"}
+	for _, subscription := range []*Subscription{first, second} {
+		if actual := receive(t, subscription); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("existing resource sent as %#v, expected %#v", actual, expected)
+		}
+	}
+
+	// The fake client drops changes made before the informer starts watching.
+	<-watching
+	_, err = client.Resource(velero.BackupGVR).Namespace("velero").Create(context.TODO(), newBackup("velero", "created"), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Create() returned error: %s", err.Error())
+	}
+	err = client.Resource(velero.BackupGVR).Namespace("velero").Delete(context.TODO(), "existing", metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Delete() returned error: %s", err.Error())
+	}
+
+	for _, expected := range []Event{{Type: watch.Added, Object: "created"}, {Type: watch.Deleted, Object: "existing"}} {
+		for _, subscription := range []*Subscription{first, second} {
+			if actual := receive(t, subscription); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("received %#v, expected %#v", actual, expected)
+			}
+		}
+	}
+
+	first.Close()
+	if len(hub.watches) != 1 {
+		t.Errorf("watch stopped while subscribed")
+	}
+	second.Close()
+	second.Close()
+	if len(hub.watches) != 0 {
+		t.Errorf("watch not stopped after all subscriptions were closed")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/client"
+)
+
+// Verbs of the Velero mutations checked with access reviews.
+const (
+	VerbCreate = "create"
+	VerbDelete = "delete"
+	VerbPatch  = "patch"
+	VerbUpdate = "update"
+)
+
+// mutations lists the verbs allowed on each Velero resource through the dashboard.
+var mutations = []struct {
+	resource schema.GroupVersionResource
+	verbs    []string
+}{
+	{BackupGVR, []string{VerbCreate, VerbDelete, VerbPatch}},
+	{RestoreGVR, []string{VerbCreate, VerbDelete, VerbPatch}},
+	{ScheduleGVR, []string{VerbCreate, VerbDelete, VerbPatch}},
+	{DeleteBackupRequestGVR, []string{VerbCreate, VerbDelete}},
+	{DownloadRequestGVR, []string{VerbDelete}},
+	{BackupStorageLocationGVR, []string{VerbPatch}},
+}
+
+// Access tells whether the user may run the verb on the Velero resource.
+type Access struct {
+	Verb      string `json:"verb"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	Allowed   bool   `json:"allowed"`
+
+	// Reason is the explanation of the authorizer, if it gave one.
+	Reason string `json:"reason,omitempty"`
+}
+
+// AccessList tells which Velero mutations the user may run in a namespace, so that UIs can hide the ones that would be
+// forbidden.
+type AccessList struct {
+	Namespace string   `json:"namespace"`
+	Items     []Access `json:"items"`
+}
+
+// GetAccessList reviews every Velero mutation of the dashboard in the namespace.
+func GetAccessList(request *http.Request, namespace string) (*AccessList, error) {
+	ctx, cancel := OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AccessList{Namespace: namespace, Items: make([]Access, 0)}
+	for _, mutation := range mutations {
+		for _, verb := range mutation.verbs {
+			access, err := reviewAccess(ctx, k8sClient, verb, mutation.resource, namespace, "")
+			if err != nil {
+				return nil, err
+			}
+			result.Items = append(result.Items, *access)
+		}
+	}
+
+	return result, nil
+}
+
+// CheckAccess returns a forbidden error naming the missing permission when the user may not run the verb on the
+// Velero resource, instead of the error of the API server call wrapped by the caller. When the review itself fails
+// its error is returned, so that the mutation is not started without knowing it is allowed.
+func CheckAccess(request *http.Request, verb string, resource schema.GroupVersionResource, namespace, name string) error {
+	ctx, cancel := OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return err
+	}
+
+	return checkAccess(ctx, k8sClient, verb, resource, namespace, name)
+}
+
+func checkAccess(ctx context.Context, client kubernetes.Interface, verb string, resource schema.GroupVersionResource, namespace, name string) error {
+	access, err := reviewAccess(ctx, client, verb, resource, namespace, name)
+	if err != nil {
+		return fmt.Errorf("Failed to review access to %s %s.%s: %w", verb, resource.Resource, resource.Group, err)
+	}
+
+	if access.Allowed {
+		return nil
+	}
+
+	scope := "cluster wide"
+	if namespace != "" {
+		scope = "in namespace " + namespace
+	}
+
+	message := fmt.Sprintf("missing permission to %s %s.%s %s", verb, resource.Resource, resource.Group, scope)
+	if access.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, access.Reason)
+	}
+
+	return k8serrors.NewForbidden(resource.GroupResource(), name, fmt.Errorf("%s", message))
+}
+
+func reviewAccess(ctx context.Context, client kubernetes.Interface, verb string, resource schema.GroupVersionResource, namespace, name string) (*Access, error) {
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Name:      name,
+				Verb:      verb,
+				Group:     resource.Group,
+				Resource:  resource.Resource,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Access{
+		Verb:      verb,
+		Resource:  resource.Resource,
+		Namespace: namespace,
+		Allowed:   review.Status.Allowed,
+		Reason:    review.Status.Reason,
+	}, nil
+}
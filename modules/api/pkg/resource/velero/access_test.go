@@ -0,0 +1,100 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newReviewClient returns a client answering access reviews with the given status, or failing them when err is set.
+func newReviewClient(allowed bool, reason string, err error) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if err != nil {
+			return true, nil, err
+		}
+
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: allowed, Reason: reason}
+		return true, review, nil
+	})
+
+	return client
+}
+
+func TestCheckAccess(t *testing.T) {
+	cases := []struct {
+		info      string
+		client    *fake.Clientset
+		forbidden bool
+		message   string
+	}{
+		{"allowed", newReviewClient(true, "", nil), false, ""},
+		{
+			"review failed",
+			newReviewClient(false, "", fmt.Errorf("connection refused")),
+			false,
+			"Failed to review access to delete backups.velero.io: connection refused",
+		},
+		{
+			"denied",
+			newReviewClient(false, "", nil),
+			true,
+			`backups.velero.io "daily" is forbidden: missing permission to delete backups.velero.io in namespace velero`,
+		},
+		{
+			"denied with reason",
+			newReviewClient(false, "no RBAC policy matched", nil),
+			true,
+			"in namespace velero: no RBAC policy matched",
+		},
+	}
+
+	for _, c := range cases {
+		err := checkAccess(context.TODO(), c.client, VerbDelete, BackupGVR, "velero", "daily")
+		if c.message == "" {
+			if err != nil {
+				t.Errorf("%s: checkAccess() returned error: %s", c.info, err.Error())
+			}
+			continue
+		}
+
+		if err == nil || k8serrors.IsForbidden(err) != c.forbidden || !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s: checkAccess() == %v, expected forbidden error: %t containing %q", c.info, err, c.forbidden,
+				c.message)
+		}
+	}
+}
+
+func TestReviewAccess(t *testing.T) {
+	access, err := reviewAccess(context.TODO(), newReviewClient(true, "", nil), VerbPatch, ScheduleGVR, "velero", "")
+	if err != nil {
+		t.Fatalf("reviewAccess() returned error: %s", err.Error())
+	}
+
+	expected := Access{Verb: VerbPatch, Resource: "schedules", Namespace: "velero", Allowed: true}
+	if *access != expected {
+		t.Errorf("reviewAccess() == %#v, expected %#v", *access, expected)
+	}
+}
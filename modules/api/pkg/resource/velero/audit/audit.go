@@ -0,0 +1,218 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records who created and deleted Velero resources through the dashboard. Created objects are
+// annotated with their creator and every action is recorded as an event of the object. Events are created by the
+// dashboard itself, so that users cannot skip them, and signed with the key of the velero-audit-key argument, so that
+// faked events can be told apart. The API server keeps them only for its event TTL, one hour by default.
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+const (
+	// CreatedByAnnotation is set on Velero objects created through the dashboard to the user who created them.
+	CreatedByAnnotation = "dashboard.kubernetes.io/created-by"
+	// SubjectAnnotation is set on audit events to the user who acted, as events have no field for it.
+	SubjectAnnotation = "dashboard.kubernetes.io/subject"
+	// SignatureAnnotation is set on audit events to their signature, telling them apart from events faked by users
+	// who can create events.
+	SignatureAnnotation = "dashboard.kubernetes.io/signature"
+
+	// Component is the source component of audit events.
+	Component = "kubernetes-dashboard"
+
+	// UnknownSubject is recorded when the API server cannot tell who the user is, e.g. before Kubernetes 1.28.
+	UnknownSubject = "unknown"
+
+	// Retention tells how long recorded actions are listed, as the API server does not keep events for longer.
+	Retention = "Actions are recorded as events, which the API server deletes after its event TTL, one hour by default."
+
+	// reasonPrefix prefixes the action in the reason of audit events, e.g. "DashboardCreated".
+	reasonPrefix = "Dashboard"
+)
+
+// Action is an action on a Velero resource recorded by the audit.
+type Action string
+
+const (
+	ActionCreated Action = "Created"
+	ActionDeleted Action = "Deleted"
+)
+
+// GetSubject returns the name of the user the request is authenticated as, or UnknownSubject when it cannot be
+// reviewed.
+func GetSubject(request *http.Request) string {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return UnknownSubject
+	}
+
+	return getSubject(ctx, k8sClient)
+}
+
+func getSubject(ctx context.Context, client kubernetes.Interface) string {
+	review, err := client.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{},
+		metav1.CreateOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Could not review the user of the request")
+		return UnknownSubject
+	}
+
+	if review.Status.UserInfo.Username == "" {
+		return UnknownSubject
+	}
+
+	return review.Status.UserInfo.Username
+}
+
+// SetCreatedBy annotates the object with the user of the request before it is created, keeping other annotations.
+func SetCreatedBy(request *http.Request, object metav1.Object) {
+	SetCreatedByUser(object, GetSubject(request))
+}
+
+// SetCreatedByUser annotates the object with the subject before it is created, keeping other annotations.
+func SetCreatedByUser(object metav1.Object, subject string) {
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[CreatedByAnnotation] = subject
+	object.SetAnnotations(annotations)
+}
+
+// Record records the action on the object of the kind as an event of the object. The user of a creation is
+// taken from the annotation set by SetCreatedBy, the user of other actions is reviewed. The event is created with the
+// service account of the dashboard, so that actions of users who cannot create events are recorded too. Nothing is
+// recorded in dry run. The action already happened, so an error recording it is meant to be returned as a
+// non-critical error along the result.
+func Record(request *http.Request, action Action, kind schema.GroupVersionKind, meta types.ObjectMeta, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	subject := meta.Annotations[CreatedByAnnotation]
+	if action != ActionCreated || subject == "" {
+		subject = GetSubject(request)
+	}
+
+	k8sClient := client.InClusterClient()
+	if k8sClient == nil {
+		return toRecordError(action, kind, meta, fmt.Errorf("client package not initialized"))
+	}
+
+	if err := record(ctx, k8sClient, signingKey(), action, kind, meta, subject, time.Now()); err != nil {
+		return toRecordError(action, kind, meta, err)
+	}
+
+	return nil
+}
+
+func toRecordError(action Action, kind schema.GroupVersionKind, meta types.ObjectMeta, err error) error {
+	return errors.NewInternal(fmt.Sprintf("Failed to record that %s %s was %s in the audit log: %s", kind.Kind, meta.Name,
+		strings.ToLower(string(action)), err.Error()))
+}
+
+// signingKey returns the key audit events are signed with, nil when the velero-audit-key argument is not set.
+func signingKey() []byte {
+	if args.VeleroAuditKey() == "" {
+		return nil
+	}
+
+	return []byte(args.VeleroAuditKey())
+}
+
+// sign returns the signature of the event by the key, empty without a key. The event name is signed along the
+// action, so that a signed event cannot be copied to another object or namespace.
+func sign(key []byte, event *v1.Event) string {
+	if len(key) == 0 {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, key)
+	for _, value := range []string{event.Namespace, event.Name, event.Reason, event.InvolvedObject.Kind,
+		event.InvolvedObject.Name, event.Annotations[SubjectAnnotation], event.LastTimestamp.UTC().Format(time.RFC3339)} {
+		mac.Write([]byte(value))
+		mac.Write([]byte{0})
+	}
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify tells whether the event is signed by the key.
+func verify(key []byte, event *v1.Event) bool {
+	signature := event.Annotations[SignatureAnnotation]
+	expected := sign(key, event)
+	return expected != "" && hmac.Equal([]byte(signature), []byte(expected))
+}
+
+func record(ctx context.Context, client kubernetes.Interface, key []byte, action Action, kind schema.GroupVersionKind, meta types.ObjectMeta, subject string, now time.Time) error {
+	timestamp := metav1.NewTime(now)
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s.%x", meta.Name, now.UnixNano()),
+			Namespace:   meta.Namespace,
+			Annotations: map[string]string{SubjectAnnotation: subject},
+		},
+		InvolvedObject: v1.ObjectReference{
+			APIVersion: kind.GroupVersion().String(),
+			Kind:       kind.Kind,
+			Namespace:  meta.Namespace,
+			Name:       meta.Name,
+			UID:        meta.UID,
+		},
+		Reason:              reasonPrefix + string(action),
+		Message:             fmt.Sprintf("%s by %s through the dashboard", action, subject),
+		Type:                v1.EventTypeNormal,
+		Source:              v1.EventSource{Component: Component},
+		ReportingController: Component,
+		FirstTimestamp:      timestamp,
+		LastTimestamp:       timestamp,
+		Count:               1,
+	}
+	if signature := sign(key, event); signature != "" {
+		event.Annotations[SignatureAnnotation] = signature
+	}
+
+	_, err := client.CoreV1().Events(meta.Namespace).Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
@@ -0,0 +1,168 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"k8s.io/dashboard/types"
+)
+
+func TestGetSubject(t *testing.T) {
+	cases := []struct {
+		info     string
+		username string
+		err      error
+		expected string
+	}{
+		{"returns the reviewed user", "alice", nil, "alice"},
+		{"returns unknown without a user", "", nil, UnknownSubject},
+		{"returns unknown when the review fails", "", errors.New("not found"), UnknownSubject},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "selfsubjectreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			review := &authenticationv1.SelfSubjectReview{}
+			review.Status.UserInfo.Username = c.username
+			return true, review, c.err
+		})
+
+		actual := getSubject(context.TODO(), client)
+		if actual != c.expected {
+			t.Errorf("%s: getSubject() returned %q, expected %q", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestSetCreatedBy(t *testing.T) {
+	object := &unstructured.Unstructured{}
+	object.SetAnnotations(map[string]string{"dashboard.kubernetes.io/slo-target": "0.99"})
+
+	SetCreatedByUser(object, "alice")
+
+	expected := map[string]string{"dashboard.kubernetes.io/slo-target": "0.99", CreatedByAnnotation: "alice"}
+	if !reflect.DeepEqual(object.GetAnnotations(), expected) {
+		t.Errorf("SetCreatedByUser() set annotations %v, expected %v", object.GetAnnotations(), expected)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	meta := types.ObjectMeta{Namespace: "velero", Name: "daily", UID: "uid-1"}
+
+	kind := schema.GroupVersionKind{Group: "velero.io", Version: "v1", Kind: "Schedule"}
+	if err := record(context.TODO(), client, []byte("key"), ActionDeleted, kind, meta, "alice", now); err != nil {
+		t.Fatalf("record() returned error: %s", err.Error())
+	}
+
+	events, err := client.CoreV1().Events("velero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(events.Items) != 1 {
+		t.Fatalf("record() created %v events, expected 1 (error: %v)", events, err)
+	}
+
+	entry, ok := toEntry(toUnstructured(t, &events.Items[0]), []byte("key"))
+	expected := Entry{
+		Action:    ActionDeleted,
+		Subject:   "alice",
+		Time:      metav1.NewTime(now),
+		Kind:      "Schedule",
+		Namespace: "velero",
+		Name:      "daily",
+		Verified:  true,
+	}
+	if !ok || !reflect.DeepEqual(entry, expected) {
+		t.Errorf("record() recorded %#v, expected %#v", entry, expected)
+	}
+	if events.Items[0].InvolvedObject.UID != "uid-1" || events.Items[0].InvolvedObject.APIVersion != "velero.io/v1" {
+		t.Errorf("record() recorded involved object %#v, expected the Velero schedule", events.Items[0].InvolvedObject)
+	}
+
+	kind = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	meta = types.ObjectMeta{Namespace: "policies", Name: "skip-large-volumes"}
+	if err := record(context.TODO(), client, nil, ActionCreated, kind, meta, "alice", now); err != nil {
+		t.Fatalf("record() returned error: %s", err.Error())
+	}
+
+	events, err = client.CoreV1().Events("policies").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(events.Items) != 1 {
+		t.Fatalf("record() created %v events, expected 1 (error: %v)", events, err)
+	}
+	if object := events.Items[0].InvolvedObject; object.APIVersion != "v1" || object.Kind != "ConfigMap" {
+		t.Errorf("record() recorded involved object %#v, expected the ConfigMap", object)
+	}
+	if _, ok := events.Items[0].Annotations[SignatureAnnotation]; ok {
+		t.Errorf("record() signed the event without a key")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key := []byte("key")
+	event := newEvent("nightly.1", "DashboardDeleted", Component, "alice", time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+	event.Annotations[SignatureAnnotation] = sign(key, event)
+
+	changedSubject := event.DeepCopy()
+	changedSubject.Annotations[SubjectAnnotation] = "bob"
+	copied := event.DeepCopy()
+	copied.Namespace = "other"
+	unsigned := event.DeepCopy()
+	delete(unsigned.Annotations, SignatureAnnotation)
+
+	cases := []struct {
+		info     string
+		event    *v1.Event
+		key      []byte
+		expected bool
+	}{
+		{"signed event", event, key, true},
+		{"other key", event, []byte("other"), false},
+		{"without key", event, nil, false},
+		{"changed subject", changedSubject, key, false},
+		{"copied to another namespace", copied, key, false},
+		{"unsigned event", unsigned, key, false},
+	}
+
+	for _, c := range cases {
+		if actual := verify(c.key, c.event); actual != c.expected {
+			t.Errorf("%s: verify() == %t, expected %t", c.info, actual, c.expected)
+		}
+	}
+}
+
+func toUnstructured(t *testing.T, object runtime.Object) unstructured.Unstructured {
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		t.Fatalf("could not convert %#v: %s", object, err.Error())
+	}
+
+	result := unstructured.Unstructured{Object: raw}
+	result.SetAPIVersion("v1")
+	result.SetKind("Event")
+	return result
+}
@@ -0,0 +1,137 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+// eventGVR is the resource of the events actions are recorded as.
+var eventGVR = schema.GroupVersionResource{Version: "v1", Resource: "events"}
+
+// Entry is an action on a Velero resource taken through the dashboard.
+type Entry struct {
+	Action  Action      `json:"action"`
+	Subject string      `json:"subject"`
+	Time    metav1.Time `json:"time"`
+
+	// Kind, Namespace and Name identify the Velero object acted on.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Verified tells that the entry is signed with the audit key of the dashboard. Unverified entries were recorded
+	// without or before a change of the key, or faked by a user who can create events.
+	Verified bool `json:"verified"`
+}
+
+// Log contains the recorded actions that are still kept by the API server.
+type Log struct {
+	ListMeta types.ListMeta `json:"listMeta"`
+	Items    []Entry        `json:"items"`
+
+	// Retention tells that older actions are not listed anymore.
+	Retention string `json:"retention"`
+
+	// Signed tells that the dashboard has an audit key to sign and verify entries with. Without it no entry is
+	// verified.
+	Signed bool `json:"signed"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetLog returns the actions recorded in the namespaces of the query. Entries are only returned as long as the API
+// server keeps their events, which the Retention of the log tells.
+func GetLog(request *http.Request, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*Log, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getLog(ctx, dynamicClient, signingKey(), namespace, dsQuery)
+}
+
+func getLog(ctx context.Context, client dynamic.Interface, key []byte, namespace *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*Log, error) {
+	items, nonCriticalErrors, err := velero.ListInNamespaces(ctx, client, eventGVR, namespace,
+		metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("source", Component).String()},
+		args.VeleroMaxListItems())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0)
+	for _, item := range items {
+		if entry, ok := toEntry(item, key); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(entries), dsQuery)
+	return &Log{
+		ListMeta:  types.ListMeta{TotalItems: filteredTotal},
+		Items:     fromCells(cells),
+		Retention: Retention,
+		Signed:    len(key) > 0,
+		Errors:    nonCriticalErrors,
+	}, nil
+}
+
+// toEntry returns the action recorded by the event, if it is an audit event. The field selector of the list is not
+// relied on, as not every API server honors it. The entry is verified when the event is signed by the key.
+func toEntry(item unstructured.Unstructured, key []byte) (Entry, bool) {
+	event := v1.Event{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &event); err != nil {
+		return Entry{}, false
+	}
+
+	if event.Source.Component != Component || !strings.HasPrefix(event.Reason, reasonPrefix) {
+		return Entry{}, false
+	}
+
+	subject := event.Annotations[SubjectAnnotation]
+	if subject == "" {
+		subject = UnknownSubject
+	}
+
+	return Entry{
+		Action:    Action(strings.TrimPrefix(event.Reason, reasonPrefix)),
+		Subject:   subject,
+		Time:      event.LastTimestamp,
+		Kind:      event.InvolvedObject.Kind,
+		Namespace: event.InvolvedObject.Namespace,
+		Name:      event.InvolvedObject.Name,
+		Verified:  verify(key, &event),
+	}, true
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newEvent(name, reason, component, subject string, time time.Time) *v1.Event {
+	return &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "velero",
+			Name:        name,
+			Annotations: map[string]string{SubjectAnnotation: subject},
+		},
+		InvolvedObject: v1.ObjectReference{APIVersion: "velero.io/v1", Kind: "Backup", Namespace: "velero", Name: "nightly"},
+		Reason:         reason,
+		Source:         v1.EventSource{Component: component},
+		LastTimestamp:  metav1.NewTime(time),
+	}
+}
+
+func TestGetLog(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	created := toUnstructured(t, newEvent("nightly.1", "DashboardCreated", Component, "alice", now))
+	deleted := toUnstructured(t, newEvent("nightly.2", "DashboardDeleted", Component, "", now.Add(time.Hour)))
+	completed := toUnstructured(t, newEvent("nightly.3", "Completed", "velero-backup-controller", "", now))
+	other := toUnstructured(t, newEvent("nightly.4", "Scheduled", Component, "", now))
+	signedEvent := newEvent("nightly.5", "DashboardDeleted", Component, "bob", now.Add(2*time.Hour))
+	signedEvent.Annotations[SignatureAnnotation] = sign([]byte("key"), signedEvent)
+	signed := toUnstructured(t, signedEvent)
+
+	client := velerofake.NewDynamicClient(map[schema.GroupVersionResource]string{eventGVR: "EventList"},
+		&created, &deleted, &completed, &other, &signed)
+
+	actual, err := getLog(context.TODO(), client, []byte("key"), common.NewSameNamespaceQuery("velero"), dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("getLog() returned error: %s", err.Error())
+	}
+
+	expected := []Entry{
+		{Action: ActionCreated, Subject: "alice", Time: metav1.NewTime(now), Kind: "Backup", Namespace: "velero", Name: "nightly"},
+		{Action: ActionDeleted, Subject: UnknownSubject, Time: metav1.NewTime(now.Add(time.Hour)), Kind: "Backup",
+			Namespace: "velero", Name: "nightly"},
+		{Action: ActionDeleted, Subject: "bob", Time: metav1.NewTime(now.Add(2 * time.Hour)), Kind: "Backup",
+			Namespace: "velero", Name: "nightly", Verified: true},
+	}
+	if !reflect.DeepEqual(actual.Items, expected) || actual.ListMeta.TotalItems != len(expected) {
+		t.Errorf("getLog() returned %#v, expected %#v", actual.Items, expected)
+	}
+	if actual.Retention != Retention || !actual.Signed {
+		t.Errorf("getLog() returned retention %q, signed %t, expected %q, signed", actual.Retention, actual.Signed,
+			Retention)
+	}
+}
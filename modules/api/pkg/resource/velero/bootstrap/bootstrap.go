@@ -0,0 +1,264 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bootstrap installs Velero from the dashboard, so that backups can be set up without the velero CLI or Helm.
+// The objects created are the ones `velero install` creates for a server without node-agent.
+package bootstrap
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+)
+
+// crdEstablishedTimeout is how long the backup storage location CRD may take to be served after it was applied.
+const crdEstablishedTimeout = 30 * time.Second
+
+// storageLocationCRDName is the CRD the storage location can only be applied with once it is established.
+var storageLocationCRDName = velero.BackupStorageLocationGVR.GroupResource().String()
+
+// anyResource matches every resource of every API group. Only cluster admins may run any verb on it.
+var anyResource = schema.GroupVersionResource{Group: "*", Resource: "*"}
+
+// Spec describes the Velero installation to bootstrap.
+type Spec struct {
+	// Namespace Velero is installed in. Defaults to velero.DefaultNamespace.
+	Namespace string `json:"namespace"`
+
+	// Image of the Velero server, e.g. velero/velero:v1.14.0.
+	Image string `json:"image"`
+
+	// Plugins are images of the object store and volume snapshotter plugins, e.g.
+	// velero/velero-plugin-for-aws:v1.10.0.
+	Plugins []string `json:"plugins"`
+
+	// CRDs are the manifests of the Velero CRDs matching the image, e.g. the output of
+	// `velero install --crds-only --dry-run -o yaml`. They are only required when Velero is not installed yet.
+	CRDs string `json:"crds"`
+
+	StorageLocation StorageLocationSpec `json:"storageLocation"`
+
+	// Credentials are the contents of the credentials file of the object storage provider. They are stored in the
+	// velero.CredentialsSecretName Secret and never returned. Leave empty to authenticate with e.g. workload identity.
+	Credentials string `json:"credentials"`
+}
+
+// StorageLocationSpec describes the default backup storage location of the installation.
+type StorageLocationSpec struct {
+	// Provider is the name of the object store plugin, e.g. aws.
+	Provider string `json:"provider"`
+	Bucket   string `json:"bucket"`
+	Prefix   string `json:"prefix"`
+
+	// Config is passed to the object store plugin, e.g. the region of the bucket.
+	Config map[string]string `json:"config"`
+}
+
+// Result lists the objects applied for the installation, or the objects that would be applied in dry run.
+type Result struct {
+	Namespace string          `json:"namespace"`
+	Items     []AppliedObject `json:"items"`
+}
+
+// AppliedObject is an object applied for the installation.
+type AppliedObject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Bootstrap installs Velero as described by the spec. Objects are applied with server-side apply, so that running it
+// again updates the installation. Only cluster admins may bootstrap, as the Velero server is bound to the
+// cluster-admin role. In dry run the spec is validated and the objects that would be applied are returned, since most
+// of them can not be validated by the API server before the namespace and CRDs exist.
+func Bootstrap(request *http.Request, spec *Spec, dryRun bool) (*Result, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	if spec.Namespace == "" {
+		spec.Namespace = velero.DefaultNamespace
+	}
+
+	status, err := velero.GetInstallStatus(request)
+	if err != nil {
+		return nil, err
+	}
+
+	crds, err := decodeCRDs(spec.CRDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := spec.validate(status.Installed || len(crds) > 0); err != nil {
+		return nil, err
+	}
+
+	if err := velero.CheckAccess(request, "*", anyResource, "", ""); err != nil {
+		return nil, err
+	}
+
+	objects, err := buildObjects(spec)
+	if err != nil {
+		return nil, err
+	}
+	objects = append(crds, objects...)
+
+	result := &Result{Namespace: spec.Namespace, Items: make([]AppliedObject, 0, len(objects))}
+	if dryRun {
+		for _, object := range objects {
+			result.Items = append(result.Items, toAppliedObject(object.object))
+		}
+		return result, nil
+	}
+
+	dynamicClient, err := client.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	apiextensionsClient, err := client.APIExtensionsClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, object := range objects {
+		if object.resource == velero.BackupStorageLocationGVR {
+			if err := waitForEstablished(ctx, apiextensionsClient, storageLocationCRDName); err != nil {
+				return nil, fmt.Errorf("Failed to wait for CRD %s after applying %d of %d objects: %w",
+					storageLocationCRDName, i, len(objects), err)
+			}
+		}
+
+		applied, err := apply(ctx, dynamicClient, object)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to apply %s %s after applying %d of %d objects: %w",
+				object.object.GetKind(), object.object.GetName(), i, len(objects), err)
+		}
+		result.Items = append(result.Items, toAppliedObject(applied))
+	}
+
+	return result, nil
+}
+
+func (in *Spec) validate(crdsAvailable bool) error {
+	if in.Image == "" {
+		return errors.NewBadRequest("image of the Velero server is required")
+	}
+
+	if in.StorageLocation.Provider == "" || in.StorageLocation.Bucket == "" {
+		return errors.NewBadRequest("provider and bucket of the storage location are required")
+	}
+
+	// Plugins are installed by init containers named after their images, which have to be unique in the pod.
+	plugins := make(map[string]string, len(in.Plugins))
+	for _, image := range in.Plugins {
+		name := toContainerName(image)
+		if other, ok := plugins[name]; ok {
+			return errors.NewBadRequest(fmt.Sprintf("plugins %s and %s are the same plugin %s, only one can be installed",
+				other, image, name))
+		}
+		plugins[name] = image
+	}
+
+	if !crdsAvailable {
+		return errors.NewBadRequest("Velero is not installed, CRDs are required, e.g. the output of " +
+			"`velero install --crds-only --dry-run -o yaml`")
+	}
+
+	return nil
+}
+
+// decodeCRDs decodes the CRD manifests, accepting only CRDs of the Velero API group.
+func decodeCRDs(content string) ([]object, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096)
+	result := make([]object, 0)
+	for {
+		crd := &unstructured.Unstructured{}
+		if err := decoder.Decode(&crd.Object); err != nil {
+			if stderrors.Is(err, io.EOF) {
+				break
+			}
+			return nil, errors.NewBadRequest(fmt.Sprintf("CRD document %d: %s", len(result)+1, err.Error()))
+		}
+
+		// Empty documents, e.g. after a trailing separator, are skipped.
+		if len(crd.Object) == 0 {
+			continue
+		}
+
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		if crd.GetKind() != "CustomResourceDefinition" || group != velero.GroupName {
+			return nil, errors.NewBadRequest(fmt.Sprintf("CRD document %d: %s %s is not a CRD of the %s group",
+				len(result)+1, crd.GetKind(), crd.GetName(), velero.GroupName))
+		}
+
+		unstructured.RemoveNestedField(crd.Object, "status")
+		unstructured.RemoveNestedField(crd.Object, "metadata", "creationTimestamp")
+		result = append(result, object{resource: customResourceDefinitionGVR, object: crd})
+	}
+
+	return result, nil
+}
+
+func apply(ctx context.Context, dynamicClient dynamic.Interface, object object) (*unstructured.Unstructured, error) {
+	resource := dynamicClient.Resource(object.resource)
+	if namespace := object.object.GetNamespace(); namespace != "" {
+		return resource.Namespace(namespace).Apply(ctx, object.object.GetName(), object.object, velero.ApplyOptions(false))
+	}
+
+	return resource.Apply(ctx, object.object.GetName(), object.object, velero.ApplyOptions(false))
+}
+
+// waitForEstablished waits until the API server serves the resources of the CRD, so that objects of it can be applied.
+func waitForEstablished(ctx context.Context, apiextensionsClient apiextensionsclientset.Interface, name string) error {
+	return wait.PollUntilContextTimeout(ctx, time.Second, crdEstablishedTimeout, true, func(ctx context.Context) (bool, error) {
+		crd, err := apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		for _, condition := range crd.Status.Conditions {
+			if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+}
+
+func toAppliedObject(object *unstructured.Unstructured) AppliedObject {
+	return AppliedObject{Kind: object.GetKind(), Name: object.GetName(), Namespace: object.GetNamespace()}
+}
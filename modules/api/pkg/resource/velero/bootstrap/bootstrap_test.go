@@ -0,0 +1,200 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+func TestDecodeCRDs(t *testing.T) {
+	cases := []struct {
+		info     string
+		content  string
+		expected []string
+		err      bool
+	}{
+		{"no CRDs", "", []string{}, false},
+		{
+			"Velero CRDs",
+			`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: backups.velero.io
+  creationTimestamp: null
+spec:
+  group: velero.io
+status: {}
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: backupstoragelocations.velero.io
+spec:
+  group: velero.io
+---
+`,
+			[]string{"backups.velero.io", "backupstoragelocations.velero.io"},
+			false,
+		},
+		{
+			"CRD of another group",
+			`{"apiVersion": "apiextensions.k8s.io/v1", "kind": "CustomResourceDefinition", "metadata": {"name": "widgets.example.com"}, "spec": {"group": "example.com"}}`,
+			nil,
+			true,
+		},
+		{
+			"not a CRD",
+			`{"apiVersion": "velero.io/v1", "kind": "Backup", "metadata": {"name": "nightly"}, "spec": {"group": "velero.io"}}`,
+			nil,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		objects, err := decodeCRDs(c.content)
+		if (err != nil) != c.err {
+			t.Errorf("%s: decodeCRDs() returned error %v, expected error: %t", c.info, err, c.err)
+			continue
+		}
+		if c.err {
+			continue
+		}
+
+		names := make([]string, 0)
+		for _, object := range objects {
+			if object.resource != customResourceDefinitionGVR {
+				t.Errorf("%s: decodeCRDs() resource == %#v, expected %#v", c.info, object.resource, customResourceDefinitionGVR)
+			}
+			if _, found := object.object.Object["status"]; found {
+				t.Errorf("%s: decodeCRDs() kept status of %s", c.info, object.object.GetName())
+			}
+			names = append(names, object.object.GetName())
+		}
+		if !reflect.DeepEqual(names, c.expected) {
+			t.Errorf("%s: decodeCRDs() == %#v, expected %#v", c.info, names, c.expected)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Spec{Image: "velero/velero:v1.14.0", StorageLocation: StorageLocationSpec{Provider: "aws", Bucket: "backups"}}
+	cases := []struct {
+		info          string
+		spec          Spec
+		crdsAvailable bool
+		err           bool
+	}{
+		{"valid", valid, true, false},
+		{"missing CRDs", valid, false, true},
+		{"missing image", Spec{StorageLocation: valid.StorageLocation}, true, true},
+		{"missing bucket", Spec{Image: valid.Image, StorageLocation: StorageLocationSpec{Provider: "aws"}}, true, true},
+		{"plugins", Spec{Image: valid.Image, StorageLocation: valid.StorageLocation,
+			Plugins: []string{"velero/velero-plugin-for-aws:v1.10.0", "velero/velero-plugin-for-gcp:v1.10.0"}}, true, false},
+		{"same plugin twice", Spec{Image: valid.Image, StorageLocation: valid.StorageLocation,
+			Plugins: []string{"velero/velero-plugin-for-aws:v1.10.0", "mirror.local/velero-plugin-for-aws:v1.9.0"}}, true, true},
+	}
+
+	for _, c := range cases {
+		err := c.spec.validate(c.crdsAvailable)
+		if (err != nil) != c.err {
+			t.Errorf("%s: validate() returned error %v, expected error: %t", c.info, err, c.err)
+		}
+	}
+}
+
+func TestBuildObjects(t *testing.T) {
+	spec := &Spec{
+		Namespace:   "backup-system",
+		Image:       "velero/velero:v1.14.0",
+		Plugins:     []string{"velero/velero-plugin-for-aws:v1.10.0"},
+		Credentials: "[default]\naws_access_key_id=key\n",
+		StorageLocation: StorageLocationSpec{
+			Provider: "aws", Bucket: "backups", Prefix: "cluster-a", Config: map[string]string{"region": "eu-west-1"},
+		},
+	}
+
+	objects, err := buildObjects(spec)
+	if err != nil {
+		t.Fatalf("buildObjects() returned unexpected error: %s", err.Error())
+	}
+
+	kinds := make([]string, 0)
+	for _, object := range objects {
+		kinds = append(kinds, object.object.GetKind()+"/"+object.object.GetNamespace()+"/"+object.object.GetName())
+		if _, found := object.object.Object["status"]; found {
+			t.Errorf("buildObjects() kept status of %s", object.object.GetKind())
+		}
+	}
+	expected := []string{
+		"Namespace//backup-system",
+		"ServiceAccount/backup-system/velero",
+		"ClusterRoleBinding//velero-backup-system",
+		"Secret/backup-system/cloud-credentials",
+		"Deployment/backup-system/velero",
+		"BackupStorageLocation/backup-system/default",
+	}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("buildObjects() == \n%#v\nexpected \n%#v\n", kinds, expected)
+	}
+
+	initContainers, _, _ := unstructured.NestedSlice(objects[4].object.Object, "spec", "template", "spec", "initContainers")
+	if len(initContainers) != 1 || initContainers[0].(map[string]interface{})["name"] != "velero-plugin-for-aws" {
+		t.Errorf("buildObjects() init containers == %#v", initContainers)
+	}
+
+	location, _, _ := unstructured.NestedMap(objects[5].object.Object, "spec")
+	expectedLocation := map[string]interface{}{
+		"provider":      "aws",
+		"default":       true,
+		"objectStorage": map[string]interface{}{"bucket": "backups", "prefix": "cluster-a"},
+		"config":        map[string]interface{}{"region": "eu-west-1"},
+	}
+	if !reflect.DeepEqual(location, expectedLocation) {
+		t.Errorf("buildObjects() storage location spec == \n%#v\nexpected \n%#v\n", location, expectedLocation)
+	}
+
+	spec.Namespace, spec.Credentials = velero.DefaultNamespace, ""
+	objects, err = buildObjects(spec)
+	if err != nil {
+		t.Fatalf("buildObjects() returned unexpected error: %s", err.Error())
+	}
+	if len(objects) != 5 || objects[2].object.GetName() != "velero" {
+		t.Errorf("buildObjects() without credentials returned %d objects, binding %s", len(objects),
+			objects[2].object.GetName())
+	}
+}
+
+func TestToContainerName(t *testing.T) {
+	cases := []struct {
+		image    string
+		expected string
+	}{
+		{"velero/velero-plugin-for-aws:v1.10.0", "velero-plugin-for-aws"},
+		{"registry.local:5000/velero/velero-plugin-for-gcp:v1.10.0", "velero-plugin-for-gcp"},
+		{"example.com/velero_plugin.custom@sha256:0123", "velero-plugin-custom"},
+	}
+
+	for _, c := range cases {
+		actual := toContainerName(c.image)
+		if actual != c.expected {
+			t.Errorf("toContainerName(%#v) == %#v, expected %#v", c.image, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"path"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// Names of the objects created by `velero install`.
+const (
+	serverName          = "velero"
+	storageLocationName = "default"
+	clusterAdminRole    = "cluster-admin"
+)
+
+// Paths the server container mounts its volumes at.
+const (
+	pluginsPath     = "/plugins"
+	scratchPath     = "/scratch"
+	credentialsPath = "/credentials"
+)
+
+var (
+	customResourceDefinitionGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	namespaceGVR                = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	serviceAccountGVR           = schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	secretGVR                   = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	clusterRoleBindingGVR       = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}
+	deploymentGVR               = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+)
+
+// object is an object to apply and the resource it is applied to.
+type object struct {
+	resource schema.GroupVersionResource
+	object   *unstructured.Unstructured
+}
+
+// typedObject is an object built from the Kubernetes types, which is converted to unstructured to be applied.
+type typedObject struct {
+	resource schema.GroupVersionResource
+	object   runtime.Object
+}
+
+// buildObjects returns the objects of the installation in the order they are applied in, so that each object only
+// depends on the ones before it.
+func buildObjects(spec *Spec) ([]object, error) {
+	typed := []typedObject{
+		{namespaceGVR, &v1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: newObjectMeta(spec.Namespace, ""),
+		}},
+		{serviceAccountGVR, &v1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: newObjectMeta(serverName, spec.Namespace),
+		}},
+		{clusterRoleBindingGVR, newClusterRoleBinding(spec.Namespace)},
+	}
+
+	if spec.Credentials != "" {
+		typed = append(typed, typedObject{secretGVR, &v1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: newObjectMeta(velero.CredentialsSecretName, spec.Namespace),
+			Type:       v1.SecretTypeOpaque,
+			StringData: map[string]string{velero.CredentialsSecretKey: spec.Credentials},
+		}})
+	}
+
+	typed = append(typed, typedObject{deploymentGVR, newDeployment(spec)})
+
+	result := make([]object, 0, len(typed)+1)
+	for _, item := range typed {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item.object)
+		if err != nil {
+			return nil, err
+		}
+
+		// Typed objects always have these fields, which would be applied as empty otherwise.
+		delete(content, "status")
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(content, "spec", "template", "metadata", "creationTimestamp")
+		result = append(result, object{resource: item.resource, object: &unstructured.Unstructured{Object: content}})
+	}
+
+	return append(result, object{resource: velero.BackupStorageLocationGVR, object: newStorageLocation(spec)}), nil
+}
+
+func newObjectMeta(name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"component": serverName}}
+}
+
+// newClusterRoleBinding binds the Velero server to the cluster-admin role, as `velero install` does, since it backs up
+// and restores resources of any kind. The binding of other namespaces than the default one is suffixed with the
+// namespace, so that several installations do not share it.
+func newClusterRoleBinding(namespace string) *rbacv1.ClusterRoleBinding {
+	name := serverName
+	if namespace != velero.DefaultNamespace {
+		name = serverName + "-" + namespace
+	}
+
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+		ObjectMeta: newObjectMeta(name, ""),
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: serverName, Namespace: namespace}},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: clusterAdminRole},
+	}
+}
+
+func newDeployment(spec *Spec) *appsv1.Deployment {
+	labels := map[string]string{"component": serverName, "deploy": serverName}
+	replicas := int32(1)
+
+	volumes := []v1.Volume{
+		{Name: "plugins", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+		{Name: "scratch", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+	}
+	mounts := []v1.VolumeMount{
+		{Name: "plugins", MountPath: pluginsPath},
+		{Name: "scratch", MountPath: scratchPath},
+	}
+	env := []v1.EnvVar{
+		{Name: "VELERO_SCRATCH_DIR", Value: scratchPath},
+		{Name: "VELERO_NAMESPACE", ValueFrom: &v1.EnvVarSource{
+			FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+		}},
+		{Name: "LD_LIBRARY_PATH", Value: pluginsPath},
+	}
+
+	if spec.Credentials != "" {
+		volumes = append(volumes, v1.Volume{Name: velero.CredentialsSecretName, VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{SecretName: velero.CredentialsSecretName},
+		}})
+		mounts = append(mounts, v1.VolumeMount{Name: velero.CredentialsSecretName, MountPath: credentialsPath})
+
+		// Each provider SDK reads its credentials file from a different variable.
+		file := path.Join(credentialsPath, velero.CredentialsSecretKey)
+		for _, name := range []string{"AWS_SHARED_CREDENTIALS_FILE", "GOOGLE_APPLICATION_CREDENTIALS",
+			"AZURE_CREDENTIALS_FILE", "ALIBABA_CLOUD_CREDENTIALS_FILE"} {
+			env = append(env, v1.EnvVar{Name: name, Value: file})
+		}
+	}
+
+	// Plugins copy their binaries into the shared plugins volume before the server starts.
+	initContainers := make([]v1.Container, 0, len(spec.Plugins))
+	for _, image := range spec.Plugins {
+		initContainers = append(initContainers, v1.Container{
+			Name:         toContainerName(image),
+			Image:        image,
+			VolumeMounts: []v1.VolumeMount{{Name: "plugins", MountPath: "/target"}},
+		})
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: serverName, Namespace: spec.Namespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"deploy": serverName}},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					ServiceAccountName: serverName,
+					InitContainers:     initContainers,
+					Containers: []v1.Container{{
+						Name:         serverName,
+						Image:        spec.Image,
+						Command:      []string{"/velero"},
+						Args:         []string{"server"},
+						Ports:        []v1.ContainerPort{{Name: "metrics", ContainerPort: 8085}},
+						Env:          env,
+						VolumeMounts: mounts,
+					}},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+// newStorageLocation returns the default backup storage location. It is built as unstructured, as the dashboard has no
+// Velero types.
+func newStorageLocation(spec *Spec) *unstructured.Unstructured {
+	objectStorage := map[string]interface{}{"bucket": spec.StorageLocation.Bucket}
+	if spec.StorageLocation.Prefix != "" {
+		objectStorage["prefix"] = spec.StorageLocation.Prefix
+	}
+
+	locationSpec := map[string]interface{}{
+		"provider":      spec.StorageLocation.Provider,
+		"objectStorage": objectStorage,
+		"default":       true,
+	}
+	if len(spec.StorageLocation.Config) > 0 {
+		config := make(map[string]interface{}, len(spec.StorageLocation.Config))
+		for key, value := range spec.StorageLocation.Config {
+			config[key] = value
+		}
+		locationSpec["config"] = config
+	}
+
+	location := &unstructured.Unstructured{Object: map[string]interface{}{"spec": locationSpec}}
+	location.SetAPIVersion(velero.BackupStorageLocationGVR.GroupVersion().String())
+	location.SetKind("BackupStorageLocation")
+	location.SetName(storageLocationName)
+	location.SetNamespace(spec.Namespace)
+	location.SetLabels(map[string]string{"component": serverName})
+	return location
+}
+
+// toContainerName returns the name of the init container of a plugin image, e.g. velero-plugin-for-aws for
+// velero/velero-plugin-for-aws:v1.10.0, as `velero install` names it.
+func toContainerName(image string) string {
+	name, _, _ := strings.Cut(image, "@")
+	name = name[strings.LastIndex(name, "/")+1:]
+	name, _, _ = strings.Cut(name, ":")
+	return strings.NewReplacer(".", "-", "_", "-").Replace(name)
+}
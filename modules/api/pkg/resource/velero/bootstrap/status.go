@@ -0,0 +1,76 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// Status tells which parts of a Velero installation exist, so that a setup wizard can offer to bootstrap the missing
+// ones.
+type Status struct {
+	velero.InstallStatus
+
+	// ServerDeployed tells whether the Velero server Deployment exists in the Velero namespace.
+	ServerDeployed bool `json:"serverDeployed"`
+
+	// StorageLocationConfigured tells whether a backup storage location exists in the Velero namespace.
+	StorageLocationConfigured bool `json:"storageLocationConfigured"`
+
+	// CanBootstrap tells whether the user is a cluster admin, who may bootstrap an installation.
+	CanBootstrap bool `json:"canBootstrap"`
+}
+
+// GetStatus returns which parts of a Velero installation exist in the cluster.
+func GetStatus(request *http.Request) (*Status, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	installStatus, err := velero.GetInstallStatus(request)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Status{InstallStatus: *installStatus}
+	namespace := velero.GetNamespace(request).Namespace
+
+	health, err := velero.GetControllerHealth(request)
+	if err != nil {
+		return nil, err
+	}
+	result.ServerDeployed = health.Server != nil
+
+	// Storage locations can only be listed once the CRDs are installed.
+	if result.Installed {
+		dynamicClient, err := velero.DynamicClient(request)
+		if err != nil {
+			return nil, err
+		}
+
+		locations, err := dynamicClient.Resource(velero.BackupStorageLocationGVR).Namespace(namespace).
+			List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		result.StorageLocationConfigured = len(locations.Items) > 0
+	}
+
+	result.CanBootstrap = velero.CheckAccess(request, "*", anyResource, "", "") == nil
+	return result, nil
+}
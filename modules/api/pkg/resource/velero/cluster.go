@@ -0,0 +1,47 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+const (
+	// SourceClusterNameAnnotation and SourceClusterUIDAnnotation identify the cluster a backup was taken in. They are
+	// set by tooling managing several clusters sharing a bucket, as Velero does not record the cluster identity.
+	SourceClusterNameAnnotation = "dashboard.kubernetes.io/source-cluster-name"
+	SourceClusterUIDAnnotation  = "dashboard.kubernetes.io/source-cluster-uid"
+
+	// SourceClusterVersionAnnotation is set by Velero to the Kubernetes version of the cluster a backup was taken in.
+	SourceClusterVersionAnnotation = "velero.io/source-cluster-k8s-gitversion"
+)
+
+// SourceCluster identifies the cluster a backup was taken in, which differs from the current one for backups synced
+// from a bucket shared by several clusters.
+type SourceCluster struct {
+	Name              string `json:"name,omitempty"`
+	UID               string `json:"uid,omitempty"`
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+}
+
+// NewSourceCluster reads the source cluster from annotations of a backup, returning nil when none is recorded.
+func NewSourceCluster(annotations map[string]string) *SourceCluster {
+	cluster := &SourceCluster{
+		Name:              annotations[SourceClusterNameAnnotation],
+		UID:               annotations[SourceClusterUIDAnnotation],
+		KubernetesVersion: annotations[SourceClusterVersionAnnotation],
+	}
+	if *cluster == (SourceCluster{}) {
+		return nil
+	}
+
+	return cluster
+}
@@ -0,0 +1,49 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSourceCluster(t *testing.T) {
+	cases := []struct {
+		annotations map[string]string
+		expected    *SourceCluster
+	}{
+		{nil, nil},
+		{map[string]string{"dashboard.kubernetes.io/exclusion-presets": "logs"}, nil},
+		{
+			map[string]string{
+				SourceClusterNameAnnotation:    "eu-west-prod",
+				SourceClusterUIDAnnotation:     "3f1c7a52-9d0e-4c55-a9f8-5b7e2b1d6c10",
+				SourceClusterVersionAnnotation: "v1.30.4",
+			},
+			&SourceCluster{Name: "eu-west-prod", UID: "3f1c7a52-9d0e-4c55-a9f8-5b7e2b1d6c10", KubernetesVersion: "v1.30.4"},
+		},
+		{
+			map[string]string{SourceClusterVersionAnnotation: "v1.29.8"},
+			&SourceCluster{KubernetesVersion: "v1.29.8"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := NewSourceCluster(c.annotations)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("NewSourceCluster(%#v) == \n%#v\nexpected \n%#v\n", c.annotations, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/dashboard/api/pkg/args"
+)
+
+// OperationContext returns the context to make API server calls in on behalf of the request. It is cancelled once
+// the request is cancelled or the configured operation timeout passes.
+func OperationContext(request *http.Request) (context.Context, context.CancelFunc) {
+	if timeout := args.VeleroOperationTimeout(); timeout > 0 {
+		return context.WithTimeout(request.Context(), timeout)
+	}
+
+	return context.WithCancel(request.Context())
+}
@@ -0,0 +1,41 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"k8s.io/dashboard/api/pkg/args"
+)
+
+func TestOperationContext(t *testing.T) {
+	requestCtx, cancelRequest := context.WithCancel(context.Background())
+	request, _ := http.NewRequestWithContext(requestCtx, http.MethodGet, "/api/v1/velero/backup", nil)
+
+	ctx, cancel := OperationContext(request)
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok != (args.VeleroOperationTimeout() > 0) {
+		t.Errorf("OperationContext() deadline set: %t, expected %t", ok, args.VeleroOperationTimeout() > 0)
+	}
+
+	cancelRequest()
+	<-ctx.Done()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("OperationContext() error after request cancellation == %v, expected %v", ctx.Err(), context.Canceled)
+	}
+}
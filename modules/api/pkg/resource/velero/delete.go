@@ -0,0 +1,94 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/types"
+)
+
+// DeletionImpact describes a deleted Velero object, so that the impact of a deletion can be previewed in dry run.
+type DeletionImpact struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	// DryRun tells that the deletion was only validated and the object still exists.
+	DryRun bool `json:"dryRun"`
+
+	// Related lists objects referencing the deleted one. Velero keeps them, but they lose the reference.
+	Related []RelatedObject `json:"related"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// RelatedObject is an object referencing a deleted Velero object.
+type RelatedObject struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+}
+
+// Delete deletes the Velero object, or in dry run only validates its deletion, and returns the deleted object.
+func Delete(ctx context.Context, client dynamic.Interface, resource schema.GroupVersionResource, kind types.ResourceKind,
+	namespace, name string, dryRun bool) (*DeletionImpact, error) {
+	object, err := client.Resource(resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Resource(resource).Namespace(namespace).Delete(ctx, name, DeleteOptions(dryRun)); err != nil {
+		return nil, err
+	}
+
+	return &DeletionImpact{
+		ObjectMeta: NewObjectMeta(object.Object),
+		TypeMeta:   types.TypeMeta{Kind: kind},
+		DryRun:     dryRun,
+		Related:    make([]RelatedObject, 0),
+		Errors:     make([]error, 0),
+	}, nil
+}
+
+// AddRelated lists objects in the namespace of the deleted object and adds the ones matching the filter to the
+// related objects. The deletion is not undone when listing fails, so errors are reported as non-critical.
+func (in *DeletionImpact) AddRelated(ctx context.Context, client dynamic.Interface, resource schema.GroupVersionResource,
+	kind types.ResourceKind, options metav1.ListOptions, filter func(item unstructured.Unstructured) bool) {
+	items, truncated, err := List(ctx, client.Resource(resource).Namespace(in.ObjectMeta.Namespace), options,
+		args.VeleroMaxListItems())
+	if err != nil {
+		in.Errors = append(in.Errors, err)
+		return
+	}
+
+	for _, item := range items {
+		if filter(item) {
+			in.Related = append(in.Related, RelatedObject{
+				ObjectMeta: NewObjectMeta(item.Object),
+				TypeMeta:   types.TypeMeta{Kind: kind},
+			})
+		}
+	}
+
+	if truncated {
+		in.Errors = append(in.Errors, NewListTruncatedError(resource.Resource, args.VeleroMaxListItems()))
+	}
+}
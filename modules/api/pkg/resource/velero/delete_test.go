@@ -0,0 +1,80 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+
+	"k8s.io/dashboard/types"
+)
+
+func newDeleteTestObject(kind, namespace, name, backupName string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       map[string]interface{}{"backupName": backupName},
+	}}
+}
+
+func TestDelete(t *testing.T) {
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{BackupGVR: "BackupList", RestoreGVR: "RestoreList"},
+		newDeleteTestObject("Backup", "velero", "daily", ""),
+		newDeleteTestObject("Restore", "velero", "daily-1", "daily"),
+		newDeleteTestObject("Restore", "velero", "weekly-1", "weekly"),
+		newDeleteTestObject("Restore", "other", "daily-2", "daily"))
+
+	impact, err := Delete(context.TODO(), dynamicClient, BackupGVR, "Backup", "velero", "daily", false)
+	if err != nil {
+		t.Fatalf("Delete() returned error: %s", err.Error())
+	}
+
+	impact.AddRelated(context.TODO(), dynamicClient, RestoreGVR, "Restore", metav1.ListOptions{},
+		func(item unstructured.Unstructured) bool {
+			name, _, _ := unstructured.NestedString(item.Object, "spec", "backupName")
+			return name == "daily"
+		})
+
+	expected := &DeletionImpact{
+		ObjectMeta: types.ObjectMeta{Name: "daily", Namespace: "velero"},
+		TypeMeta:   types.TypeMeta{Kind: "Backup"},
+		Related: []RelatedObject{
+			{ObjectMeta: types.ObjectMeta{Name: "daily-1", Namespace: "velero"}, TypeMeta: types.TypeMeta{Kind: "Restore"}},
+		},
+		Errors: []error{},
+	}
+	if !reflect.DeepEqual(impact, expected) {
+		t.Errorf("Delete() == \n%#v\nexpected \n%#v\n", impact, expected)
+	}
+
+	_, err = dynamicClient.Resource(BackupGVR).Namespace("velero").Get(context.TODO(), "daily", metav1.GetOptions{})
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("Delete() kept the backup, expected it to be deleted")
+	}
+
+	if _, err := Delete(context.TODO(), dynamicClient, BackupGVR, "Backup", "velero", "daily", false); !k8serrors.IsNotFound(err) {
+		t.Errorf("Delete() of a missing backup returned %v, expected not found error", err)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+// DownloadTargetKind is the kind of backup or restore data Velero can provide a download URL for.
+type DownloadTargetKind string
+
+const (
+	DownloadTargetKindBackupLog          DownloadTargetKind = "BackupLog"
+	DownloadTargetKindBackupContents     DownloadTargetKind = "BackupContents"
+	DownloadTargetKindBackupResourceList DownloadTargetKind = "BackupResourceList"
+	DownloadTargetKindBackupResults      DownloadTargetKind = "BackupResults"
+	DownloadTargetKindRestoreLog         DownloadTargetKind = "RestoreLog"
+	DownloadTargetKindRestoreResults     DownloadTargetKind = "RestoreResults"
+
+	// Item operations of backups and restores run asynchronously by plugins, recorded by Velero 1.12 and later.
+	DownloadTargetKindBackupItemOperations  DownloadTargetKind = "BackupItemOperations"
+	DownloadTargetKindRestoreItemOperations DownloadTargetKind = "RestoreItemOperations"
+)
+
+const (
+	// downloadPollInterval is how often the download request is checked until Velero processes it.
+	downloadPollInterval = 500 * time.Millisecond
+
+	// maxDownloadSize limits how much decompressed data is read, so a huge log cannot exhaust memory of the API.
+	maxDownloadSize = 64 << 20
+)
+
+// Download asks Velero for a signed URL to the data of a backup or restore in the Velero namespace and returns the
+// data decompressed. The download request is removed afterwards.
+func Download(ctx context.Context, client dynamic.Interface, namespace string, kind DownloadTargetKind, name string) ([]byte, error) {
+	requests := client.Resource(DownloadRequestGVR).Namespace(namespace)
+
+	downloadRequest := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": DownloadRequestGVR.GroupVersion().String(),
+			"kind":       "DownloadRequest",
+			"metadata": map[string]interface{}{
+				"name":      fmt.Sprintf("%s-%s", name, utilrand.String(5)),
+				"namespace": namespace,
+			},
+			"spec": map[string]interface{}{
+				"target": map[string]interface{}{
+					"kind": string(kind),
+					"name": name,
+				},
+			},
+		},
+	}
+
+	created, err := requests.Create(ctx, downloadRequest, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Velero garbage collects processed requests that were left behind, so failures are not reported.
+		_ = requests.Delete(context.Background(), created.GetName(), metav1.DeleteOptions{})
+	}()
+
+	var downloadURL string
+	err = wait.PollUntilContextCancel(ctx, downloadPollInterval, true, func(ctx context.Context) (bool, error) {
+		item, err := requests.Get(ctx, created.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		downloadURL, _, _ = unstructured.NestedString(item.Object, "status", "downloadURL")
+		return phase == "Processed" && downloadURL != "", nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get download URL of %s %s: %w", kind, name, err)
+	}
+
+	return fetch(ctx, downloadURL)
+}
+
+func fetch(ctx context.Context, url string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to download from object storage: %s", response.Status)
+	}
+
+	reader, err := gzip.NewReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(io.LimitReader(reader, maxDownloadSize))
+}
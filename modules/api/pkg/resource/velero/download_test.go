@@ -0,0 +1,69 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writer := gzip.NewWriter(w)
+		_, _ = writer.Write([]byte(`{"v1/ConfigMap":["shop/settings"]}`))
+		_ = writer.Close()
+	}))
+	defer server.Close()
+
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{DownloadRequestGVR: "DownloadRequestList"})
+	polls := 0
+	client.PrependReactor("get", "downloadrequests", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		polls++
+		item := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		item.SetName(action.(k8stesting.GetAction).GetName())
+		if polls > 1 {
+			item.Object["status"] = map[string]interface{}{"phase": "Processed", "downloadURL": server.URL}
+		}
+		return true, item, nil
+	})
+
+	data, err := Download(context.TODO(), client, "velero", DownloadTargetKindBackupResourceList, "daily")
+	if err != nil {
+		t.Fatalf("Download() returned error: %s", err.Error())
+	}
+
+	if expected := `{"v1/ConfigMap":["shop/settings"]}`; string(data) != expected {
+		t.Errorf("Download() == %s, expected %s", data, expected)
+	}
+
+	list, err := client.Resource(DownloadRequestGVR).Namespace("velero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() returned error: %s", err.Error())
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("Download() left %d download requests behind, expected 0", len(list.Items))
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FieldManager is the name of the dashboard in managed fields of the objects it applies.
+const FieldManager = "kubernetes-dashboard"
+
+// dryRun returns the dry run option of API server calls. In dry run the API server runs admission and schema
+// validation of the request without persisting anything.
+func dryRun(enabled bool) []string {
+	if enabled {
+		return []string{metav1.DryRunAll}
+	}
+
+	return nil
+}
+
+// CreateOptions returns options to create an object with, validating it only when dry run is enabled.
+func CreateOptions(dryRunEnabled bool) metav1.CreateOptions {
+	return metav1.CreateOptions{DryRun: dryRun(dryRunEnabled)}
+}
+
+// DeleteOptions returns options to delete an object with, keeping it when dry run is enabled.
+func DeleteOptions(dryRunEnabled bool) metav1.DeleteOptions {
+	return metav1.DeleteOptions{DryRun: dryRun(dryRunEnabled)}
+}
+
+// PatchOptions returns options to patch an object with, validating the patched object only when dry run is enabled.
+func PatchOptions(dryRunEnabled bool) metav1.PatchOptions {
+	return metav1.PatchOptions{DryRun: dryRun(dryRunEnabled)}
+}
+
+// UpdateOptions returns options to update an object with, validating the updated object only when dry run is enabled.
+func UpdateOptions(dryRunEnabled bool) metav1.UpdateOptions {
+	return metav1.UpdateOptions{DryRun: dryRun(dryRunEnabled)}
+}
+
+// ApplyOptions returns options to apply an object with server-side apply, taking over fields managed by others, and
+// validating the applied object only when dry run is enabled.
+func ApplyOptions(dryRunEnabled bool) metav1.ApplyOptions {
+	return metav1.ApplyOptions{DryRun: dryRun(dryRunEnabled), FieldManager: FieldManager, Force: true}
+}
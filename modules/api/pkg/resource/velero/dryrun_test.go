@@ -0,0 +1,54 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCreateOptions(t *testing.T) {
+	cases := []struct {
+		dryRun   bool
+		expected metav1.CreateOptions
+	}{
+		{false, metav1.CreateOptions{}},
+		{true, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}},
+	}
+
+	for _, c := range cases {
+		actual := CreateOptions(c.dryRun)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("CreateOptions(%t) == %#v, expected %#v", c.dryRun, actual, c.expected)
+		}
+	}
+}
+
+func TestDeleteAndPatchOptions(t *testing.T) {
+	if actual := DeleteOptions(false); !reflect.DeepEqual(actual, metav1.DeleteOptions{}) {
+		t.Errorf("DeleteOptions(false) == %#v, expected no dry run", actual)
+	}
+	if actual := DeleteOptions(true); !reflect.DeepEqual(actual.DryRun, []string{metav1.DryRunAll}) {
+		t.Errorf("DeleteOptions(true) == %#v, expected dry run of all stages", actual)
+	}
+	if actual := PatchOptions(false); !reflect.DeepEqual(actual, metav1.PatchOptions{}) {
+		t.Errorf("PatchOptions(false) == %#v, expected no dry run", actual)
+	}
+	if actual := PatchOptions(true); !reflect.DeepEqual(actual.DryRun, []string{metav1.DryRunAll}) {
+		t.Errorf("PatchOptions(true) == %#v, expected dry run of all stages", actual)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"k8s.io/dashboard/errors"
+)
+
+// DurationFormats describes the durations Velero accepts, as Velero decodes them with metav1.Duration.
+const DurationFormats = `numbers with units h, m, s, ms, us or ns, e.g. "720h", "1h30m" or "90s"; days are not supported, ` +
+	`use "720h" for 30 days`
+
+// ValidateDuration returns a field level error when the value is not a duration Velero accepts. An empty value is
+// valid, as Velero defaults apply to it. Velero would otherwise fail decoding the whole object and only report it on
+// the object, long after it was created.
+func ValidateDuration(path *field.Path, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return errors.NewBadRequest(field.Invalid(path, value, "invalid duration, accepted formats are "+DurationFormats).Error())
+	}
+
+	if duration < 0 {
+		return errors.NewBadRequest(field.Invalid(path, value, "duration must not be negative").Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateDuration(t *testing.T) {
+	cases := []struct {
+		value   string
+		message string
+	}{
+		{"", ""},
+		{"720h", ""},
+		{"1h30m", ""},
+		{"0s", ""},
+		{"30d", `ttl: Invalid value: "30d": invalid duration, accepted formats are numbers with units`},
+		{"720", `ttl: Invalid value: "720": invalid duration`},
+		{"-1h", `ttl: Invalid value: "-1h": duration must not be negative`},
+	}
+
+	for _, c := range cases {
+		err := ValidateDuration(field.NewPath("ttl"), c.value)
+		if c.message == "" {
+			if err != nil {
+				t.Errorf("ValidateDuration(%q) returned error: %s", c.value, err.Error())
+			}
+			continue
+		}
+
+		if err == nil || !strings.HasPrefix(err.Error(), c.message) {
+			t.Errorf("ValidateDuration(%q) == %v, expected error starting with %q", c.value, err, c.message)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+type versionTrackerKey struct{}
+
+// VersionTracker records the resource versions of the objects read through the Velero dynamic client while serving a
+// request. As long as none of them changed, the response did not change either, which ETag tells from a single
+// value.
+type VersionTracker struct {
+	mu          sync.Mutex
+	versions    []string
+	invalidated bool
+}
+
+// WithVersionTracker returns the request with a new version tracker, which clients returned by DynamicClient and
+// MetadataClient for the request record the versions of the objects they read in.
+func WithVersionTracker(request *http.Request) (*http.Request, *VersionTracker) {
+	tracker := &VersionTracker{}
+	return request.WithContext(context.WithValue(request.Context(), versionTrackerKey{}, tracker)), tracker
+}
+
+// Invalidate marks the response of the request as depending on more than the recorded objects, e.g. on objects read
+// with another client. No ETag is returned for it then. It does nothing when the request has no tracker.
+func Invalidate(request *http.Request) {
+	if tracker := versionTrackerFrom(request.Context()); tracker != nil {
+		tracker.mu.Lock()
+		tracker.invalidated = true
+		tracker.mu.Unlock()
+	}
+}
+
+// Record adds the versions of the items to the version tracker of the request, e.g. for objects read with another
+// client than the Velero ones. It does nothing when the request has no tracker.
+func Record(request *http.Request, resource schema.GroupVersionResource, items ...unstructured.Unstructured) {
+	if tracker := versionTrackerFrom(request.Context()); tracker != nil {
+		tracker.Record(resource, items...)
+	}
+}
+
+// ETag returns a weak entity tag derived from the recorded resource versions, independent of the order the objects
+// were read in. It returns an empty string when nothing was recorded or the tracker was invalidated.
+func (in *VersionTracker) ETag() string {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if in.invalidated || len(in.versions) == 0 {
+		return ""
+	}
+
+	versions := make([]string, len(in.versions))
+	copy(versions, in.versions)
+	sort.Strings(versions)
+
+	hash := sha256.Sum256([]byte(strings.Join(versions, "\n")))
+	return `W/"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+// Record adds the versions of the items to the tracker. Objects read through the tracking clients are recorded on their
+// own, objects read otherwise can be added with it.
+func (in *VersionTracker) Record(resource schema.GroupVersionResource, items ...unstructured.Unstructured) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	for _, item := range items {
+		in.versions = append(in.versions, strings.Join([]string{resource.GroupResource().String(), item.GetNamespace(),
+			item.GetName(), item.GetResourceVersion()}, "/"))
+	}
+}
+
+func versionTrackerFrom(ctx context.Context) *VersionTracker {
+	tracker, _ := ctx.Value(versionTrackerKey{}).(*VersionTracker)
+	return tracker
+}
+
+// withVersionTracking returns the client recording the versions of the objects it reads in the tracker of the
+// request, or the client itself when the request has no tracker.
+func withVersionTracking(request *http.Request, client dynamic.Interface) dynamic.Interface {
+	tracker := versionTrackerFrom(request.Context())
+	if tracker == nil {
+		return client
+	}
+
+	return &trackingClient{Interface: client, tracker: tracker}
+}
+
+// trackingClient records the versions of the objects read through it. Only gets and lists are recorded, the other
+// calls are passed on as they are.
+type trackingClient struct {
+	dynamic.Interface
+	tracker *VersionTracker
+}
+
+func (in *trackingClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &trackingResource{
+		NamespaceableResourceInterface: in.Interface.Resource(resource),
+		resource:                       resource,
+		tracker:                        in.tracker,
+	}
+}
+
+type trackingResource struct {
+	dynamic.NamespaceableResourceInterface
+	resource schema.GroupVersionResource
+	tracker  *VersionTracker
+}
+
+func (in *trackingResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &trackingNamespacedResource{
+		ResourceInterface: in.NamespaceableResourceInterface.Namespace(namespace),
+		resource:          in.resource,
+		tracker:           in.tracker,
+	}
+}
+
+func (in *trackingResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return trackGet(ctx, in.tracker, in.resource, in.NamespaceableResourceInterface, name, options, subresources...)
+}
+
+func (in *trackingResource) List(ctx context.Context, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return trackList(ctx, in.tracker, in.resource, in.NamespaceableResourceInterface, options)
+}
+
+type trackingNamespacedResource struct {
+	dynamic.ResourceInterface
+	resource schema.GroupVersionResource
+	tracker  *VersionTracker
+}
+
+func (in *trackingNamespacedResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return trackGet(ctx, in.tracker, in.resource, in.ResourceInterface, name, options, subresources...)
+}
+
+func (in *trackingNamespacedResource) List(ctx context.Context, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return trackList(ctx, in.tracker, in.resource, in.ResourceInterface, options)
+}
+
+func trackGet(ctx context.Context, tracker *VersionTracker, resource schema.GroupVersionResource, client dynamic.ResourceInterface, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	item, err := client.Get(ctx, name, options, subresources...)
+	if err == nil {
+		tracker.Record(resource, *item)
+	}
+
+	return item, err
+}
+
+// trackList records the versions of the listed items. The version of the list itself changes with every write to the
+// cluster, so it is left out.
+func trackList(ctx context.Context, tracker *VersionTracker, resource schema.GroupVersionResource, client dynamic.ResourceInterface, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	list, err := client.List(ctx, options)
+	if err == nil {
+		tracker.Record(resource, list.Items...)
+	}
+
+	return list, err
+}
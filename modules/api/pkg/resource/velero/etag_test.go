@@ -0,0 +1,91 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+)
+
+// trackedETag lists backups and reads the daily backup through a tracking client, in the given order, and returns
+// the resulting ETag.
+func trackedETag(t *testing.T, dailyVersion string, getFirst bool) string {
+	daily := newNamespacedObject("velero.io/v1", "Backup", "velero", "daily")
+	daily.SetResourceVersion(dailyVersion)
+	weekly := newNamespacedObject("velero.io/v1", "Backup", "velero", "weekly")
+	weekly.SetResourceVersion("7")
+	fake := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{BackupGVR: "BackupList"}, daily, weekly)
+
+	request, tracker := WithVersionTracker(&http.Request{})
+	client := withVersionTracking(request, fake)
+	get := func() {
+		if _, err := client.Resource(BackupGVR).Namespace("velero").Get(context.TODO(), "daily", metav1.GetOptions{}); err != nil {
+			t.Fatalf("Get() returned error: %s", err.Error())
+		}
+	}
+	list := func() {
+		if _, err := client.Resource(BackupGVR).List(context.TODO(), metav1.ListOptions{}); err != nil {
+			t.Fatalf("List() returned error: %s", err.Error())
+		}
+	}
+
+	if getFirst {
+		get()
+		list()
+	} else {
+		list()
+		get()
+	}
+
+	return tracker.ETag()
+}
+
+func TestVersionTrackerETag(t *testing.T) {
+	etag := trackedETag(t, "5", false)
+	if etag == "" {
+		t.Fatalf("ETag() returned no ETag for read objects")
+	}
+
+	if reordered := trackedETag(t, "5", true); reordered != etag {
+		t.Errorf("ETag() == %s after reading the objects in another order, expected %s", reordered, etag)
+	}
+	if changed := trackedETag(t, "6", false); changed == etag {
+		t.Errorf("ETag() == %s after the daily backup changed, expected another ETag", changed)
+	}
+}
+
+func TestVersionTrackerInvalidate(t *testing.T) {
+	request, tracker := WithVersionTracker(&http.Request{})
+	tracker.Record(BackupGVR, *newNamespacedObject("velero.io/v1", "Backup", "velero", "daily"))
+	Invalidate(request)
+
+	if etag := tracker.ETag(); etag != "" {
+		t.Errorf("ETag() == %s after Invalidate(), expected no ETag", etag)
+	}
+}
+
+func TestWithVersionTrackingWithoutTracker(t *testing.T) {
+	fake := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	if client := withVersionTracking(&http.Request{}, fake); client != fake {
+		t.Errorf("withVersionTracking() wrapped the client of a request without tracker")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"net/http"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/event"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+)
+
+// GetEvents returns events of the Velero object of the kind, e.g. "Backup", so that validation failures and controller
+// errors can be shown along with it. Events of other objects with the same name, e.g. pods restored into the Velero
+// namespace, are left out.
+func GetEvents(request *http.Request, kind, namespace, name string, dsQuery *dataselect.DataSelectQuery) (*common.EventList, error) {
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := event.GetEvents(k8sClient, namespace, name)
+	nonCriticalErrors, criticalError := errors.ExtractErrors(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	result := event.CreateEventList(filterEvents(events, kind), dsQuery)
+	result.Errors = nonCriticalErrors
+	return &result, nil
+}
+
+func filterEvents(events []v1.Event, kind string) []v1.Event {
+	result := make([]v1.Event, 0, len(events))
+	for _, item := range events {
+		if item.InvolvedObject.Kind == kind && isVeleroAPIVersion(item.InvolvedObject.APIVersion) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+func isVeleroAPIVersion(apiVersion string) bool {
+	return strings.HasPrefix(apiVersion, GroupName+"/")
+}
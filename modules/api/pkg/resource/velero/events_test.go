@@ -0,0 +1,55 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFilterEvents(t *testing.T) {
+	newEvent := func(name, kind, apiVersion string) v1.Event {
+		return v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name},
+			InvolvedObject: v1.ObjectReference{Kind: kind, APIVersion: apiVersion, Name: "nightly"},
+		}
+	}
+
+	events := []v1.Event{
+		newEvent("backup", "Backup", "velero.io/v1"),
+		newEvent("restore", "Restore", "velero.io/v1"),
+		newEvent("pod", "Pod", "v1"),
+		newEvent("other-backup", "Backup", "example.com/v1"),
+	}
+
+	cases := []struct {
+		kind     string
+		expected []v1.Event
+	}{
+		{"Backup", []v1.Event{events[0]}},
+		{"Restore", []v1.Event{events[1]}},
+		{"Schedule", []v1.Event{}},
+	}
+
+	for _, c := range cases {
+		actual := filterEvents(events, c.kind)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("filterEvents(%#v) == \n%#v\nexpected \n%#v\n", c.kind, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake provides fakes of the clients used for Velero resources, to test operations on them without a cluster.
+package fake
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+)
+
+// listKinds maps the Velero resources to the kinds of their lists, which the fake dynamic client cannot guess.
+var listKinds = map[schema.GroupVersionResource]string{
+	velero.BackupGVR:                 "BackupList",
+	velero.RestoreGVR:                "RestoreList",
+	velero.ScheduleGVR:               "ScheduleList",
+	velero.BackupStorageLocationGVR:  "BackupStorageLocationList",
+	velero.VolumeSnapshotLocationGVR: "VolumeSnapshotLocationList",
+	velero.DeleteBackupRequestGVR:    "DeleteBackupRequestList",
+	velero.DownloadRequestGVR:        "DownloadRequestList",
+	velero.PodVolumeBackupGVR:        "PodVolumeBackupList",
+	velero.PodVolumeRestoreGVR:       "PodVolumeRestoreList",
+	velero.BackupRepositoryGVR:       "BackupRepositoryList",
+	velero.ServerStatusRequestGVR:    "ServerStatusRequestList",
+	velero.DataUploadGVR:             "DataUploadList",
+	velero.DataDownloadGVR:           "DataDownloadList",
+	velero.VolumeSnapshotGVR:         "VolumeSnapshotList",
+	velero.VolumeSnapshotContentGVR:  "VolumeSnapshotContentList",
+	velero.VolumeSnapshotClassGVR:    "VolumeSnapshotClassList",
+}
+
+// NewDynamicClient returns a fake dynamic client serving the objects, which can list every Velero resource. Kinds of
+// other lists, e.g. of the resources restores collide with, are passed as extraListKinds.
+func NewDynamicClient(extraListKinds map[schema.GroupVersionResource]string, objects ...runtime.Object) *fakedynamic.FakeDynamicClient {
+	kinds := make(map[schema.GroupVersionResource]string, len(listKinds)+len(extraListKinds))
+	for resource, kind := range listKinds {
+		kinds[resource] = kind
+	}
+	for resource, kind := range extraListKinds {
+		kinds[resource] = kind
+	}
+
+	return fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), kinds, objects...)
+}
+
+// NewObject returns an object of the Velero resource, e.g. a Backup for velero.BackupGVR, at the version of the GVR.
+func NewObject(resource schema.GroupVersionResource, namespace, name string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion(resource.GroupVersion().String())
+	object.SetKind(strings.TrimSuffix(listKinds[resource], "List"))
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	return object
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package velero holds what all Velero resource packages share to talk to the Velero API.
+package velero
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the API group of all Velero resources.
+const GroupName = "velero.io"
+
+var (
+	BackupGVR                 = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "backups"}
+	RestoreGVR                = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "restores"}
+	ScheduleGVR               = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "schedules"}
+	BackupStorageLocationGVR  = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "backupstoragelocations"}
+	VolumeSnapshotLocationGVR = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "volumesnapshotlocations"}
+	DeleteBackupRequestGVR    = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "deletebackuprequests"}
+	DownloadRequestGVR        = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "downloadrequests"}
+	PodVolumeBackupGVR        = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "podvolumebackups"}
+	PodVolumeRestoreGVR       = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "podvolumerestores"}
+	BackupRepositoryGVR       = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "backuprepositories"}
+	ServerStatusRequestGVR    = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "serverstatusrequests"}
+	DataUploadGVR             = schema.GroupVersionResource{Group: GroupName, Version: "v2alpha1", Resource: "datauploads"}
+	DataDownloadGVR           = schema.GroupVersionResource{Group: GroupName, Version: "v2alpha1", Resource: "datadownloads"}
+)
+
+// SnapshotGroupName is the API group of CSI snapshot resources, which Velero uses to take CSI volume snapshots.
+const SnapshotGroupName = "snapshot.storage.k8s.io"
+
+var (
+	VolumeSnapshotGVR        = schema.GroupVersionResource{Group: SnapshotGroupName, Version: "v1", Resource: "volumesnapshots"}
+	VolumeSnapshotContentGVR = schema.GroupVersionResource{Group: SnapshotGroupName, Version: "v1", Resource: "volumesnapshotcontents"}
+	VolumeSnapshotClassGVR   = schema.GroupVersionResource{Group: SnapshotGroupName, Version: "v1", Resource: "volumesnapshotclasses"}
+)
@@ -0,0 +1,230 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/event"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+)
+
+// nodeAgentLabelSelector matches the node-agent DaemonSet created by `velero install` and the Helm chart.
+const nodeAgentLabelSelector = "name=node-agent"
+
+// recentEventsWindow is how far back warning events of the Velero components are reported.
+const recentEventsWindow = time.Hour
+
+// ControllerHealth is the health of the Velero components in the namespace Velero is installed in, which tells
+// whether Backups stuck in the New phase are caused by a down controller.
+type ControllerHealth struct {
+	Namespace string `json:"namespace"`
+
+	// Server is the Velero server Deployment. It is nil when no Deployment was found.
+	Server *ComponentHealth `json:"server"`
+
+	// NodeAgent is the node-agent DaemonSet, which is only installed for file system backups and data movement. It
+	// is nil when no DaemonSet was found.
+	NodeAgent *ComponentHealth `json:"nodeAgent"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ComponentHealth is the health of a Deployment or DaemonSet running a Velero component.
+type ComponentHealth struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+
+	// Desired is the number of pods that should be running and Ready the number of them that are ready.
+	Desired int32 `json:"desired"`
+	Ready   int32 `json:"ready"`
+
+	// Image of the component container and the Velero version taken from its tag, e.g. v1.14.0.
+	Image   string `json:"image"`
+	Version string `json:"version,omitempty"`
+
+	// Restarts is the number of container restarts summed over all pods of the component.
+	Restarts int32 `json:"restarts"`
+
+	// Warnings are warning events of the component, its replica sets and pods from the last hour, newest first.
+	Warnings []common.Event `json:"warnings"`
+}
+
+// GetControllerHealth returns the health of the Velero server and node-agent in the namespace Velero is installed in.
+func GetControllerHealth(request *http.Request) (*ControllerHealth, error) {
+	ctx, cancel := OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getControllerHealth(ctx, k8sClient, GetNamespace(request).Namespace, time.Now())
+}
+
+func getControllerHealth(ctx context.Context, k8sClient kubernetes.Interface, namespace string, now time.Time) (*ControllerHealth, error) {
+	result := &ControllerHealth{Namespace: namespace, Errors: make([]error, 0)}
+
+	deployments, err := k8sClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: serverLabelSelector})
+	result.Errors, err = errors.AppendError(err, result.Errors)
+	if err != nil {
+		return nil, err
+	}
+
+	daemonSets, err := k8sClient.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: nodeAgentLabelSelector})
+	result.Errors, err = errors.AppendError(err, result.Errors)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := k8sClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("type", v1.EventTypeWarning).String(),
+	})
+	result.Errors, err = errors.AppendError(err, result.Errors)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []v1.Event
+	if events != nil {
+		warnings = events.Items
+	}
+	since := now.Add(-recentEventsWindow)
+
+	if deployments != nil && len(deployments.Items) > 0 {
+		deployment := deployments.Items[0]
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+
+		pods, err := getComponentPods(ctx, k8sClient, namespace, deployment.Spec.Selector)
+		result.Errors, err = errors.AppendError(err, result.Errors)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Server = toComponentHealth("Deployment", deployment.Name, desired, deployment.Status.ReadyReplicas,
+			deployment.Spec.Template.Spec, pods, warnings, since)
+	}
+
+	if daemonSets != nil && len(daemonSets.Items) > 0 {
+		daemonSet := daemonSets.Items[0]
+		pods, err := getComponentPods(ctx, k8sClient, namespace, daemonSet.Spec.Selector)
+		result.Errors, err = errors.AppendError(err, result.Errors)
+		if err != nil {
+			return nil, err
+		}
+
+		result.NodeAgent = toComponentHealth("DaemonSet", daemonSet.Name, daemonSet.Status.DesiredNumberScheduled,
+			daemonSet.Status.NumberReady, daemonSet.Spec.Template.Spec, pods, warnings, since)
+	}
+
+	return result, nil
+}
+
+func getComponentPods(ctx context.Context, k8sClient kubernetes.Interface, namespace string,
+	selector *metav1.LabelSelector) ([]v1.Pod, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	return pods.Items, nil
+}
+
+func toComponentHealth(kind, name string, desired, ready int32, podSpec v1.PodSpec, pods []v1.Pod, events []v1.Event,
+	since time.Time) *ComponentHealth {
+	result := &ComponentHealth{
+		Name:     name,
+		Healthy:  desired > 0 && ready >= desired,
+		Desired:  desired,
+		Ready:    ready,
+		Warnings: make([]common.Event, 0),
+	}
+
+	// Plugins run as init containers, so the first container is the component itself.
+	if len(podSpec.Containers) > 0 {
+		result.Image = podSpec.Containers[0].Image
+		result.Version = toImageVersion(result.Image)
+	}
+
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			result.Restarts += status.RestartCount
+		}
+	}
+
+	for _, item := range events {
+		if item.Type != v1.EventTypeWarning || !isComponentEvent(kind, name, item.InvolvedObject) {
+			continue
+		}
+
+		warning := event.ToEvent(item)
+		if !warning.LastSeen.Time.Before(since) {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+
+	sort.SliceStable(result.Warnings, func(i, j int) bool {
+		return result.Warnings[j].LastSeen.Before(&result.Warnings[i].LastSeen)
+	})
+
+	return result
+}
+
+// isComponentEvent reports whether the event is about the component, or one of its replica sets and pods, whose
+// names start with the name of the component. Pods that were already replaced are matched as well.
+func isComponentEvent(kind, name string, object v1.ObjectReference) bool {
+	switch object.Kind {
+	case kind:
+		return object.Name == name
+	case "Pod", "ReplicaSet":
+		return strings.HasPrefix(object.Name, name+"-")
+	default:
+		return false
+	}
+}
+
+// toImageVersion returns the tag of the image, e.g. v1.14.0 for velero/velero:v1.14.0, or an empty string when the
+// image is not tagged.
+func toImageVersion(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+
+	index := strings.LastIndex(image, ":")
+	if index < 0 || strings.Contains(image[index:], "/") {
+		return ""
+	}
+
+	return image[index+1:]
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+)
+
+func TestGetControllerHealth(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	replicas := int32(1)
+	selector := func(name string) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchLabels: map[string]string{"name": name}}
+	}
+	template := func(image string) v1.PodTemplateSpec {
+		return v1.PodTemplateSpec{Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "velero-plugin-for-aws", Image: "velero/velero-plugin-for-aws:v1.10.0"}},
+			Containers:     []v1.Container{{Name: "velero", Image: image}},
+		}}
+	}
+	pod := func(name, component string, restarts int32) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "velero", Labels: map[string]string{"name": component}},
+			Status:     v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{RestartCount: restarts}}},
+		}
+	}
+	warning := func(name, eventType, kind, objectName string, lastSeen time.Time) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "velero"},
+			InvolvedObject: v1.ObjectReference{Kind: kind, Name: objectName},
+			Type:           eventType,
+			Reason:         "BackOff",
+			LastTimestamp:  metav1.NewTime(lastSeen),
+		}
+	}
+
+	objects := []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "velero", Namespace: "velero", Labels: map[string]string{"component": "velero"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Selector: selector("velero"), Template: template("velero/velero:v1.14.0")},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 0},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-agent", Namespace: "velero", Labels: map[string]string{"name": "node-agent"}},
+			Spec:       appsv1.DaemonSetSpec{Selector: selector("node-agent"), Template: template("velero/velero@sha256:0123")},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 2},
+		},
+		pod("velero-6d9c8-x2k4p", "velero", 5),
+		pod("node-agent-abcde", "node-agent", 1),
+		pod("node-agent-fghij", "node-agent", 0),
+		warning("old", v1.EventTypeWarning, "Pod", "velero-6d9c8-old", now.Add(-2*time.Hour)),
+		warning("older", v1.EventTypeWarning, "Pod", "velero-6d9c8-x2k4p", now.Add(-30*time.Minute)),
+		warning("newer", v1.EventTypeWarning, "Deployment", "velero", now.Add(-time.Minute)),
+		warning("normal", v1.EventTypeNormal, "Pod", "velero-6d9c8-x2k4p", now),
+		warning("other", v1.EventTypeWarning, "Backup", "velero", now),
+	}
+
+	actual, err := getControllerHealth(context.Background(), fake.NewSimpleClientset(objects...), "velero", now)
+	if err != nil {
+		t.Fatalf("getControllerHealth() returned unexpected error: %s", err.Error())
+	}
+
+	server := actual.Server
+	if server == nil || server.Name != "velero" || server.Healthy || server.Desired != 1 || server.Ready != 0 ||
+		server.Image != "velero/velero:v1.14.0" || server.Version != "v1.14.0" || server.Restarts != 5 {
+		t.Errorf("getControllerHealth().Server == \n%#v\n", server)
+	}
+
+	if server != nil {
+		warnings := make([]string, 0)
+		for _, item := range server.Warnings {
+			warnings = append(warnings, item.ObjectMeta.Name)
+		}
+		if expected := []string{"newer", "older"}; !reflect.DeepEqual(warnings, expected) {
+			t.Errorf("getControllerHealth().Server.Warnings == %#v, expected %#v", warnings, expected)
+		}
+	}
+
+	nodeAgent := actual.NodeAgent
+	expected := &ComponentHealth{
+		Name: "node-agent", Healthy: true, Desired: 2, Ready: 2, Image: "velero/velero@sha256:0123", Restarts: 1,
+		Warnings: []common.Event{},
+	}
+	if !reflect.DeepEqual(nodeAgent, expected) {
+		t.Errorf("getControllerHealth().NodeAgent == \n%#v\nexpected \n%#v\n", nodeAgent, expected)
+	}
+}
+
+func TestToImageVersion(t *testing.T) {
+	cases := []struct {
+		image    string
+		expected string
+	}{
+		{"velero/velero:v1.14.0", "v1.14.0"},
+		{"registry.local:5000/velero/velero:v1.13.2", "v1.13.2"},
+		{"registry.local:5000/velero/velero", ""},
+		{"velero/velero:v1.14.0@sha256:0123", "v1.14.0"},
+		{"velero/velero", ""},
+	}
+
+	for _, c := range cases {
+		actual := toImageVersion(c.image)
+		if actual != c.expected {
+			t.Errorf("toImageVersion(%#v) == %#v, expected %#v", c.image, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+// HookErrorMode tells Velero what to do when a backup or restore hook command fails.
+type HookErrorMode string
+
+const (
+	HookErrorModeContinue HookErrorMode = "Continue"
+	HookErrorModeFail     HookErrorMode = "Fail"
+)
+
+// IsValid tells whether Velero accepts the mode. Unset mode is valid, Velero picks the default of the hook.
+func (in HookErrorMode) IsValid() bool {
+	return in == "" || in == HookErrorModeContinue || in == HookErrorModeFail
+}
@@ -0,0 +1,149 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+)
+
+// serverLabelSelector matches the Deployment of the Velero server created by `velero install` and the Helm chart.
+const serverLabelSelector = "component=velero"
+
+// Secret and key `velero install` stores the credentials of the object storage provider in. The Velero server reads
+// them from the file the key is mounted as.
+const (
+	CredentialsSecretName = "cloud-credentials"
+	CredentialsSecretKey  = "cloud"
+)
+
+// ErrVeleroNotInstalled is returned in place of a not found error when the Velero API is missing from the cluster.
+var ErrVeleroNotInstalled = errors.NewNotFound("Velero is not installed in the cluster")
+
+// IsNotInstalled reports whether the error tells that Velero is not installed in the cluster.
+func IsNotInstalled(err error) bool {
+	return stderrors.Is(err, ErrVeleroNotInstalled)
+}
+
+// InstallStatus tells whether Velero is installed in the cluster, so that clients can hide what depends on it.
+type InstallStatus struct {
+	Installed bool `json:"installed"`
+
+	// Versions of the Velero API group served by the cluster, e.g. v1 and v2alpha1.
+	Versions []string `json:"versions"`
+
+	// Namespace Velero is installed in. It is empty when it could not be detected.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// GetInstallStatus returns whether and where Velero is installed in the cluster.
+func GetInstallStatus(request *http.Request) (*InstallStatus, error) {
+	ctx, cancel := OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return getInstallStatus(ctx, k8sClient, dynamicClient)
+}
+
+// CheckInstalled returns ErrVeleroNotInstalled in place of a not found error when the Velero API is missing from the
+// cluster. Other errors are returned as they are.
+func CheckInstalled(request *http.Request, err error) error {
+	if !k8serrors.IsNotFound(err) || IsNotInstalled(err) {
+		return err
+	}
+
+	k8sClient, clientErr := client.Client(request)
+	if clientErr != nil {
+		return err
+	}
+
+	if versions, discoveryErr := getServedVersions(k8sClient.Discovery()); discoveryErr == nil && len(versions) == 0 {
+		return ErrVeleroNotInstalled
+	}
+
+	return err
+}
+
+func getInstallStatus(ctx context.Context, k8sClient kubernetes.Interface, dynamicClient dynamic.Interface) (*InstallStatus, error) {
+	versions, err := getServedVersions(k8sClient.Discovery())
+	if err != nil {
+		return nil, err
+	}
+
+	status := &InstallStatus{Installed: len(versions) > 0, Versions: versions}
+	if status.Installed {
+		status.Namespace = getInstallNamespace(ctx, k8sClient, dynamicClient)
+	}
+
+	return status, nil
+}
+
+func getServedVersions(discoveryClient discovery.DiscoveryInterface) ([]string, error) {
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0)
+	for _, group := range groups.Groups {
+		if group.Name != GroupName {
+			continue
+		}
+		for _, version := range group.Versions {
+			versions = append(versions, version.Version)
+		}
+	}
+
+	return versions, nil
+}
+
+// getInstallNamespace detects the Velero namespace from backup storage locations, which Velero only reads from its own
+// namespace, and falls back to the namespace of the Velero server Deployment. Errors are ignored as both are only
+// hints the user may not be allowed to read.
+func getInstallNamespace(ctx context.Context, k8sClient kubernetes.Interface, dynamicClient dynamic.Interface) string {
+	locations, err := dynamicClient.Resource(BackupStorageLocationGVR).List(ctx, metav1.ListOptions{Limit: 1})
+	if err == nil && len(locations.Items) > 0 {
+		return locations.Items[0].GetNamespace()
+	}
+
+	deployments, err := k8sClient.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: serverLabelSelector,
+		Limit:         1,
+	})
+	if err == nil && len(deployments.Items) > 0 {
+		return deployments.Items[0].Namespace
+	}
+
+	return ""
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/dashboard/errors"
+)
+
+func newBackupStorageLocation(namespace string) *unstructured.Unstructured {
+	item := &unstructured.Unstructured{}
+	item.SetAPIVersion("velero.io/v1")
+	item.SetKind("BackupStorageLocation")
+	item.SetNamespace(namespace)
+	item.SetName("default")
+	return item
+}
+
+func TestGetInstallStatus(t *testing.T) {
+	veleroResources := []*metav1.APIResourceList{
+		{GroupVersion: "velero.io/v1", APIResources: []metav1.APIResource{{Name: "backups"}}},
+		{GroupVersion: "velero.io/v2alpha1", APIResources: []metav1.APIResource{{Name: "datauploads"}}},
+	}
+	server := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name: "velero", Namespace: "backup-system", Labels: map[string]string{"component": "velero"},
+	}}
+
+	cases := []struct {
+		info      string
+		resources []*metav1.APIResourceList
+		objects   []runtime.Object
+		locations []runtime.Object
+		expected  *InstallStatus
+	}{
+		{
+			"Velero is not installed",
+			[]*metav1.APIResourceList{{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "pods"}}}},
+			nil,
+			nil,
+			&InstallStatus{Installed: false, Versions: []string{}},
+		},
+		{
+			"namespace is detected from backup storage locations",
+			veleroResources,
+			[]runtime.Object{server},
+			[]runtime.Object{newBackupStorageLocation("velero")},
+			&InstallStatus{Installed: true, Versions: []string{"v1", "v2alpha1"}, Namespace: "velero"},
+		},
+		{
+			"namespace is detected from the server deployment",
+			veleroResources,
+			[]runtime.Object{server},
+			nil,
+			&InstallStatus{Installed: true, Versions: []string{"v1", "v2alpha1"}, Namespace: "backup-system"},
+		},
+		{
+			"namespace is not detected",
+			veleroResources,
+			nil,
+			nil,
+			&InstallStatus{Installed: true, Versions: []string{"v1", "v2alpha1"}},
+		},
+	}
+
+	for _, c := range cases {
+		k8sClient := fake.NewSimpleClientset(c.objects...)
+		k8sClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = c.resources
+		dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{BackupStorageLocationGVR: "BackupStorageLocationList"}, c.locations...)
+
+		actual, err := getInstallStatus(context.TODO(), k8sClient, dynamicClient)
+		if err != nil {
+			t.Errorf("%s: getInstallStatus() returned error: %s", c.info, err.Error())
+			continue
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: getInstallStatus() == \n%#v\nexpected \n%#v\n", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestIsNotInstalled(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{ErrVeleroNotInstalled, true},
+		{fmt.Errorf("failed to list backups: %w", ErrVeleroNotInstalled), true},
+		{errors.NewNotFound("backups.velero.io \"daily\" not found"), false},
+	}
+
+	for _, c := range cases {
+		if actual := IsNotInstalled(c.err); actual != c.expected {
+			t.Errorf("IsNotInstalled(%#v) == %t, expected %t", c.err, actual, c.expected)
+		}
+	}
+}
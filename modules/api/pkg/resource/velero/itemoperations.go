@@ -0,0 +1,171 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ItemOperationsStatus counts the asynchronous item operations plugins started for a backup or restore. Backups and
+// restores wait in the WaitingForPluginOperations phases until all of them finish.
+type ItemOperationsStatus struct {
+	Attempted int `json:"attempted"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// NewItemOperationsStatus reads the counts from the status of a backup or restore, with prefix "backup" or "restore"
+// respectively. It returns nil when no operation was attempted.
+func NewItemOperationsStatus(status map[string]interface{}, prefix string) *ItemOperationsStatus {
+	attempted, _ := status[prefix+"ItemOperationsAttempted"].(float64)
+	if attempted == 0 {
+		return nil
+	}
+
+	completed, _ := status[prefix+"ItemOperationsCompleted"].(float64)
+	failed, _ := status[prefix+"ItemOperationsFailed"].(float64)
+	return &ItemOperationsStatus{Attempted: int(attempted), Completed: int(completed), Failed: int(failed)}
+}
+
+// ItemOperationList contains the asynchronous item operations of a backup or restore.
+type ItemOperationList struct {
+	Items []ItemOperation `json:"items"`
+}
+
+// ItemOperation is a single asynchronous operation a plugin ran for an item, e.g. uploading the data of a volume
+// snapshot.
+type ItemOperation struct {
+	OperationID string `json:"operationID"`
+
+	// Action is the name of the backup or restore item action plugin that started the operation.
+	Action string `json:"action"`
+
+	// Item the operation was started for.
+	Group     string `json:"group,omitempty"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	Phase       string `json:"phase"`
+	Error       string `json:"error,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Progress of the operation in the units reported by the plugin, e.g. bytes.
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+	Units     string `json:"units,omitempty"`
+
+	Created *metav1.Time `json:"created,omitempty"`
+	Started *metav1.Time `json:"started,omitempty"`
+	Updated *metav1.Time `json:"updated,omitempty"`
+}
+
+// rawItemOperation is an item operation as Velero stores it in object storage. Backup and restore operations differ
+// only in the name of the action field.
+type rawItemOperation struct {
+	Spec struct {
+		BackupItemAction   string `json:"backupItemAction"`
+		RestoreItemAction  string `json:"restoreItemAction"`
+		OperationID        string `json:"operationID"`
+		ResourceIdentifier struct {
+			Group     string `json:"group"`
+			Resource  string `json:"resource"`
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+		} `json:"resourceIdentifier"`
+	} `json:"spec"`
+	Status struct {
+		Phase          string       `json:"phase"`
+		Error          string       `json:"error"`
+		Description    string       `json:"description"`
+		NTotal         int64        `json:"nTotal"`
+		NCompleted     int64        `json:"nCompleted"`
+		OperationUnits string       `json:"operationUnits"`
+		Created        *metav1.Time `json:"created"`
+		Started        *metav1.Time `json:"started"`
+		Updated        *metav1.Time `json:"updated"`
+	} `json:"status"`
+}
+
+// GetItemOperations downloads the item operations of a backup or restore in the namespace. The kind is either
+// DownloadTargetKindBackupItemOperations or DownloadTargetKindRestoreItemOperations.
+func GetItemOperations(request *http.Request, kind DownloadTargetKind, namespace, name string) (*ItemOperationList, error) {
+	ctx, cancel := OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := Download(ctx, dynamicClient, namespace, kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := parseItemOperations(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ItemOperationList{Items: items}, nil
+}
+
+// parseItemOperations returns the operations sorted by creation time, the ones started first at the top.
+func parseItemOperations(data []byte) ([]ItemOperation, error) {
+	var rawOperations []rawItemOperation
+	if err := json.Unmarshal(data, &rawOperations); err != nil {
+		return nil, err
+	}
+
+	result := make([]ItemOperation, 0, len(rawOperations))
+	for _, raw := range rawOperations {
+		action := raw.Spec.BackupItemAction
+		if action == "" {
+			action = raw.Spec.RestoreItemAction
+		}
+
+		result = append(result, ItemOperation{
+			OperationID: raw.Spec.OperationID,
+			Action:      action,
+			Group:       raw.Spec.ResourceIdentifier.Group,
+			Resource:    raw.Spec.ResourceIdentifier.Resource,
+			Namespace:   raw.Spec.ResourceIdentifier.Namespace,
+			Name:        raw.Spec.ResourceIdentifier.Name,
+			Phase:       raw.Status.Phase,
+			Error:       raw.Status.Error,
+			Description: raw.Status.Description,
+			Total:       raw.Status.NTotal,
+			Completed:   raw.Status.NCompleted,
+			Units:       raw.Status.OperationUnits,
+			Created:     raw.Status.Created,
+			Started:     raw.Status.Started,
+			Updated:     raw.Status.Updated,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Created == nil || result[j].Created == nil {
+			return result[i].Created != nil
+		}
+		return result[i].Created.Before(result[j].Created)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewItemOperationsStatus(t *testing.T) {
+	cases := []struct {
+		status   map[string]interface{}
+		prefix   string
+		expected *ItemOperationsStatus
+	}{
+		{map[string]interface{}{"phase": "Completed"}, "backup", nil},
+		{
+			map[string]interface{}{
+				"backupItemOperationsAttempted": float64(3),
+				"backupItemOperationsCompleted": float64(2),
+				"backupItemOperationsFailed":    float64(1),
+			},
+			"backup",
+			&ItemOperationsStatus{Attempted: 3, Completed: 2, Failed: 1},
+		},
+		{
+			map[string]interface{}{"restoreItemOperationsAttempted": float64(2)},
+			"restore",
+			&ItemOperationsStatus{Attempted: 2},
+		},
+	}
+
+	for _, c := range cases {
+		actual := NewItemOperationsStatus(c.status, c.prefix)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("NewItemOperationsStatus(%#v, %s) == %#v, expected %#v", c.status, c.prefix, actual, c.expected)
+		}
+	}
+}
+
+func TestParseItemOperations(t *testing.T) {
+	data := []byte(`[
+		{
+			"spec": {
+				"backupName": "daily",
+				"backupItemAction": "velero.io/csi-pvc-backupper",
+				"resourceIdentifier": {"Group": "", "Resource": "persistentvolumeclaims", "Namespace": "shop", "Name": "data"},
+				"operationID": "du-2"
+			},
+			"status": {
+				"phase": "InProgress",
+				"nTotal": 4096,
+				"nCompleted": 1024,
+				"operationUnits": "Bytes",
+				"created": "2026-10-17T01:00:05Z"
+			}
+		},
+		{
+			"spec": {
+				"restoreItemAction": "velero.io/csi-pvc-restorer",
+				"resourceIdentifier": {"Group": "", "Resource": "persistentvolumeclaims", "Namespace": "shop", "Name": "logs"},
+				"operationID": "du-1"
+			},
+			"status": {"phase": "Failed", "error": "timed out", "created": "2026-10-17T01:00:00Z"}
+		}
+	]`)
+
+	newTime := func(value string) *metav1.Time {
+		parsed, _ := time.Parse(time.RFC3339, value)
+		result := metav1.NewTime(parsed.Local())
+		return &result
+	}
+
+	expected := []ItemOperation{
+		{
+			OperationID: "du-1",
+			Action:      "velero.io/csi-pvc-restorer",
+			Resource:    "persistentvolumeclaims",
+			Namespace:   "shop",
+			Name:        "logs",
+			Phase:       "Failed",
+			Error:       "timed out",
+			Created:     newTime("2026-10-17T01:00:00Z"),
+		},
+		{
+			OperationID: "du-2",
+			Action:      "velero.io/csi-pvc-backupper",
+			Resource:    "persistentvolumeclaims",
+			Namespace:   "shop",
+			Name:        "data",
+			Phase:       "InProgress",
+			Total:       4096,
+			Completed:   1024,
+			Units:       "Bytes",
+			Created:     newTime("2026-10-17T01:00:05Z"),
+		},
+	}
+
+	actual, err := parseItemOperations(data)
+	if err != nil {
+		t.Fatalf("parseItemOperations() returned error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("parseItemOperations() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
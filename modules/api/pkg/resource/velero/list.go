@@ -0,0 +1,213 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/common"
+)
+
+// listPageSize is the number of resources read from the API server at once.
+const listPageSize = 500
+
+// namespaceGVR is the resource of namespaces, listed when the user may not list a Velero resource cluster wide.
+var namespaceGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// List reads resources page by page, so that a long list is never held in memory as a whole, and stops after
+// maxItems of them. It reports whether resources were left out. A maxItems of 0 reads all of them.
+func List(ctx context.Context, client dynamic.ResourceInterface, options metav1.ListOptions, maxItems int) ([]unstructured.Unstructured, bool, error) {
+	items := make([]unstructured.Unstructured, 0)
+	for {
+		options.Limit = listPageSize
+		if maxItems > 0 && maxItems-len(items) < listPageSize {
+			options.Limit = int64(maxItems - len(items))
+		}
+
+		page, err := client.List(ctx, options)
+		if err != nil {
+			return nil, false, err
+		}
+
+		items = append(items, page.Items...)
+		options.Continue = page.GetContinue()
+		if options.Continue == "" {
+			return items, false, nil
+		}
+		if maxItems > 0 && len(items) >= maxItems {
+			return items, true, nil
+		}
+	}
+}
+
+// NewListTruncatedError returns a non-critical error telling the user that only the first maxItems resources of a
+// kind were processed.
+func NewListTruncatedError(kind string, maxItems int) error {
+	return k8serrors.NewRequestEntityTooLargeError(
+		fmt.Sprintf("only the first %d %s are included, narrow the list down by namespace", maxItems, kind))
+}
+
+// ListInNamespaces lists the Velero resource in the namespaces of the query, reading at most maxItems of them. Users
+// who may only read some of the namespaces get the resources of those, rather than a forbidden error for the whole
+// list: when the cluster wide list is forbidden, every namespace the user can see is listed on its own, and the
+// namespaces the user may not list the resource in are left out. Left out namespaces and resources beyond maxItems
+// are reported as non-critical errors. A forbidden error is only returned when none of the namespaces can be read.
+// Namespaces listed on their own are listed concurrently, so once maxItems is reached, which of them fill up the list
+// depends on which were listed first.
+func ListInNamespaces(ctx context.Context, client dynamic.Interface, resource schema.GroupVersionResource, namespace *common.NamespaceQuery, options metav1.ListOptions, maxItems int) ([]unstructured.Unstructured, []error, error) {
+	namespaces := namespace.Namespaces()
+	if len(namespaces) == 0 {
+		items, truncated, err := List(ctx, client.Resource(resource).Namespace(metav1.NamespaceAll), options, maxItems)
+		if err == nil {
+			return items, toListErrors(resource, truncated, maxItems), nil
+		}
+		if !k8serrors.IsForbidden(err) {
+			return nil, nil, err
+		}
+
+		var namespacesErr error
+		namespaces, namespacesErr = listNamespaceNames(ctx, client)
+		if namespacesErr != nil {
+			klog.V(args.LogLevelVerbose).Infof("Could not list namespaces to list %s in: %s", resource.Resource,
+				namespacesErr.Error())
+			return nil, nil, err
+		}
+	}
+
+	lists, err := listNamespaces(ctx, client, resource, namespaces, options, maxItems)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]unstructured.Unstructured, 0)
+	forbidden := make([]string, 0)
+	truncated := false
+	var forbiddenErr error
+	for i, list := range lists {
+		if list.forbiddenErr != nil {
+			forbidden = append(forbidden, namespaces[i])
+			forbiddenErr = list.forbiddenErr
+			continue
+		}
+		// Namespaces are listed concurrently, so a namespace may have been left out for reaching maxItems before the
+		// namespaces preceding it were listed. Those still fill up the list in the order of the namespaces.
+		truncated = truncated || list.truncated
+		items = append(items, list.items...)
+		if maxItems > 0 && len(items) > maxItems {
+			items = items[:maxItems]
+			truncated = true
+		}
+	}
+
+	if len(forbidden) > 0 && len(forbidden) == len(namespaces) {
+		return nil, nil, forbiddenErr
+	}
+
+	nonCriticalErrors := toListErrors(resource, truncated, maxItems)
+	if len(forbidden) > 0 {
+		nonCriticalErrors = append(nonCriticalErrors, newNamespacesForbiddenError(resource, namespace, forbidden))
+	}
+
+	return items, nonCriticalErrors, nil
+}
+
+// namespaceList is the result of listing a Velero resource in a single namespace.
+type namespaceList struct {
+	items     []unstructured.Unstructured
+	truncated bool
+
+	// forbiddenErr is set when the user may not list the resource in the namespace.
+	forbiddenErr error
+}
+
+// listNamespaces lists the resource in each of the namespaces concurrently and returns the lists in the order of the
+// namespaces. Namespaces are listed with the number of items still missing to reach maxItems as their limit, and are
+// reported as truncated without being listed once it is reached.
+func listNamespaces(ctx context.Context, client dynamic.Interface, resource schema.GroupVersionResource, namespaces []string, options metav1.ListOptions, maxItems int) ([]namespaceList, error) {
+	lists := make([]namespaceList, len(namespaces))
+	var listed atomic.Int64
+	err := ForEach(ctx, len(namespaces), func(ctx context.Context, i int) error {
+		limit := 0
+		if maxItems > 0 {
+			limit = maxItems - int(listed.Load())
+			if limit <= 0 {
+				lists[i].truncated = true
+				return nil
+			}
+		}
+
+		items, truncated, err := List(ctx, client.Resource(resource).Namespace(namespaces[i]), options, limit)
+		if k8serrors.IsForbidden(err) {
+			lists[i].forbiddenErr = err
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		listed.Add(int64(len(items)))
+		lists[i] = namespaceList{items: items, truncated: truncated}
+		return nil
+	})
+
+	return lists, err
+}
+
+func toListErrors(resource schema.GroupVersionResource, truncated bool, maxItems int) []error {
+	nonCriticalErrors := make([]error, 0)
+	if truncated {
+		nonCriticalErrors = append(nonCriticalErrors, NewListTruncatedError(resource.Resource, maxItems))
+	}
+
+	return nonCriticalErrors
+}
+
+// newNamespacesForbiddenError returns a non-critical error naming the namespaces left out of a list. Namespaces the
+// user did not select are only counted, as they are usually most of the cluster.
+func newNamespacesForbiddenError(resource schema.GroupVersionResource, namespace *common.NamespaceQuery, forbidden []string) error {
+	message := fmt.Sprintf("missing permission to list %s.%s in namespaces %s, they are left out", resource.Resource,
+		resource.Group, strings.Join(forbidden, ", "))
+	if len(namespace.Namespaces()) == 0 {
+		message = fmt.Sprintf("missing permission to list %s.%s cluster wide, only the namespaces they can be listed in "+
+			"are included, %d namespaces are left out", resource.Resource, resource.Group, len(forbidden))
+	}
+
+	return k8serrors.NewForbidden(resource.GroupResource(), "", fmt.Errorf("%s", message))
+}
+
+func listNamespaceNames(ctx context.Context, client dynamic.Interface) ([]string, error) {
+	items, _, err := List(ctx, client.Resource(namespaceGVR), metav1.ListOptions{}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.GetName())
+	}
+
+	return names, nil
+}
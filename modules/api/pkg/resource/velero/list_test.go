@@ -0,0 +1,205 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"k8s.io/dashboard/api/pkg/resource/common"
+)
+
+// pagingResource serves total backups honoring the limit and continue list options and counts the requests made.
+// The dynamic fake client ignores both options, so List is implemented here.
+type pagingResource struct {
+	dynamic.ResourceInterface
+	total    int
+	requests int
+}
+
+func (in *pagingResource) List(_ context.Context, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	in.requests++
+	offset, _ := strconv.Atoi(options.Continue)
+	limit := int(options.Limit)
+
+	page := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "velero.io/v1", "kind": "BackupList"}}
+	for i := offset; i < in.total && i < offset+limit; i++ {
+		item := unstructured.Unstructured{}
+		item.SetAPIVersion("velero.io/v1")
+		item.SetKind("Backup")
+		item.SetName(fmt.Sprintf("backup-%d", i))
+		page.Items = append(page.Items, item)
+	}
+	if offset+limit < in.total {
+		page.SetContinue(strconv.Itoa(offset + limit))
+	}
+
+	return page, nil
+}
+
+func TestList(t *testing.T) {
+	cases := []struct {
+		info              string
+		total             int
+		maxItems          int
+		expectedItems     int
+		expectedTruncated bool
+		expectedRequests  int
+	}{
+		{"reads all pages without a limit", 1200, 0, 1200, false, 3},
+		{"reads a list shorter than the limit", 300, 1000, 300, false, 1},
+		{"reads a list exactly as long as the limit", 1000, 1000, 1000, false, 2},
+		{"stops at the limit", 1200, 700, 700, true, 2},
+	}
+
+	for _, c := range cases {
+		resource := &pagingResource{total: c.total}
+
+		items, truncated, err := List(context.TODO(), resource, metav1.ListOptions{}, c.maxItems)
+		if err != nil {
+			t.Fatalf("%s: List() returned error: %s", c.info, err.Error())
+		}
+
+		if len(items) != c.expectedItems || truncated != c.expectedTruncated || resource.requests != c.expectedRequests {
+			t.Errorf("%s: List() returned %d items, truncated %t in %d requests, expected %d items, "+
+				"truncated %t in %d requests", c.info, len(items), truncated, resource.requests, c.expectedItems, c.expectedTruncated,
+				c.expectedRequests)
+		}
+	}
+}
+
+func newNamespacedObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	return object
+}
+
+func TestListInNamespaces(t *testing.T) {
+	cases := []struct {
+		info                  string
+		namespaces            []string
+		readable              []string
+		namespacesListable    bool
+		expectedNames         []string
+		expectedErrors        int
+		expectedForbiddenList bool
+	}{
+		{"lists cluster wide when allowed", nil, nil, true, []string{"a-backup", "b-backup", "c-backup"}, 0, false},
+		{"falls back to readable namespaces", nil, []string{"a", "c"}, true, []string{"a-backup", "c-backup"}, 1, false},
+		{"fails when namespaces cannot be listed", nil, []string{"a"}, false, nil, 0, true},
+		{"fails when no namespace is readable", nil, []string{}, true, nil, 0, true},
+		{"lists only the selected namespaces", []string{"a", "b"}, nil, true, []string{"a-backup", "b-backup"}, 0, false},
+		{"leaves out forbidden selected namespaces", []string{"a", "b"}, []string{"a"}, false, []string{"a-backup"}, 1, false},
+		{"fails when the selected namespace is forbidden", []string{"b"}, []string{"a"}, true, nil, 0, true},
+	}
+
+	namespaceGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	for _, c := range cases {
+		client := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{BackupGVR: "BackupList", namespaceGVR: "NamespaceList"},
+			newNamespacedObject("v1", "Namespace", "", "a"),
+			newNamespacedObject("v1", "Namespace", "", "b"),
+			newNamespacedObject("v1", "Namespace", "", "c"),
+			newNamespacedObject("velero.io/v1", "Backup", "a", "a-backup"),
+			newNamespacedObject("velero.io/v1", "Backup", "b", "b-backup"),
+			newNamespacedObject("velero.io/v1", "Backup", "c", "c-backup"))
+
+		client.PrependReactor("list", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			resource := action.GetResource().GroupResource()
+			if action.GetResource() == namespaceGVR {
+				if c.namespacesListable {
+					return false, nil, nil
+				}
+				return true, nil, k8serrors.NewForbidden(resource, "", fmt.Errorf("forbidden"))
+			}
+
+			if c.readable == nil {
+				return false, nil, nil
+			}
+			for _, namespace := range c.readable {
+				if action.GetNamespace() == namespace {
+					return false, nil, nil
+				}
+			}
+			return true, nil, k8serrors.NewForbidden(resource, "", fmt.Errorf("forbidden"))
+		})
+
+		items, nonCriticalErrors, err := ListInNamespaces(context.TODO(), client, BackupGVR,
+			common.NewNamespaceQuery(c.namespaces), metav1.ListOptions{}, 0)
+		if c.expectedForbiddenList {
+			if !k8serrors.IsForbidden(err) {
+				t.Errorf("%s: ListInNamespaces() returned error %v, expected forbidden", c.info, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: ListInNamespaces() returned error: %s", c.info, err.Error())
+		}
+
+		var names []string
+		for _, item := range items {
+			names = append(names, item.GetName())
+		}
+
+		if !reflect.DeepEqual(names, c.expectedNames) || len(nonCriticalErrors) != c.expectedErrors {
+			t.Errorf("%s: ListInNamespaces() returned %v with %d errors, expected %v with %d errors", c.info, names,
+				len(nonCriticalErrors), c.expectedNames, c.expectedErrors)
+		}
+	}
+}
+
+func TestListInNamespacesTruncated(t *testing.T) {
+	client := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{BackupGVR: "BackupList"},
+		newNamespacedObject("velero.io/v1", "Backup", "a", "a-backup"),
+		newNamespacedObject("velero.io/v1", "Backup", "b", "b-backup"),
+		newNamespacedObject("velero.io/v1", "Backup", "c", "c-backup"))
+
+	// Namespaces are listed concurrently, so which of them fill up the list depends on which are listed first, but
+	// the items are kept in the order of the namespaces.
+	for i := 0; i < 10; i++ {
+		items, nonCriticalErrors, err := ListInNamespaces(context.TODO(), client, BackupGVR,
+			common.NewNamespaceQuery([]string{"a", "b", "c"}), metav1.ListOptions{}, 2)
+		if err != nil {
+			t.Fatalf("ListInNamespaces() returned error: %s", err.Error())
+		}
+
+		var names []string
+		for _, item := range items {
+			names = append(names, item.GetName())
+		}
+
+		if len(names) != 2 || !sort.StringsAreSorted(names) || len(nonCriticalErrors) != 1 {
+			t.Errorf("ListInNamespaces() returned %v with %d errors, expected 2 backups in namespace order with 1 "+
+				"error", names, len(nonCriticalErrors))
+		}
+	}
+}
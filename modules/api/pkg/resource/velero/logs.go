@@ -0,0 +1,33 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"net/http"
+)
+
+// GetLogs downloads the logs Velero wrote while running a backup or restore in the namespace. The kind is either
+// DownloadTargetKindBackupLog or DownloadTargetKindRestoreLog.
+func GetLogs(request *http.Request, kind DownloadTargetKind, namespace, name string) ([]byte, error) {
+	ctx, cancel := OperationContext(request)
+	defer cancel()
+
+	dynamicClient, err := DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return Download(ctx, dynamicClient, namespace, kind, name)
+}
@@ -0,0 +1,207 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package maintenance removes Velero request objects that served their purpose. Velero deletes them on its own only
+// while its controllers run, and interrupted flows such as log downloads can leave them behind.
+package maintenance
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
+	"k8s.io/dashboard/types"
+)
+
+// DefaultCleanupOlderThan is how old requests have to be to be removed when the caller does not ask for a specific age.
+const DefaultCleanupOlderThan = "24h"
+
+// CleanupReason tells why a request is removed.
+type CleanupReason string
+
+const (
+	// CleanupReasonProcessed means Velero processed the request before the cutoff.
+	CleanupReasonProcessed CleanupReason = "Processed"
+	// CleanupReasonExpired means the download URL of a download request expired.
+	CleanupReasonExpired CleanupReason = "Expired"
+	// CleanupReasonAbandoned means a download request was created before the cutoff and never processed, e.g. because
+	// its storage location was unavailable.
+	CleanupReasonAbandoned CleanupReason = "Abandoned"
+)
+
+// CleanupSpec selects stale requests in a namespace.
+type CleanupSpec struct {
+	// Namespace of the requests, the Velero namespace when empty.
+	Namespace string `json:"namespace"`
+
+	// OlderThan selects requests created before that long ago, e.g. "24h" or "7d". Expired download requests are
+	// removed regardless of their age.
+	OlderThan string `json:"olderThan,omitempty"`
+}
+
+// CleanupResult contains the outcome of removing each stale request.
+type CleanupResult struct {
+	Items []CleanupItem `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// CleanupItem is the outcome of removing a single request, Error is set when deleting it failed.
+type CleanupItem struct {
+	Kind   string        `json:"kind"`
+	Name   string        `json:"name"`
+	Reason CleanupReason `json:"reason"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// staleRequests describes how to tell stale requests of a kind apart.
+type staleRequests struct {
+	resource schema.GroupVersionResource
+	kind     string
+	reason   func(item unstructured.Unstructured, cutoff, now time.Time) (CleanupReason, bool)
+}
+
+var cleanedUp = []staleRequests{
+	{velero.DeleteBackupRequestGVR, "DeleteBackupRequest", deleteBackupRequestReason},
+	{velero.DownloadRequestGVR, "DownloadRequest", downloadRequestReason},
+}
+
+// CleanupRequests removes processed delete backup requests and download requests created before the cutoff, expired
+// download requests and download requests Velero never processed. Delete backup requests still being processed are
+// kept, as removing them would leave the deletion unfinished. Deleting a request does not stop at errors of previous
+// ones, they are reported per item instead. In dry run each deletion is only validated by the API server.
+func CleanupRequests(request *http.Request, spec *CleanupSpec, dryRun bool) (*CleanupResult, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	spec.Namespace = velero.NamespaceOrDefault(request, spec.Namespace)
+	if spec.OlderThan == "" {
+		spec.OlderThan = DefaultCleanupOlderThan
+	}
+
+	olderThan, err := backup.ParseWindow(spec.OlderThan)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stale := range cleanedUp {
+		if err := velero.CheckAccess(request, velero.VerbDelete, stale.resource, spec.Namespace, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result, err := cleanupRequests(ctx, dynamicClient, spec.Namespace, now.Add(-olderThan), now, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Items {
+		if item.Error == "" {
+			meta := types.ObjectMeta{Namespace: spec.Namespace, Name: item.Name}
+			if err := audit.Record(request, audit.ActionDeleted, toGroupVersionKind(item), meta, dryRun); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// toGroupVersionKind returns the kind of the removed request.
+func toGroupVersionKind(item CleanupItem) schema.GroupVersionKind {
+	for _, stale := range cleanedUp {
+		if stale.kind == item.Kind {
+			return stale.resource.GroupVersion().WithKind(stale.kind)
+		}
+	}
+
+	return schema.GroupVersionKind{Kind: item.Kind}
+}
+
+func cleanupRequests(ctx context.Context, client dynamic.Interface, namespace string, cutoff, now time.Time, dryRun bool) (*CleanupResult, error) {
+	result := &CleanupResult{Items: make([]CleanupItem, 0), Errors: make([]error, 0)}
+	for _, stale := range cleanedUp {
+		items, nonCriticalErrors, err := velero.ListInNamespaces(ctx, client, stale.resource,
+			common.NewSameNamespaceQuery(namespace), metav1.ListOptions{}, args.VeleroMaxListItems())
+		if err != nil {
+			return nil, err
+		}
+		result.Errors = append(result.Errors, nonCriticalErrors...)
+
+		for _, item := range items {
+			reason, ok := stale.reason(item, cutoff, now)
+			if !ok {
+				continue
+			}
+
+			cleanupItem := CleanupItem{Kind: stale.kind, Name: item.GetName(), Reason: reason}
+			err := client.Resource(stale.resource).Namespace(namespace).
+				Delete(ctx, item.GetName(), velero.DeleteOptions(dryRun))
+			if err != nil {
+				cleanupItem.Error = err.Error()
+			}
+
+			result.Items = append(result.Items, cleanupItem)
+		}
+	}
+
+	return result, nil
+}
+
+// deleteBackupRequestReason tells whether the delete backup request was processed before the cutoff.
+func deleteBackupRequestReason(item unstructured.Unstructured, cutoff, _ time.Time) (CleanupReason, bool) {
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	if velero.DeleteBackupRequestPhase(phase) != velero.DeleteBackupRequestPhaseProcessed ||
+		!item.GetCreationTimestamp().Time.Before(cutoff) {
+		return "", false
+	}
+
+	return CleanupReasonProcessed, true
+}
+
+// downloadRequestReason tells whether the download URL expired, or the download request was created before the cutoff.
+func downloadRequestReason(item unstructured.Unstructured, cutoff, now time.Time) (CleanupReason, bool) {
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	expiration, _, _ := unstructured.NestedString(item.Object, "status", "expiration")
+	if expires, err := time.Parse(time.RFC3339, expiration); err == nil && expires.Before(now) {
+		return CleanupReasonExpired, true
+	}
+
+	if !item.GetCreationTimestamp().Time.Before(cutoff) {
+		return "", false
+	}
+
+	if phase == "Processed" {
+		return CleanupReasonProcessed, true
+	}
+
+	return CleanupReasonAbandoned, true
+}
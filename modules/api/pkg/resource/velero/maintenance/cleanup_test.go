@@ -0,0 +1,90 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	velerofake "k8s.io/dashboard/api/pkg/resource/velero/fake"
+)
+
+func newRequest(resource schema.GroupVersionResource, name string, created time.Time, status map[string]interface{}) *unstructured.Unstructured {
+	item := velerofake.NewObject(resource, "velero", name)
+	item.SetCreationTimestamp(metav1.NewTime(created))
+	if status != nil {
+		item.Object["status"] = status
+	}
+
+	return item
+}
+
+func TestCleanupRequests(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	cutoff := now.Add(-24 * time.Hour)
+	old, recent := now.Add(-48*time.Hour), now.Add(-time.Hour)
+	processed := map[string]interface{}{"phase": "Processed"}
+
+	dynamicClient := velerofake.NewDynamicClient(nil,
+		newRequest(velero.DeleteBackupRequestGVR, "daily-old", old, processed),
+		newRequest(velero.DeleteBackupRequestGVR, "daily-recent", recent, processed),
+		newRequest(velero.DeleteBackupRequestGVR, "daily-running", old, map[string]interface{}{"phase": "InProgress"}),
+		newRequest(velero.DownloadRequestGVR, "log-expired", recent, map[string]interface{}{
+			"phase": "Processed", "expiration": now.Add(-time.Minute).Format(time.RFC3339)}),
+		newRequest(velero.DownloadRequestGVR, "log-valid", recent, map[string]interface{}{
+			"phase": "Processed", "expiration": now.Add(time.Minute).Format(time.RFC3339)}),
+		newRequest(velero.DownloadRequestGVR, "log-abandoned", old, nil),
+	)
+
+	actual, err := cleanupRequests(context.Background(), dynamicClient, "velero", cutoff, now, false)
+	if err != nil {
+		t.Fatalf("cleanupRequests() returned error: %v", err)
+	}
+
+	expected := []CleanupItem{
+		{Kind: "DeleteBackupRequest", Name: "daily-old", Reason: CleanupReasonProcessed},
+		{Kind: "DownloadRequest", Name: "log-abandoned", Reason: CleanupReasonAbandoned},
+		{Kind: "DownloadRequest", Name: "log-expired", Reason: CleanupReasonExpired},
+	}
+	if !reflect.DeepEqual(actual.Items, expected) {
+		t.Errorf("cleanupRequests() == %#v, expected %#v", actual.Items, expected)
+	}
+
+	remaining := map[schema.GroupVersionResource][]string{
+		velero.DeleteBackupRequestGVR: {"daily-recent", "daily-running"},
+		velero.DownloadRequestGVR:     {"log-valid"},
+	}
+	for resource, expectedNames := range remaining {
+		list, err := dynamicClient.Resource(resource).Namespace("velero").List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("List(%s) returned error: %v", resource.Resource, err)
+		}
+
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		if !reflect.DeepEqual(names, expectedNames) {
+			t.Errorf("remaining %s == %v, expected %v", resource.Resource, names, expectedNames)
+		}
+	}
+}
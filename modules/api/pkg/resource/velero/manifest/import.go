@@ -0,0 +1,252 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package manifest imports Velero resources from YAML manifests, e.g. ones kept in git or exported from another
+// cluster, so that they are created through the dashboard.
+package manifest
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/restore"
+	"k8s.io/dashboard/api/pkg/resource/schedule"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/api/pkg/resource/velero/audit"
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+// importableResources are the Velero kinds that can be imported and their resources.
+var importableResources = map[string]string{
+	"Backup":                velero.BackupGVR.Resource,
+	"Schedule":              velero.ScheduleGVR.Resource,
+	"Restore":               velero.RestoreGVR.Resource,
+	"BackupStorageLocation": velero.BackupStorageLocationGVR.Resource,
+}
+
+// ImportSpec contains Velero manifests to import, as YAML or JSON documents.
+type ImportSpec struct {
+	// Namespace the objects are created in. Objects of the manifests must be in it or have no namespace.
+	Namespace string `json:"namespace"`
+	Content   string `json:"content"`
+}
+
+// ImportResult lists the objects created from the manifests, or the objects that would be created in dry run.
+type ImportResult struct {
+	Items []ImportedObject `json:"items"`
+
+	// List of non-critical errors, that occurred while recording the imported objects in the audit log.
+	Errors []error `json:"errors"`
+}
+
+// ImportedObject is a Velero object created from a manifest.
+type ImportedObject struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+}
+
+// Import creates the Velero objects of the manifests. Every object is checked by the dashboard first and then by the
+// API server in dry run, which validates it against the installed CRD schema, so that nothing is created unless all
+// objects are valid.
+func Import(request *http.Request, spec *ImportSpec, dryRun bool) (*ImportResult, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	spec.Namespace = velero.NamespaceOrDefault(request, spec.Namespace)
+	objects, err := decodeManifests(spec.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, object := range objects {
+		if err := prepareObject(object, spec.Namespace); err != nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("document %d: %s", i+1, err.Error()))
+		}
+	}
+
+	for _, object := range objects {
+		if err := velero.CheckAccess(request, velero.VerbCreate, toGVR(object), spec.Namespace, object.GetName()); err != nil {
+			return nil, err
+		}
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Items: make([]ImportedObject, 0, len(objects))}
+	for _, object := range objects {
+		created, err := dynamicClient.Resource(toGVR(object)).Namespace(spec.Namespace).
+			Create(ctx, object, velero.CreateOptions(true))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to validate %s %s: %w", object.GetKind(), object.GetName(), err)
+		}
+		result.Items = append(result.Items, toImportedObject(created))
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	result.Items = result.Items[:0]
+	for i, object := range objects {
+		audit.SetCreatedBy(request, object)
+		created, err := dynamicClient.Resource(toGVR(object)).Namespace(spec.Namespace).
+			Create(ctx, object, velero.CreateOptions(false))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to import %s %s after importing %d of %d objects: %w", object.GetKind(),
+				object.GetName(), i, len(objects), err)
+		}
+		result.Items = append(result.Items, toImportedObject(created))
+		if err := audit.Record(request, audit.ActionCreated, created.GroupVersionKind(), velero.NewObjectMeta(created.Object), dryRun); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+
+	return result, nil
+}
+
+func decodeManifests(content string) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096)
+	objects := make([]*unstructured.Unstructured, 0)
+	for {
+		object := &unstructured.Unstructured{}
+		if err := decoder.Decode(&object.Object); err != nil {
+			if stderrors.Is(err, io.EOF) {
+				break
+			}
+			return nil, errors.NewBadRequest(fmt.Sprintf("document %d: %s", len(objects)+1, err.Error()))
+		}
+
+		// Empty documents, e.g. after a trailing separator, are skipped.
+		if len(object.Object) > 0 {
+			objects = append(objects, object)
+		}
+	}
+
+	if len(objects) == 0 {
+		return nil, errors.NewBadRequest("manifests contain no objects")
+	}
+
+	return objects, nil
+}
+
+// prepareObject applies the import guardrails: only Velero backups, schedules, restores and backup storage locations
+// in the target namespace are accepted, fields set by the API server are dropped, and specs are validated as if they
+// were created through the dashboard.
+func prepareObject(object *unstructured.Unstructured, namespace string) error {
+	gv, err := schema.ParseGroupVersion(object.GetAPIVersion())
+	if err != nil || gv.Group != velero.GroupName {
+		return fmt.Errorf("%s %q is not a Velero resource", object.GetKind(), object.GetAPIVersion())
+	}
+
+	if _, ok := importableResources[object.GetKind()]; !ok {
+		return fmt.Errorf("kind %s can not be imported, expected Backup, Schedule, Restore or BackupStorageLocation",
+			object.GetKind())
+	}
+
+	if object.GetName() == "" && object.GetGenerateName() == "" {
+		return fmt.Errorf("%s requires a name", object.GetKind())
+	}
+
+	if object.GetNamespace() != "" && object.GetNamespace() != namespace {
+		return fmt.Errorf("%s %s is in namespace %s, expected %s", object.GetKind(), object.GetName(),
+			object.GetNamespace(), namespace)
+	}
+	object.SetNamespace(namespace)
+
+	// Objects exported from a cluster keep fields that belong to the original object. Owner references would let
+	// the garbage collector delete the object, as their owners do not exist in this cluster.
+	unstructured.RemoveNestedField(object.Object, "status")
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "deletionTimestamp",
+		"managedFields", "ownerReferences", "selfLink"} {
+		unstructured.RemoveNestedField(object.Object, "metadata", field)
+	}
+
+	spec, _, _ := unstructured.NestedMap(object.Object, "spec")
+	if err := validateSpec(object.GetKind(), spec); err != nil {
+		return fmt.Errorf("%s %s: %w", object.GetKind(), object.GetName(), err)
+	}
+
+	return nil
+}
+
+func validateSpec(kind string, spec map[string]interface{}) error {
+	switch kind {
+	case "Backup":
+		return validateBackupTemplate(spec)
+	case "Schedule":
+		cron, _, _ := unstructured.NestedString(spec, "schedule")
+		if err := schedule.ValidateSchedule(cron); err != nil {
+			return err
+		}
+		template, _, _ := unstructured.NestedMap(spec, "template")
+		return validateBackupTemplate(template)
+	case "Restore":
+		restoreSpec := &restore.RestoreSpec{}
+		// Velero references the resource modifier ConfigMap by an object, while the dashboard spec only holds its
+		// name. It is checked by Velero when the restore starts.
+		rawSpec := runtime.DeepCopyJSON(spec)
+		delete(rawSpec, "resourceModifier")
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSpec, restoreSpec); err != nil {
+			return err
+		}
+		if backupName, _, _ := unstructured.NestedString(spec, "backupName"); backupName == "" {
+			if scheduleName, _, _ := unstructured.NestedString(spec, "scheduleName"); scheduleName == "" {
+				return fmt.Errorf("backupName or scheduleName is required")
+			}
+		}
+		return restoreSpec.Validate()
+	case "BackupStorageLocation":
+		if provider, _, _ := unstructured.NestedString(spec, "provider"); provider == "" {
+			return fmt.Errorf("provider is required")
+		}
+		if bucket, _, _ := unstructured.NestedString(spec, "objectStorage", "bucket"); bucket == "" {
+			return fmt.Errorf("objectStorage.bucket is required")
+		}
+	}
+
+	return nil
+}
+
+func validateBackupTemplate(spec map[string]interface{}) error {
+	template := &backup.BackupTemplate{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, template); err != nil {
+		return err
+	}
+
+	return template.Validate()
+}
+
+func toGVR(object *unstructured.Unstructured) schema.GroupVersionResource {
+	return object.GroupVersionKind().GroupVersion().WithResource(importableResources[object.GetKind()])
+}
+
+func toImportedObject(object *unstructured.Unstructured) ImportedObject {
+	return ImportedObject{
+		ObjectMeta: velero.NewObjectMeta(object.Object),
+		TypeMeta:   types.TypeMeta{Kind: types.ResourceKind(object.GetKind())},
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDecodeManifests(t *testing.T) {
+	content := `apiVersion: velero.io/v1
+kind: Schedule
+metadata:
+  name: daily
+spec:
+  schedule: "0 2 * * *"
+---
+---
+{"apiVersion": "velero.io/v1", "kind": "Backup", "metadata": {"name": "before-upgrade"}}
+`
+	objects, err := decodeManifests(content)
+	if err != nil {
+		t.Fatalf("decodeManifests() returned error: %s", err.Error())
+	}
+
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		names = append(names, object.GetKind()+"/"+object.GetName())
+	}
+	expected := []string{"Schedule/daily", "Backup/before-upgrade"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("decodeManifests() == %v, expected %v", names, expected)
+	}
+
+	for _, invalid := range []string{"", "---\n", "kind: [Backup"} {
+		if _, err := decodeManifests(invalid); err == nil {
+			t.Errorf("decodeManifests(%q) returned no error", invalid)
+		}
+	}
+}
+
+func TestPrepareObject(t *testing.T) {
+	exported := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Schedule",
+		"metadata": map[string]interface{}{
+			"name":              "daily",
+			"namespace":         "velero",
+			"uid":               "0a6d6b4c-0f0a-4d6e-9c1e-2b7c2f9f4e11",
+			"resourceVersion":   "4711",
+			"creationTimestamp": "2026-01-01T00:00:00Z",
+			"labels":            map[string]interface{}{"team": "shop"},
+			"ownerReferences":   []interface{}{map[string]interface{}{"kind": "Application", "name": "velero"}},
+		},
+		"spec": map[string]interface{}{
+			"schedule": "0 2 * * *",
+			"template": map[string]interface{}{"includedNamespaces": []interface{}{"shop"}, "ttl": "720h"},
+		},
+		"status": map[string]interface{}{"phase": "Enabled"},
+	}}
+	expected := map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Schedule",
+		"metadata": map[string]interface{}{
+			"name":      "daily",
+			"namespace": "velero",
+			"labels":    map[string]interface{}{"team": "shop"},
+		},
+		"spec": map[string]interface{}{
+			"schedule": "0 2 * * *",
+			"template": map[string]interface{}{"includedNamespaces": []interface{}{"shop"}, "ttl": "720h"},
+		},
+	}
+
+	if err := prepareObject(exported, "velero"); err != nil {
+		t.Fatalf("prepareObject() returned error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(exported.Object, expected) {
+		t.Errorf("prepareObject() == \n%#v\nexpected \n%#v\n", exported.Object, expected)
+	}
+}
+
+func TestPrepareObjectGuardrails(t *testing.T) {
+	newObject := func(apiVersion, kind, namespace string, spec map[string]interface{}) *unstructured.Unstructured {
+		metadata := map[string]interface{}{"name": "imported"}
+		if namespace != "" {
+			metadata["namespace"] = namespace
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion, "kind": kind, "metadata": metadata, "spec": spec,
+		}}
+	}
+
+	cases := []struct {
+		info    string
+		object  *unstructured.Unstructured
+		isError bool
+	}{
+		{"backup", newObject("velero.io/v1", "Backup", "", map[string]interface{}{"ttl": "24h"}), false},
+		{"restore", newObject("velero.io/v1", "Restore", "velero", map[string]interface{}{
+			"backupName":       "daily",
+			"resourceModifier": map[string]interface{}{"kind": "ConfigMap", "name": "scale-down"},
+		}), false},
+		{"backup storage location", newObject("velero.io/v1", "BackupStorageLocation", "", map[string]interface{}{
+			"provider": "aws", "objectStorage": map[string]interface{}{"bucket": "backups"},
+		}), false},
+		{"other group", newObject("apps/v1", "Deployment", "", nil), true},
+		{"other Velero kind", newObject("velero.io/v1", "PodVolumeBackup", "", nil), true},
+		{"other namespace", newObject("velero.io/v1", "Backup", "shop", nil), true},
+		{"invalid backup", newObject("velero.io/v1", "Backup", "", map[string]interface{}{"csiSnapshotTimeout": "soon"}), true},
+		{"invalid schedule", newObject("velero.io/v1", "Schedule", "", map[string]interface{}{"schedule": "daily"}), true},
+		{"restore without backup", newObject("velero.io/v1", "Restore", "", map[string]interface{}{}), true},
+		{"invalid restore", newObject("velero.io/v1", "Restore", "", map[string]interface{}{
+			"backupName": "daily", "existingResourcePolicy": "replace",
+		}), true},
+		{"backup storage location without bucket", newObject("velero.io/v1", "BackupStorageLocation", "",
+			map[string]interface{}{"provider": "aws"}), true},
+	}
+
+	for _, c := range cases {
+		err := prepareObject(c.object, "velero")
+		if (err != nil) != c.isError {
+			t.Errorf("%s: prepareObject() == %v, expected error: %t", c.info, err, c.isError)
+		}
+	}
+}
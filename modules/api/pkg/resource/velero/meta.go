@@ -0,0 +1,51 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s.io/dashboard/types"
+)
+
+// NewObjectMeta returns the dashboard object meta of a Velero resource decoded into a generic map, the same way
+// types.NewObjectMeta does for typed resources.
+func NewObjectMeta(rawObject map[string]interface{}) types.ObjectMeta {
+	meta := metav1.ObjectMeta{}
+	if rawMeta, ok := rawObject["metadata"].(map[string]interface{}); ok {
+		// Malformed fields are left empty, same as missing ones.
+		_ = runtime.DefaultUnstructuredConverter.FromUnstructured(rawMeta, &meta)
+	}
+
+	return types.NewObjectMeta(meta)
+}
+
+// NewStringSlice returns the strings of a list decoded into a generic slice, nil when it is not a list.
+func NewStringSlice(rawList interface{}) []string {
+	list, ok := rawList.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	for _, item := range list {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+
+	return result
+}
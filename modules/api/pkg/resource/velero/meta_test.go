@@ -0,0 +1,70 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/dashboard/types"
+)
+
+func TestNewObjectMeta(t *testing.T) {
+	cases := []struct {
+		rawObject map[string]interface{}
+		expected  types.ObjectMeta
+	}{
+		{
+			map[string]interface{}{},
+			types.ObjectMeta{},
+		},
+		{
+			map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":              "daily-20261017",
+					"namespace":         "velero",
+					"uid":               "8b2f6f4e",
+					"resourceVersion":   "42",
+					"creationTimestamp": "2026-10-17T02:00:00Z",
+					"labels":            map[string]interface{}{"velero.io/schedule-name": "daily"},
+					"annotations":       map[string]interface{}{"velero.io/source-cluster-k8s-gitversion": "v1.31.0"},
+					"ownerReferences": []interface{}{
+						map[string]interface{}{"apiVersion": "velero.io/v1", "kind": "Schedule", "name": "daily", "uid": "1c0e"},
+					},
+				},
+			},
+			types.ObjectMeta{
+				Name:              "daily-20261017",
+				Namespace:         "velero",
+				UID:               "8b2f6f4e",
+				ResourceVersion:   "42",
+				CreationTimestamp: metav1.NewTime(time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC).Local()),
+				Labels:            map[string]string{"velero.io/schedule-name": "daily"},
+				Annotations:       map[string]string{"velero.io/source-cluster-k8s-gitversion": "v1.31.0"},
+				OwnerReferences:   []types.OwnerReference{{Kind: "Schedule", Name: "daily"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := NewObjectMeta(c.rawObject)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("NewObjectMeta(%#v) == \n%#v\nexpected \n%#v\n", c.rawObject, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+// reservedKeyPrefix marks the labels and annotations Velero relies on, e.g. to link backups to their schedule and
+// storage location, which cannot be changed through the dashboard.
+const reservedKeyPrefix = "velero.io/"
+
+// MetadataSpec lists the labels and annotations to add to a Velero resource, replacing the values of existing ones,
+// and the ones to remove from it.
+type MetadataSpec struct {
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	RemoveLabels      []string          `json:"removeLabels,omitempty"`
+	RemoveAnnotations []string          `json:"removeAnnotations,omitempty"`
+}
+
+// Validate rejects malformed keys and values, keys that are both added and removed and the keys reserved by Velero.
+func (in *MetadataSpec) Validate() error {
+	if len(in.Labels) == 0 && len(in.Annotations) == 0 && len(in.RemoveLabels) == 0 && len(in.RemoveAnnotations) == 0 {
+		return errors.NewBadRequest("no labels or annotations to add or remove")
+	}
+
+	allErrs := metav1validation.ValidateLabels(in.Labels, field.NewPath("labels"))
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(in.Annotations, field.NewPath("annotations"))...)
+	allErrs = append(allErrs, validateRemovedKeys(in.RemoveLabels, in.Labels, field.NewPath("removeLabels"))...)
+	allErrs = append(allErrs, validateRemovedKeys(in.RemoveAnnotations, in.Annotations, field.NewPath("removeAnnotations"))...)
+	allErrs = append(allErrs, validateReservedKeys(in.Labels, in.RemoveLabels, field.NewPath("labels"))...)
+	allErrs = append(allErrs, validateReservedKeys(in.Annotations, in.RemoveAnnotations, field.NewPath("annotations"))...)
+	if len(allErrs) > 0 {
+		return errors.NewBadRequest(allErrs.ToAggregate().Error())
+	}
+
+	return nil
+}
+
+func validateRemovedKeys(keys []string, added map[string]string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, key := range keys {
+		allErrs = append(allErrs, metav1validation.ValidateLabelName(key, path.Index(i))...)
+		if _, ok := added[key]; ok {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), key, "cannot be both added and removed"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateReservedKeys(added map[string]string, removed []string, path *field.Path) field.ErrorList {
+	keys := append([]string{}, removed...)
+	for key := range added {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	allErrs := field.ErrorList{}
+	for _, key := range keys {
+		if strings.HasPrefix(key, reservedKeyPrefix) {
+			allErrs = append(allErrs, field.Forbidden(path.Key(key), "keys prefixed with "+reservedKeyPrefix+" are managed by Velero"))
+		}
+	}
+
+	return allErrs
+}
+
+// UpdateMetadata adds and removes labels and annotations of a backup, restore or schedule, e.g. to tag backups with
+// ticket IDs or retention classes. Labels and annotations not listed in the spec are left untouched. In dry run the
+// patched resource is only validated by the API server.
+func UpdateMetadata(request *http.Request, resource schema.GroupVersionResource, namespace, name string, spec *MetadataSpec, dryRun bool) (*types.ObjectMeta, error) {
+	ctx, cancel := OperationContext(request)
+	defer cancel()
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := CheckAccess(request, VerbPatch, resource, namespace, name); err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateMetadata(ctx, dynamicClient, resource, namespace, name, spec, dryRun)
+}
+
+func updateMetadata(ctx context.Context, client dynamic.Interface, resource schema.GroupVersionResource, namespace, name string, spec *MetadataSpec, dryRun bool) (*types.ObjectMeta, error) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      toMetadataPatch(spec.Labels, spec.RemoveLabels),
+			"annotations": toMetadataPatch(spec.Annotations, spec.RemoveAnnotations),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := client.Resource(resource).Namespace(namespace).
+		Patch(ctx, name, k8stypes.MergePatchType, patch, PatchOptions(dryRun))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update labels and annotations: %w", err)
+	}
+
+	meta := NewObjectMeta(patched.Object)
+	return &meta, nil
+}
+
+// toMetadataPatch returns the merge patch of a label or annotation map, in which null removes the key.
+func toMetadataPatch(added map[string]string, removed []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(added)+len(removed))
+	for key, value := range added {
+		result[key] = value
+	}
+
+	for _, key := range removed {
+		result[key] = nil
+	}
+
+	return result
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+)
+
+func TestMetadataSpecValidate(t *testing.T) {
+	cases := []struct {
+		info    string
+		spec    MetadataSpec
+		isError bool
+	}{
+		{"valid", MetadataSpec{Labels: map[string]string{"ticket": "OPS-1234"}, RemoveAnnotations: []string{"note"}}, false},
+		{"empty", MetadataSpec{}, true},
+		{"invalid label value", MetadataSpec{Labels: map[string]string{"ticket": "OPS 1234"}}, true},
+		{"invalid removed key", MetadataSpec{RemoveLabels: []string{"-ticket"}}, true},
+		{"added and removed", MetadataSpec{Annotations: map[string]string{"note": "keep"}, RemoveAnnotations: []string{"note"}}, true},
+		{"reserved label", MetadataSpec{Labels: map[string]string{"velero.io/schedule-name": "daily"}}, true},
+		{"reserved removed annotation", MetadataSpec{RemoveAnnotations: []string{"velero.io/source-cluster-k8s-gitversion"}}, true},
+	}
+
+	for _, c := range cases {
+		err := c.spec.Validate()
+		if (err != nil) != c.isError {
+			t.Errorf("%s: Validate() == %v, expected error: %t", c.info, err, c.isError)
+		}
+	}
+}
+
+func TestUpdateMetadata(t *testing.T) {
+	backup := &unstructured.Unstructured{}
+	backup.SetAPIVersion("velero.io/v1")
+	backup.SetKind("Backup")
+	backup.SetNamespace("velero")
+	backup.SetName("daily")
+	backup.SetLabels(map[string]string{"velero.io/schedule-name": "daily", "retention": "short"})
+	backup.SetAnnotations(map[string]string{"note": "before upgrade"})
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{BackupGVR: "BackupList"}, backup)
+
+	spec := &MetadataSpec{
+		Labels:            map[string]string{"retention": "long", "ticket": "OPS-1234"},
+		RemoveAnnotations: []string{"note"},
+	}
+	actual, err := updateMetadata(context.TODO(), dynamicClient, BackupGVR, "velero", "daily", spec, false)
+	if err != nil {
+		t.Fatalf("updateMetadata() returned error: %s", err.Error())
+	}
+
+	expectedLabels := map[string]string{"velero.io/schedule-name": "daily", "retention": "long", "ticket": "OPS-1234"}
+	if !reflect.DeepEqual(actual.Labels, expectedLabels) || len(actual.Annotations) != 0 {
+		t.Errorf("updateMetadata() == labels %v, annotations %v, expected labels %v, no annotations", actual.Labels,
+			actual.Annotations, expectedLabels)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Names of the caches of the Velero module, used as the cache label of cacheLookups.
+const (
+	crdVersionCacheName = "crdversions"
+	namespaceCacheName  = "namespace"
+)
+
+// cacheLookups counts lookups of each cache by whether the value was cached, so that the hit ratio can be computed.
+var cacheLookups = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "dashboard_velero_cache_lookups_total",
+		Help: "Counter of lookups of the Velero caches broken out for each cache and result, hit or miss.",
+	},
+	[]string{"cache", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(cacheLookups)
+}
+
+// observeCacheLookup counts a lookup of the cache.
+func observeCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	cacheLookups.WithLabelValues(cache, result).Inc()
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNamespaceCacheLookups(t *testing.T) {
+	hits := cacheLookups.WithLabelValues(namespaceCacheName, "hit")
+	misses := cacheLookups.WithLabelValues(namespaceCacheName, "miss")
+	hitsBefore, missesBefore := testutil.ToFloat64(hits), testutil.ToFloat64(misses)
+
+	cache := &namespaceCache{}
+	detect := func(context.Context) string { return "velero" }
+	cache.get(context.TODO(), "alice", detect)
+	cache.get(context.TODO(), "alice", detect)
+	cache.set("alice", "velero", time.Now().Add(-namespaceCacheTTL))
+	cache.get(context.TODO(), "alice", detect)
+
+	if actual := testutil.ToFloat64(hits) - hitsBefore; actual != 1 {
+		t.Errorf("namespace cache counted %v hits, expected 1", actual)
+	}
+	if actual := testutil.ToFloat64(misses) - missesBefore; actual != 2 {
+		t.Errorf("namespace cache counted %v misses, expected 2", actual)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/client"
+)
+
+// DefaultNamespace is the namespace `velero install` and the Helm chart install Velero in.
+const DefaultNamespace = "velero"
+
+// NamespaceConfigMapKey is the key of the Dashboard settings config map that holds the Velero namespace.
+const NamespaceConfigMapKey = "veleroNamespace"
+
+const (
+	// namespaceCacheTTL is how long a detected Velero namespace is reused before it is detected again.
+	namespaceCacheTTL = 5 * time.Minute
+
+	// namespaceCacheMissTTL is how long a missing Velero installation is reused, so that detecting it is not repeated
+	// by every request while Velero is not installed, but an installation is still picked up soon.
+	namespaceCacheMissTTL = 30 * time.Second
+
+	// namespaceDetectTimeout bounds detecting the namespace, which is not canceled together with the request that
+	// started it.
+	namespaceDetectTimeout = 10 * time.Second
+)
+
+// NamespaceSource tells where the Velero namespace was taken from.
+type NamespaceSource string
+
+const (
+	// NamespaceSourceArgument means the namespace was set with the velero-namespace argument or its environment
+	// variable.
+	NamespaceSourceArgument NamespaceSource = "Argument"
+	// NamespaceSourceSettings means the namespace was read from the Dashboard settings config map.
+	NamespaceSourceSettings NamespaceSource = "Settings"
+	// NamespaceSourceDetected means the namespace was detected from the Velero installation in the cluster.
+	NamespaceSourceDetected NamespaceSource = "Detected"
+	// NamespaceSourceDefault means none of the above was available and DefaultNamespace is used.
+	NamespaceSourceDefault NamespaceSource = "Default"
+)
+
+// Namespace is the namespace Velero resources are created in when the caller does not specify one.
+type Namespace struct {
+	Namespace string          `json:"namespace"`
+	Source    NamespaceSource `json:"source"`
+}
+
+// namespaceCache holds the detected Velero namespace by user, as detecting it requires listing resources cluster wide
+// with the credentials of the user, which may not be allowed to see the installation.
+type namespaceCache struct {
+	mu      sync.Mutex
+	entries map[string]namespaceCacheEntry
+
+	// detections coalesces detecting the namespace for a user, which is done without holding mu.
+	detections singleflight.Group
+}
+
+// namespaceCacheEntry is a detected namespace, empty when no installation was found.
+type namespaceCacheEntry struct {
+	namespace string
+	expires   time.Time
+}
+
+var detectedNamespaces = &namespaceCache{}
+
+// GetNamespace returns the namespace Velero is installed in. It is taken from the velero-namespace argument, the
+// Dashboard settings config map or the Velero installation, in that order, and falls back to DefaultNamespace. The
+// installation is detected with the credentials of the request, so the result is cached for its user only.
+func GetNamespace(request *http.Request) *Namespace {
+	ctx, cancel := OperationContext(request)
+	defer cancel()
+
+	return resolveNamespace(ctx, args.VeleroNamespace(), client.InClusterClient(), func() string {
+		key, err := client.UserKey(request)
+		if err != nil {
+			return ""
+		}
+
+		return detectedNamespaces.get(ctx, key, func(ctx context.Context) string {
+			k8sClient, err := client.Client(request)
+			if err != nil {
+				return ""
+			}
+
+			dynamicClient, err := DynamicClient(request)
+			if err != nil {
+				return ""
+			}
+
+			return getInstallNamespace(ctx, k8sClient, dynamicClient)
+		})
+	})
+}
+
+// NamespaceOrDefault returns the namespace when it is set, otherwise the namespace Velero is installed in.
+func NamespaceOrDefault(request *http.Request, namespace string) string {
+	if namespace != "" {
+		return namespace
+	}
+
+	return GetNamespace(request).Namespace
+}
+
+func resolveNamespace(ctx context.Context, configured string, settingsClient kubernetes.Interface, detect func() string) *Namespace {
+	if configured != "" {
+		return &Namespace{Namespace: configured, Source: NamespaceSourceArgument}
+	}
+
+	if namespace := getSettingsNamespace(ctx, settingsClient); namespace != "" {
+		return &Namespace{Namespace: namespace, Source: NamespaceSourceSettings}
+	}
+
+	if namespace := detect(); namespace != "" {
+		return &Namespace{Namespace: namespace, Source: NamespaceSourceDetected}
+	}
+
+	return &Namespace{Namespace: DefaultNamespace, Source: NamespaceSourceDefault}
+}
+
+// getSettingsNamespace reads the Velero namespace from the Dashboard settings config map. Errors are ignored, as the
+// namespace is optional in settings.
+func getSettingsNamespace(ctx context.Context, client kubernetes.Interface) string {
+	configMap, err := client.CoreV1().ConfigMaps(args.Namespace()).Get(ctx, args.SettingsConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		klog.V(args.LogLevelVerbose).Infof("Could not read Velero namespace from settings: %s", err.Error())
+		return ""
+	}
+
+	return configMap.Data[NamespaceConfigMapKey]
+}
+
+// get returns the namespace cached for the user of the key, detecting it again once it expired. Concurrent requests of
+// the user wait for the same detection. A missing installation is cached for a shorter time than a detected one.
+func (in *namespaceCache) get(ctx context.Context, key string, detect func(ctx context.Context) string) string {
+	in.mu.Lock()
+	entry, ok := in.entries[key]
+	in.mu.Unlock()
+
+	hit := ok && time.Now().Before(entry.expires)
+	observeCacheLookup(namespaceCacheName, hit)
+	if hit {
+		return entry.namespace
+	}
+
+	detection := in.detections.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), namespaceDetectTimeout)
+		defer cancel()
+
+		namespace := detect(ctx)
+		in.set(key, namespace, time.Now())
+		return namespace, nil
+	})
+
+	select {
+	case result := <-detection:
+		return result.Val.(string)
+	case <-ctx.Done():
+		return ""
+	}
+}
+
+// set caches the namespace detected for the user of the key and drops expired entries of other users.
+func (in *namespaceCache) set(key, namespace string, now time.Time) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if in.entries == nil {
+		in.entries = make(map[string]namespaceCacheEntry)
+	}
+	for cached, entry := range in.entries {
+		if !now.Before(entry.expires) {
+			delete(in.entries, cached)
+		}
+	}
+
+	ttl := namespaceCacheTTL
+	if namespace == "" {
+		ttl = namespaceCacheMissTTL
+	}
+	in.entries[key] = namespaceCacheEntry{namespace: namespace, expires: now.Add(ttl)}
+}
@@ -0,0 +1,128 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/dashboard/api/pkg/args"
+)
+
+func TestResolveNamespace(t *testing.T) {
+	settings := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: args.SettingsConfigMapName(), Namespace: args.Namespace()},
+		Data:       map[string]string{NamespaceConfigMapKey: "backup-system"},
+	}
+
+	cases := []struct {
+		info       string
+		configured string
+		settings   *fake.Clientset
+		detected   string
+		expected   *Namespace
+	}{
+		{"argument", "velero-prod", fake.NewSimpleClientset(settings), "velero", &Namespace{"velero-prod", NamespaceSourceArgument}},
+		{"settings", "", fake.NewSimpleClientset(settings), "velero", &Namespace{"backup-system", NamespaceSourceSettings}},
+		{"detected", "", fake.NewSimpleClientset(), "oadp", &Namespace{"oadp", NamespaceSourceDetected}},
+		{"default", "", fake.NewSimpleClientset(), "", &Namespace{DefaultNamespace, NamespaceSourceDefault}},
+	}
+
+	for _, c := range cases {
+		actual := resolveNamespace(context.TODO(), c.configured, c.settings, func() string { return c.detected })
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: resolveNamespace() == %#v, expected %#v", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestNamespaceCache(t *testing.T) {
+	cache := &namespaceCache{}
+	calls := 0
+	detect := func(namespace string) func(context.Context) string {
+		return func(context.Context) string {
+			calls++
+			return namespace
+		}
+	}
+
+	if actual := cache.get(context.TODO(), "alice", detect("")); actual != "" || calls != 1 {
+		t.Errorf("get() == %q after %d calls, expected no namespace after 1 call", actual, calls)
+	}
+	if actual := cache.get(context.TODO(), "alice", detect("velero")); actual != "" || calls != 1 {
+		t.Errorf("get() == %q after %d calls, expected the cached missing namespace", actual, calls)
+	}
+	if actual := cache.get(context.TODO(), "bob", detect("velero")); actual != "velero" || calls != 2 {
+		t.Errorf("get() == %q after %d calls, expected velero detected for another user", actual, calls)
+	}
+
+	cache.set("alice", "", time.Now().Add(-namespaceCacheMissTTL))
+	if actual := cache.get(context.TODO(), "alice", detect("velero")); actual != "velero" || calls != 3 {
+		t.Errorf("get() == %q after %d calls, expected velero after the missing namespace expired", actual, calls)
+	}
+	if actual := cache.get(context.TODO(), "alice", detect("oadp")); actual != "velero" || calls != 3 {
+		t.Errorf("get() == %q after %d calls, expected cached velero", actual, calls)
+	}
+
+	cache.set("alice", "velero", time.Now().Add(-namespaceCacheTTL))
+	if actual := cache.get(context.TODO(), "alice", detect("oadp")); actual != "oadp" || calls != 4 {
+		t.Errorf("get() == %q after %d calls, expected oadp after expiry", actual, calls)
+	}
+}
+
+func TestNamespaceCacheCoalescesDetections(t *testing.T) {
+	cache := &namespaceCache{}
+	release := make(chan struct{})
+	var calls atomic.Int32
+	detect := func(context.Context) string {
+		calls.Add(1)
+		<-release
+		return "velero"
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = cache.get(context.TODO(), "alice", detect)
+		}()
+	}
+
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("get() detected the namespace %d times, expected once", calls.Load())
+	}
+	for _, actual := range results {
+		if actual != "velero" {
+			t.Errorf("get() == %q, expected velero", actual)
+		}
+	}
+}
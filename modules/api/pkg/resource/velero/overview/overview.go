@@ -0,0 +1,242 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package overview
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/common"
+	"k8s.io/dashboard/api/pkg/resource/dataselect"
+	"k8s.io/dashboard/api/pkg/resource/restore"
+	"k8s.io/dashboard/api/pkg/resource/schedule"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+// DefaultExpiringPeriod is how soon backups have to expire to be reported when the caller does not ask for a specific
+// period.
+const DefaultExpiringPeriod = "7d"
+
+// storageLocationPhaseUnavailable is the phase of backup storage locations Velero could not validate.
+const storageLocationPhaseUnavailable = "Unavailable"
+
+// Overview summarizes Velero backups, restores, schedules and backup storage locations, so that a landing page can
+// be rendered with a single request.
+type Overview struct {
+	Backups   velero.ListStatus `json:"backups"`
+	Restores  velero.ListStatus `json:"restores"`
+	Schedules velero.ListStatus `json:"schedules"`
+
+	// LastSuccessfulBackup is the completion time of the latest backup that succeeded.
+	LastSuccessfulBackup *metav1.Time `json:"lastSuccessfulBackup,omitempty"`
+
+	// FailingSchedules lists schedules that failed validation or whose latest backup failed.
+	FailingSchedules []schedule.Schedule `json:"failingSchedules"`
+
+	// ExpiringBackups lists backups expiring within the requested period, the ones expiring first at the top.
+	ExpiringBackups []backup.Backup `json:"expiringBackups"`
+
+	// UnavailableStorageLocations lists backup storage locations Velero could not validate.
+	UnavailableStorageLocations []StorageLocation `json:"unavailableStorageLocations"`
+
+	// InProgressBackups and InProgressRestores list operations that are pending or running.
+	InProgressBackups  []backup.Backup   `json:"inProgressBackups"`
+	InProgressRestores []restore.Restore `json:"inProgressRestores"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// StorageLocation is a Velero backup storage location along with the result of its latest validation.
+type StorageLocation struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	Phase              string       `json:"phase,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastValidationTime *metav1.Time `json:"lastValidationTime,omitempty"`
+}
+
+// GetOverview returns the summary of all Velero resources in namespaces matching the query. Backups expiring within
+// the period, e.g. "7d", are reported as expiring.
+func GetOverview(request *http.Request, namespace *common.NamespaceQuery, expiringPeriod string) (*Overview, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	expiringWithin, err := backup.ParseWindow(expiringPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := backup.GetBackupList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	restores, err := restore.GetRestoreList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := schedule.GetScheduleList(request, namespace, dataselect.NoDataSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, locationErrors, err := getStorageLocations(ctx, dynamicClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	nonCriticalErrors := make([]error, 0)
+	nonCriticalErrors = append(nonCriticalErrors, backups.Errors...)
+	nonCriticalErrors = append(nonCriticalErrors, restores.Errors...)
+	nonCriticalErrors = append(nonCriticalErrors, schedules.Errors...)
+	nonCriticalErrors = append(nonCriticalErrors, locationErrors...)
+
+	now := time.Now()
+	return &Overview{
+		Backups:                     backups.Status,
+		Restores:                    restores.Status,
+		Schedules:                   schedules.Status,
+		LastSuccessfulBackup:        getLastSuccessfulBackup(backups.Items),
+		FailingSchedules:            getFailingSchedules(schedules.Items, backups.Items),
+		ExpiringBackups:             backup.FilterExpiringBackups(backups.Items, now, now.Add(expiringWithin)),
+		UnavailableStorageLocations: getUnavailableStorageLocations(locations),
+		InProgressBackups:           getInProgressBackups(backups.Items),
+		InProgressRestores:          getInProgressRestores(restores.Items),
+		Errors:                      nonCriticalErrors,
+	}, nil
+}
+
+// getStorageLocations lists backup storage locations. Locations beyond the configured limit and namespaces the user
+// may not read are left out and reported as non-critical errors.
+func getStorageLocations(ctx context.Context, client dynamic.Interface, namespace *common.NamespaceQuery) ([]unstructured.Unstructured, []error, error) {
+	return velero.ListInNamespaces(ctx, client, velero.BackupStorageLocationGVR, namespace,
+		metav1.ListOptions{}, args.VeleroMaxListItems())
+}
+
+func getLastSuccessfulBackup(backups []backup.Backup) *metav1.Time {
+	var latest *metav1.Time
+	for _, item := range backups {
+		if item.Phase.Status() != velero.StatusSucceeded || item.CompletionTime == nil {
+			continue
+		}
+
+		if latest == nil || latest.Before(item.CompletionTime) {
+			latest = item.CompletionTime
+		}
+	}
+
+	return latest
+}
+
+// getFailingSchedules returns schedules that failed validation or whose latest backup, by creation time, failed or
+// partially failed.
+func getFailingSchedules(schedules []schedule.Schedule, backups []backup.Backup) []schedule.Schedule {
+	latest := make(map[string]backup.Backup)
+	for _, item := range backups {
+		name, ok := item.ObjectMeta.Labels[backup.ScheduleNameLabel]
+		if !ok {
+			continue
+		}
+
+		key := item.ObjectMeta.Namespace + "/" + name
+		if current, found := latest[key]; !found ||
+			current.ObjectMeta.CreationTimestamp.Before(&item.ObjectMeta.CreationTimestamp) {
+			latest[key] = item
+		}
+	}
+
+	result := make([]schedule.Schedule, 0)
+	for _, item := range schedules {
+		if item.Phase.Status() == velero.StatusFailedValidation {
+			result = append(result, item)
+			continue
+		}
+
+		last, found := latest[item.ObjectMeta.Namespace+"/"+item.ObjectMeta.Name]
+		if !found {
+			continue
+		}
+
+		switch last.Phase.Status() {
+		case velero.StatusFailed, velero.StatusPartiallyFailed, velero.StatusFailedValidation:
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+func getUnavailableStorageLocations(items []unstructured.Unstructured) []StorageLocation {
+	result := make([]StorageLocation, 0)
+	for _, item := range items {
+		location := toStorageLocation(item)
+		if location.Phase == storageLocationPhaseUnavailable {
+			result = append(result, location)
+		}
+	}
+
+	return result
+}
+
+func toStorageLocation(item unstructured.Unstructured) StorageLocation {
+	location := StorageLocation{}
+	if status, ok := item.Object["status"].(map[string]interface{}); ok {
+		// Malformed fields are left empty, same as missing ones.
+		_ = runtime.DefaultUnstructuredConverter.FromUnstructured(status, &location)
+	}
+
+	location.ObjectMeta = velero.NewObjectMeta(item.Object)
+	location.TypeMeta = types.TypeMeta{Kind: "BackupStorageLocation"}
+	return location
+}
+
+func getInProgressBackups(backups []backup.Backup) []backup.Backup {
+	result := make([]backup.Backup, 0)
+	for _, item := range backups {
+		if item.Phase.Status().IsActive() {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+func getInProgressRestores(restores []restore.Restore) []restore.Restore {
+	result := make([]restore.Restore, 0)
+	for _, item := range restores {
+		if item.Phase.Status().IsActive() {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package overview
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/restore"
+	"k8s.io/dashboard/api/pkg/resource/schedule"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+var now = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+func newTime(offset time.Duration) *metav1.Time {
+	result := metav1.NewTime(now.Add(offset))
+	return &result
+}
+
+func newBackup(name, scheduleName string, phase velero.BackupPhase, created time.Duration) backup.Backup {
+	labels := map[string]string{}
+	if scheduleName != "" {
+		labels[backup.ScheduleNameLabel] = scheduleName
+	}
+
+	return backup.Backup{
+		ObjectMeta: types.ObjectMeta{Name: name, Namespace: "velero", Labels: labels, CreationTimestamp: *newTime(created)},
+		Phase:      phase,
+	}
+}
+
+func newSchedule(name string, phase velero.SchedulePhase) schedule.Schedule {
+	return schedule.Schedule{ObjectMeta: types.ObjectMeta{Name: name, Namespace: "velero"}, Phase: phase}
+}
+
+func TestGetLastSuccessfulBackup(t *testing.T) {
+	completed := newBackup("completed", "", velero.BackupPhaseCompleted, -48*time.Hour)
+	completed.CompletionTime = newTime(-47 * time.Hour)
+	failed := newBackup("failed", "", velero.BackupPhaseFailed, -time.Hour)
+	failed.CompletionTime = newTime(-time.Hour)
+	older := newBackup("older", "", velero.BackupPhaseCompleted, -72*time.Hour)
+	older.CompletionTime = newTime(-71 * time.Hour)
+
+	if actual := getLastSuccessfulBackup([]backup.Backup{older, completed, failed}); !reflect.DeepEqual(actual, completed.CompletionTime) {
+		t.Errorf("getLastSuccessfulBackup() == %v, expected %v", actual, completed.CompletionTime)
+	}
+
+	if actual := getLastSuccessfulBackup([]backup.Backup{failed}); actual != nil {
+		t.Errorf("getLastSuccessfulBackup() == %v, expected nil", actual)
+	}
+}
+
+func TestGetFailingSchedules(t *testing.T) {
+	schedules := []schedule.Schedule{
+		newSchedule("daily", velero.SchedulePhaseEnabled),
+		newSchedule("hourly", velero.SchedulePhaseEnabled),
+		newSchedule("weekly", velero.SchedulePhaseEnabled),
+		newSchedule("invalid", velero.SchedulePhaseFailedValidation),
+	}
+	backups := []backup.Backup{
+		newBackup("daily-2", "daily", velero.BackupPhaseFailed, -time.Hour),
+		newBackup("daily-1", "daily", velero.BackupPhaseCompleted, -25*time.Hour),
+		newBackup("hourly-2", "hourly", velero.BackupPhaseCompleted, -time.Hour),
+		newBackup("hourly-1", "hourly", velero.BackupPhasePartiallyFailed, -2*time.Hour),
+		newBackup("manual", "", velero.BackupPhaseFailed, 0),
+	}
+
+	expected := []schedule.Schedule{schedules[0], schedules[3]}
+	if actual := getFailingSchedules(schedules, backups); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getFailingSchedules() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestGetUnavailableStorageLocations(t *testing.T) {
+	newLocation := func(name string, status map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name, "namespace": "velero"},
+			"status":   status,
+		}}
+	}
+	items := []unstructured.Unstructured{
+		newLocation("default", map[string]interface{}{"phase": "Available"}),
+		newLocation("secondary", map[string]interface{}{
+			"phase":              "Unavailable",
+			"message":            "bucket not found",
+			"lastValidationTime": "2026-10-17T11:00:00Z",
+		}),
+		newLocation("new", nil),
+	}
+
+	expected := []StorageLocation{{
+		ObjectMeta:         types.ObjectMeta{Name: "secondary", Namespace: "velero"},
+		TypeMeta:           types.TypeMeta{Kind: "BackupStorageLocation"},
+		Phase:              "Unavailable",
+		Message:            "bucket not found",
+		LastValidationTime: &metav1.Time{Time: now.Add(-time.Hour).Local()},
+	}}
+
+	if actual := getUnavailableStorageLocations(items); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getUnavailableStorageLocations() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestGetInProgress(t *testing.T) {
+	backups := []backup.Backup{
+		newBackup("new", "", velero.BackupPhaseNew, 0),
+		newBackup("running", "", velero.BackupPhaseInProgress, 0),
+		newBackup("done", "", velero.BackupPhaseCompleted, 0),
+	}
+	if actual := getInProgressBackups(backups); !reflect.DeepEqual(actual, backups[:2]) {
+		t.Errorf("getInProgressBackups() == \n%#v\nexpected \n%#v\n", actual, backups[:2])
+	}
+
+	restores := []restore.Restore{
+		{ObjectMeta: types.ObjectMeta{Name: "running"}, Phase: velero.RestorePhaseInProgress},
+		{ObjectMeta: types.ObjectMeta{Name: "done"}, Phase: velero.RestorePhaseCompleted},
+	}
+	if actual := getInProgressRestores(restores); !reflect.DeepEqual(actual, restores[:1]) {
+		t.Errorf("getInProgressRestores() == \n%#v\nexpected \n%#v\n", actual, restores[:1])
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"k8s.io/dashboard/api/pkg/args"
+)
+
+// ForEach calls fn for every index from 0 to count-1, running at most args.VeleroListWorkers() calls at once. Calls
+// share the context of the request, so they are bound by its timeout. After the first error no more calls are
+// started, the context passed to running calls is cancelled and the error is returned.
+func ForEach(ctx context.Context, count int, fn func(ctx context.Context, i int) error) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(max(args.VeleroListWorkers(), 1))
+	for i := 0; i < count && groupCtx.Err() == nil; i++ {
+		group.Go(func() error {
+			return fn(groupCtx, i)
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
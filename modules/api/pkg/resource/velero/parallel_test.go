@@ -0,0 +1,90 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/dashboard/api/pkg/args"
+)
+
+func TestForEach(t *testing.T) {
+	var running, maxRunning, calls atomic.Int64
+	err := ForEach(context.TODO(), 50, func(_ context.Context, _ int) error {
+		calls.Add(1)
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			previous := maxRunning.Load()
+			if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEach() returned error: %s", err.Error())
+	}
+
+	if calls.Load() != 50 {
+		t.Errorf("ForEach() made %d calls, expected 50", calls.Load())
+	}
+	if maxRunning.Load() > int64(args.VeleroListWorkers()) {
+		t.Errorf("ForEach() ran %d calls at once, expected at most %d", maxRunning.Load(), args.VeleroListWorkers())
+	}
+}
+
+func TestForEachError(t *testing.T) {
+	expected := errors.New("list failed")
+	var calls atomic.Int64
+	err := ForEach(context.TODO(), 1000, func(ctx context.Context, i int) error {
+		calls.Add(1)
+		if i == 0 {
+			return expected
+		}
+
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if !errors.Is(err, expected) {
+		t.Errorf("ForEach() returned error %v, expected %v", err, expected)
+	}
+	if calls.Load() == 1000 {
+		t.Errorf("ForEach() kept starting calls after the first error")
+	}
+}
+
+func TestForEachCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	var calls atomic.Int64
+	err := ForEach(ctx, 10, func(_ context.Context, _ int) error {
+		calls.Add(1)
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) || calls.Load() != 0 {
+		t.Errorf("ForEach() returned error %v after %d calls, expected %v without calls", err, calls.Load(),
+			context.Canceled)
+	}
+}
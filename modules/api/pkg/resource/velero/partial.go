@@ -0,0 +1,43 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"net/http"
+
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/client"
+)
+
+// MetadataClient returns a dynamic client like DynamicClient, except that lists contain only the metadata of
+// resources. The API server leaves out spec and status, which keeps very long lists cheap to transfer and decode.
+// Objects in such lists carry the PartialObjectMetadata kind; single objects are still returned in full.
+func MetadataClient(request *http.Request) (dynamic.Interface, error) {
+	metadataClient, err := client.VeleroMetadataClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	apiextensionsClient, err := client.APIExtensionsClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return withVersionTracking(request, &negotiatingClient{
+		Interface: metadataClient,
+		versions:  negotiatedVersions.get(request.Context(), apiextensionsClient),
+	}), nil
+}
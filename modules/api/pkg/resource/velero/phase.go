@@ -0,0 +1,226 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+// BackupPhase is the phase of a Velero backup, as reported in its status.
+type BackupPhase string
+
+const (
+	BackupPhaseNew                                       BackupPhase = "New"
+	BackupPhaseQueued                                    BackupPhase = "Queued"
+	BackupPhaseReadyToStart                              BackupPhase = "ReadyToStart"
+	BackupPhaseFailedValidation                          BackupPhase = "FailedValidation"
+	BackupPhaseInProgress                                BackupPhase = "InProgress"
+	BackupPhaseWaitingForPluginOperations                BackupPhase = "WaitingForPluginOperations"
+	BackupPhaseWaitingForPluginOperationsPartiallyFailed BackupPhase = "WaitingForPluginOperationsPartiallyFailed"
+	BackupPhaseFinalizing                                BackupPhase = "Finalizing"
+	BackupPhaseFinalizingPartiallyFailed                 BackupPhase = "FinalizingPartiallyFailed"
+	BackupPhaseCompleted                                 BackupPhase = "Completed"
+	BackupPhasePartiallyFailed                           BackupPhase = "PartiallyFailed"
+	BackupPhaseFailed                                    BackupPhase = "Failed"
+	BackupPhaseDeleting                                  BackupPhase = "Deleting"
+)
+
+// RestorePhase is the phase of a Velero restore, as reported in its status.
+type RestorePhase string
+
+const (
+	RestorePhaseNew                                       RestorePhase = "New"
+	RestorePhaseFailedValidation                          RestorePhase = "FailedValidation"
+	RestorePhaseInProgress                                RestorePhase = "InProgress"
+	RestorePhaseWaitingForPluginOperations                RestorePhase = "WaitingForPluginOperations"
+	RestorePhaseWaitingForPluginOperationsPartiallyFailed RestorePhase = "WaitingForPluginOperationsPartiallyFailed"
+	RestorePhaseFinalizing                                RestorePhase = "Finalizing"
+	RestorePhaseFinalizingPartiallyFailed                 RestorePhase = "FinalizingPartiallyFailed"
+	RestorePhaseCompleted                                 RestorePhase = "Completed"
+	RestorePhasePartiallyFailed                           RestorePhase = "PartiallyFailed"
+	RestorePhaseFailed                                    RestorePhase = "Failed"
+)
+
+// SchedulePhase is the phase of a Velero schedule, as reported in its status.
+type SchedulePhase string
+
+const (
+	SchedulePhaseNew              SchedulePhase = "New"
+	SchedulePhaseEnabled          SchedulePhase = "Enabled"
+	SchedulePhaseFailedValidation SchedulePhase = "FailedValidation"
+)
+
+// DeleteBackupRequestPhase is the phase of a Velero delete backup request, as reported in its status.
+type DeleteBackupRequestPhase string
+
+const (
+	DeleteBackupRequestPhaseNew        DeleteBackupRequestPhase = "New"
+	DeleteBackupRequestPhaseInProgress DeleteBackupRequestPhase = "InProgress"
+	DeleteBackupRequestPhaseProcessed  DeleteBackupRequestPhase = "Processed"
+)
+
+// Status groups phases of all Velero resources into the few states the dashboard tells apart.
+type Status string
+
+const (
+	StatusPending          Status = "Pending"
+	StatusRunning          Status = "Running"
+	StatusSucceeded        Status = "Succeeded"
+	StatusPartiallyFailed  Status = "PartiallyFailed"
+	StatusFailed           Status = "Failed"
+	StatusFailedValidation Status = "FailedValidation"
+	StatusDeleting         Status = "Deleting"
+
+	// StatusWaitingForPluginOperations means Velero is done with the items and waits for asynchronous operations of
+	// plugins, e.g. moving snapshot data, which can take much longer than the backup or restore itself.
+	StatusWaitingForPluginOperations Status = "WaitingForPluginOperations"
+
+	// StatusUnknown is used for phases the dashboard does not know, e.g. ones added by a newer Velero version.
+	StatusUnknown Status = "Unknown"
+)
+
+// IsActive reports whether Velero has yet to start or finish the backup or restore.
+func (in Status) IsActive() bool {
+	return in == StatusPending || in == StatusRunning || in == StatusWaitingForPluginOperations
+}
+
+// Status maps the phase to its status. A backup without a phase has not been picked up by Velero yet.
+func (in BackupPhase) Status() Status {
+	switch in {
+	case "", BackupPhaseNew, BackupPhaseQueued, BackupPhaseReadyToStart:
+		return StatusPending
+	case BackupPhaseInProgress, BackupPhaseFinalizing, BackupPhaseFinalizingPartiallyFailed:
+		return StatusRunning
+	case BackupPhaseWaitingForPluginOperations, BackupPhaseWaitingForPluginOperationsPartiallyFailed:
+		return StatusWaitingForPluginOperations
+	case BackupPhaseCompleted:
+		return StatusSucceeded
+	case BackupPhasePartiallyFailed:
+		return StatusPartiallyFailed
+	case BackupPhaseFailed:
+		return StatusFailed
+	case BackupPhaseFailedValidation:
+		return StatusFailedValidation
+	case BackupPhaseDeleting:
+		return StatusDeleting
+	default:
+		return StatusUnknown
+	}
+}
+
+// IsFinal reports whether Velero is done working on the backup.
+func (in BackupPhase) IsFinal() bool {
+	switch in {
+	case BackupPhaseCompleted, BackupPhasePartiallyFailed, BackupPhaseFailed, BackupPhaseFailedValidation:
+		return true
+	default:
+		return false
+	}
+}
+
+// Status maps the phase to its status. A restore without a phase has not been picked up by Velero yet.
+func (in RestorePhase) Status() Status {
+	switch in {
+	case "", RestorePhaseNew:
+		return StatusPending
+	case RestorePhaseInProgress, RestorePhaseFinalizing, RestorePhaseFinalizingPartiallyFailed:
+		return StatusRunning
+	case RestorePhaseWaitingForPluginOperations, RestorePhaseWaitingForPluginOperationsPartiallyFailed:
+		return StatusWaitingForPluginOperations
+	case RestorePhaseCompleted:
+		return StatusSucceeded
+	case RestorePhasePartiallyFailed:
+		return StatusPartiallyFailed
+	case RestorePhaseFailed:
+		return StatusFailed
+	case RestorePhaseFailedValidation:
+		return StatusFailedValidation
+	default:
+		return StatusUnknown
+	}
+}
+
+// IsFinal reports whether Velero is done working on the restore.
+func (in RestorePhase) IsFinal() bool {
+	switch in {
+	case RestorePhaseCompleted, RestorePhasePartiallyFailed, RestorePhaseFailed, RestorePhaseFailedValidation:
+		return true
+	default:
+		return false
+	}
+}
+
+// Status maps the phase to its status. An enabled schedule is running, as it keeps creating backups.
+func (in SchedulePhase) Status() Status {
+	switch in {
+	case "", SchedulePhaseNew:
+		return StatusPending
+	case SchedulePhaseEnabled:
+		return StatusRunning
+	case SchedulePhaseFailedValidation:
+		return StatusFailedValidation
+	default:
+		return StatusUnknown
+	}
+}
+
+// Status maps the phase to its status. A processed request may still have failed to delete the backup data, which
+// only its errors tell.
+func (in DeleteBackupRequestPhase) Status() Status {
+	switch in {
+	case "", DeleteBackupRequestPhaseNew:
+		return StatusPending
+	case DeleteBackupRequestPhaseInProgress:
+		return StatusRunning
+	case DeleteBackupRequestPhaseProcessed:
+		return StatusSucceeded
+	default:
+		return StatusUnknown
+	}
+}
+
+// ListStatus counts resources of a list by their status.
+type ListStatus struct {
+	Pending int `json:"pending"`
+	Running int `json:"running"`
+
+	WaitingForPluginOperations int `json:"waitingForPluginOperations"`
+	Succeeded                  int `json:"succeeded"`
+	PartiallyFailed            int `json:"partiallyFailed"`
+	Failed                     int `json:"failed"`
+	FailedValidation           int `json:"failedValidation"`
+	Deleting                   int `json:"deleting"`
+	Unknown                    int `json:"unknown"`
+}
+
+// Add counts a resource with the status.
+func (in *ListStatus) Add(status Status) {
+	switch status {
+	case StatusPending:
+		in.Pending++
+	case StatusRunning:
+		in.Running++
+	case StatusWaitingForPluginOperations:
+		in.WaitingForPluginOperations++
+	case StatusSucceeded:
+		in.Succeeded++
+	case StatusPartiallyFailed:
+		in.PartiallyFailed++
+	case StatusFailed:
+		in.Failed++
+	case StatusFailedValidation:
+		in.FailedValidation++
+	case StatusDeleting:
+		in.Deleting++
+	default:
+		in.Unknown++
+	}
+}
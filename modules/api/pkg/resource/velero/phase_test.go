@@ -0,0 +1,122 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"testing"
+)
+
+func TestBackupPhaseStatus(t *testing.T) {
+	cases := []struct {
+		phase    BackupPhase
+		expected Status
+	}{
+		{"", StatusPending},
+		{BackupPhaseQueued, StatusPending},
+		{BackupPhaseInProgress, StatusRunning},
+		{BackupPhaseWaitingForPluginOperations, StatusWaitingForPluginOperations},
+		{BackupPhaseWaitingForPluginOperationsPartiallyFailed, StatusWaitingForPluginOperations},
+		{BackupPhaseFinalizingPartiallyFailed, StatusRunning},
+		{BackupPhaseCompleted, StatusSucceeded},
+		{BackupPhasePartiallyFailed, StatusPartiallyFailed},
+		{BackupPhaseFailedValidation, StatusFailedValidation},
+		{BackupPhaseDeleting, StatusDeleting},
+		{"SomethingNew", StatusUnknown},
+	}
+
+	for _, c := range cases {
+		if actual := c.phase.Status(); actual != c.expected {
+			t.Errorf("BackupPhase(%q).Status() == %q, expected %q", c.phase, actual, c.expected)
+		}
+	}
+}
+
+func TestRestorePhaseStatus(t *testing.T) {
+	cases := []struct {
+		phase    RestorePhase
+		expected Status
+	}{
+		{RestorePhaseNew, StatusPending},
+		{RestorePhaseFinalizing, StatusRunning},
+		{RestorePhaseWaitingForPluginOperations, StatusWaitingForPluginOperations},
+		{RestorePhaseCompleted, StatusSucceeded},
+		{RestorePhasePartiallyFailed, StatusPartiallyFailed},
+		{RestorePhaseFailed, StatusFailed},
+		{RestorePhaseFailedValidation, StatusFailedValidation},
+		{"SomethingNew", StatusUnknown},
+	}
+
+	for _, c := range cases {
+		if actual := c.phase.Status(); actual != c.expected {
+			t.Errorf("RestorePhase(%q).Status() == %q, expected %q", c.phase, actual, c.expected)
+		}
+	}
+}
+
+func TestDeleteBackupRequestPhaseStatus(t *testing.T) {
+	cases := []struct {
+		phase    DeleteBackupRequestPhase
+		expected Status
+	}{
+		{"", StatusPending},
+		{DeleteBackupRequestPhaseNew, StatusPending},
+		{DeleteBackupRequestPhaseInProgress, StatusRunning},
+		{DeleteBackupRequestPhaseProcessed, StatusSucceeded},
+		{"SomethingNew", StatusUnknown},
+	}
+
+	for _, c := range cases {
+		if actual := c.phase.Status(); actual != c.expected {
+			t.Errorf("DeleteBackupRequestPhase(%q).Status() == %q, expected %q", c.phase, actual, c.expected)
+		}
+	}
+}
+
+func TestStatusIsActive(t *testing.T) {
+	cases := []struct {
+		status   Status
+		expected bool
+	}{
+		{StatusPending, true},
+		{StatusRunning, true},
+		{StatusWaitingForPluginOperations, true},
+		{StatusPartiallyFailed, false},
+		{StatusDeleting, false},
+		{StatusUnknown, false},
+	}
+
+	for _, c := range cases {
+		if actual := c.status.IsActive(); actual != c.expected {
+			t.Errorf("Status(%q).IsActive() == %t, expected %t", c.status, actual, c.expected)
+		}
+	}
+}
+
+func TestListStatusAdd(t *testing.T) {
+	phases := []BackupPhase{BackupPhaseNew, BackupPhaseInProgress, BackupPhaseWaitingForPluginOperations,
+		BackupPhaseCompleted, BackupPhaseCompleted, BackupPhasePartiallyFailed, BackupPhaseFailed,
+		BackupPhaseFailedValidation, BackupPhaseDeleting, "SomethingNew"}
+	expected := ListStatus{Pending: 1, Running: 1, WaitingForPluginOperations: 1, Succeeded: 2, PartiallyFailed: 1,
+		Failed: 1, FailedValidation: 1, Deleting: 1, Unknown: 1}
+
+	actual := ListStatus{}
+	for _, phase := range phases {
+		actual.Add(phase.Status())
+	}
+
+	if actual != expected {
+		t.Errorf("ListStatus.Add() for %v == \n%#v\nexpected \n%#v\n", phases, actual, expected)
+	}
+}
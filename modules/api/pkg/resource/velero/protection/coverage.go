@@ -0,0 +1,183 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/types"
+)
+
+// NamespaceCoverageList tells app teams which schedules and backups protect their namespaces.
+type NamespaceCoverageList struct {
+	Items []NamespaceCoverage `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// NamespaceCoverage lists the schedules and backups that include a namespace.
+type NamespaceCoverage struct {
+	Namespace string `json:"namespace"`
+
+	// Protected is true when a schedule includes the namespace.
+	Protected bool             `json:"protected"`
+	Schedules []CoverageSource `json:"schedules"`
+
+	// Backups including the namespace, newest first.
+	Backups []CoverageSource `json:"backups"`
+
+	// LastSuccessfulBackupTime is the completion time of the latest completed backup including the namespace, nil
+	// when there is none.
+	LastSuccessfulBackupTime *metav1.Time `json:"lastSuccessfulBackupTime"`
+}
+
+// CoverageSource is a schedule or backup including a namespace.
+type CoverageSource struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	// Phase and CompletionTime are only set for backups.
+	Phase          string       `json:"phase,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Partial is true when label selectors or resource filters limit what is backed up to a part of the namespace.
+	Partial bool `json:"partial"`
+}
+
+// GetNamespaceCoverage matches the given namespace, or every namespace of the cluster when it is empty, against the
+// included and excluded namespaces of schedules and backups. Names may be glob patterns, as in Velero.
+func GetNamespaceCoverage(request *http.Request, namespace string) (*NamespaceCoverageList, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	names := []string{namespace}
+	if namespace == "" {
+		k8sClient, err := client.Client(request)
+		if err != nil {
+			return nil, err
+		}
+
+		namespaces, err := k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		names = make([]string, 0, len(namespaces.Items))
+		for _, item := range namespaces.Items {
+			names = append(names, item.Name)
+		}
+		sort.Strings(names)
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, scheduleErrors, err := listAll(ctx, dynamicClient, velero.ScheduleGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, backupErrors, err := listAll(ctx, dynamicClient, velero.BackupGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NamespaceCoverageList{Items: make([]NamespaceCoverage, 0, len(names))}
+	for _, name := range names {
+		result.Items = append(result.Items, toNamespaceCoverage(name, schedules, backups))
+	}
+	result.Errors = append(scheduleErrors, backupErrors...)
+
+	return result, nil
+}
+
+func toNamespaceCoverage(namespace string, schedules, backups []unstructured.Unstructured) NamespaceCoverage {
+	coverage := NamespaceCoverage{Namespace: namespace, Schedules: make([]CoverageSource, 0), Backups: make([]CoverageSource, 0)}
+	for _, item := range schedules {
+		template, _, _ := unstructured.NestedMap(item.Object, "spec", "template")
+		if included, partial := includesNamespace(template, namespace); included {
+			coverage.Schedules = append(coverage.Schedules, CoverageSource{
+				ObjectMeta: velero.NewObjectMeta(item.Object),
+				TypeMeta:   types.TypeMeta{Kind: "Schedule"},
+				Partial:    partial,
+			})
+		}
+	}
+	coverage.Protected = len(coverage.Schedules) > 0
+
+	for _, item := range backups {
+		spec, _, _ := unstructured.NestedMap(item.Object, "spec")
+		included, partial := includesNamespace(spec, namespace)
+		if !included {
+			continue
+		}
+
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		source := CoverageSource{
+			ObjectMeta: velero.NewObjectMeta(item.Object),
+			TypeMeta:   types.TypeMeta{Kind: "Backup"},
+			Phase:      phase,
+			Partial:    partial,
+		}
+
+		completion, _, _ := unstructured.NestedString(item.Object, "status", "completionTimestamp")
+		if completed, err := time.Parse(time.RFC3339, completion); err == nil {
+			completionTime := metav1.NewTime(completed)
+			source.CompletionTime = &completionTime
+
+			last := coverage.LastSuccessfulBackupTime
+			if velero.BackupPhase(phase) == velero.BackupPhaseCompleted && (last == nil || last.Before(&completionTime)) {
+				coverage.LastSuccessfulBackupTime = &completionTime
+			}
+		}
+		coverage.Backups = append(coverage.Backups, source)
+	}
+
+	sort.SliceStable(coverage.Backups, func(i, j int) bool {
+		return coverage.Backups[j].ObjectMeta.CreationTimestamp.Before(&coverage.Backups[i].ObjectMeta.CreationTimestamp)
+	})
+
+	return coverage
+}
+
+// includesNamespace tells whether a backup spec includes the namespace and whether only a part of it is backed up,
+// as label selectors or resource filters leave resources out.
+func includesNamespace(rawSpec map[string]interface{}, namespace string) (included bool, partial bool) {
+	spec := &backup.BackupTemplate{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSpec, spec); err != nil {
+		return false, false
+	}
+
+	if !matchesNames(spec.IncludedNamespaces, spec.ExcludedNamespaces, namespace) {
+		return false, false
+	}
+
+	allResources := (len(spec.IncludedResources) == 0 || slices.Equal(spec.IncludedResources, []string{"*"})) &&
+		len(spec.ExcludedResources) == 0
+	return true, spec.LabelSelector != nil || len(spec.OrLabelSelectors) > 0 || !allResources
+}
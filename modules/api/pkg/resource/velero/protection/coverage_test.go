@@ -0,0 +1,90 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestToNamespaceCoverage(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	selective := newSchedule("shop-db", "shop")
+	selective.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["labelSelector"] =
+		map[string]interface{}{"matchLabels": map[string]interface{}{"tier": "db"}}
+	all := newSchedule("all")
+	all.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["excludedNamespaces"] =
+		[]interface{}{"kube-*"}
+
+	schedules := []unstructured.Unstructured{newSchedule("shop", "shop"), selective, all, newSchedule("blog", "blog")}
+	backups := []unstructured.Unstructured{
+		newCompletedBackup("shop-old", now.Add(-48*time.Hour), []interface{}{"shop"}, nil),
+		newCompletedBackup("shop", now.Add(-24*time.Hour), []interface{}{"shop"}, nil),
+		newBackup("shop-failed", "Failed", now.Add(-time.Hour), "shop"),
+		newCompletedBackup("blog", now.Add(-time.Hour), []interface{}{"blog"}, nil),
+	}
+
+	lastShopBackup := now.Add(-24 * time.Hour)
+
+	type source struct {
+		name    string
+		partial bool
+	}
+	cases := []struct {
+		namespace        string
+		protected        bool
+		schedules        []source
+		backups          []source
+		lastSuccessfulAt *time.Time
+	}{
+		{
+			"shop", true,
+			[]source{{"shop", false}, {"shop-db", true}, {"all", false}},
+			[]source{{"shop-failed", false}, {"shop", false}, {"shop-old", false}},
+			&lastShopBackup,
+		},
+		{"kube-system", false, []source{}, []source{}, nil},
+	}
+
+	for _, c := range cases {
+		actual := toNamespaceCoverage(c.namespace, schedules, backups)
+		if actual.Namespace != c.namespace || actual.Protected != c.protected {
+			t.Errorf("toNamespaceCoverage(%s) == %#v, expected protected %t", c.namespace, actual, c.protected)
+		}
+
+		toSources := func(items []CoverageSource) []source {
+			result := make([]source, 0)
+			for _, item := range items {
+				result = append(result, source{item.ObjectMeta.Name, item.Partial})
+			}
+			return result
+		}
+		if sources := toSources(actual.Schedules); !reflect.DeepEqual(sources, c.schedules) {
+			t.Errorf("toNamespaceCoverage(%s).Schedules == %#v, expected %#v", c.namespace, sources, c.schedules)
+		}
+		if sources := toSources(actual.Backups); !reflect.DeepEqual(sources, c.backups) {
+			t.Errorf("toNamespaceCoverage(%s).Backups == %#v, expected %#v", c.namespace, sources, c.backups)
+		}
+
+		last := actual.LastSuccessfulBackupTime
+		if (last == nil) != (c.lastSuccessfulAt == nil) || (last != nil && !last.Time.Equal(*c.lastSuccessfulAt)) {
+			t.Errorf("toNamespaceCoverage(%s).LastSuccessfulBackupTime == %v, expected %v", c.namespace, last,
+				c.lastSuccessfulAt)
+		}
+	}
+}
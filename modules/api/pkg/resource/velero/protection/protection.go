@@ -0,0 +1,187 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/types"
+)
+
+// DefaultRecentPeriod is how far back backups are considered recent when the caller does not ask for a specific
+// period.
+const DefaultRecentPeriod = "7d"
+
+// WarningReason tells why deleting a namespace affects Velero protection.
+type WarningReason string
+
+const (
+	// WarningReasonScheduleReference means a schedule lists the namespace in its included namespaces.
+	WarningReasonScheduleReference WarningReason = "ScheduleReference"
+	// WarningReasonOnlyNamespaceInBackups means recent backups contain the namespace and nothing else.
+	WarningReasonOnlyNamespaceInBackups WarningReason = "OnlyNamespaceInBackups"
+)
+
+// NamespaceDeletionAdvice lists Velero schedules and backups that protect a namespace, so that users can reconsider
+// deleting it before its protection config is lost.
+type NamespaceDeletionAdvice struct {
+	Namespace string                     `json:"namespace"`
+	Warnings  []NamespaceDeletionWarning `json:"warnings"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// NamespaceDeletionWarning describes how a single schedule or backup is affected by deleting the namespace.
+type NamespaceDeletionWarning struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	Reason  WarningReason `json:"reason"`
+	Message string        `json:"message"`
+}
+
+// GetNamespaceDeletionAdvice warns about schedules referencing the namespace and about backups taken within the
+// period that contain only the namespace. Nothing is changed in the cluster.
+func GetNamespaceDeletionAdvice(request *http.Request, namespace, period string) (*NamespaceDeletionAdvice, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	duration, err := backup.ParseWindow(period)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, scheduleErrors, err := listAll(ctx, dynamicClient, velero.ScheduleGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, backupErrors, err := listAll(ctx, dynamicClient, velero.BackupGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := getScheduleWarnings(namespace, schedules)
+	if warning := getBackupWarning(namespace, backups, period, time.Now().Add(-duration)); warning != nil {
+		warnings = append(warnings, *warning)
+	}
+
+	return &NamespaceDeletionAdvice{
+		Namespace: namespace,
+		Warnings:  warnings,
+		Errors:    append(scheduleErrors, backupErrors...),
+	}, nil
+}
+
+// listAll lists the Velero resource in all namespaces, as schedules and backups live in the Velero namespace rather
+// than the namespaces they protect.
+func listAll(ctx context.Context, client dynamic.Interface, resource schema.GroupVersionResource) ([]unstructured.Unstructured, []error, error) {
+	items, truncated, err := velero.List(ctx, client.Resource(resource).Namespace(metav1.NamespaceAll), metav1.ListOptions{},
+		args.VeleroMaxListItems())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonCriticalErrors := make([]error, 0)
+	if truncated {
+		nonCriticalErrors = append(nonCriticalErrors, velero.NewListTruncatedError(resource.Resource, args.VeleroMaxListItems()))
+	}
+
+	return items, nonCriticalErrors, nil
+}
+
+func getScheduleWarnings(namespace string, schedules []unstructured.Unstructured) []NamespaceDeletionWarning {
+	warnings := make([]NamespaceDeletionWarning, 0)
+	for _, item := range schedules {
+		included, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "template", "includedNamespaces")
+		if !slices.Contains(included, namespace) {
+			continue
+		}
+
+		message := fmt.Sprintf("Schedule %s includes namespace %s and will keep backing it up by name after it is deleted.",
+			item.GetName(), namespace)
+		if len(included) == 1 {
+			message = fmt.Sprintf("Schedule %s backs up only namespace %s and will create empty backups after it is deleted.",
+				item.GetName(), namespace)
+		}
+
+		warnings = append(warnings, NamespaceDeletionWarning{
+			ObjectMeta: velero.NewObjectMeta(item.Object),
+			TypeMeta:   types.TypeMeta{Kind: "Schedule"},
+			Reason:     WarningReasonScheduleReference,
+			Message:    message,
+		})
+	}
+
+	return warnings
+}
+
+// getBackupWarning returns a single warning pointing to the latest of the successful backups started since the given
+// time that contain only the namespace, or nil when there are none.
+func getBackupWarning(namespace string, backups []unstructured.Unstructured, period string, since time.Time) *NamespaceDeletionWarning {
+	var latest *unstructured.Unstructured
+	count := 0
+	for i, item := range backups {
+		included, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "includedNamespaces")
+		if len(included) != 1 || included[0] != namespace {
+			continue
+		}
+
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		if velero.BackupPhase(phase) != velero.BackupPhaseCompleted {
+			continue
+		}
+
+		created := item.GetCreationTimestamp()
+		if created.Time.Before(since) {
+			continue
+		}
+
+		count++
+		if latest == nil || latest.GetCreationTimestamp().Time.Before(created.Time) {
+			latest = &backups[i]
+		}
+	}
+
+	if latest == nil {
+		return nil
+	}
+
+	return &NamespaceDeletionWarning{
+		ObjectMeta: velero.NewObjectMeta(latest.Object),
+		TypeMeta:   types.TypeMeta{Kind: "Backup"},
+		Reason:     WarningReasonOnlyNamespaceInBackups,
+		Message: fmt.Sprintf("%d backups completed in the last %s contain only namespace %s, the latest is %s.",
+			count, period, namespace, latest.GetName()),
+	}
+}
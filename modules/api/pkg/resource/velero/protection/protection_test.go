@@ -0,0 +1,123 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/types"
+)
+
+func newSchedule(name string, namespaces ...interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": "velero"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{"includedNamespaces": namespaces},
+		},
+	}}
+}
+
+func newBackup(name, phase string, created time.Time, namespaces ...interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         "velero",
+			"creationTimestamp": created.Format(time.RFC3339),
+		},
+		"spec":   map[string]interface{}{"includedNamespaces": namespaces},
+		"status": map[string]interface{}{"phase": phase},
+	}}
+}
+
+func TestGetScheduleWarnings(t *testing.T) {
+	schedules := []unstructured.Unstructured{
+		newSchedule("shop", "shop"),
+		newSchedule("apps", "shop", "blog"),
+		newSchedule("blog", "blog"),
+		newSchedule("all", "*"),
+	}
+
+	expected := []NamespaceDeletionWarning{
+		{
+			ObjectMeta: types.ObjectMeta{Name: "shop", Namespace: "velero"},
+			TypeMeta:   types.TypeMeta{Kind: "Schedule"},
+			Reason:     WarningReasonScheduleReference,
+			Message:    "Schedule shop backs up only namespace shop and will create empty backups after it is deleted.",
+		},
+		{
+			ObjectMeta: types.ObjectMeta{Name: "apps", Namespace: "velero"},
+			TypeMeta:   types.TypeMeta{Kind: "Schedule"},
+			Reason:     WarningReasonScheduleReference,
+			Message:    "Schedule apps includes namespace shop and will keep backing it up by name after it is deleted.",
+		},
+	}
+
+	actual := getScheduleWarnings("shop", schedules)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getScheduleWarnings(%#v) == \n%#v\nexpected \n%#v\n", schedules, actual, expected)
+	}
+}
+
+func TestGetBackupWarning(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-7 * 24 * time.Hour)
+	latest := now.Add(-time.Hour)
+
+	cases := []struct {
+		info     string
+		backups  []unstructured.Unstructured
+		expected *NamespaceDeletionWarning
+	}{
+		{
+			"no backups contain only the namespace",
+			[]unstructured.Unstructured{
+				newBackup("apps", "Completed", latest, "shop", "blog"),
+				newBackup("old", "Completed", since.Add(-time.Hour), "shop"),
+				newBackup("failed", "Failed", latest, "shop"),
+			},
+			nil,
+		},
+		{
+			"latest completed backup is reported",
+			[]unstructured.Unstructured{
+				newBackup("shop-1", "Completed", now.Add(-48*time.Hour), "shop"),
+				newBackup("shop-2", "Completed", latest, "shop"),
+				newBackup("shop-3", "InProgress", now, "shop"),
+			},
+			&NamespaceDeletionWarning{
+				ObjectMeta: types.ObjectMeta{
+					Name:              "shop-2",
+					Namespace:         "velero",
+					CreationTimestamp: metav1.NewTime(latest.Local()),
+				},
+				TypeMeta: types.TypeMeta{Kind: "Backup"},
+				Reason:   WarningReasonOnlyNamespaceInBackups,
+				Message:  "2 backups completed in the last 7d contain only namespace shop, the latest is shop-2.",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := getBackupWarning("shop", c.backups, "7d", since)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: getBackupWarning() == \n%#v\nexpected \n%#v\n", c.info, actual, c.expected)
+		}
+	}
+}
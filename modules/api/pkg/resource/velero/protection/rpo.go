@@ -0,0 +1,150 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/types"
+)
+
+// DefaultRPOThreshold is the maximum age of the latest successful backup of a namespace when the caller does not ask
+// for a specific one.
+const DefaultRPOThreshold = "24h"
+
+// RPOReport lists namespaces whose latest successful backup is older than the recovery point objective.
+type RPOReport struct {
+	Threshold           string `json:"threshold"`
+	TotalNamespaces     int    `json:"totalNamespaces"`
+	CompliantNamespaces int    `json:"compliantNamespaces"`
+
+	// Violations lists namespaces that were never backed up first, followed by the ones with the oldest backups.
+	Violations []NamespaceRPO `json:"violations"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// NamespaceRPO is a namespace along with its latest successful backup.
+type NamespaceRPO struct {
+	Namespace string `json:"namespace"`
+
+	// LastBackup is the latest completed backup including the namespace, nil when there is none.
+	LastBackup     *types.ObjectMeta `json:"lastBackup,omitempty"`
+	LastBackupTime *metav1.Time      `json:"lastBackupTime,omitempty"`
+}
+
+// GetRPOReport checks every namespace of the cluster, except the excluded ones, against the included and excluded
+// namespaces of completed backups. Names may be glob patterns in both.
+func GetRPOReport(request *http.Request, threshold string, excluded []string) (*RPOReport, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	duration, err := backup.ParseWindow(threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, err := k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, nonCriticalErrors, err := listAll(ctx, dynamicClient, velero.BackupGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		if !matchesNames(nil, excluded, namespace.Name) {
+			continue
+		}
+		names = append(names, namespace.Name)
+	}
+
+	report := toRPOReport(names, backups, time.Now().Add(-duration))
+	report.Threshold = threshold
+	report.Errors = nonCriticalErrors
+	return report, nil
+}
+
+func toRPOReport(namespaces []string, backups []unstructured.Unstructured, since time.Time) *RPOReport {
+	latest := make(map[string]NamespaceRPO, len(namespaces))
+	for _, namespace := range namespaces {
+		latest[namespace] = NamespaceRPO{Namespace: namespace}
+	}
+
+	for _, item := range backups {
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		completion, _, _ := unstructured.NestedString(item.Object, "status", "completionTimestamp")
+		completed, err := time.Parse(time.RFC3339, completion)
+		if velero.BackupPhase(phase) != velero.BackupPhaseCompleted || err != nil {
+			continue
+		}
+
+		included, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "includedNamespaces")
+		excluded, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "excludedNamespaces")
+		for _, namespace := range namespaces {
+			current := latest[namespace]
+			if !matchesNames(included, excluded, namespace) ||
+				(current.LastBackupTime != nil && !current.LastBackupTime.Time.Before(completed)) {
+				continue
+			}
+
+			meta := velero.NewObjectMeta(item.Object)
+			completionTime := metav1.NewTime(completed)
+			latest[namespace] = NamespaceRPO{Namespace: namespace, LastBackup: &meta, LastBackupTime: &completionTime}
+		}
+	}
+
+	report := &RPOReport{TotalNamespaces: len(namespaces), Violations: make([]NamespaceRPO, 0)}
+	for _, namespace := range namespaces {
+		rpo := latest[namespace]
+		if rpo.LastBackupTime != nil && !rpo.LastBackupTime.Time.Before(since) {
+			report.CompliantNamespaces++
+			continue
+		}
+		report.Violations = append(report.Violations, rpo)
+	}
+
+	sort.SliceStable(report.Violations, func(i, j int) bool {
+		left, right := report.Violations[i].LastBackupTime, report.Violations[j].LastBackupTime
+		if left == nil || right == nil {
+			return left == nil && right != nil
+		}
+		return left.Before(right)
+	})
+
+	return report
+}
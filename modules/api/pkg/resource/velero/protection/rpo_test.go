@@ -0,0 +1,80 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/types"
+)
+
+func newCompletedBackup(name string, completed time.Time, included, excluded []interface{}) unstructured.Unstructured {
+	item := newBackup(name, "Completed", completed, included...)
+	item.Object["spec"].(map[string]interface{})["excludedNamespaces"] = excluded
+	item.Object["status"].(map[string]interface{})["completionTimestamp"] = completed.Format(time.RFC3339)
+	return item
+}
+
+func TestToRPOReport(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-24 * time.Hour)
+	recent := now.Add(-time.Hour)
+	old := now.Add(-48 * time.Hour)
+	older := now.Add(-72 * time.Hour)
+
+	backups := []unstructured.Unstructured{
+		newCompletedBackup("shop", recent, []interface{}{"shop"}, nil),
+		newCompletedBackup("all", old, nil, []interface{}{"kube-*"}),
+		newCompletedBackup("blog", older, []interface{}{"blog"}, nil),
+		newBackup("blog-failed", "Failed", recent, "blog"),
+	}
+
+	oldTime := metav1.NewTime(old.Local())
+	expected := &RPOReport{
+		TotalNamespaces:     4,
+		CompliantNamespaces: 1,
+		Violations: []NamespaceRPO{
+			{Namespace: "kube-system"},
+			{
+				Namespace: "blog",
+				LastBackup: &types.ObjectMeta{
+					Name:              "all",
+					Namespace:         "velero",
+					CreationTimestamp: oldTime,
+				},
+				LastBackupTime: &metav1.Time{Time: old},
+			},
+			{
+				Namespace: "docs",
+				LastBackup: &types.ObjectMeta{
+					Name:              "all",
+					Namespace:         "velero",
+					CreationTimestamp: oldTime,
+				},
+				LastBackupTime: &metav1.Time{Time: old},
+			},
+		},
+	}
+
+	actual := toRPOReport([]string{"shop", "blog", "docs", "kube-system"}, backups, since)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toRPOReport() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/dashboard/api/pkg/resource/backup"
+	"k8s.io/dashboard/api/pkg/resource/velero"
+	"k8s.io/dashboard/client"
+	"k8s.io/dashboard/errors"
+	"k8s.io/dashboard/types"
+)
+
+// WorkloadProtection tells whether a workload is protected by Velero, to be shown as a badge on its detail page.
+type WorkloadProtection struct {
+	Workload backup.WorkloadReference `json:"workload"`
+
+	// Protected is true when a schedule covers the workload.
+	Protected bool `json:"protected"`
+
+	// HasVolumes is true when pods of the workload use persistent volume claims.
+	HasVolumes bool `json:"hasVolumes"`
+
+	// Schedules whose backups contain the workload.
+	Schedules []ProtectionSource `json:"schedules"`
+
+	// LastBackup is the latest completed backup containing the workload, nil when there is none.
+	LastBackup *ProtectionSource `json:"lastBackup"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ProtectionSource is a schedule or backup containing the workload.
+type ProtectionSource struct {
+	ObjectMeta types.ObjectMeta `json:"objectMeta"`
+	TypeMeta   types.TypeMeta   `json:"typeMeta"`
+
+	// CompletionTime is only set for backups.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// VolumesIncluded is true when data of the workload volumes is backed up, by snapshots or the file system
+	// uploader, not only the persistent volume claims.
+	VolumesIncluded bool `json:"volumesIncluded"`
+}
+
+// protectedWorkload holds what is needed from a Deployment or StatefulSet to match it against backup specs.
+type protectedWorkload struct {
+	resource   string
+	namespace  string
+	labels     map[string]string
+	hasVolumes bool
+}
+
+// GetWorkloadProtection returns the schedules covering a Deployment or StatefulSet and its latest backup.
+func GetWorkloadProtection(request *http.Request, ref backup.WorkloadReference) (*WorkloadProtection, error) {
+	ctx, cancel := velero.OperationContext(request)
+	defer cancel()
+
+	k8sClient, err := client.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := getProtectedWorkload(ctx, k8sClient, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := velero.DynamicClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, scheduleErrors, err := listAll(ctx, dynamicClient, velero.ScheduleGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, backupErrors, err := listAll(ctx, dynamicClient, velero.BackupGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	protection := toWorkloadProtection(target, schedules, backups)
+	protection.Workload = ref
+	protection.Errors = append(scheduleErrors, backupErrors...)
+
+	return protection, nil
+}
+
+func getProtectedWorkload(ctx context.Context, client kubernetes.Interface, ref backup.WorkloadReference) (*protectedWorkload, error) {
+	switch types.ResourceKind(strings.ToLower(ref.Kind)) {
+	case types.ResourceKindDeployment:
+		deployment, err := client.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &protectedWorkload{resource: "deployments", namespace: ref.Namespace, labels: deployment.Labels,
+			hasVolumes: hasClaims(deployment.Spec.Template.Spec.Volumes)}, nil
+	case types.ResourceKindStatefulSet:
+		statefulSet, err := client.AppsV1().StatefulSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &protectedWorkload{resource: "statefulsets", namespace: ref.Namespace, labels: statefulSet.Labels,
+			hasVolumes: len(statefulSet.Spec.VolumeClaimTemplates) > 0 || hasClaims(statefulSet.Spec.Template.Spec.Volumes)}, nil
+	default:
+		return nil, errors.NewBadRequest(fmt.Sprintf("unsupported workload kind %q, expected Deployment or StatefulSet", ref.Kind))
+	}
+}
+
+func hasClaims(volumes []v1.Volume) bool {
+	for _, volume := range volumes {
+		if volume.PersistentVolumeClaim != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toWorkloadProtection(target *protectedWorkload, schedules, backups []unstructured.Unstructured) *WorkloadProtection {
+	protection := &WorkloadProtection{HasVolumes: target.hasVolumes, Schedules: make([]ProtectionSource, 0)}
+	for _, item := range schedules {
+		template, _, _ := unstructured.NestedMap(item.Object, "spec", "template")
+		if covered, volumesIncluded := covers(template, target); covered {
+			protection.Schedules = append(protection.Schedules, ProtectionSource{
+				ObjectMeta:      velero.NewObjectMeta(item.Object),
+				TypeMeta:        types.TypeMeta{Kind: "Schedule"},
+				VolumesIncluded: volumesIncluded,
+			})
+		}
+	}
+	protection.Protected = len(protection.Schedules) > 0
+
+	for _, item := range backups {
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		if velero.BackupPhase(phase) != velero.BackupPhaseCompleted {
+			continue
+		}
+
+		completed, _, _ := unstructured.NestedString(item.Object, "status", "completionTimestamp")
+		completionTime := &metav1.Time{}
+		if err := completionTime.UnmarshalQueryParameter(completed); err != nil || completionTime.IsZero() {
+			continue
+		}
+		if protection.LastBackup != nil && !protection.LastBackup.CompletionTime.Before(completionTime) {
+			continue
+		}
+
+		spec, _, _ := unstructured.NestedMap(item.Object, "spec")
+		if covered, volumesIncluded := covers(spec, target); covered {
+			protection.LastBackup = &ProtectionSource{
+				ObjectMeta:      velero.NewObjectMeta(item.Object),
+				TypeMeta:        types.TypeMeta{Kind: "Backup"},
+				CompletionTime:  completionTime,
+				VolumesIncluded: volumesIncluded,
+			}
+		}
+	}
+
+	return protection
+}
+
+// covers tells whether a backup spec selects the workload and whether it backs up the data of its volumes.
+func covers(rawSpec map[string]interface{}, target *protectedWorkload) (covered bool, volumesIncluded bool) {
+	spec := &backup.BackupTemplate{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSpec, spec); err != nil {
+		return false, false
+	}
+
+	if !matchesNames(spec.IncludedNamespaces, spec.ExcludedNamespaces, target.namespace) ||
+		!matchesNames(spec.IncludedResources, spec.ExcludedResources, target.resource, target.resource+".apps") ||
+		!matchesLabels(spec, target.labels) {
+		return false, false
+	}
+
+	claimsIncluded := matchesNames(spec.IncludedResources, spec.ExcludedResources, "persistentvolumeclaims")
+	dataBackedUp := spec.SnapshotVolumes == nil || *spec.SnapshotVolumes ||
+		(spec.DefaultVolumesToFsBackup != nil && *spec.DefaultVolumesToFsBackup)
+
+	return true, target.hasVolumes && claimsIncluded && dataBackedUp
+}
+
+// matchesNames tells whether any of the names is included and none is excluded. Empty included names and "*" include
+// everything, and names may be glob patterns as supported by Velero.
+func matchesNames(included, excluded []string, names ...string) bool {
+	matches := func(patterns []string) bool {
+		return slices.ContainsFunc(patterns, func(pattern string) bool {
+			return slices.ContainsFunc(names, func(name string) bool {
+				matched, _ := path.Match(pattern, name)
+				return matched
+			})
+		})
+	}
+
+	return (len(included) == 0 || matches(included)) && !matches(excluded)
+}
+
+// matchesLabels tells whether the workload labels match the label selector of the spec, or any of its alternative
+// label selectors.
+func matchesLabels(spec *backup.BackupTemplate, workloadLabels map[string]string) bool {
+	selectors := spec.OrLabelSelectors
+	if spec.LabelSelector != nil {
+		selectors = append(selectors, spec.LabelSelector)
+	}
+	if len(selectors) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(selectors, func(labelSelector *metav1.LabelSelector) bool {
+		selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+		return err == nil && selector.Matches(labels.Set(workloadLabels))
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/dashboard/types"
+)
+
+func newCoveringSchedule(name string, template map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": "velero"},
+		"spec":     map[string]interface{}{"template": template},
+	}}
+}
+
+func newCoveringBackup(name, phase string, completed time.Time, spec map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": "velero"},
+		"spec":     spec,
+		"status":   map[string]interface{}{"phase": phase, "completionTimestamp": completed.Format(time.RFC3339)},
+	}}
+}
+
+func TestToWorkloadProtection(t *testing.T) {
+	target := &protectedWorkload{resource: "statefulsets", namespace: "shop", labels: map[string]string{"app": "db"},
+		hasVolumes: true}
+	older := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+	newer := time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC)
+
+	schedules := []unstructured.Unstructured{
+		newCoveringSchedule("shop", map[string]interface{}{"includedNamespaces": []interface{}{"shop"}}),
+		newCoveringSchedule("all-without-snapshots", map[string]interface{}{
+			"includedNamespaces": []interface{}{"*"},
+			"snapshotVolumes":    false,
+		}),
+		newCoveringSchedule("all-fs-backup", map[string]interface{}{
+			"snapshotVolumes":          false,
+			"defaultVolumesToFsBackup": true,
+		}),
+		newCoveringSchedule("blog", map[string]interface{}{"includedNamespaces": []interface{}{"blog"}}),
+		newCoveringSchedule("shop-without-statefulsets", map[string]interface{}{
+			"includedNamespaces": []interface{}{"shop"},
+			"excludedResources":  []interface{}{"statefulsets.apps"},
+		}),
+		newCoveringSchedule("shop-frontend", map[string]interface{}{
+			"includedNamespaces": []interface{}{"shop"},
+			"labelSelector":      map[string]interface{}{"matchLabels": map[string]interface{}{"app": "frontend"}},
+		}),
+		newCoveringSchedule("shop-without-claims", map[string]interface{}{
+			"includedNamespaces": []interface{}{"shop"},
+			"includedResources":  []interface{}{"statefulsets", "pods"},
+		}),
+	}
+	backups := []unstructured.Unstructured{
+		newCoveringBackup("shop-older", "Completed", older, map[string]interface{}{"includedNamespaces": []interface{}{"shop"}}),
+		newCoveringBackup("shop-failed", "Failed", newer.Add(time.Hour), map[string]interface{}{}),
+		newCoveringBackup("blog-newer", "Completed", newer.Add(time.Hour), map[string]interface{}{"includedNamespaces": []interface{}{"blog"}}),
+		newCoveringBackup("shop-newer", "Completed", newer, map[string]interface{}{
+			"includedNamespaces": []interface{}{"shop"},
+			"snapshotVolumes":    false,
+		}),
+	}
+
+	newerTime := metav1.NewTime(newer.Local())
+	expected := &WorkloadProtection{
+		Protected:  true,
+		HasVolumes: true,
+		Schedules: []ProtectionSource{
+			{ObjectMeta: types.ObjectMeta{Name: "shop", Namespace: "velero"}, TypeMeta: types.TypeMeta{Kind: "Schedule"}, VolumesIncluded: true},
+			{ObjectMeta: types.ObjectMeta{Name: "all-without-snapshots", Namespace: "velero"}, TypeMeta: types.TypeMeta{Kind: "Schedule"}},
+			{ObjectMeta: types.ObjectMeta{Name: "all-fs-backup", Namespace: "velero"}, TypeMeta: types.TypeMeta{Kind: "Schedule"}, VolumesIncluded: true},
+			{ObjectMeta: types.ObjectMeta{Name: "shop-without-claims", Namespace: "velero"}, TypeMeta: types.TypeMeta{Kind: "Schedule"}},
+		},
+		LastBackup: &ProtectionSource{
+			ObjectMeta:     types.ObjectMeta{Name: "shop-newer", Namespace: "velero"},
+			TypeMeta:       types.TypeMeta{Kind: "Backup"},
+			CompletionTime: &newerTime,
+		},
+	}
+
+	actual := toWorkloadProtection(target, schedules, backups)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toWorkloadProtection() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestToWorkloadProtectionUnprotected(t *testing.T) {
+	target := &protectedWorkload{resource: "deployments", namespace: "shop", labels: map[string]string{"app": "web"}}
+	schedules := []unstructured.Unstructured{
+		newCoveringSchedule("blog", map[string]interface{}{"includedNamespaces": []interface{}{"blog"}}),
+	}
+	expected := &WorkloadProtection{Schedules: []ProtectionSource{}}
+
+	actual := toWorkloadProtection(target, schedules, nil)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toWorkloadProtection() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ToRequestStatusError returns the Kubernetes status of an error caused by the request, e.g. a conflict or an invalid
+// object, so that it can be reported with its code, reason and field causes. The message is taken from the error to
+// keep the context the status was wrapped with. Unauthorized and forbidden errors are not returned, they are reported
+// the same way as for other resources.
+func ToRequestStatusError(err error) (*k8serrors.StatusError, bool) {
+	var status *k8serrors.StatusError
+	if !stderrors.As(err, &status) {
+		return nil, false
+	}
+
+	code := status.ErrStatus.Code
+	if code < http.StatusBadRequest || code >= http.StatusInternalServerError || code == http.StatusUnauthorized ||
+		code == http.StatusForbidden {
+		return nil, false
+	}
+
+	result := &k8serrors.StatusError{ErrStatus: *status.ErrStatus.DeepCopy()}
+	result.ErrStatus.Message = err.Error()
+	return result, true
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestToRequestStatusError(t *testing.T) {
+	invalid := k8serrors.NewInvalid(schema.GroupKind{Group: "velero.io", Kind: "Backup"}, "daily",
+		field.ErrorList{field.Invalid(field.NewPath("spec", "ttl"), "1x", "invalid duration")})
+
+	cases := []struct {
+		info            string
+		err             error
+		expectedCode    int32
+		expectedMessage string
+		expectedCauses  int
+	}{
+		{"not a status", fmt.Errorf("connection refused"), 0, "", 0},
+		{
+			"wrapped conflict",
+			fmt.Errorf("Failed to create backup: %w", k8serrors.NewAlreadyExists(BackupGVR.GroupResource(), "daily")),
+			http.StatusConflict,
+			`Failed to create backup: backups.velero.io "daily" already exists`,
+			0,
+		},
+		{"invalid", invalid, http.StatusUnprocessableEntity, invalid.Error(), 1},
+		{"forbidden", k8serrors.NewForbidden(BackupGVR.GroupResource(), "daily", fmt.Errorf("denied")), 0, "", 0},
+		{"internal", k8serrors.NewInternalError(fmt.Errorf("etcd")), 0, "", 0},
+	}
+
+	for _, c := range cases {
+		actual, ok := ToRequestStatusError(c.err)
+		if !ok {
+			if c.expectedCode != 0 {
+				t.Errorf("%s: ToRequestStatusError() returned no status, expected code %d", c.info, c.expectedCode)
+			}
+			continue
+		}
+
+		causes := 0
+		if actual.ErrStatus.Details != nil {
+			causes = len(actual.ErrStatus.Details.Causes)
+		}
+		if actual.ErrStatus.Code != c.expectedCode || actual.ErrStatus.Message != c.expectedMessage ||
+			causes != c.expectedCauses {
+			t.Errorf("%s: ToRequestStatusError() == %d %q with %d causes, expected %d %q with %d causes", c.info,
+				actual.ErrStatus.Code, actual.ErrStatus.Message, causes, c.expectedCode, c.expectedMessage,
+				c.expectedCauses)
+		}
+	}
+}
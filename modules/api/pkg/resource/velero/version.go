@@ -0,0 +1,212 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"k8s.io/dashboard/api/pkg/args"
+	"k8s.io/dashboard/client"
+)
+
+const (
+	// versionCacheTTL is how long versions read from the Velero CRDs are reused, so that Velero upgrades are picked up
+	// without reading the CRDs on every request.
+	versionCacheTTL = 5 * time.Minute
+
+	// versionCacheFailureTTL is how long reading the CRDs is not tried again after it failed, so that a failing API
+	// server is not asked again by every request.
+	versionCacheFailureTTL = 30 * time.Second
+
+	// versionReadTimeout bounds reading the CRDs, which is not canceled together with the request that started it.
+	versionReadTimeout = 10 * time.Second
+)
+
+// crdVersions are the versions a Velero CRD serves and the one its objects are stored at.
+type crdVersions struct {
+	Served  []string
+	Storage string
+}
+
+// versionCache holds versions of Velero CRDs by resource, e.g. "backups". The versions are shared by the requests of
+// all users, as they only tell which versions the cluster serves. They are read with the credentials of the request
+// that finds them expired, a failure to read them, e.g. for lack of permissions, is not retried until it expires.
+type versionCache struct {
+	mu       sync.Mutex
+	versions map[string]crdVersions
+	expires  time.Time
+
+	// reads coalesces reading the CRDs, which is done without holding mu.
+	reads singleflight.Group
+}
+
+var negotiatedVersions = &versionCache{}
+
+// DynamicClient returns a dynamic client that requests Velero resources at a version the cluster serves. Resources
+// are requested at the version of their GVR when it is served, otherwise at the version the CRD stores objects at,
+// which the API server returns without calling a conversion webhook. When the CRDs cannot be read, e.g. for lack of
+// permissions, the versions of the GVRs are used as they are. Objects read through the client are recorded in the
+// version tracker of the request, if it has one. The client is shared by the requests of a user, see
+// client.VeleroClient.
+func DynamicClient(request *http.Request) (dynamic.Interface, error) {
+	dynamicClient, err := client.VeleroClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	apiextensionsClient, err := client.APIExtensionsClient(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return withVersionTracking(request, &negotiatingClient{
+		Interface: dynamicClient,
+		versions:  negotiatedVersions.get(request.Context(), apiextensionsClient),
+	}), nil
+}
+
+// negotiatingClient rewrites the version of Velero resources before passing them to the wrapped client.
+type negotiatingClient struct {
+	dynamic.Interface
+	versions map[string]crdVersions
+}
+
+func (in *negotiatingClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return in.Interface.Resource(negotiateVersion(resource, in.versions, args.VeleroAPIVersions()))
+}
+
+// negotiateVersion returns the resource at the version it should be requested at. A version overridden for the
+// resource is used as it is. Otherwise the version of the GVR is kept when it is served, and the preferred served
+// version is used when it is not.
+func negotiateVersion(resource schema.GroupVersionResource, versions map[string]crdVersions, overrides map[string]string) schema.GroupVersionResource {
+	if resource.Group != GroupName {
+		return resource
+	}
+
+	if version, ok := overrides[resource.Resource]; ok && version != "" {
+		resource.Version = version
+		return resource
+	}
+
+	crd, ok := versions[resource.Resource]
+	if !ok || len(crd.Served) == 0 || slices.Contains(crd.Served, resource.Version) {
+		return resource
+	}
+
+	resource.Version = crd.preferredVersion()
+	return resource
+}
+
+// preferredVersion returns the storage version when it is served, as objects are returned at it without calling a
+// conversion webhook. Otherwise it returns the served version Kubernetes prefers, i.e. the most stable and recent one,
+// e.g. v2 over v2beta1 and v1.
+func (in crdVersions) preferredVersion() string {
+	if in.Storage != "" && slices.Contains(in.Served, in.Storage) {
+		return in.Storage
+	}
+
+	return slices.MaxFunc(in.Served, version.CompareKubeAwareVersionStrings)
+}
+
+// get returns the cached versions. Expired versions are returned while they are read again in the background, only
+// the first read is waited for. The last known versions are kept when the CRDs cannot be read.
+func (in *versionCache) get(ctx context.Context, apiextensionsClient apiextensionsclientset.Interface) map[string]crdVersions {
+	in.mu.Lock()
+	versions, expires := in.versions, in.expires
+	in.mu.Unlock()
+
+	hit := time.Now().Before(expires)
+	observeCacheLookup(crdVersionCacheName, hit)
+	if hit {
+		return versions
+	}
+
+	read := in.reads.DoChan("versions", func() (interface{}, error) {
+		return in.refresh(context.WithoutCancel(ctx), apiextensionsClient), nil
+	})
+	if versions != nil {
+		return versions
+	}
+
+	select {
+	case result := <-read:
+		return result.Val.(map[string]crdVersions)
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// refresh reads the versions from the CRDs and caches them, or caches the failure to read them.
+func (in *versionCache) refresh(ctx context.Context, apiextensionsClient apiextensionsclientset.Interface) map[string]crdVersions {
+	ctx, cancel := context.WithTimeout(ctx, versionReadTimeout)
+	defer cancel()
+
+	versions, err := getCRDVersions(ctx, apiextensionsClient)
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if err != nil {
+		klog.V(args.LogLevelVerbose).Infof("Could not read Velero CRD versions: %s", err.Error())
+		in.expires = time.Now().Add(versionCacheFailureTTL)
+		return in.versions
+	}
+
+	in.versions = versions
+	in.expires = time.Now().Add(versionCacheTTL)
+	return in.versions
+}
+
+func getCRDVersions(ctx context.Context, apiextensionsClient apiextensionsclientset.Interface) (map[string]crdVersions, error) {
+	crds, err := apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]crdVersions)
+	for _, crd := range crds.Items {
+		if crd.Spec.Group == GroupName {
+			versions[crd.Spec.Names.Plural] = toCRDVersions(crd.Spec.Versions)
+		}
+	}
+
+	return versions, nil
+}
+
+func toCRDVersions(versions []apiextensionsv1.CustomResourceDefinitionVersion) crdVersions {
+	result := crdVersions{Served: make([]string, 0, len(versions))}
+	for _, version := range versions {
+		if version.Served {
+			result.Served = append(result.Served, version.Name)
+		}
+		if version.Storage {
+			result.Storage = version.Name
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,158 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	fakeapiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestNegotiateVersion(t *testing.T) {
+	versions := map[string]crdVersions{
+		"backups":          {Served: []string{"v1"}, Storage: "v1"},
+		"restores":         {Served: []string{"v1beta1", "v2"}, Storage: "v2"},
+		"schedules":        {Served: []string{"v2"}, Storage: "v3"},
+		"datauploads":      {Served: []string{"v2alpha1", "v2"}, Storage: "v2"},
+		"podvolumebackups": {Served: []string{"v2beta1", "v2", "v1beta1"}, Storage: "v3"},
+		"datadownloads":    {Served: []string{"v2alpha1"}, Storage: "v2alpha1"},
+	}
+	overrides := map[string]string{"datadownloads": "v2beta1"}
+	cases := []struct {
+		info     string
+		resource schema.GroupVersionResource
+		expected schema.GroupVersionResource
+	}{
+		{"served version", BackupGVR, BackupGVR},
+		{"served version other than storage", DataUploadGVR, DataUploadGVR},
+		{"storage version", RestoreGVR, schema.GroupVersionResource{Group: GroupName, Version: "v2", Resource: "restores"}},
+		{"storage version not served", ScheduleGVR, schema.GroupVersionResource{Group: GroupName, Version: "v2", Resource: "schedules"}},
+		{"preferred served version", PodVolumeBackupGVR, schema.GroupVersionResource{Group: GroupName, Version: "v2", Resource: "podvolumebackups"}},
+		{"overridden version", DataDownloadGVR, schema.GroupVersionResource{Group: GroupName, Version: "v2beta1", Resource: "datadownloads"}},
+		{"unknown resource", DownloadRequestGVR, DownloadRequestGVR},
+		{
+			"other group",
+			schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "restores"},
+			schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "restores"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := negotiateVersion(c.resource, versions, overrides)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: negotiateVersion(%#v) == \n%#v\nexpected \n%#v\n", c.info, c.resource, actual, c.expected)
+		}
+	}
+}
+
+func TestGetCRDVersions(t *testing.T) {
+	apiextensionsClient := fakeapiextensions.NewSimpleClientset(
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "backups.velero.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: GroupName,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "backups"},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1", Served: true},
+					{Name: "v2", Served: true, Storage: true},
+					{Name: "v2beta1", Served: false},
+				},
+			},
+		},
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "certificates.cert-manager.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group:    "cert-manager.io",
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Plural: "certificates"},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+			},
+		},
+	)
+	expected := map[string]crdVersions{"backups": {Served: []string{"v1", "v2"}, Storage: "v2"}}
+
+	actual, err := getCRDVersions(context.Background(), apiextensionsClient)
+	if err != nil {
+		t.Fatalf("getCRDVersions() returned error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getCRDVersions() == \n%#v\nexpected \n%#v\n", actual, expected)
+	}
+}
+
+func TestVersionCacheGet(t *testing.T) {
+	newClient := func(fail bool, lists *int32) *fakeapiextensions.Clientset {
+		apiextensionsClient := fakeapiextensions.NewSimpleClientset(&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "backups.velero.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group:    GroupName,
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Plural: "backups"},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+			},
+		})
+		apiextensionsClient.PrependReactor("list", "customresourcedefinitions", func(clienttesting.Action) (bool, runtime.Object, error) {
+			atomic.AddInt32(lists, 1)
+			if fail {
+				return true, nil, k8serrors.NewServiceUnavailable("unavailable")
+			}
+			return false, nil, nil
+		})
+		return apiextensionsClient
+	}
+	expired := func(cache *versionCache) bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return time.Now().After(cache.expires)
+	}
+	expected := map[string]crdVersions{"backups": {Served: []string{"v1"}, Storage: "v1"}}
+
+	cache := &versionCache{}
+	var failedLists, lists int32
+	if actual := cache.get(context.Background(), newClient(true, &failedLists)); actual != nil || failedLists != 1 {
+		t.Errorf("get() with failing reads == %#v after %d lists, expected nil after 1 list", actual, failedLists)
+	}
+
+	// A failure is not retried until it expires.
+	if actual := cache.get(context.Background(), newClient(false, &lists)); actual != nil || lists != 0 {
+		t.Errorf("get() after a failure == %#v after %d lists, expected nil without listing", actual, lists)
+	}
+
+	cache.expires = time.Time{}
+	if actual := cache.get(context.Background(), newClient(false, &lists)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("get() == %#v, expected %#v", actual, expected)
+	}
+
+	// Expired versions are returned while they are read again, and kept when reading fails.
+	cache.expires = time.Time{}
+	if actual := cache.get(context.Background(), newClient(true, &failedLists)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("get() of expired versions == %#v, expected %#v", actual, expected)
+	}
+	for deadline := time.Now().Add(time.Second); expired(cache) && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if expired(cache) || !reflect.DeepEqual(cache.versions, expected) || atomic.LoadInt32(&failedLists) != 2 {
+		t.Errorf("get() of expired versions did not read them again in the background")
+	}
+}
@@ -27,6 +27,8 @@ var (
 	argCacheSize             = pflag.Int("cache-size", 1000, "max number of cache entries")
 	argCacheTTL              = pflag.Duration("cache-ttl", 10*time.Minute, "cache entry TTL")
 	argCacheRefreshDebounce  = pflag.Duration("cache-refresh-debounce", 5*time.Second, "minimal time between cache refreshes in the background")
+	argVeleroClientQPS       = pflag.Float32("velero-client-qps", 0, "maximum number of calls per second to the API server for Velero resources, shared by all dashboard users, 0 disables the limit")
+	argVeleroClientBurst     = pflag.Int("velero-client-burst", 100, "maximum burst of calls to the API server for Velero resources above velero-client-qps")
 )
 
 func Ensure() {
@@ -58,3 +60,11 @@ func CacheTTL() time.Duration {
 func CacheRefreshDebounce() time.Duration {
 	return *argCacheRefreshDebounce
 }
+
+func VeleroClientQPS() float32 {
+	return *argVeleroClientQPS
+}
+
+func VeleroClientBurst() int {
+	return *argVeleroClientBurst
+}
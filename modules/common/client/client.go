@@ -23,6 +23,7 @@ import (
 	v1 "k8s.io/api/authorization/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	client "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -117,6 +118,19 @@ func Config(request *http.Request) (*rest.Config, error) {
 	return configFromRequest(request)
 }
 
+func DynamicClient(request *http.Request) (dynamic.Interface, error) {
+	if !isInitialized() {
+		return nil, fmt.Errorf("client package not initialized")
+	}
+
+	config, err := configFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamic.NewForConfig(dynamic.ConfigFor(config))
+}
+
 func RestClientForHost(host string) (rest.Interface, error) {
 	config := setConfigRateLimitDefaults(&rest.Config{Host: host})
 	restClient, err := client.NewForConfig(config)
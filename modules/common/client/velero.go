@@ -0,0 +1,161 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Yiling-J/theine-go"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"k8s.io/dashboard/client/args"
+)
+
+// partialObjectMetadataListMediaType asks the API server to list only the metadata of objects, as a
+// PartialObjectMetadataList. Plain JSON is accepted as well, for aggregated API servers that do not support it.
+const partialObjectMetadataListMediaType = "application/json;as=PartialObjectMetadataList;g=meta.k8s.io;v=v1,application/json"
+
+// veleroClients holds Velero clients by the credentials they were built for, so that the requests of a user share
+// the connections of a single client.
+var veleroClients *theine.Cache[string, dynamic.Interface]
+
+func init() {
+	var err error
+	if veleroClients, err = theine.NewBuilder[string, dynamic.Interface](int64(args.CacheSize())).Build(); err != nil {
+		panic(err)
+	}
+}
+
+// veleroRateLimiter is shared by all Velero clients, as clients are built for every user and a limit per client
+// would not bound the load on the API server.
+var veleroRateLimiter = sync.OnceValue(func() flowcontrol.RateLimiter {
+	if args.VeleroClientQPS() <= 0 {
+		return nil
+	}
+
+	return flowcontrol.NewTokenBucketRateLimiter(args.VeleroClientQPS(), max(args.VeleroClientBurst(), 1))
+})
+
+// VeleroClient returns a dynamic client for Velero resources built for the credentials of the request. Clients are
+// cached by the token and impersonation headers of the request and calls of all of them are limited by the rate
+// set with the velero-client-qps argument. Velero resources reference namespaces, events and workloads, so the
+// client is not restricted to the velero.io group.
+func VeleroClient(request *http.Request) (dynamic.Interface, error) {
+	return veleroClient(request, "full", nil)
+}
+
+// VeleroMetadataClient works like VeleroClient, but lists only the metadata of objects. The dynamic client decodes
+// the returned PartialObjectMetadataList as unstructured objects without a spec or status.
+func VeleroMetadataClient(request *http.Request) (dynamic.Interface, error) {
+	return veleroClient(request, "metadata", func(rt http.RoundTripper) http.RoundTripper {
+		return &metadataRoundTripper{delegate: rt}
+	})
+}
+
+func veleroClient(request *http.Request, kind string, wrap func(http.RoundTripper) http.RoundTripper) (dynamic.Interface, error) {
+	if !isInitialized() {
+		return nil, fmt.Errorf("client package not initialized")
+	}
+
+	authInfo, err := buildAuthInfo(request)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := veleroClientKey(kind, authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, ok := veleroClients.Get(key); ok {
+		return cached, nil
+	}
+
+	config, err := buildConfigFromAuthInfo(authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	veleroClient, err := newVeleroClient(config, wrap)
+	if err != nil {
+		return nil, err
+	}
+
+	veleroClients.SetWithTTL(key, veleroClient, 1, args.CacheTTL())
+	return veleroClient, nil
+}
+
+// UserKey returns a key of the credentials of the request that does not keep the token in memory, so that data read
+// with them can be cached for the user.
+func UserKey(request *http.Request) (string, error) {
+	if !isInitialized() {
+		return "", fmt.Errorf("client package not initialized")
+	}
+
+	authInfo, err := buildAuthInfo(request)
+	if err != nil {
+		return "", err
+	}
+
+	return veleroClientKey("user", authInfo)
+}
+
+// veleroClientKey returns a key of the cache of Velero clients that does not keep the token in memory.
+func veleroClientKey(kind string, authInfo *api.AuthInfo) (string, error) {
+	encoded, err := json.Marshal(authInfo)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(append([]byte(kind+"/"+baseConfig.Host+"/"), encoded...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func newVeleroClient(config *rest.Config, wrap func(http.RoundTripper) http.RoundTripper) (dynamic.Interface, error) {
+	config = rest.CopyConfig(config)
+	if limiter := veleroRateLimiter(); limiter != nil {
+		config.RateLimiter = limiter
+	}
+
+	if wrap != nil {
+		config.Wrap(wrap)
+	}
+
+	return dynamic.NewForConfig(config)
+}
+
+// metadataRoundTripper asks for partial object metadata in list requests. The dynamic client always accepts plain
+// JSON, so the Accept header is replaced on the way out.
+type metadataRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (in *metadataRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != http.MethodGet {
+		return in.delegate.RoundTrip(request)
+	}
+
+	request = request.Clone(request.Context())
+	request.Header.Set("Accept", partialObjectMetadataListMediaType)
+	return in.delegate.RoundTrip(request)
+}
@@ -0,0 +1,143 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+var backupGVR = schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "backups"}
+
+// withBaseConfig initializes the package with the config for the duration of the test.
+func withBaseConfig(t *testing.T, config *rest.Config) {
+	previous := baseConfig
+	baseConfig = config
+	t.Cleanup(func() { baseConfig = previous })
+}
+
+func newVeleroRequest(token string, headers map[string]string) *http.Request {
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/backup", nil)
+	SetAuthorizationHeader(request, token)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	return request
+}
+
+func TestVeleroMetadataClient(t *testing.T) {
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/json;as=PartialObjectMetadataList;g=meta.k8s.io;v=v1")
+		_, _ = w.Write([]byte(`{"kind":"PartialObjectMetadataList","apiVersion":"meta.k8s.io/v1","metadata":{},` +
+			`"items":[{"kind":"PartialObjectMetadata","apiVersion":"meta.k8s.io/v1",` +
+			`"metadata":{"name":"daily","namespace":"velero","labels":{"app":"shop"}}}]}`))
+	}))
+	defer server.Close()
+
+	withBaseConfig(t, &rest.Config{Host: server.URL})
+	client, err := VeleroMetadataClient(newVeleroRequest("metadata-token", nil))
+	if err != nil {
+		t.Fatalf("VeleroMetadataClient() returned error: %v", err)
+	}
+
+	list, err := client.Resource(backupGVR).Namespace("velero").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	if accept != partialObjectMetadataListMediaType {
+		t.Errorf("List() sent Accept %q, expected %q", accept, partialObjectMetadataListMediaType)
+	}
+	if len(list.Items) != 1 || list.Items[0].GetName() != "daily" || list.Items[0].GetLabels()["app"] != "shop" {
+		t.Errorf("List() == %#v, expected the metadata of the daily backup", list.Items)
+	}
+	if _, found := list.Items[0].Object["spec"]; found {
+		t.Errorf("List() returned a spec for %s", list.Items[0].GetName())
+	}
+}
+
+func TestVeleroClientCache(t *testing.T) {
+	withBaseConfig(t, &rest.Config{Host: "https://127.0.0.1:6443"})
+
+	cases := []struct {
+		info     string
+		request  *http.Request
+		metadata bool
+		shared   bool
+	}{
+		{
+			"same token",
+			newVeleroRequest("cache-token", nil),
+			false,
+			true,
+		},
+		{
+			"other token",
+			newVeleroRequest("other-token", nil),
+			false,
+			false,
+		},
+		{
+			"impersonated user",
+			newVeleroRequest("cache-token", map[string]string{ImpersonateUserHeader: "alice"}),
+			false,
+			false,
+		},
+		{
+			"metadata client",
+			newVeleroRequest("cache-token", nil),
+			true,
+			false,
+		},
+	}
+
+	expected, err := VeleroClient(newVeleroRequest("cache-token", nil))
+	if err != nil {
+		t.Fatalf("VeleroClient() returned error: %v", err)
+	}
+
+	for _, c := range cases {
+		get := VeleroClient
+		if c.metadata {
+			get = VeleroMetadataClient
+		}
+
+		actual, err := get(c.request)
+		if err != nil {
+			t.Fatalf("%s: returned error: %v", c.info, err)
+		}
+
+		if shared := actual == expected; shared != c.shared {
+			t.Errorf("%s: shares the cached client == %t, expected %t", c.info, shared, c.shared)
+		}
+	}
+}
+
+func TestVeleroClientUnauthorized(t *testing.T) {
+	withBaseConfig(t, &rest.Config{Host: "https://127.0.0.1:6443"})
+
+	if _, err := VeleroClient(httptest.NewRequest(http.MethodGet, "/api/v1/backup", nil)); err == nil {
+		t.Error("VeleroClient() returned no error for a request without a token")
+	}
+}
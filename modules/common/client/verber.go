@@ -34,6 +34,8 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+
+	"k8s.io/dashboard/types"
 )
 
 var (
@@ -43,8 +45,37 @@ var (
 // resourceVerber is a struct responsible for doing common verb operations on resources, like
 // DELETE, PUT, UPDATE.
 type resourceVerber struct {
-	client    dynamic.Interface
-	discovery discovery.DiscoveryInterface
+	client       dynamic.Interface
+	veleroClient dynamic.Interface
+	discovery    discovery.DiscoveryInterface
+}
+
+// clientFor returns the client of the type the resource is served by.
+func (v *resourceVerber) clientFor(gvr schema.GroupVersionResource) dynamic.Interface {
+	if types.ClientTypeFor(gvr.Group, gvr.Resource) == types.ClientTypeVelero && v.veleroClient != nil {
+		return v.veleroClient
+	}
+
+	return v.client
+}
+
+// namespaceFor returns the namespace to use for the resource of the kind. Kinds mapped to namespaced resources
+// require a namespace and the namespace of cluster-scoped ones is dropped.
+func (v *resourceVerber) namespaceFor(kind string, namespace string) (string, error) {
+	mapping, ok := types.ResourceKind(kind).APIMapping()
+	if !ok {
+		return namespace, nil
+	}
+
+	if !mapping.Namespaced {
+		return "", nil
+	}
+
+	if len(namespace) == 0 {
+		return "", fmt.Errorf("namespace is required for kind %s", kind)
+	}
+
+	return namespace, nil
 }
 
 func (v *resourceVerber) groupVersionResourceFromUnstructured(object *unstructured.Unstructured) schema.GroupVersionResource {
@@ -58,6 +89,11 @@ func (v *resourceVerber) groupVersionResourceFromUnstructured(object *unstructur
 }
 
 func (v *resourceVerber) groupVersionResourceFromKind(kind string) (schema.GroupVersionResource, error) {
+	// Mapped kinds are looked up as CRD resources, e.g. backups.velero.io
+	if mapping, ok := types.ResourceKind(kind).APIMapping(); ok {
+		kind = fmt.Sprintf("%s.%s", mapping.Resource, mapping.Group)
+	}
+
 	if gvr, exists := kindToGroupVersionResource[kind]; exists {
 		klog.V(4).InfoS("GroupVersionResource cache hit", "kind", kind)
 		return gvr, nil
@@ -135,6 +171,11 @@ func (v *resourceVerber) Delete(kind string, namespace string, name string, prop
 		return err
 	}
 
+	namespace, err = v.namespaceFor(kind, namespace)
+	if err != nil {
+		return err
+	}
+
 	defaultPropagationPolicy := v.toDeletePropagationPolicy(propagationPolicy)
 	defaultDeleteOptions := metav1.DeleteOptions{
 		PropagationPolicy: &defaultPropagationPolicy,
@@ -146,7 +187,7 @@ func (v *resourceVerber) Delete(kind string, namespace string, name string, prop
 	}
 
 	klog.V(2).InfoS("deleting resource", "kind", kind, "namespace", namespace, "name", name, "propagationPolicy", propagationPolicy, "deleteNow", deleteNow)
-	return v.client.Resource(gvr).Namespace(namespace).Delete(context.TODO(), name, defaultDeleteOptions)
+	return v.clientFor(gvr).Resource(gvr).Namespace(namespace).Delete(context.TODO(), name, defaultDeleteOptions)
 }
 
 // Update patches resource of the given kind in the given namespace with the given name.
@@ -157,7 +198,7 @@ func (v *resourceVerber) Update(object *unstructured.Unstructured) error {
 
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		klog.V(4).InfoS("fetching latest resource version", "group", gvr.Group, "version", gvr.Version, "resource", gvr.Resource, "name", name, "namespace", namespace)
-		result, getErr := v.client.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		result, getErr := v.clientFor(gvr).Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 		if getErr != nil {
 			return fmt.Errorf("failed to get latest %s version: %w", gvr.Resource, getErr)
 		}
@@ -193,7 +234,7 @@ func (v *resourceVerber) Update(object *unstructured.Unstructured) error {
 			}
 
 			klog.V(2).InfoS("patching resource", "group", gvr.Group, "version", gvr.Version, "resource", gvr.Resource, "name", name, "namespace", namespace, "patch", string(patchBytes))
-			_, updateErr := v.client.Resource(gvr).Namespace(namespace).Patch(context.TODO(), name, k8stypes.MergePatchType, patchBytes, metav1.PatchOptions{})
+			_, updateErr := v.clientFor(gvr).Resource(gvr).Namespace(namespace).Patch(context.TODO(), name, k8stypes.MergePatchType, patchBytes, metav1.PatchOptions{})
 			return updateErr
 		case err != nil:
 			return err
@@ -204,7 +245,7 @@ func (v *resourceVerber) Update(object *unstructured.Unstructured) error {
 			}
 
 			klog.V(2).InfoS("patching resource", "group", gvr.Group, "version", gvr.Version, "resource", gvr.Resource, "name", name, "namespace", namespace, "patch", string(patchBytes))
-			_, updateErr := v.client.Resource(gvr).Namespace(namespace).Patch(context.TODO(), name, k8stypes.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+			_, updateErr := v.clientFor(gvr).Resource(gvr).Namespace(namespace).Patch(context.TODO(), name, k8stypes.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
 			return updateErr
 		}
 	})
@@ -217,7 +258,12 @@ func (v *resourceVerber) Get(kind string, namespace string, name string) (runtim
 		return nil, err
 	}
 
-	return v.client.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	namespace, err = v.namespaceFor(kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.clientFor(gvr).Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 }
 
 func VerberClient(request *http.Request) (ResourceVerber, error) {
@@ -238,8 +284,14 @@ func VerberClient(request *http.Request) (ResourceVerber, error) {
 		return nil, err
 	}
 
+	veleroClient, err := VeleroClient(request)
+	if err != nil {
+		return nil, err
+	}
+
 	return &resourceVerber{
-		client:    dynamicClient,
-		discovery: discoveryClient,
+		client:       dynamicClient,
+		veleroClient: veleroClient,
+		discovery:    discoveryClient,
 	}, nil
 }
@@ -0,0 +1,107 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGroupVersionResourceFromKind(t *testing.T) {
+	discovery := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	discovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "velero.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "backups", Kind: "Backup", Namespaced: true},
+				{Name: "schedules", Kind: "Schedule", Namespaced: true},
+			},
+		},
+		{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "schedules", Kind: "Schedule", Namespaced: true},
+			},
+		},
+	}
+	verber := &resourceVerber{discovery: discovery}
+
+	cases := []struct {
+		kind     string
+		expected schema.GroupVersionResource
+	}{
+		{"backup", schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "backups"}},
+		{"schedule", schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "schedules"}},
+		{"schedules.example.com", schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "schedules"}},
+	}
+
+	for _, c := range cases {
+		actual, err := verber.groupVersionResourceFromKind(c.kind)
+		if err != nil {
+			t.Fatalf("groupVersionResourceFromKind(%s) returned error: %v", c.kind, err)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("groupVersionResourceFromKind(%s) == %v, expected %v", c.kind, actual, c.expected)
+		}
+	}
+}
+
+func TestNamespaceFor(t *testing.T) {
+	cases := []struct {
+		kind      string
+		namespace string
+		expected  string
+		err       bool
+	}{
+		{"backup", "velero", "velero", false},
+		{"backupstoragelocation", "", "", true},
+		{"node", "", "", false},
+		{"deployment", "default", "default", false},
+	}
+
+	verber := &resourceVerber{}
+	for _, c := range cases {
+		actual, err := verber.namespaceFor(c.kind, c.namespace)
+		if (err != nil) != c.err {
+			t.Errorf("namespaceFor(%s, %q) returned error %v, expected error %t", c.kind, c.namespace, err, c.err)
+		}
+
+		if actual != c.expected {
+			t.Errorf("namespaceFor(%s, %q) == %q, expected %q", c.kind, c.namespace, actual, c.expected)
+		}
+	}
+}
+
+func TestClientFor(t *testing.T) {
+	dynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	veleroClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	verber := &resourceVerber{client: dynamicClient, veleroClient: veleroClient}
+
+	if verber.clientFor(schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "restores"}) != veleroClient {
+		t.Error("clientFor(restores.velero.io) did not return the Velero client")
+	}
+
+	if verber.clientFor(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}) != dynamicClient {
+		t.Error("clientFor(deployments.apps) did not return the dynamic client")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// ClientType is the type of client generic operations, e.g. raw get and delete, use for resources of a kind.
+type ClientType string
+
+const (
+	// ClientTypeDynamic resources are served by the dynamic client of the request.
+	ClientTypeDynamic ClientType = "dynamic"
+	// ClientTypeVelero resources are served by the Velero client of the request, which is cached and rate limited.
+	ClientTypeVelero ClientType = "velero"
+)
+
+// APIMapping is the API resource that resources of a kind are served at.
+type APIMapping struct {
+	// Group of the resource, e.g. velero.io.
+	Group string
+
+	// Resource is the plural name of the resource, e.g. backups.
+	Resource string
+
+	// ClientType is the type of client generic operations use for the resource.
+	ClientType ClientType
+
+	// Namespaced represents whether resources of the kind live in namespaces.
+	Namespaced bool
+}
+
+// KindToAPIMapping maps kinds that are not found by API discovery on their own. Kinds of core resources are matched
+// against discovery by their name, while Velero kinds, e.g. backup or schedule, are common enough to be served by
+// other CRDs as well and are mapped to the velero.io group explicitly.
+var KindToAPIMapping = map[ResourceKind]APIMapping{
+	ResourceKindVeleroBackup:                 {"velero.io", "backups", ClientTypeVelero, true},
+	ResourceKindVeleroRestore:                {"velero.io", "restores", ClientTypeVelero, true},
+	ResourceKindVeleroSchedule:               {"velero.io", "schedules", ClientTypeVelero, true},
+	ResourceKindVeleroBackupStorageLocation:  {"velero.io", "backupstoragelocations", ClientTypeVelero, true},
+	ResourceKindVeleroVolumeSnapshotLocation: {"velero.io", "volumesnapshotlocations", ClientTypeVelero, true},
+}
+
+// APIMapping returns the API resource the kind is mapped to, if it is mapped.
+func (k ResourceKind) APIMapping() (APIMapping, bool) {
+	mapping, ok := KindToAPIMapping[k]
+	return mapping, ok
+}
+
+// ClientTypeFor returns the type of client used for the resource of the group, which is the dynamic client for
+// resources that no kind is mapped to.
+func ClientTypeFor(group, resource string) ClientType {
+	for _, mapping := range KindToAPIMapping {
+		if mapping.Group == group && mapping.Resource == resource {
+			return mapping.ClientType
+		}
+	}
+
+	return ClientTypeDynamic
+}
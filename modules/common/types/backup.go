@@ -0,0 +1,41 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// BackupExclusionPreset is a named set of resources left out of Velero backups that reference it by name.
+// Presets are managed through Dashboard settings and expanded by the API when a backup is created.
+type BackupExclusionPreset struct {
+	Name              string   `json:"name"`
+	Description       string   `json:"description,omitempty"`
+	ExcludedResources []string `json:"excludedResources,omitempty"`
+
+	// ExcludeCompletedJobs leaves out Jobs that completed successfully, together with their pods.
+	ExcludeCompletedJobs bool `json:"excludeCompletedJobs,omitempty"`
+}
+
+// DefaultBackupExclusionPresets are used until presets are saved in settings.
+var DefaultBackupExclusionPresets = []BackupExclusionPreset{
+	{
+		Name:        "skip-system-resources",
+		Description: "Skip Events, EndpointSlices, Leases and completed Jobs",
+		ExcludedResources: []string{
+			"events",
+			"events.events.k8s.io",
+			"endpointslices.discovery.k8s.io",
+			"leases.coordination.k8s.io",
+		},
+		ExcludeCompletedJobs: true,
+	},
+}
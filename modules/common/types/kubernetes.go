@@ -64,6 +64,10 @@ type ObjectMeta struct {
 	// intent and helps make sure that UIDs and names do not get conflated.
 	UID types.UID `json:"uid,omitempty"`
 
+	// ResourceVersion is an opaque value that represents the internal version of this object.
+	// Clients can use it to detect that an object changed since they last read it.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
 	// OwnerReference contains enough information to let you identify an owning
 	// object. See [OwnerReference] for more information.
 	OwnerReferences []OwnerReference `json:"ownerReferences,omitempty"`
@@ -125,6 +129,7 @@ func NewObjectMeta(k8SObjectMeta metav1.ObjectMeta) ObjectMeta {
 		CreationTimestamp: k8SObjectMeta.CreationTimestamp,
 		Annotations:       k8SObjectMeta.Annotations,
 		UID:               k8SObjectMeta.UID,
+		ResourceVersion:   k8SObjectMeta.ResourceVersion,
 		OwnerReferences:   toOwnerReferences(k8SObjectMeta.OwnerReferences),
 	}
 }
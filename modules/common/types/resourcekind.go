@@ -54,9 +54,11 @@ const (
 	ResourceKindIngressClass             = "ingressclass"
 
     // Velero Resource Kinds
-    ResourceKindVeleroBackup             = "backup"
-    ResourceKindVeleroRestore            = "restore"
-    ResourceKindVeleroSchedule           = "schedule"
+    ResourceKindVeleroBackup                 = "backup"
+    ResourceKindVeleroRestore                = "restore"
+    ResourceKindVeleroSchedule               = "schedule"
+    ResourceKindVeleroBackupStorageLocation  = "backupstoragelocation"
+    ResourceKindVeleroVolumeSnapshotLocation = "volumesnapshotlocation"
 )
 
 // Scalable method return whether ResourceKind is scalable.
@@ -29,8 +29,10 @@ import (
 	"k8s.io/dashboard/metrics-scraper/pkg/args"
 	"k8s.io/dashboard/metrics-scraper/pkg/database"
 	"k8s.io/dashboard/metrics-scraper/pkg/environment"
+	"k8s.io/dashboard/metrics-scraper/pkg/velero"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
@@ -57,6 +59,12 @@ func main() {
 		klog.Fatalf("Unable to generate a clientset: %s", err)
 	}
 
+	// Generate the client of the Velero server proxy
+	kubernetesClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		klog.Fatalf("Unable to generate a kubernetes client: %s", err)
+	}
+
 	// Create the db "connection"
 	db, err := sql.Open("sqlite", args.DBFile())
 	if err != nil {
@@ -89,6 +97,10 @@ func main() {
 			return
 
 		case <-ticker.C:
+			if args.VeleroNamespace() != "" {
+				updateVelero(kubernetesClient, db, args.VeleroNamespace(), args.VeleroMetricsPort())
+			}
+
 			err = update(clientset, db, args.MetricDuration(), args.MetricNamespaces())
 			if err != nil {
 				break
@@ -145,3 +157,23 @@ func update(client *metricsclient.Clientset, db *sql.DB, metricDuration time.Dur
 	klog.Infof("Database updated: %d nodes, %d pods", len(nodeMetrics.Items), len(podMetrics.Items))
 	return nil
 }
+
+/**
+* Update the Velero metrics in the provided DB. Failures are only logged, so that
+* node and pod metrics are still updated when Velero is not installed.
+ */
+func updateVelero(client kubernetes.Interface, db *sql.DB, namespace string, port int) {
+	samples, err := velero.Scrape(context.TODO(), client, namespace, port)
+	if err != nil {
+		klog.V(args.LogLevelVerbose).Infof("Error scraping Velero metrics in '%s': %s", namespace, err)
+		return
+	}
+
+	err = database.UpdateVeleroDatabase(db, namespace, samples)
+	if err != nil {
+		klog.Errorf("Error updating database with Velero metrics: %s", err)
+		return
+	}
+
+	klog.V(args.LogLevelVerbose).Infof("Database updated: %d Velero metrics", len(samples))
+}
@@ -33,6 +33,7 @@ import (
 func DashboardRouter(r *mux.Router, db *sql.DB) {
 	r.Path("/nodes/{Name}/metrics/{MetricName}/{Whatever}").HandlerFunc(nodeHandler(db))
 	r.Path("/namespaces/{Namespace}/pod-list/{Name}/metrics/{MetricName}/{Whatever}").HandlerFunc(podHandler(db))
+	r.Path("/namespaces/{Namespace}/schedule-list/{Name}/metrics/velero/{MetricName}").HandlerFunc(scheduleHandler(db))
 	r.PathPrefix("/").HandlerFunc(defaultHandler)
 }
 
@@ -116,6 +117,42 @@ func podHandler(db *sql.DB) http.HandlerFunc {
 	return fn
 }
 
+func scheduleHandler(db *sql.DB) http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		resp, err := getScheduleMetrics(db, vars["MetricName"], ResourceSelector{
+			Namespace:    vars["Namespace"],
+			ResourceName: vars["Name"],
+		})
+
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := fmt.Fprintf(w, "Schedule Metrics Error - %v", err.Error())
+			if err != nil {
+				klog.Errorf("Error cannot write response: %v", err)
+			}
+		}
+
+		j, err := json.Marshal(resp)
+
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := fmt.Fprintf(w, "JSON Error - %v", err.Error())
+			if err != nil {
+				klog.Errorf("Error cannot write response: %v", err)
+			}
+		}
+
+		_, err = w.Write(j)
+		if err != nil {
+			klog.Errorf("Error cannot write response: %v", err)
+		}
+	}
+
+	return fn
+}
+
 func getRows(db *sql.DB, table string, metricName string, selector ResourceSelector) (*sql.Rows, error) {
 	var query string
 	var values []interface{}
@@ -300,3 +337,80 @@ func getNodeMetrics(db *sql.DB, metricName string, selector ResourceSelector) (S
 
 	return result, nil
 }
+
+/*
+getScheduleMetrics: With a database connection and a resource selector
+Queries SQLite and returns a list of Velero metrics of the selected schedules.
+*/
+func getScheduleMetrics(db *sql.DB, metricName string, selector ResourceSelector) (SidecarMetricResultList, error) {
+	args := []string{"namespace = ?", "metric = ?"}
+	values := []interface{}{selector.Namespace, metricName}
+	subargs := []string{}
+	for _, v := range strings.Split(selector.ResourceName, ",") {
+		subargs = append(subargs, "?")
+		values = append(values, v)
+	}
+	args = append(args, "schedule in ("+strings.Join(subargs, ",")+")")
+
+	rows, err := db.Query("select value, schedule, time from velero where "+strings.Join(args, " and ")+
+		" order by schedule, time;", values...)
+	if err != nil {
+		klog.Errorf("Error getting schedule metrics: %v", err)
+		return SidecarMetricResultList{}, err
+	}
+
+	defer rows.Close()
+
+	resultList := make(map[string]SidecarMetric)
+
+	for rows.Next() {
+		var metricValue string
+		var schedule string
+		var metricTime string
+		err = rows.Scan(&metricValue, &schedule, &metricTime)
+		if err != nil {
+			return SidecarMetricResultList{}, err
+		}
+
+		layout := "2006-01-02T15:04:05Z"
+		t, err := time.Parse(layout, metricTime)
+		if err != nil {
+			return SidecarMetricResultList{}, err
+		}
+
+		v, err := strconv.ParseUint(metricValue, 10, 64)
+		if err != nil {
+			return SidecarMetricResultList{}, err
+		}
+
+		newMetric := MetricPoint{
+			Timestamp: t,
+			Value:     v,
+		}
+
+		if metricThing, ok := resultList[schedule]; ok {
+			metricThing.AddMetricPoint(newMetric)
+			resultList[schedule] = metricThing
+		} else {
+			resultList[schedule] = SidecarMetric{
+				MetricName:   metricName,
+				MetricPoints: []MetricPoint{newMetric},
+				DataPoints:   []DataPoint{},
+				UIDs: []types.UID{
+					types.UID(schedule),
+				},
+			}
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return SidecarMetricResultList{}, err
+	}
+
+	result := SidecarMetricResultList{}
+	for _, v := range resultList {
+		result.Items = append(result.Items, v)
+	}
+
+	return result, nil
+}
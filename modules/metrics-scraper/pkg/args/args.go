@@ -43,6 +43,10 @@ var (
 	argMetricDuration   = pflag.Duration("metric-duration", 15*time.Minute, "The duration after which metrics are purged from the database.")
 	// When running in a scoped namespace, disable Node lookup and only capture metrics for the given namespace(s)
 	argMetricNamespaces = pflag.StringSlice("namespaces", []string{helpers.GetEnv("POD_NAMESPACE", "")}, "The namespaces to use for all metric calls. When provided, skip node metrics. (defaults to cluster level metrics)")
+
+	// Velero server to scrape backup metrics from, next to the metrics of pods and nodes
+	argVeleroNamespace   = pflag.String("velero-namespace", "velero", "The namespace of the Velero server to scrape backup metrics from. Leave it empty to skip Velero metrics.")
+	argVeleroMetricsPort = pflag.Int("velero-metrics-port", 8085, "The port the Velero server exposes its Prometheus metrics on.")
 )
 
 func init() {
@@ -74,6 +78,14 @@ func MetricNamespaces() []string {
 	return *argMetricNamespaces
 }
 
+func VeleroNamespace() string {
+	return *argVeleroNamespace
+}
+
+func VeleroMetricsPort() int {
+	return *argVeleroMetricsPort
+}
+
 func APILogLevel() klog.Level {
 	v := pflag.Lookup("v")
 	if v == nil {
@@ -23,15 +23,17 @@ import (
 	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
 
 	"k8s.io/dashboard/metrics-scraper/pkg/args"
+	"k8s.io/dashboard/metrics-scraper/pkg/velero"
 )
 
 /*
-CreateDatabase creates tables for node and pod metrics
+CreateDatabase creates tables for node, pod and Velero metrics
 */
 func CreateDatabase(db *sql.DB) error {
 	sqlStmt := `
 	create table if not exists nodes (uid text, name text, cpu text, memory text, storage text, time datetime);
 	create table if not exists pods (uid text, name text, namespace text, container text, cpu text, memory text, storage text, time datetime);
+	create table if not exists velero (schedule text, namespace text, metric text, value text, time datetime);
 	`
 	_, err := db.Exec(sqlStmt)
 	if err != nil {
@@ -91,7 +93,41 @@ func UpdateDatabase(db *sql.DB, nodeMetrics *v1beta1.NodeMetricsList, podMetrics
 }
 
 /*
-CullDatabase deletes rows from nodes and pods based on a time window.
+UpdateVeleroDatabase updates Velero metrics with data scraped from the Velero server in the namespace
+*/
+func UpdateVeleroDatabase(db *sql.DB, namespace string, samples []velero.Sample) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into velero(schedule, namespace, metric, value, time) values(?, ?, ?, ?, datetime('now'))")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, v := range samples {
+		_, err = stmt.Exec(v.Schedule, namespace, v.Metric, v.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		rberr := tx.Rollback()
+		if rberr != nil {
+			return rberr
+		}
+		return err
+	}
+
+	return nil
+}
+
+/*
+CullDatabase deletes rows from nodes, pods and velero based on a time window.
 */
 func CullDatabase(db *sql.DB, window time.Duration) error {
 	tx, err := db.Begin()
@@ -128,6 +164,20 @@ func CullDatabase(db *sql.DB, window time.Duration) error {
 
 	affected, _ = res.RowsAffected()
 	klog.V(args.LogLevelDebug).Infof("Cleaning up pods: %d rows removed", affected)
+
+	velerostmt, err := tx.Prepare("delete from velero where time <= datetime('now', ?);")
+	if err != nil {
+		return err
+	}
+
+	defer velerostmt.Close()
+	res, err = velerostmt.Exec(windowStr)
+	if err != nil {
+		return err
+	}
+
+	affected, _ = res.RowsAffected()
+	klog.V(args.LogLevelDebug).Infof("Cleaning up velero: %d rows removed", affected)
 	err = tx.Commit()
 
 	if err != nil {
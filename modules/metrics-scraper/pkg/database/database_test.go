@@ -28,6 +28,7 @@ import (
 	_ "modernc.org/sqlite"
 
 	"k8s.io/dashboard/metrics-scraper/pkg/database"
+	"k8s.io/dashboard/metrics-scraper/pkg/velero"
 )
 
 func TestMetricsUtil(t *testing.T) {
@@ -238,5 +239,56 @@ var _ = ginkgo.Describe("Database functions", func() {
 			}
 
 		})
+		ginkgo.It("should insert and cull Velero metrics.", func() {
+			db, err := sql.Open("sqlite", ":memory:")
+			if err != nil {
+				panic(err.Error())
+			}
+			defer db.Close()
+
+			err = database.CreateDatabase(db)
+			if err != nil {
+				panic(err.Error())
+			}
+
+			err = database.UpdateVeleroDatabase(db, "velero", []velero.Sample{
+				{Schedule: "daily", Metric: velero.BackupFailureTotal, Value: 2},
+			})
+			if err != nil {
+				panic(err.Error())
+			}
+
+			sqlStmt := "insert into velero(schedule,namespace,metric,value,time) values('weekly','velero','backup_failure_total','1',datetime('now','-20 minutes'));"
+			_, err = db.Exec(sqlStmt)
+			if err != nil {
+				panic(err.Error())
+			}
+
+			err = database.CullDatabase(db, 5*time.Minute)
+			if err != nil {
+				panic(err.Error())
+			}
+
+			rows, err := db.Query("select schedule, namespace, metric, value from velero")
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer rows.Close()
+			count := 0
+			for rows.Next() {
+				var schedule, namespace, metric string
+				var value int64
+				err = rows.Scan(&schedule, &namespace, &metric, &value)
+				if err != nil {
+					log.Fatal(err)
+				}
+				count++
+				gomega.Expect(schedule).To(gomega.Equal("daily"))
+				gomega.Expect(namespace).To(gomega.Equal("velero"))
+				gomega.Expect(metric).To(gomega.Equal(velero.BackupFailureTotal))
+				gomega.Expect(value).To(gomega.Equal(int64(2)))
+			}
+			gomega.Expect(count).To(gomega.Equal(1))
+		})
 	})
 })
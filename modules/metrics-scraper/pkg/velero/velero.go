@@ -0,0 +1,208 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Names of the metrics stored for each schedule. Backups created without a schedule are stored with an empty schedule.
+const (
+	// BackupDurationSeconds is the average duration of the backups the server observed since it started.
+	BackupDurationSeconds     = "backup_duration_seconds"
+	BackupAttemptTotal        = "backup_attempt_total"
+	BackupSuccessTotal        = "backup_success_total"
+	BackupPartialFailureTotal = "backup_partial_failure_total"
+	BackupFailureTotal        = "backup_failure_total"
+)
+
+// serverLabelSelector selects the pods of the Velero server, as labeled by `velero install` and the Helm chart.
+const serverLabelSelector = "component=velero"
+
+// counters maps the counters exposed by the Velero server to the metrics they are stored as.
+var counters = map[string]string{
+	"velero_backup_attempt_total":         BackupAttemptTotal,
+	"velero_backup_success_total":         BackupSuccessTotal,
+	"velero_backup_partial_failure_total": BackupPartialFailureTotal,
+	"velero_backup_failure_total":         BackupFailureTotal,
+}
+
+// Sample is the value of a metric of a schedule at the time of the scrape.
+type Sample struct {
+	Schedule string
+	Metric   string
+	Value    uint64
+}
+
+// Scrape reads the metrics of the running Velero server in the namespace through the API server proxy. No samples are
+// returned when no server is running.
+func Scrape(ctx context.Context, client kubernetes.Interface, namespace string, port int) ([]Sample, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: serverLabelSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+
+		raw, err := client.CoreV1().Pods(namespace).ProxyGet("http", pod.Name, strconv.Itoa(port), "/metrics", nil).
+			DoRaw(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return Parse(bytes.NewReader(raw))
+	}
+
+	return nil, nil
+}
+
+// Parse reads the backup metrics of each schedule from metrics in the Prometheus text format. Other metrics are
+// skipped.
+func Parse(reader io.Reader) ([]Sample, error) {
+	samples := make([]Sample, 0)
+	durationSums := make(map[string]float64)
+	durationCounts := make(map[string]float64)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, labels, value, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		schedule := labels["schedule"]
+		switch name {
+		case "velero_backup_duration_seconds_sum":
+			durationSums[schedule] = value
+		case "velero_backup_duration_seconds_count":
+			durationCounts[schedule] = value
+		default:
+			if metric, ok := counters[name]; ok {
+				samples = append(samples, Sample{Schedule: schedule, Metric: metric, Value: toValue(value)})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for schedule, count := range durationCounts {
+		if count > 0 {
+			samples = append(samples, Sample{
+				Schedule: schedule,
+				Metric:   BackupDurationSeconds,
+				Value:    toValue(durationSums[schedule] / count),
+			})
+		}
+	}
+
+	return samples, nil
+}
+
+// parseLine splits a sample line, e.g. `velero_backup_success_total{schedule="daily"} 3`, into the metric name, its
+// labels and value. A trailing timestamp is ignored.
+func parseLine(line string) (string, map[string]string, float64, error) {
+	labels := make(map[string]string)
+	name, rest := line, ""
+	if i := strings.IndexAny(line, "{ "); i >= 0 {
+		name, rest = line[:i], line[i:]
+	}
+
+	if strings.HasPrefix(rest, "{") {
+		end := strings.LastIndex(rest, "}")
+		if end < 0 {
+			return "", nil, 0, fmt.Errorf("unterminated labels in %q", line)
+		}
+
+		for _, pair := range splitLabels(rest[1:end]) {
+			key, quoted, found := strings.Cut(pair, "=")
+			if !found {
+				return "", nil, 0, fmt.Errorf("invalid label %q in %q", pair, line)
+			}
+
+			value, err := strconv.Unquote(strings.TrimSpace(quoted))
+			if err != nil {
+				return "", nil, 0, fmt.Errorf("invalid label %q in %q", pair, line)
+			}
+			labels[strings.TrimSpace(key)] = value
+		}
+		rest = rest[end+1:]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, fmt.Errorf("missing value in %q", line)
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("invalid value in %q", line)
+	}
+
+	return name, labels, value, nil
+}
+
+// splitLabels splits label pairs at the commas outside of quoted values.
+func splitLabels(raw string) []string {
+	pairs := make([]string, 0)
+	quoted, escaped, start := false, false, 0
+	for i, c := range raw {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			pairs = append(pairs, raw[start:i])
+			start = i + 1
+		}
+	}
+
+	if strings.TrimSpace(raw[start:]) != "" {
+		pairs = append(pairs, raw[start:])
+	}
+
+	return pairs
+}
+
+// toValue rounds the value to the unsigned integers metrics are stored as.
+func toValue(value float64) uint64 {
+	if value < 0 || math.IsNaN(value) {
+		return 0
+	}
+
+	return uint64(math.Round(value))
+}
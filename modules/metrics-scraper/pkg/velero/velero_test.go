@@ -0,0 +1,71 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"k8s.io/dashboard/metrics-scraper/pkg/velero"
+)
+
+func TestVelero(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Velero Metrics Test")
+}
+
+const metrics = `# HELP velero_backup_attempt_total Total number of attempted backups
+# TYPE velero_backup_attempt_total counter
+velero_backup_attempt_total{schedule=""} 2
+velero_backup_attempt_total{schedule="daily"} 10
+velero_backup_failure_total{schedule="daily"} 1
+velero_backup_success_total{schedule="daily"} 9 1700000000000
+# TYPE velero_backup_duration_seconds histogram
+velero_backup_duration_seconds_bucket{schedule="daily",le="10"} 4
+velero_backup_duration_seconds_sum{schedule="daily"} 125.5
+velero_backup_duration_seconds_count{schedule="daily"} 5
+velero_backup_duration_seconds_sum{schedule="weekly"} 0
+velero_backup_duration_seconds_count{schedule="weekly"} 0
+velero_restore_attempt_total{schedule="daily"} 3
+go_goroutines 42
+`
+
+var _ = ginkgo.Describe("Velero metrics", func() {
+	ginkgo.It("should parse backup metrics per schedule.", func() {
+		samples, err := velero.Parse(strings.NewReader(metrics))
+		gomega.Expect(err).To(gomega.BeNil())
+		gomega.Expect(samples).To(gomega.ConsistOf(
+			velero.Sample{Schedule: "", Metric: velero.BackupAttemptTotal, Value: 2},
+			velero.Sample{Schedule: "daily", Metric: velero.BackupAttemptTotal, Value: 10},
+			velero.Sample{Schedule: "daily", Metric: velero.BackupFailureTotal, Value: 1},
+			velero.Sample{Schedule: "daily", Metric: velero.BackupSuccessTotal, Value: 9},
+			velero.Sample{Schedule: "daily", Metric: velero.BackupDurationSeconds, Value: 25},
+		))
+	})
+
+	ginkgo.It("should parse quoted label values.", func() {
+		samples, err := velero.Parse(strings.NewReader(`velero_backup_failure_total{a="x,\"y\"",schedule="daily"} 4` + "\n"))
+		gomega.Expect(err).To(gomega.BeNil())
+		gomega.Expect(samples).To(gomega.ConsistOf(velero.Sample{Schedule: "daily", Metric: velero.BackupFailureTotal, Value: 4}))
+	})
+
+	ginkgo.It("should reject malformed lines.", func() {
+		_, err := velero.Parse(strings.NewReader(`velero_backup_failure_total{schedule="daily" 4` + "\n"))
+		gomega.Expect(err).NotTo(gomega.BeNil())
+	})
+})
@@ -0,0 +1,52 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/dashboard/types"
+)
+
+type BackupExclusionPresets []types.BackupExclusionPreset
+
+func (p BackupExclusionPresets) Validate() error {
+	names := make(map[string]bool, len(p))
+	for _, preset := range p {
+		if len(preset.Name) == 0 {
+			return fmt.Errorf("backup exclusion preset name is required")
+		}
+
+		if names[preset.Name] {
+			return fmt.Errorf("duplicate backup exclusion preset %q", preset.Name)
+		}
+
+		names[preset.Name] = true
+	}
+
+	return nil
+}
+
+func (p BackupExclusionPresets) Marshal() string {
+	bytes, _ := json.Marshal(p)
+	return string(bytes)
+}
+
+func UnmarshalBackupExclusionPresets(data string) (BackupExclusionPresets, error) {
+	p := new(BackupExclusionPresets)
+	err := json.Unmarshal([]byte(data), p)
+	return *p, err
+}
@@ -60,6 +60,9 @@ func init() {
 	router.Root().PUT("/settings/pinnedresources", handleSettingsSavePinned)
 	router.Root().DELETE("/settings/pinnedresources/:kind/:nameOrNamespace/:name", handleSettingsDeletePinned)
 	router.Root().DELETE("/settings/pinnedresources/:kind/:nameOrNamespace", handleSettingsDeletePinned)
+	router.Root().GET("/settings/backupexclusionpresets/cani", handleGetSettingsGlobalCanI)
+	router.Root().GET("/settings/backupexclusionpresets", handleSettingsGetBackupExclusionPresets)
+	router.Root().PUT("/settings/backupexclusionpresets", handleSettingsSaveBackupExclusionPresets)
 }
 
 func handleGetSettingsGlobalCanI(c *gin.Context) {
@@ -160,3 +163,30 @@ func handleSettingsDeletePinned(c *gin.Context) {
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+func handleSettingsGetBackupExclusionPresets(c *gin.Context) {
+	k8sClient := client.InClusterClient()
+	c.JSON(http.StatusOK, manager.GetBackupExclusionPresets(k8sClient))
+}
+
+func handleSettingsSaveBackupExclusionPresets(c *gin.Context) {
+	k8sClient, err := client.Client(c.Request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, err)
+		return
+	}
+
+	presets := BackupExclusionPresets{}
+	if err := c.Bind(&presets); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	if err := manager.SaveBackupExclusionPresets(k8sClient, presets); err != nil {
+		code, err := errors.HandleError(err)
+		c.JSON(code, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
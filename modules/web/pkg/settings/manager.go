@@ -29,6 +29,7 @@ import (
 	"k8s.io/dashboard/web/pkg/args"
 
 	"k8s.io/dashboard/errors"
+	dashboardtypes "k8s.io/dashboard/types"
 )
 
 const (
@@ -38,6 +39,9 @@ const (
 	// PinnedResourcesKey is a settings map key which maps to current pinned resources.
 	PinnedResourcesKey = "pinnedResources"
 
+	// BackupExclusionPresetsKey is a settings map key which maps to current backup exclusion presets.
+	BackupExclusionPresetsKey = "backupExclusionPresets"
+
 	// ConcurrentSettingsChangeError occurs during settings save if settings were modified concurrently.
 	// Keep it in sync with concurrentChangeErr_ constant from the frontend.
 	ConcurrentSettingsChangeError = "settings changed since last reload"
@@ -48,10 +52,11 @@ const (
 
 // SettingsManager is a structure containing all settings manager members.
 type SettingsManager struct {
-	settings        *Settings
-	pinnedResources PinnedResources
-	rawSettings     map[string]string
-	mux             sync.Mutex
+	settings               *Settings
+	pinnedResources        PinnedResources
+	backupExclusionPresets BackupExclusionPresets
+	rawSettings            map[string]string
+	mux                    sync.Mutex
 }
 
 // SettingsManagerI is used for user settings management.
@@ -66,6 +71,10 @@ type SettingsManagerI interface {
 	SavePinnedResource(client kubernetes.Interface, r *PinnedResource) error
 	// DeletePinnedResource removes a pinned resource from config map.
 	DeletePinnedResource(client kubernetes.Interface, r *PinnedResource) error
+	// GetBackupExclusionPresets gets the backup exclusion presets from config map.
+	GetBackupExclusionPresets(client kubernetes.Interface) BackupExclusionPresets
+	// SaveBackupExclusionPresets replaces backup exclusion presets in config map.
+	SaveBackupExclusionPresets(client kubernetes.Interface, p BackupExclusionPresets) error
 }
 
 // NewSettingsManager creates new settings manager.
@@ -101,6 +110,14 @@ func (sm *SettingsManager) load(client kubernetes.Interface) (changed bool) {
 			}
 		}
 
+		if presets, ok := sm.rawSettings[BackupExclusionPresetsKey]; ok {
+			if p, err := UnmarshalBackupExclusionPresets(presets); err != nil {
+				klog.ErrorS(err, "cannot unmarshal backup exclusion presets", "backupExclusionPresets", presets)
+			} else {
+				sm.backupExclusionPresets = p
+			}
+		}
+
 		if settings, ok := sm.rawSettings[ConfigMapSettingsKey]; ok {
 			if s, err := UnmarshalSettings(settings); err != nil {
 				klog.ErrorS(err, "cannot unmarshal settings", "settings", settings)
@@ -168,6 +185,31 @@ func (sm *SettingsManager) DeletePinnedResource(client kubernetes.Interface, r *
 	return sm.patchConfigMap(client, PinnedResourcesKey, sm.pinnedResources.Marshal())
 }
 
+func (sm *SettingsManager) GetBackupExclusionPresets(client kubernetes.Interface) BackupExclusionPresets {
+	_ = sm.load(client)
+
+	if len(sm.backupExclusionPresets) == 0 {
+		return dashboardtypes.DefaultBackupExclusionPresets
+	}
+
+	return sm.backupExclusionPresets
+}
+
+func (sm *SettingsManager) SaveBackupExclusionPresets(client kubernetes.Interface, p BackupExclusionPresets) error {
+	if err := p.Validate(); err != nil {
+		return errors.NewBadRequest(err.Error())
+	}
+
+	if changed := sm.load(client); changed {
+		return errors.NewInvalid(ConcurrentSettingsChangeError)
+	}
+
+	defer sm.load(client)
+
+	sm.backupExclusionPresets = p
+	return sm.patchConfigMap(client, BackupExclusionPresetsKey, sm.backupExclusionPresets.Marshal())
+}
+
 func (sm *SettingsManager) patchConfigMap(client kubernetes.Interface, key, value string) error {
 	patch, err := json.Marshal(v1.ConfigMap{Data: map[string]string{key: value}})
 	if err != nil {